@@ -17,9 +17,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -32,9 +38,92 @@ func (mcm *ManagedClusterModule) SetupWebhookWithManager(mgr ctrl.Manager) error
 	// need to make sure it is set correctly in the +kubebuilder annotation below.
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(mcm).
+		WithValidator(&managedClusterModuleValidator{client: mgr.GetClient()}).
 		Complete()
 }
 
+// managedClusterModuleValidator runs the validation implemented by ManagedClusterModule, and rejects dependency cycles
+// between the Modules created from ManagedClusterModules.
+// Only ManagedClusterModules are considered: a cycle involving a Module created directly in a managed cluster is
+// rejected by the webhook of that cluster when the Module is applied there.
+// +kubebuilder:object:generate=false
+type managedClusterModuleValidator struct {
+	client client.Reader
+}
+
+var _ admission.CustomValidator = &managedClusterModuleValidator{}
+
+func (v *managedClusterModuleValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	mcm, ok := obj.(*ManagedClusterModule)
+	if !ok {
+		return nil, fmt.Errorf("object %v is not of the expected type *ManagedClusterModule", obj)
+	}
+
+	warnings, err := mcm.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, v.validateDependencyCycles(ctx, mcm)
+}
+
+func (v *managedClusterModuleValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	mcm, ok := newObj.(*ManagedClusterModule)
+	if !ok {
+		return nil, fmt.Errorf("object %v is not of the expected type *ManagedClusterModule", newObj)
+	}
+
+	warnings, err := mcm.ValidateUpdate(oldObj)
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, v.validateDependencyCycles(ctx, mcm)
+}
+
+func (v *managedClusterModuleValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	mcm, ok := obj.(*ManagedClusterModule)
+	if !ok {
+		return nil, fmt.Errorf("object %v is not of the expected type *ManagedClusterModule", obj)
+	}
+
+	return mcm.ValidateDelete()
+}
+
+func (v *managedClusterModuleValidator) validateDependencyCycles(ctx context.Context, mcm *ManagedClusterModule) error {
+	if len(mcm.Spec.ModuleSpec.Dependencies) == 0 {
+		return nil
+	}
+
+	mcmList := ManagedClusterModuleList{}
+
+	if err := v.client.List(ctx, &mcmList); err != nil {
+		return fmt.Errorf("could not list ManagedClusterModules: %v", err)
+	}
+
+	modules := make([]kmmv1beta1.Module, 0, len(mcmList.Items))
+
+	for i := range mcmList.Items {
+		modules = append(modules, spokeModule(&mcmList.Items[i]))
+	}
+
+	m := spokeModule(mcm)
+
+	if cycle := kmmv1beta1.DependencyCycle(&m, modules); cycle != nil {
+		return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	return nil
+}
+
+// spokeModule returns the Module that mcm creates in the managed clusters.
+func spokeModule(mcm *ManagedClusterModule) kmmv1beta1.Module {
+	return kmmv1beta1.Module{
+		ObjectMeta: metav1.ObjectMeta{Name: mcm.Name, Namespace: mcm.Spec.SpokeNamespace},
+		Spec:       mcm.Spec.ModuleSpec,
+	}
+}
+
 //+kubebuilder:webhook:path=/validate-hub-kmm-sigs-x-k8s-io-v1beta1-managedclustermodule,mutating=false,failurePolicy=fail,sideEffects=None,groups=hub.kmm.sigs.x-k8s.io,resources=managedclustermodules,verbs=create;update,versions=v1beta1,name=vmanagedclustermodule.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Validator = &ManagedClusterModule{}
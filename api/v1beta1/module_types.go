@@ -19,6 +19,7 @@ package v1beta1
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // BuildArg represents a build argument used when building a container image.
@@ -121,7 +122,47 @@ type KernelMapping struct {
 	// +optional
 	// InTreeModuleToRemove specifies the in-tree kernel module that should be removed (if present)
 	// before loading the kernel module from the ContainerImage
+	// Deprecated: use InTreeModulesToRemove instead.
 	InTreeModuleToRemove string `json:"inTreeModuleToRemove"`
+
+	// +optional
+	// InTreeModulesToRemove specifies the in-tree kernel modules that should be removed (if present)
+	// before loading the kernel module from the ContainerImage.
+	// They are also blacklisted on the node while the Module is loaded, so that they are not loaded again at boot.
+	InTreeModulesToRemove []string `json:"inTreeModulesToRemove,omitempty"`
+
+	// +optional
+	// Platform overrides the Module's platform for this mapping.
+	Platform string `json:"platform,omitempty"`
+
+	// NodeMatch restricts this mapping to some of the nodes running a matching kernel.
+	NodeMatch `json:",inline"`
+}
+
+// NodeMatch selects nodes by their labels and by the properties they report in .status.nodeInfo.
+// A node matches if it matches all the fields that are set.
+type NodeMatch struct {
+	// +optional
+	// NodeSelector selects nodes by their labels.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// +optional
+	// OSImage is a regular expression to be matched against the node's OS image.
+	OSImage string `json:"osImage,omitempty"`
+
+	// +optional
+	// Architecture is a regular expression to be matched against the node's architecture, e.g. amd64.
+	Architecture string `json:"architecture,omitempty"`
+
+	// +optional
+	// ContainerRuntimeVersion is a regular expression to be matched against the node's container runtime version,
+	// e.g. containerd://1.7.0.
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion,omitempty"`
+}
+
+// IsEmpty returns true if nm matches all nodes.
+func (nm *NodeMatch) IsEmpty() bool {
+	return nm.NodeSelector == nil && nm.OSImage == "" && nm.Architecture == "" && nm.ContainerRuntimeVersion == ""
 }
 
 type ModprobeArgs struct {
@@ -176,8 +217,60 @@ type ModprobeSpec struct {
 	// In order to load all 3 modules, moduleA shoud be defined in the ModuleName parameter of this struct
 	// +optional
 	ModulesLoadingOrder []string `json:"modulesLoadingOrder,omitempty"`
+
+	// InUsePolicy defines what the worker does when the module is still in use when it should be unloaded.
+	// Wait waits for the module to be released and fails with the ModuleInUse reason if it is not; the worker is then
+	// retried.
+	// Force removes the module anyway, which requires a kernel built with CONFIG_MODULE_FORCE_UNLOAD.
+	// Skip leaves the module loaded and considers it unloaded.
+	// +kubebuilder:default=Wait
+	// +optional
+	InUsePolicy ModuleInUsePolicy `json:"inUsePolicy,omitempty"`
+
+	// Hooks are executables in the kmod image that the worker runs before and after loading or unloading the module.
+	// +optional
+	Hooks *ModprobeHooks `json:"hooks,omitempty"`
+}
+
+// ModprobeHooks lists the hooks run around modprobe.
+// PreUnload runs before the worker checks whether the module is in use, so that it can release the module.
+type ModprobeHooks struct {
+	// +optional
+	PreLoad *ModprobeHook `json:"preLoad,omitempty"`
+	// +optional
+	PostLoad *ModprobeHook `json:"postLoad,omitempty"`
+	// +optional
+	PreUnload *ModprobeHook `json:"preUnload,omitempty"`
+	// +optional
+	PostUnload *ModprobeHook `json:"postUnload,omitempty"`
 }
 
+// ModprobeHook is an executable run by the worker with the KERNEL_VERSION and MOD_NAME environment variables, from the
+// root of the kmod image.
+type ModprobeHook struct {
+	// Path is the absolute path of the executable in the kmod image.
+	Path string `json:"path"`
+
+	// Args are passed to the executable.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Timeout is the duration after which the hook is killed and considered failed.
+	// Defaults to 30 seconds.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ModuleInUsePolicy is the action taken when unloading a module that is in use.
+// +kubebuilder:validation:Enum=Wait;Force;Skip
+type ModuleInUsePolicy string
+
+const (
+	ModuleInUsePolicyWait  ModuleInUsePolicy = "Wait"
+	ModuleInUsePolicyForce ModuleInUsePolicy = "Force"
+	ModuleInUsePolicySkip  ModuleInUsePolicy = "Skip"
+)
+
 type ModuleLoaderContainerSpec struct {
 	// Build contains build instructions.
 	// +optional
@@ -220,7 +313,67 @@ type ModuleLoaderContainerSpec struct {
 	// +optional
 	// InTreeModuleToRemove specifies the in-tree kernel module that should be removed (if present)
 	// before loading the kernel module from the ContainerImage
+	// Deprecated: use InTreeModulesToRemove instead.
 	InTreeModuleToRemove string `json:"inTreeModuleToRemove"`
+
+	// +optional
+	// InTreeModulesToRemove specifies the in-tree kernel modules that should be removed (if present)
+	// before loading the kernel module from the ContainerImage.
+	// They are also blacklisted on the node while the Module is loaded, so that they are not loaded again at boot.
+	InTreeModulesToRemove []string `json:"inTreeModulesToRemove,omitempty"`
+
+	// +optional
+	// Platform selects the image to use in multi-architecture images, in the os/architecture[/variant] format
+	// (for example linux/arm64).
+	// If empty, the image matching the node's platform is used.
+	Platform string `json:"platform,omitempty"`
+
+	// +optional
+	// ImageVerification makes the worker verify the cosign signature or attestation of the container image before
+	// unpacking it.
+	// Images that are not signed with one of the configured keys are not loaded.
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
+
+	// +optional
+	// Timeouts bound the time that worker Pods spend pulling the image and loading or unloading the module.
+	// Unset timeouts take the defaults set in the operator's configuration.
+	Timeouts *ModuleTimeouts `json:"timeouts,omitempty"`
+}
+
+type ModuleTimeouts struct {
+	// +optional
+	// Pull bounds pulling and unpacking the container image.
+	Pull *metav1.Duration `json:"pull,omitempty"`
+
+	// +optional
+	// Load bounds the steps that load the module, once the image is unpacked.
+	Load *metav1.Duration `json:"load,omitempty"`
+
+	// +optional
+	// Unload bounds the steps that unload the module, once the image is unpacked.
+	Unload *metav1.Duration `json:"unload,omitempty"`
+}
+
+// ImageVerificationType is the kind of cosign artifact to verify.
+// +kubebuilder:validation:Enum=Signature;Attestation
+type ImageVerificationType string
+
+const (
+	// ImageVerificationSignature verifies a cosign image signature.
+	ImageVerificationSignature ImageVerificationType = "Signature"
+	// ImageVerificationAttestation verifies a cosign in-toto attestation whose subject is the image.
+	ImageVerificationAttestation ImageVerificationType = "Attestation"
+)
+
+type ImageVerification struct {
+	// KeysSecret is a Secret in the Module's namespace holding PEM-encoded public keys.
+	// The image is accepted if it is signed with any of them.
+	KeysSecret v1.LocalObjectReference `json:"keysSecret"`
+
+	// +optional
+	// +kubebuilder:default=Signature
+	// Type is the kind of cosign artifact to verify.
+	Type ImageVerificationType `json:"type,omitempty"`
 }
 
 type ModuleLoaderSpec struct {
@@ -298,6 +451,11 @@ type DevicePluginSpec struct {
 
 // ModuleSpec describes how the KMM operator should deploy a Module on those nodes that need it.
 type ModuleSpec struct {
+	// Dependencies are the Modules whose kernel modules must be loaded on a node before this one, and unloaded after
+	// it.
+	// +optional
+	Dependencies []ModuleDependency `json:"dependencies,omitempty"`
+
 	// DevicePlugin allows overriding some properties of the container that deploys the device plugin on the node.
 	// Name is ignored and is set automatically by the KMM Operator.
 	// +optional
@@ -312,8 +470,80 @@ type ModuleSpec struct {
 	// +optional
 	ImageRepoSecret *v1.LocalObjectReference `json:"imageRepoSecret,omitempty"`
 
+	// MaintenancePolicy makes KMM cordon and drain nodes before the kernel module is unloaded or reloaded.
+	// Nodes are uncordoned once the new config is loaded, or the kernel module unloaded.
+	// +optional
+	MaintenancePolicy *MaintenancePolicy `json:"maintenancePolicy,omitempty"`
+
 	// Selector describes on which nodes the Module should be loaded and optionally built.
 	Selector map[string]string `json:"selector"`
+
+	// UpdateStrategy describes how changes to the kernel module's config are applied to the nodes.
+	// By default, all nodes are updated at the same time.
+	// +optional
+	UpdateStrategy *ModuleUpdateStrategy `json:"updateStrategy,omitempty"`
+}
+
+// ModuleDependency references a Module that another Module depends on.
+type ModuleDependency struct {
+	// Name is the name of the Module.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Module.
+	// Defaults to the namespace of the dependent Module.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// MaintenancePolicy selects the Pods that are evicted from a node before the kernel module is unloaded from it.
+type MaintenancePolicy struct {
+	// ResourceNames are the extended resources provided by the kernel module, typically through its device plugin.
+	// Pods requesting any of them are evicted.
+	// +optional
+	ResourceNames []v1.ResourceName `json:"resourceNames,omitempty"`
+
+	// PodSelector selects additional Pods to evict, in all namespaces.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// ModuleUpdateStrategyType is how changes to a Module are applied to the nodes.
+// +kubebuilder:validation:Enum=AllAtOnce;RollingUpdate
+type ModuleUpdateStrategyType string
+
+const (
+	// AllAtOnceModuleUpdateStrategyType updates the kernel module on all nodes at the same time.
+	AllAtOnceModuleUpdateStrategyType ModuleUpdateStrategyType = "AllAtOnce"
+	// RollingUpdateModuleUpdateStrategyType updates the kernel module on a limited number of nodes at a time.
+	RollingUpdateModuleUpdateStrategyType ModuleUpdateStrategyType = "RollingUpdate"
+)
+
+type ModuleUpdateStrategy struct {
+	// Type is AllAtOnce or RollingUpdate.
+	// +kubebuilder:default=AllAtOnce
+	// +optional
+	Type ModuleUpdateStrategyType `json:"type,omitempty"`
+
+	// RollingUpdate is only used if Type is RollingUpdate.
+	// +optional
+	RollingUpdate *RollingUpdateModule `json:"rollingUpdate,omitempty"`
+}
+
+type RollingUpdateModule struct {
+	// MaxUnavailable is the maximum number of nodes on which the kernel module may be unavailable during an update,
+	// either because its new config is being loaded or because its current config is not loaded.
+	// It is a count or a percentage of the nodes targeted by the Module, rounded up.
+	// Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Partition is the number of nodes targeted by the Module, in name order, that keep their current config.
+	// Nodes on which the kernel module is not configured yet get the new config regardless of Partition.
+	// Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Partition *int32 `json:"partition,omitempty"`
 }
 
 // DaemonSetStatus contains the status for a daemonset deployed during
@@ -327,6 +557,84 @@ type DaemonSetStatus struct {
 	AvailableNumber int32 `json:"availableNumber,omitempty"`
 }
 
+const (
+	// ModuleConditionReady is True if the kernel module is loaded with its current config on all nodes that have a
+	// kernel mapping.
+	ModuleConditionReady = "Ready"
+	// ModuleConditionProgressing is True while the kernel module is being loaded on some nodes, while their
+	// image is being built, or while the rolling update holds back its new config on some nodes.
+	ModuleConditionProgressing = "Progressing"
+	// ModuleConditionDegraded is True if the kernel module failed to load on some nodes.
+	ModuleConditionDegraded = "Degraded"
+	// ModuleConditionBuildFailed is True if the build of the image failed for some kernel versions.
+	ModuleConditionBuildFailed = "BuildFailed"
+	// ModuleConditionSignFailed is True if the signing of the image failed for some kernel versions.
+	ModuleConditionSignFailed = "SignFailed"
+	// ModuleConditionNoMatchingKernelMapping is True if some targeted nodes run a kernel that matches no kernel
+	// mapping.
+	ModuleConditionNoMatchingKernelMapping = "NoMatchingKernelMapping"
+	// ModuleConditionMissingDependencies is True if some targeted nodes wait for dependencies that are not configured
+	// on them, because the Module they refer to does not exist or does not target those nodes.
+	ModuleConditionMissingDependencies = "MissingDependencies"
+
+	// MaxModuleStatusNodes is the maximum number of nodes listed in each category of ModuleNodesStatus.
+	MaxModuleStatusNodes = 10
+)
+
+// NodeList is a bounded list of nodes.
+type NodeList struct {
+	// Total is the number of nodes in that category, including those not listed.
+	Total int32 `json:"total,omitempty"`
+	// Names are the first nodes in name order.
+	// +optional
+	Names []string `json:"names,omitempty"`
+}
+
+type NodeFailure struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+	// Reason is the reason of the last failed attempt to load the kernel module.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable description of the failure.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// FailedNodeList is a bounded list of nodes on which the kernel module failed to load.
+type FailedNodeList struct {
+	// Total is the number of failed nodes, including those not listed.
+	Total int32 `json:"total,omitempty"`
+	// Nodes are the first failed nodes in name order.
+	// +optional
+	Nodes []NodeFailure `json:"nodes,omitempty"`
+}
+
+// ModuleNodesStatus summarizes the state of the kernel module on the targeted nodes that are not ready.
+// At most MaxModuleStatusNodes nodes are listed in each category.
+type ModuleNodesStatus struct {
+	// NoMatchingKernelMapping lists the nodes running a kernel that matches no kernel mapping.
+	// +optional
+	NoMatchingKernelMapping NodeList `json:"noMatchingKernelMapping,omitempty"`
+	// WaitingForImage lists the nodes waiting for the image of their kernel to be built, signed or pushed.
+	// +optional
+	WaitingForImage NodeList `json:"waitingForImage,omitempty"`
+	// Failed lists the nodes on which the last attempt to load the kernel module failed.
+	// +optional
+	Failed FailedNodeList `json:"failed,omitempty"`
+	// HeldBack lists the nodes that keep the previous config of the kernel module because of the rolling update's
+	// partition or maxUnavailable.
+	// +optional
+	HeldBack NodeList `json:"heldBack,omitempty"`
+	// MissingDependencies lists the nodes on which the kernel module waits for dependencies that are not configured
+	// on the node.
+	// +optional
+	MissingDependencies NodeList `json:"missingDependencies,omitempty"`
+	// Progressing lists the nodes on which the current config of the kernel module is being loaded.
+	// +optional
+	Progressing NodeList `json:"progressing,omitempty"`
+}
+
 // ModuleStatus defines the observed state of Module.
 type ModuleStatus struct {
 	// DevicePlugin contains the status of the Device Plugin daemonset
@@ -334,6 +642,16 @@ type ModuleStatus struct {
 	DevicePlugin DaemonSetStatus `json:"devicePlugin,omitempty"`
 	// ModuleLoader contains the status of the ModuleLoader daemonset
 	ModuleLoader DaemonSetStatus `json:"moduleLoader"`
+
+	// Conditions describe the overall state of the Module.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Nodes summarizes the state of the kernel module on the targeted nodes that are not ready.
+	// +optional
+	Nodes ModuleNodesStatus `json:"nodes,omitempty"`
 }
 
 //+kubebuilder:object:root=true
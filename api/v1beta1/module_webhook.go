@@ -17,13 +17,20 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/localimage"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/platform"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -40,9 +47,124 @@ func (m *Module) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	// need to make sure it is set correctly in the +kubebuilder annotation below.
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(m).
+		WithValidator(&moduleValidator{client: mgr.GetClient()}).
 		Complete()
 }
 
+// moduleValidator runs the validation implemented by Module, and rejects dependency cycles between Modules, which
+// requires listing the existing ones.
+// +kubebuilder:object:generate=false
+type moduleValidator struct {
+	client client.Reader
+}
+
+var _ admission.CustomValidator = &moduleValidator{}
+
+func (v *moduleValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	m, ok := obj.(*Module)
+	if !ok {
+		return nil, fmt.Errorf("object %v is not of the expected type *Module", obj)
+	}
+
+	warnings, err := m.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, v.validateDependencyCycles(ctx, m)
+}
+
+func (v *moduleValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	m, ok := newObj.(*Module)
+	if !ok {
+		return nil, fmt.Errorf("object %v is not of the expected type *Module", newObj)
+	}
+
+	warnings, err := m.ValidateUpdate(oldObj)
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, v.validateDependencyCycles(ctx, m)
+}
+
+func (v *moduleValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	m, ok := obj.(*Module)
+	if !ok {
+		return nil, fmt.Errorf("object %v is not of the expected type *Module", obj)
+	}
+
+	return m.ValidateDelete()
+}
+
+func (v *moduleValidator) validateDependencyCycles(ctx context.Context, m *Module) error {
+	if len(m.Spec.Dependencies) == 0 {
+		return nil
+	}
+
+	modList := ModuleList{}
+
+	if err := v.client.List(ctx, &modList); err != nil {
+		return fmt.Errorf("could not list Modules: %v", err)
+	}
+
+	if cycle := DependencyCycle(m, modList.Items); cycle != nil {
+		return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	return nil
+}
+
+// DependencyCycle returns the namespace/name of the Modules forming a dependency cycle through m, starting and ending
+// with m, or nil if there is none.
+// m replaces its existing version in modules, if any.
+func DependencyCycle(m *Module, modules []Module) []string {
+	deps := make(map[string][]string, len(modules)+1)
+
+	addModule := func(mod *Module) {
+		key := mod.Namespace + "/" + mod.Name
+
+		deps[key] = make([]string, 0, len(mod.Spec.Dependencies))
+
+		for _, d := range mod.Spec.Dependencies {
+			deps[key] = append(deps[key], d.namespacedName(mod.Namespace))
+		}
+	}
+
+	for i := range modules {
+		addModule(&modules[i])
+	}
+
+	addModule(m)
+
+	start := m.Namespace + "/" + m.Name
+	visited := sets.New[string]()
+
+	var visit func(path []string) []string
+
+	visit = func(path []string) []string {
+		for _, d := range deps[path[len(path)-1]] {
+			if d == start {
+				return append(path, d)
+			}
+
+			if visited.Has(d) {
+				continue
+			}
+
+			visited.Insert(d)
+
+			if cycle := visit(append(path, d)); cycle != nil {
+				return cycle
+			}
+		}
+
+		return nil
+	}
+
+	return visit([]string{start})
+}
+
 //+kubebuilder:webhook:path=/validate-kmm-sigs-x-k8s-io-v1beta1-module,mutating=false,failurePolicy=fail,sideEffects=None,groups=kmm.sigs.x-k8s.io,resources=modules,verbs=create;update,versions=v1beta1,name=vmodule.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Validator = &Module{}
@@ -93,6 +215,34 @@ func (m *Module) validate() (admission.Warnings, error) {
 		return nil, fmt.Errorf("failed to validate kernel mappings: %v", err)
 	}
 
+	if err := m.validateContainerImages(); err != nil {
+		return nil, fmt.Errorf("failed to validate container images: %v", err)
+	}
+
+	if err := m.validatePlatforms(); err != nil {
+		return nil, fmt.Errorf("failed to validate platforms: %v", err)
+	}
+
+	if err := m.validateInTreeModules(); err != nil {
+		return nil, fmt.Errorf("failed to validate in-tree modules: %v", err)
+	}
+
+	if err := m.validateTimeouts(); err != nil {
+		return nil, fmt.Errorf("failed to validate timeouts: %v", err)
+	}
+
+	if err := m.validateUpdateStrategy(); err != nil {
+		return nil, fmt.Errorf("failed to validate the update strategy: %v", err)
+	}
+
+	if err := m.validateMaintenancePolicy(); err != nil {
+		return nil, fmt.Errorf("failed to validate the maintenance policy: %v", err)
+	}
+
+	if err := m.validateDependencies(); err != nil {
+		return nil, fmt.Errorf("failed to validate dependencies: %v", err)
+	}
+
 	return nil, m.validateModprobe()
 }
 
@@ -115,11 +265,205 @@ func (m *Module) validateKernelMapping() error {
 		if container.ContainerImage == "" && km.ContainerImage == "" {
 			return fmt.Errorf("missing spec.moduleLoader.container.kernelMappings[%d].containerImage", idx)
 		}
+
+		if err := km.NodeMatch.validate(); err != nil {
+			return fmt.Errorf("invalid node match at kernelMappings[%d]: %v", idx, err)
+		}
 	}
 
 	return nil
 }
 
+func (nm *NodeMatch) validate() error {
+	if nm.NodeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(nm.NodeSelector); err != nil {
+			return fmt.Errorf("invalid nodeSelector: %v", err)
+		}
+	}
+
+	exprs := map[string]string{
+		"osImage":                 nm.OSImage,
+		"architecture":            nm.Architecture,
+		"containerRuntimeVersion": nm.ContainerRuntimeVersion,
+	}
+
+	for _, name := range []string{"osImage", "architecture", "containerRuntimeVersion"} {
+		if _, err := regexp.Compile(exprs[name]); err != nil {
+			return fmt.Errorf("invalid %s regexp: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateContainerImages checks the syntax of local image references (oci: and oci-archive:).
+// Local images are read from the nodes, so they cannot be built or signed in-cluster.
+func (m *Module) validateContainerImages() error {
+	container := m.Spec.ModuleLoader.Container
+
+	if err := validateContainerImage(container.ContainerImage); err != nil {
+		return fmt.Errorf("invalid spec.moduleLoader.container.containerImage: %v", err)
+	}
+
+	for idx, km := range container.KernelMappings {
+		if err := validateContainerImage(km.ContainerImage); err != nil {
+			return fmt.Errorf("invalid spec.moduleLoader.container.kernelMappings[%d].containerImage: %v", idx, err)
+		}
+
+		image := km.ContainerImage
+		if image == "" {
+			image = container.ContainerImage
+		}
+
+		if !localimage.IsLocal(image) {
+			continue
+		}
+
+		if km.Build != nil || container.Build != nil || km.Sign != nil || container.Sign != nil {
+			return fmt.Errorf("local image %s at kernelMappings[%d] cannot be built or signed", image, idx)
+		}
+	}
+
+	return nil
+}
+
+func validateContainerImage(image string) error {
+	if !localimage.IsLocal(image) {
+		return nil
+	}
+
+	_, err := localimage.Parse(image)
+
+	return err
+}
+
+func (m *Module) validatePlatforms() error {
+	container := m.Spec.ModuleLoader.Container
+
+	if container.Platform != "" {
+		if _, err := platform.Parse(container.Platform); err != nil {
+			return fmt.Errorf("invalid spec.moduleLoader.container.platform: %v", err)
+		}
+	}
+
+	for idx, km := range container.KernelMappings {
+		if km.Platform == "" {
+			continue
+		}
+
+		if _, err := platform.Parse(km.Platform); err != nil {
+			return fmt.Errorf("invalid spec.moduleLoader.container.kernelMappings[%d].platform: %v", idx, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Module) validateInTreeModules() error {
+	container := m.Spec.ModuleLoader.Container
+
+	if container.InTreeModuleToRemove != "" && len(container.InTreeModulesToRemove) > 0 {
+		return errors.New("inTreeModuleToRemove and inTreeModulesToRemove are mutually exclusive in spec.moduleLoader.container")
+	}
+
+	for idx, km := range container.KernelMappings {
+		if km.InTreeModuleToRemove != "" && len(km.InTreeModulesToRemove) > 0 {
+			return fmt.Errorf("inTreeModuleToRemove and inTreeModulesToRemove are mutually exclusive at kernelMappings[%d]", idx)
+		}
+	}
+
+	return nil
+}
+
+func (m *Module) validateTimeouts() error {
+	t := m.Spec.ModuleLoader.Container.Timeouts
+	if t == nil {
+		return nil
+	}
+
+	durations := map[string]*metav1.Duration{"pull": t.Pull, "load": t.Load, "unload": t.Unload}
+
+	for _, name := range []string{"pull", "load", "unload"} {
+		if d := durations[name]; d != nil && d.Duration < 0 {
+			return fmt.Errorf("timeouts.%s cannot be negative", name)
+		}
+	}
+
+	return nil
+}
+
+func (m *Module) validateUpdateStrategy() error {
+	us := m.Spec.UpdateStrategy
+	if us == nil || us.RollingUpdate == nil {
+		return nil
+	}
+
+	if us.Type != RollingUpdateModuleUpdateStrategyType {
+		return fmt.Errorf("rollingUpdate can only be set if type is %s", RollingUpdateModuleUpdateStrategyType)
+	}
+
+	if mu := us.RollingUpdate.MaxUnavailable; mu != nil {
+		// Scaling to 100 nodes is enough to tell a positive value from zero or a negative one.
+		v, err := intstr.GetScaledValueFromIntOrPercent(mu, 100, true)
+		if err != nil {
+			return fmt.Errorf("invalid rollingUpdate.maxUnavailable: %v", err)
+		}
+
+		if v <= 0 {
+			return errors.New("rollingUpdate.maxUnavailable must be greater than 0")
+		}
+	}
+
+	if p := us.RollingUpdate.Partition; p != nil && *p < 0 {
+		return errors.New("rollingUpdate.partition cannot be negative")
+	}
+
+	return nil
+}
+
+func (m *Module) validateMaintenancePolicy() error {
+	mp := m.Spec.MaintenancePolicy
+	if mp == nil || mp.PodSelector == nil {
+		return nil
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(mp.PodSelector); err != nil {
+		return fmt.Errorf("invalid podSelector: %v", err)
+	}
+
+	return nil
+}
+
+func (m *Module) validateDependencies() error {
+	self := m.Namespace + "/" + m.Name
+	s := sets.New[string]()
+
+	for idx, d := range m.Spec.Dependencies {
+		key := d.namespacedName(m.Namespace)
+
+		if key == self {
+			return fmt.Errorf("dependencies[%d]: a Module cannot depend on itself", idx)
+		}
+
+		if s.Has(key) {
+			return fmt.Errorf("dependencies[%d]: duplicate dependency on %s", idx, key)
+		}
+
+		s.Insert(key)
+	}
+
+	return nil
+}
+
+// namespacedName returns the namespace/name of the Module d refers to, defaulting to namespace.
+func (d ModuleDependency) namespacedName(namespace string) string {
+	if d.Namespace != "" {
+		namespace = d.Namespace
+	}
+
+	return namespace + "/" + d.Name
+}
+
 func (m *Module) validateModprobe() error {
 	modprobe := m.Spec.ModuleLoader.Container.Modprobe
 	moduleName := modprobe.ModuleName
@@ -17,12 +17,22 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestV1beta1(t *testing.T) {
@@ -200,6 +210,463 @@ var _ = Describe("validateKernelMapping", func() {
 			),
 		)
 	})
+
+	DescribeTable(
+		"should validate the node match",
+		func(nm NodeMatch, expectError bool) {
+			mod := &Module{
+				Spec: ModuleSpec{
+					ModuleLoader: ModuleLoaderSpec{
+						Container: ModuleLoaderContainerSpec{
+							KernelMappings: []KernelMapping{
+								{Literal: "any-value", ContainerImage: "image-url", NodeMatch: nm},
+							},
+						},
+					},
+				},
+			}
+
+			err := mod.validateKernelMapping()
+
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("empty", NodeMatch{}, false),
+		Entry(
+			"valid",
+			NodeMatch{
+				NodeSelector:            &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "a100"}},
+				OSImage:                 "^Ubuntu 22\\.04",
+				Architecture:            "amd64|arm64",
+				ContainerRuntimeVersion: "^cri-o://",
+			},
+			false,
+		),
+		Entry(
+			"invalid nodeSelector",
+			NodeMatch{
+				NodeSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "gpu", Operator: "Invalid"}},
+				},
+			},
+			true,
+		),
+		Entry("invalid osImage", NodeMatch{OSImage: "invalid)"}, true),
+		Entry("invalid architecture", NodeMatch{Architecture: "invalid)"}, true),
+		Entry("invalid containerRuntimeVersion", NodeMatch{ContainerRuntimeVersion: "invalid)"}, true),
+	)
+})
+
+var _ = Describe("validateContainerImages", func() {
+	DescribeTable(
+		"should work as expected",
+		func(container ModuleLoaderContainerSpec, expectError bool) {
+			mod := &Module{
+				Spec: ModuleSpec{
+					ModuleLoader: ModuleLoaderSpec{Container: container},
+				},
+			}
+
+			err := mod.validateContainerImages()
+
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry(
+			"registry images",
+			ModuleLoaderContainerSpec{
+				ContainerImage: "quay.io/org/image:tag",
+				Build:          &Build{},
+				KernelMappings: []KernelMapping{{Literal: "any-value"}},
+			},
+			false,
+		),
+		Entry(
+			"valid local images",
+			ModuleLoaderContainerSpec{
+				ContainerImage: "oci:/some/layout:tag",
+				KernelMappings: []KernelMapping{
+					{Literal: "any-value"},
+					{Literal: "other-value", ContainerImage: "oci-archive:/some/image.tar"},
+				},
+			},
+			false,
+		),
+		Entry(
+			"invalid top-level local image",
+			ModuleLoaderContainerSpec{ContainerImage: "oci:relative/path"},
+			true,
+		),
+		Entry(
+			"invalid local image in a kernel mapping",
+			ModuleLoaderContainerSpec{
+				KernelMappings: []KernelMapping{{Literal: "any-value", ContainerImage: "oci-archive:"}},
+			},
+			true,
+		),
+		Entry(
+			"local image with a build",
+			ModuleLoaderContainerSpec{
+				ContainerImage: "oci:/some/layout",
+				KernelMappings: []KernelMapping{{Literal: "any-value", Build: &Build{}}},
+			},
+			true,
+		),
+		Entry(
+			"local image with a top-level sign",
+			ModuleLoaderContainerSpec{
+				Sign:           &Sign{},
+				KernelMappings: []KernelMapping{{Literal: "any-value", ContainerImage: "oci:/some/layout"}},
+			},
+			true,
+		),
+	)
+})
+
+var _ = Describe("validatePlatforms", func() {
+	DescribeTable(
+		"should work as expected",
+		func(container ModuleLoaderContainerSpec, expectError bool) {
+			mod := &Module{
+				Spec: ModuleSpec{
+					ModuleLoader: ModuleLoaderSpec{Container: container},
+				},
+			}
+
+			err := mod.validatePlatforms()
+
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry(
+			"no platform",
+			ModuleLoaderContainerSpec{KernelMappings: []KernelMapping{{Literal: "any-value"}}},
+			false,
+		),
+		Entry(
+			"valid platforms",
+			ModuleLoaderContainerSpec{
+				Platform:       "linux/amd64",
+				KernelMappings: []KernelMapping{{Literal: "any-value", Platform: "linux/arm/v7"}},
+			},
+			false,
+		),
+		Entry(
+			"invalid top-level platform",
+			ModuleLoaderContainerSpec{Platform: "linux"},
+			true,
+		),
+		Entry(
+			"invalid platform in a kernel mapping",
+			ModuleLoaderContainerSpec{
+				KernelMappings: []KernelMapping{{Literal: "any-value", Platform: "linux/arm/v7/extra"}},
+			},
+			true,
+		),
+	)
+})
+
+var _ = Describe("validateInTreeModules", func() {
+	DescribeTable(
+		"should work as expected",
+		func(container ModuleLoaderContainerSpec, expectError bool) {
+			mod := &Module{
+				Spec: ModuleSpec{
+					ModuleLoader: ModuleLoaderSpec{Container: container},
+				},
+			}
+
+			err := mod.validateInTreeModules()
+
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry(
+			"one field at each level",
+			ModuleLoaderContainerSpec{
+				InTreeModuleToRemove: "a",
+				KernelMappings:       []KernelMapping{{Literal: "any-value", InTreeModulesToRemove: []string{"b", "c"}}},
+			},
+			false,
+		),
+		Entry(
+			"both fields in the container",
+			ModuleLoaderContainerSpec{InTreeModuleToRemove: "a", InTreeModulesToRemove: []string{"b"}},
+			true,
+		),
+		Entry(
+			"both fields in a kernel mapping",
+			ModuleLoaderContainerSpec{
+				KernelMappings: []KernelMapping{
+					{Literal: "any-value", InTreeModuleToRemove: "a", InTreeModulesToRemove: []string{"b"}},
+				},
+			},
+			true,
+		),
+	)
+})
+
+var _ = Describe("validateTimeouts", func() {
+	DescribeTable(
+		"should work as expected",
+		func(timeouts *ModuleTimeouts, expectError bool) {
+			mod := &Module{
+				Spec: ModuleSpec{
+					ModuleLoader: ModuleLoaderSpec{
+						Container: ModuleLoaderContainerSpec{Timeouts: timeouts},
+					},
+				},
+			}
+
+			err := mod.validateTimeouts()
+
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("no timeouts", nil, false),
+		Entry("disabled timeout", &ModuleTimeouts{Pull: &metav1.Duration{}}, false),
+		Entry("positive timeouts", &ModuleTimeouts{Load: &metav1.Duration{Duration: time.Minute}}, false),
+		Entry("negative timeout", &ModuleTimeouts{Unload: &metav1.Duration{Duration: -time.Minute}}, true),
+	)
+})
+
+var _ = Describe("validateUpdateStrategy", func() {
+	DescribeTable(
+		"should work as expected",
+		func(us *ModuleUpdateStrategy, expectError bool) {
+			mod := &Module{
+				Spec: ModuleSpec{UpdateStrategy: us},
+			}
+
+			err := mod.validateUpdateStrategy()
+
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("no strategy", nil, false),
+		Entry("AllAtOnce", &ModuleUpdateStrategy{Type: AllAtOnceModuleUpdateStrategyType}, false),
+		Entry("RollingUpdate without parameters", &ModuleUpdateStrategy{Type: RollingUpdateModuleUpdateStrategyType}, false),
+		Entry(
+			"rollingUpdate with AllAtOnce",
+			&ModuleUpdateStrategy{Type: AllAtOnceModuleUpdateStrategyType, RollingUpdate: &RollingUpdateModule{}},
+			true,
+		),
+		Entry(
+			"count",
+			&ModuleUpdateStrategy{
+				Type:          RollingUpdateModuleUpdateStrategyType,
+				RollingUpdate: &RollingUpdateModule{MaxUnavailable: ptr.To(intstr.FromInt(2))},
+			},
+			false,
+		),
+		Entry(
+			"percentage",
+			&ModuleUpdateStrategy{
+				Type:          RollingUpdateModuleUpdateStrategyType,
+				RollingUpdate: &RollingUpdateModule{MaxUnavailable: ptr.To(intstr.FromString("25%"))},
+			},
+			false,
+		),
+		Entry(
+			"zero",
+			&ModuleUpdateStrategy{
+				Type:          RollingUpdateModuleUpdateStrategyType,
+				RollingUpdate: &RollingUpdateModule{MaxUnavailable: ptr.To(intstr.FromInt(0))},
+			},
+			true,
+		),
+		Entry(
+			"invalid percentage",
+			&ModuleUpdateStrategy{
+				Type:          RollingUpdateModuleUpdateStrategyType,
+				RollingUpdate: &RollingUpdateModule{MaxUnavailable: ptr.To(intstr.FromString("a lot"))},
+			},
+			true,
+		),
+		Entry(
+			"negative partition",
+			&ModuleUpdateStrategy{
+				Type:          RollingUpdateModuleUpdateStrategyType,
+				RollingUpdate: &RollingUpdateModule{Partition: ptr.To[int32](-1)},
+			},
+			true,
+		),
+	)
+})
+
+var _ = Describe("validateMaintenancePolicy", func() {
+	DescribeTable(
+		"should work as expected",
+		func(mp *MaintenancePolicy, expectError bool) {
+			mod := &Module{
+				Spec: ModuleSpec{MaintenancePolicy: mp},
+			}
+
+			err := mod.validateMaintenancePolicy()
+
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("no policy", nil, false),
+		Entry("resources only", &MaintenancePolicy{ResourceNames: []v1.ResourceName{"example.com/gpu"}}, false),
+		Entry(
+			"valid podSelector",
+			&MaintenancePolicy{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gpu"}}},
+			false,
+		),
+		Entry(
+			"invalid podSelector",
+			&MaintenancePolicy{
+				PodSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Invalid"}},
+				},
+			},
+			true,
+		),
+	)
+})
+
+var _ = Describe("validateDependencies", func() {
+	DescribeTable(
+		"should work as expected",
+		func(deps []ModuleDependency, expectError bool) {
+			mod := &Module{
+				ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "ns"},
+				Spec:       ModuleSpec{Dependencies: deps},
+			}
+
+			err := mod.validateDependencies()
+
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("no dependencies", nil, false),
+		Entry("valid dependencies", []ModuleDependency{{Name: "a"}, {Name: "a", Namespace: "other-ns"}}, false),
+		Entry("self-dependency", []ModuleDependency{{Name: "name"}}, true),
+		Entry("self-dependency with explicit namespace", []ModuleDependency{{Name: "name", Namespace: "ns"}}, true),
+		Entry("duplicate", []ModuleDependency{{Name: "a"}, {Name: "a", Namespace: "ns"}}, true),
+	)
+})
+
+var _ = Describe("DependencyCycle", func() {
+	module := func(name string, deps ...ModuleDependency) Module {
+		return Module{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec:       ModuleSpec{Dependencies: deps},
+		}
+	}
+
+	It("should return nil if there is no cycle", func() {
+		m := module("a", ModuleDependency{Name: "b"}, ModuleDependency{Name: "c"})
+		existing := []Module{
+			module("b", ModuleDependency{Name: "c"}),
+			module("c"),
+			module("d", ModuleDependency{Name: "a"}),
+		}
+
+		Expect(DependencyCycle(&m, existing)).To(BeNil())
+	})
+
+	It("should return the cycle through the Module", func() {
+		m := module("a", ModuleDependency{Name: "b"})
+		existing := []Module{
+			module("b", ModuleDependency{Name: "c", Namespace: "ns"}),
+			module("c", ModuleDependency{Name: "a"}),
+		}
+
+		Expect(
+			DependencyCycle(&m, existing),
+		).To(
+			Equal([]string{"ns/a", "ns/b", "ns/c", "ns/a"}),
+		)
+	})
+
+	It("should use the new version of the Module", func() {
+		m := module("a")
+		existing := []Module{
+			module("a", ModuleDependency{Name: "b"}),
+			module("b", ModuleDependency{Name: "a"}),
+		}
+
+		Expect(DependencyCycle(&m, existing)).To(BeNil())
+	})
+})
+
+var _ = Describe("moduleValidator", func() {
+	var (
+		ctx = context.TODO()
+
+		clnt *client.MockClient
+		v    *moduleValidator
+	)
+
+	BeforeEach(func() {
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		v = &moduleValidator{client: clnt}
+	})
+
+	It("should not list Modules if there are no dependencies", func() {
+		mod := validModule
+
+		_, err := v.ValidateCreate(ctx, &mod)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a dependency cycle", func() {
+		mod := validModule
+		mod.Name = "a"
+		mod.Namespace = "ns"
+		mod.Spec.Dependencies = []ModuleDependency{{Name: "b"}}
+
+		clnt.
+			EXPECT().
+			List(ctx, &ModuleList{}).
+			Do(func(_ context.Context, list *ModuleList, _ ...ctrlclient.ListOption) {
+				list.Items = []Module{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns"},
+						Spec:       ModuleSpec{Dependencies: []ModuleDependency{{Name: "a"}}},
+					},
+				}
+			})
+
+		_, err := v.ValidateUpdate(ctx, &mod, &mod)
+		Expect(err).To(MatchError("dependency cycle: ns/a -> ns/b -> ns/a"))
+	})
+
+	It("should return an error if Modules could not be listed", func() {
+		mod := validModule
+		mod.Spec.Dependencies = []ModuleDependency{{Name: "b"}}
+
+		clnt.EXPECT().List(ctx, &ModuleList{}).Return(errors.New("random error"))
+
+		_, err := v.ValidateCreate(ctx, &mod)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("validateModprobe", func() {
@@ -26,9 +26,20 @@ type ModuleConfig struct {
 	ContainerImage string `json:"containerImage"`
 	// When InsecurePull is true, the container image can be pulled without TLS.
 	InsecurePull bool `json:"insecurePull"`
+	// Deprecated: use InTreeModulesToRemove instead.
 	//+optional
-	InTreeModuleToRemove string       `json:"inTreeModuleToRemove,omitempty"`
-	Modprobe             ModprobeSpec `json:"modprobe"`
+	InTreeModuleToRemove string `json:"inTreeModuleToRemove,omitempty"`
+	// InTreeModulesToRemove are unloaded before the module is loaded, and blacklisted until it is unloaded.
+	//+optional
+	InTreeModulesToRemove []string     `json:"inTreeModulesToRemove,omitempty"`
+	Modprobe              ModprobeSpec `json:"modprobe"`
+	// Platform selects the image to unpack in multi-architecture images.
+	// If empty, the worker selects the image matching the node's platform.
+	//+optional
+	Platform string `json:"platform,omitempty"`
+	// ImageVerification makes the worker verify the signature of the container image before unpacking it.
+	//+optional
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
 }
 
 type ModuleItem struct {
@@ -36,6 +47,17 @@ type ModuleItem struct {
 	Name               string                   `json:"name"`
 	Namespace          string                   `json:"namespace"`
 	ServiceAccountName string                   `json:"serviceAccountName"`
+	// Timeouts are set on worker Pods; they are not part of the config, so that changing them does not reload the
+	// module.
+	//+optional
+	Timeouts *ModuleTimeouts `json:"timeouts,omitempty"`
+	// MaintenancePolicy is applied to the node before the module is unloaded or reloaded.
+	//+optional
+	MaintenancePolicy *MaintenancePolicy `json:"maintenancePolicy,omitempty"`
+	// Dependencies are the modules that must be loaded on the node before this one, and unloaded after it.
+	// Their namespace is always set.
+	//+optional
+	Dependencies []ModuleDependency `json:"dependencies,omitempty"`
 }
 
 type NodeModuleSpec struct {
@@ -53,11 +75,68 @@ type NodeModulesConfigSpec struct {
 	Modules []NodeModuleSpec `json:"modules,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
+const (
+	// NodeModuleConditionLoaded is True if the last worker Pod that loaded the module succeeded, and False if it
+	// failed.
+	NodeModuleConditionLoaded = "Loaded"
+	// NodeModuleConditionUnloaded is False if the last worker Pod that unloaded the module failed.
+	NodeModuleConditionUnloaded = "Unloaded"
+	// NodeModuleConditionFailed is True if KMM stopped trying to load the module on the node after too many failed
+	// attempts.
+	// It is ignored once the module's config changes.
+	NodeModuleConditionFailed = "Failed"
+	// NodeModuleConditionParametersUpdated is True if the last worker Pod that updated the module's parameters
+	// without reloading it succeeded, and False if it failed.
+	NodeModuleConditionParametersUpdated = "ParametersUpdated"
+	// NodeModuleConditionVerified is True if the last worker Pod that checked that the module was still loaded
+	// succeeded.
+	// It is False with the ModuleNotLoaded reason if the module drifted from its status, for instance because it was
+	// unloaded by hand, and False with another reason if the check itself failed.
+	NodeModuleConditionVerified = "Verified"
+	// NodeModuleConditionDrained reports whether the Pods selected by the module's maintenance policy were evicted
+	// from the node before the module is unloaded.
+	NodeModuleConditionDrained = "Drained"
+
+	// NodeModulesConfigConditionCordoned is True while KMM keeps the node cordoned for the maintenance of modules.
+	NodeModulesConfigConditionCordoned = "Cordoned"
+)
+
 type NodeModuleStatus struct {
 	ModuleItem `json:",inline"`
 
+	// BootID is the boot ID of the node when the module was last loaded.
+	// The module is loaded again when the node's boot ID changes.
+	// If empty, the module is loaded again when the node's Ready condition changed after LastTransitionTime.
 	//+optional
-	Config ModuleConfig `json:"config,omitempty"`
+	BootID string `json:"bootID,omitempty"`
+	// Config is the configuration with which the module was last loaded.
+	// It is nil until a worker Pod loads the module, so that the entry can report the Conditions of a module that
+	// failed to load.
+	//+optional
+	Config *ModuleConfig `json:"config,omitempty"`
+	// Conditions report the outcome of the last worker Pods for this module.
+	// Failures carry the reason and the message reported by the worker.
+	// +listType=map
+	// +listMapKey=type
+	//+optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// FailedAttempts is the number of consecutive failed attempts at loading the config identified by
+	// FailedConfigHash.
+	//+optional
+	FailedAttempts int32 `json:"failedAttempts,omitempty"`
+	// FailedConfigHash identifies the config that could not be loaded.
+	//+optional
+	FailedConfigHash string `json:"failedConfigHash,omitempty"`
+	// FailedParametersConfigHash identifies the config whose parameters could not be updated without reloading the
+	// module; that config is applied by reloading the module instead.
+	//+optional
+	FailedParametersConfigHash string `json:"failedParametersConfigHash,omitempty"`
+	// NextRetryTime is the earliest time at which KMM will try loading the module again after a failure.
+	//+optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+	// LastVerifiedTime is the last time at which a worker Pod checked that the module was still loaded.
+	//+optional
+	LastVerifiedTime *metav1.Time `json:"lastVerifiedTime,omitempty"`
 	//+optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
@@ -71,6 +150,11 @@ type NodeModulesConfigStatus struct {
 	// +patchStrategy=merge
 	// +optional
 	Modules []NodeModuleStatus `json:"modules,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// Conditions report node-level operations performed by KMM, such as cordoning the node.
+	// +listType=map
+	// +listMapKey=type
+	//+optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
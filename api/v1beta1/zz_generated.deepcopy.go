@@ -22,7 +22,9 @@ package v1beta1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -177,6 +179,42 @@ func (in *DevicePluginSpec) DeepCopy() *DevicePluginSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailedNodeList) DeepCopyInto(out *FailedNodeList) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeFailure, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedNodeList.
+func (in *FailedNodeList) DeepCopy() *FailedNodeList {
+	if in == nil {
+		return nil
+	}
+	out := new(FailedNodeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
+	*out = *in
+	out.KeysSecret = in.KeysSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerification.
+func (in *ImageVerification) DeepCopy() *ImageVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KanikoParams) DeepCopyInto(out *KanikoParams) {
 	*out = *in
@@ -210,6 +248,12 @@ func (in *KernelMapping) DeepCopyInto(out *KernelMapping) {
 		*out = new(TLSOptions)
 		**out = **in
 	}
+	if in.InTreeModulesToRemove != nil {
+		in, out := &in.InTreeModulesToRemove, &out.InTreeModulesToRemove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.NodeMatch.DeepCopyInto(&out.NodeMatch)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelMapping.
@@ -222,6 +266,31 @@ func (in *KernelMapping) DeepCopy() *KernelMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenancePolicy) DeepCopyInto(out *MaintenancePolicy) {
+	*out = *in
+	if in.ResourceNames != nil {
+		in, out := &in.ResourceNames, &out.ResourceNames
+		*out = make([]v1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenancePolicy.
+func (in *MaintenancePolicy) DeepCopy() *MaintenancePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenancePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModprobeArgs) DeepCopyInto(out *ModprobeArgs) {
 	*out = *in
@@ -247,6 +316,66 @@ func (in *ModprobeArgs) DeepCopy() *ModprobeArgs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModprobeHook) DeepCopyInto(out *ModprobeHook) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModprobeHook.
+func (in *ModprobeHook) DeepCopy() *ModprobeHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ModprobeHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModprobeHooks) DeepCopyInto(out *ModprobeHooks) {
+	*out = *in
+	if in.PreLoad != nil {
+		in, out := &in.PreLoad, &out.PreLoad
+		*out = new(ModprobeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostLoad != nil {
+		in, out := &in.PostLoad, &out.PostLoad
+		*out = new(ModprobeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreUnload != nil {
+		in, out := &in.PreUnload, &out.PreUnload
+		*out = new(ModprobeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostUnload != nil {
+		in, out := &in.PostUnload, &out.PostUnload
+		*out = new(ModprobeHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModprobeHooks.
+func (in *ModprobeHooks) DeepCopy() *ModprobeHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(ModprobeHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModprobeSpec) DeepCopyInto(out *ModprobeSpec) {
 	*out = *in
@@ -270,6 +399,11 @@ func (in *ModprobeSpec) DeepCopyInto(out *ModprobeSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(ModprobeHooks)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModprobeSpec.
@@ -288,7 +422,7 @@ func (in *Module) DeepCopyInto(out *Module) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Module.
@@ -312,7 +446,17 @@ func (in *Module) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleConfig) DeepCopyInto(out *ModuleConfig) {
 	*out = *in
+	if in.InTreeModulesToRemove != nil {
+		in, out := &in.InTreeModulesToRemove, &out.InTreeModulesToRemove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Modprobe.DeepCopyInto(&out.Modprobe)
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerification)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleConfig.
@@ -325,6 +469,21 @@ func (in *ModuleConfig) DeepCopy() *ModuleConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleDependency) DeepCopyInto(out *ModuleDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleDependency.
+func (in *ModuleDependency) DeepCopy() *ModuleDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleItem) DeepCopyInto(out *ModuleItem) {
 	*out = *in
@@ -333,6 +492,21 @@ func (in *ModuleItem) DeepCopyInto(out *ModuleItem) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(ModuleTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenancePolicy != nil {
+		in, out := &in.MaintenancePolicy, &out.MaintenancePolicy
+		*out = new(MaintenancePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]ModuleDependency, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleItem.
@@ -399,6 +573,21 @@ func (in *ModuleLoaderContainerSpec) DeepCopyInto(out *ModuleLoaderContainerSpec
 	}
 	in.Modprobe.DeepCopyInto(&out.Modprobe)
 	out.RegistryTLS = in.RegistryTLS
+	if in.InTreeModulesToRemove != nil {
+		in, out := &in.InTreeModulesToRemove, &out.InTreeModulesToRemove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerification)
+		**out = **in
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(ModuleTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleLoaderContainerSpec.
@@ -427,9 +616,35 @@ func (in *ModuleLoaderSpec) DeepCopy() *ModuleLoaderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleNodesStatus) DeepCopyInto(out *ModuleNodesStatus) {
+	*out = *in
+	in.NoMatchingKernelMapping.DeepCopyInto(&out.NoMatchingKernelMapping)
+	in.WaitingForImage.DeepCopyInto(&out.WaitingForImage)
+	in.Failed.DeepCopyInto(&out.Failed)
+	in.HeldBack.DeepCopyInto(&out.HeldBack)
+	in.MissingDependencies.DeepCopyInto(&out.MissingDependencies)
+	in.Progressing.DeepCopyInto(&out.Progressing)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleNodesStatus.
+func (in *ModuleNodesStatus) DeepCopy() *ModuleNodesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleNodesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleSpec) DeepCopyInto(out *ModuleSpec) {
 	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]ModuleDependency, len(*in))
+		copy(*out, *in)
+	}
 	if in.DevicePlugin != nil {
 		in, out := &in.DevicePlugin, &out.DevicePlugin
 		*out = new(DevicePluginSpec)
@@ -441,6 +656,11 @@ func (in *ModuleSpec) DeepCopyInto(out *ModuleSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.MaintenancePolicy != nil {
+		in, out := &in.MaintenancePolicy, &out.MaintenancePolicy
+		*out = new(MaintenancePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
 		*out = make(map[string]string, len(*in))
@@ -448,6 +668,11 @@ func (in *ModuleSpec) DeepCopyInto(out *ModuleSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(ModuleUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleSpec.
@@ -465,6 +690,14 @@ func (in *ModuleStatus) DeepCopyInto(out *ModuleStatus) {
 	*out = *in
 	out.DevicePlugin = in.DevicePlugin
 	out.ModuleLoader = in.ModuleLoader
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Nodes.DeepCopyInto(&out.Nodes)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleStatus.
@@ -477,6 +710,111 @@ func (in *ModuleStatus) DeepCopy() *ModuleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleTimeouts) DeepCopyInto(out *ModuleTimeouts) {
+	*out = *in
+	if in.Pull != nil {
+		in, out := &in.Pull, &out.Pull
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Load != nil {
+		in, out := &in.Load, &out.Load
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Unload != nil {
+		in, out := &in.Unload, &out.Unload
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleTimeouts.
+func (in *ModuleTimeouts) DeepCopy() *ModuleTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleUpdateStrategy) DeepCopyInto(out *ModuleUpdateStrategy) {
+	*out = *in
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdateModule)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleUpdateStrategy.
+func (in *ModuleUpdateStrategy) DeepCopy() *ModuleUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeFailure) DeepCopyInto(out *NodeFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFailure.
+func (in *NodeFailure) DeepCopy() *NodeFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeList) DeepCopyInto(out *NodeList) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeList.
+func (in *NodeList) DeepCopy() *NodeList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeMatch) DeepCopyInto(out *NodeMatch) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMatch.
+func (in *NodeMatch) DeepCopy() *NodeMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeModuleSpec) DeepCopyInto(out *NodeModuleSpec) {
 	*out = *in
@@ -498,7 +836,26 @@ func (in *NodeModuleSpec) DeepCopy() *NodeModuleSpec {
 func (in *NodeModuleStatus) DeepCopyInto(out *NodeModuleStatus) {
 	*out = *in
 	in.ModuleItem.DeepCopyInto(&out.ModuleItem)
-	in.Config.DeepCopyInto(&out.Config)
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(ModuleConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastVerifiedTime != nil {
+		in, out := &in.LastVerifiedTime, &out.LastVerifiedTime
+		*out = (*in).DeepCopy()
+	}
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
@@ -603,6 +960,13 @@ func (in *NodeModulesConfigStatus) DeepCopyInto(out *NodeModulesConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModulesConfigStatus.
@@ -720,6 +1084,31 @@ func (in *PreflightValidationStatus) DeepCopy() *PreflightValidationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpdateModule) DeepCopyInto(out *RollingUpdateModule) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateModule.
+func (in *RollingUpdateModule) DeepCopy() *RollingUpdateModule {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpdateModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sign) DeepCopyInto(out *Sign) {
 	*out = *in
@@ -111,7 +111,7 @@ func main() {
 	metricsAPI := metrics.New()
 	metricsAPI.Register()
 
-	registryAPI := registry.NewRegistry()
+	registryAPI := registry.NewRegistry(cfg.RegistryMirrors)
 	podHelperAPI := utils.NewPodHelper(client)
 	buildHelper := build.NewHelper()
 
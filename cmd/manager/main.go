@@ -115,7 +115,7 @@ func main() {
 	metricsAPI := metrics.New()
 	metricsAPI.Register()
 
-	registryAPI := registry.NewRegistry()
+	registryAPI := registry.NewRegistry(cfg.RegistryMirrors)
 	podHelperAPI := utils.NewPodHelper(client)
 	buildHelperAPI := build.NewHelper()
 
@@ -170,7 +170,7 @@ func main() {
 
 	eventRecorder := mgr.GetEventRecorderFor("kmm")
 
-	if err = controllers.NewNMCReconciler(client, scheme, workerImage, &cfg.Worker, eventRecorder).SetupWithManager(ctx, mgr); err != nil {
+	if err = controllers.NewNMCReconciler(client, scheme, workerImage, &cfg.Worker, cfg.RegistryMirrors, eventRecorder).SetupWithManager(ctx, mgr); err != nil {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.NodeModulesConfigReconcilerName)
 	}
 
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func cacheFuncPreRunE(cmd *cobra.Command, args []string) error {
+	err := cmd.Parent().PersistentPreRunE(cmd.Parent(), args)
+	if err != nil {
+		return fmt.Errorf("failed to call root command pre-run: %v", err)
+	}
+
+	imageCache = worker.NewImageCache(worker.ImagesDir, logger)
+
+	return nil
+}
+
+func cacheGCFunc(cmd *cobra.Command, _ []string) error {
+	return garbageCollect(cmd)
+}
+
+func garbageCollect(cmd *cobra.Command) error {
+	keepImages, err := cmd.Flags().GetStringArray(worker.FlagKeepImage)
+	if err != nil {
+		return fmt.Errorf("could not get the %s flag: %v", worker.FlagKeepImage, err)
+	}
+
+	maxSizeStr, err := cmd.Flags().GetString(worker.FlagMaxSize)
+	if err != nil {
+		return fmt.Errorf("could not get the %s flag: %v", worker.FlagMaxSize, err)
+	}
+
+	maxSize, err := resource.ParseQuantity(maxSizeStr)
+	if err != nil {
+		return fmt.Errorf("invalid value %q for %s: %v", maxSizeStr, worker.FlagMaxSize, err)
+	}
+
+	logger.Info("Garbage collecting the image cache", "max size", maxSize.String(), "keep images", keepImages)
+
+	return imageCache.GarbageCollect(keepImages, maxSize.Value())
+}
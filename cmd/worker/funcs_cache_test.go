@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/go-logr/logr"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("garbageCollect", func() {
+	var (
+		cmd *cobra.Command
+		mic *worker.MockImageCache
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		mic = worker.NewMockImageCache(ctrl)
+		imageCache = mic
+		logger = logr.Discard()
+
+		cmd = &cobra.Command{}
+		addGarbageCollectionFlags(cmd)
+	})
+
+	AfterEach(func() {
+		imageCache = nil
+	})
+
+	It("should remove all unused images by default", func() {
+		mic.EXPECT().GarbageCollect([]string{}, int64(0))
+
+		Expect(garbageCollect(cmd)).To(Succeed())
+	})
+
+	It("should pass the flags to the cache", func() {
+		Expect(cmd.Flags().Set(worker.FlagKeepImage, "image-a")).To(Succeed())
+		Expect(cmd.Flags().Set(worker.FlagKeepImage, "image-b")).To(Succeed())
+		Expect(cmd.Flags().Set(worker.FlagMaxSize, "1Ki")).To(Succeed())
+
+		mic.EXPECT().GarbageCollect([]string{"image-a", "image-b"}, int64(1024)).Return(errors.New("random error"))
+
+		Expect(garbageCollect(cmd)).To(MatchError("random error"))
+	})
+
+	It("should return an error if the maximum size is invalid", func() {
+		Expect(cmd.Flags().Set(worker.FlagMaxSize, "invalid")).To(Succeed())
+
+		Expect(garbageCollect(cmd)).To(HaveOccurred())
+	})
+})
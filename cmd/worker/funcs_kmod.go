@@ -1,8 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
 
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mirror"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
 	"github.com/spf13/cobra"
 )
@@ -19,13 +26,112 @@ func kmodFuncPreRunE(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("could not read pull secrets: %v", err)
 	}
 
-	ip := worker.NewRemoteImageMounter(worker.ImagesDir, keyChain, logger)
-	mr := worker.NewModprobeRunner(logger)
-	w = worker.NewWorker(ip, mr, logger)
+	mirrors, err := readRegistryMirrors(cmd)
+	if err != nil {
+		return err
+	}
+
+	imageCache = worker.NewImageCache(worker.ImagesDir, logger)
+
+	ip := worker.NewImageMounterSelector(
+		worker.NewLocalImageMounter(imageCache, logger),
+		worker.NewRemoteImageMounter(
+			imageCache,
+			keyChain,
+			worker.NewSignatureVerifier(worker.VerificationKeysDir, logger),
+			mirrors,
+			logger,
+		),
+	)
+
+	mr, err := newModprobeRunner(cmd)
+	if err != nil {
+		return err
+	}
+
+	mv := worker.NewModuleVerifier(worker.SysModuleDir, logger)
+
+	// Only defined for kmod unload.
+	listHolders, _ := cmd.Flags().GetBool(worker.FlagListHolders)
+	uc := worker.NewUsageChecker(worker.SysModuleDir, worker.ProcDir, listHolders, logger)
+
+	// Only defined for kmod load and kmod unload.
+	timeouts := worker.Timeouts{}
+	timeouts.Pull, _ = cmd.Flags().GetDuration(worker.FlagPullTimeout)
+	timeouts.Load, _ = cmd.Flags().GetDuration(worker.FlagLoadTimeout)
+	timeouts.Unload, _ = cmd.Flags().GetDuration(worker.FlagUnloadTimeout)
+	// Only defined for kmod unload.
+	timeouts.InUse, _ = cmd.Flags().GetDuration(worker.FlagInUseTimeout)
+	timeouts.InUsePollInterval, _ = cmd.Flags().GetDuration(worker.FlagInUsePollInterval)
+
+	name := os.Getenv(worker.EnvModuleName)
+	namespace := os.Getenv(worker.EnvModuleNamespace)
+
+	w = worker.NewWorker(
+		ip,
+		mr,
+		mv,
+		uc,
+		newFirmwareManager(namespace, name),
+		worker.NewDepmod(logger),
+		worker.NewHookRunner(logger),
+		worker.NewBlacklistManager(worker.BlacklistDir, namespace, name, logger),
+		worker.NewParameterSetter(worker.SysModuleDir, logger),
+		timeouts,
+		logger,
+	)
 
 	return nil
 }
 
+// newFirmwareManager returns a FirmwareManager for the Module set in the environment by the operator.
+func newFirmwareManager(namespace, name string) worker.FirmwareManager {
+	var owner string
+
+	if name != "" && namespace != "" {
+		owner = namespace + "/" + name
+	}
+
+	return worker.NewFirmwareManager(owner, logger)
+}
+
+// readRegistryMirrors returns the registry mirrors in the file passed by the operator, if any.
+func readRegistryMirrors(cmd *cobra.Command) ([]mirror.Registry, error) {
+	path, err := cmd.Flags().GetString(worker.FlagRegistryMirrors)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the %s flag: %v", worker.FlagRegistryMirrors, err)
+	}
+
+	if path == "" {
+		return nil, nil
+	}
+
+	logger.V(1).Info("Reading registry mirrors", "path", path)
+
+	mirrors, err := mirror.ParseFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the registry mirrors: %v", err)
+	}
+
+	return mirrors, nil
+}
+
+func newModprobeRunner(cmd *cobra.Command) (worker.ModprobeRunner, error) {
+	impl, err := cmd.Flags().GetString(worker.FlagModprobeRunner)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the %s flag: %v", worker.FlagModprobeRunner, err)
+	}
+
+	switch impl {
+	case worker.ModprobeRunnerBinary:
+		return worker.NewModprobeRunner(logger), nil
+	case worker.ModprobeRunnerNative:
+		return worker.NewNativeModprobeRunner(logger), nil
+	default:
+		return nil, fmt.Errorf("invalid value %q for %s", impl, worker.FlagModprobeRunner)
+	}
+}
+
 func kmodLoadFunc(cmd *cobra.Command, args []string) error {
 	cfgPath := args[0]
 
@@ -61,5 +167,225 @@ func kmodUnloadFunc(cmd *cobra.Command, args []string) error {
 
 	mountPathFlag := cmd.Flags().Lookup(worker.FlagFirmwareMountPath)
 
-	return w.UnloadKmod(cmd.Context(), cfg, mountPathFlag.Value.String())
+	if err = w.UnloadKmod(cmd.Context(), cfg, mountPathFlag.Value.String()); err != nil {
+		return err
+	}
+
+	if gc, _ := cmd.Flags().GetBool(worker.FlagGarbageCollect); gc {
+		// The image is not used anymore; release it so that it can be collected.
+		imageCache.Unmount()
+
+		// The module is unloaded; failing to clean the cache up should not fail the unload.
+		if err = garbageCollect(cmd); err != nil {
+			logger.Error(err, "Could not garbage collect the image cache")
+		}
+	}
+
+	return nil
+}
+
+func kmodSetParametersFunc(cmd *cobra.Command, args []string) error {
+	cfgPath := args[0]
+
+	logger.V(1).Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+	}
+
+	names, err := cmd.Flags().GetStringArray(worker.FlagParameter)
+	if err != nil {
+		return fmt.Errorf("could not get the %s flag: %v", worker.FlagParameter, err)
+	}
+
+	return w.SetParameters(cfg, names)
+}
+
+func kmodVerifyFunc(_ *cobra.Command, args []string) error {
+	cfgPath := args[0]
+
+	logger.V(1).Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+	}
+
+	return w.VerifyKmod(cfg)
+}
+
+func kmodVerifyFirmwareFunc(cmd *cobra.Command, _ []string) error {
+	mountPathFlag := cmd.Flags().Lookup(worker.FlagFirmwareMountPath)
+
+	return w.VerifyFirmware(mountPathFlag.Value.String())
+}
+
+func kmodRenderFunc(cmd *cobra.Command, args []string) error {
+	cfgPath := args[0]
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+	}
+
+	action, _ := cmd.Flags().GetString(worker.FlagAction)
+	mountPath, _ := cmd.Flags().GetString(worker.FlagFirmwareMountPath)
+
+	plan, err := w.Render(cmd.Context(), cfg, worker.Action(action), mountPath)
+	if err != nil {
+		return fmt.Errorf("could not render the %s actions: %v", action, err)
+	}
+
+	return writeOutput(cmd, plan, func(out io.Writer) { writePlan(out, plan) })
+}
+
+func kmodStatusFunc(cmd *cobra.Command, args []string) error {
+	cfgPath := args[0]
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+	}
+
+	mountPath, _ := cmd.Flags().GetString(worker.FlagFirmwareMountPath)
+
+	status, err := w.Status(cmd.Context(), cfg, mountPath)
+	if err != nil {
+		return fmt.Errorf("could not get the status: %v", err)
+	}
+
+	return writeOutput(cmd, status, func(out io.Writer) { writeStatus(out, status) })
+}
+
+// writeOutput writes v as JSON or with writeText, depending on the output flag.
+func writeOutput(cmd *cobra.Command, v any, writeText func(io.Writer)) error {
+	output, _ := cmd.Flags().GetString(worker.FlagOutput)
+
+	switch output {
+	case worker.OutputJSON:
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(v)
+	case worker.OutputText, "":
+		writeText(cmd.OutOrStdout())
+		return nil
+	default:
+		return fmt.Errorf("invalid value %q for %s", output, worker.FlagOutput)
+	}
+}
+
+func writePlan(out io.Writer, plan *worker.Plan) {
+	fmt.Fprintf(out, "Image: %s (mounted at %s)\n", plan.Image, plan.ImageDir)
+
+	if plan.Softdep != "" {
+		fmt.Fprintln(out, "Softdep configuration:")
+
+		for _, line := range strings.Split(strings.TrimSuffix(plan.Softdep, "\n"), "\n") {
+			fmt.Fprintln(out, "  "+line)
+		}
+	}
+
+	steps := make([]string, 0, 5)
+	firmwareFiles := ""
+
+	if fp := plan.Firmware; fp != nil {
+		firmwareFiles = strings.Join(fp.Files, ", ")
+	}
+
+	modprobe := "modprobe " + strings.Join(plan.ModprobeArgs, " ")
+
+	if plan.Action == worker.ActionLoad {
+		if len(plan.InTreeModulesToRemove) > 0 {
+			steps = append(steps, fmt.Sprintf("blacklist in %s: %s", plan.Blacklist, strings.Join(plan.InTreeModulesToRemove, ", ")))
+		} else if plan.Blacklist != "" {
+			steps = append(steps, fmt.Sprintf("remove %s, if it exists", plan.Blacklist))
+		}
+
+		for _, m := range plan.InTreeModulesToRemove {
+			steps = append(steps, "modprobe -rv "+m)
+		}
+
+		if fp := plan.Firmware; fp != nil {
+			steps = append(steps, fmt.Sprintf("install firmware from %s into %s: %s", fp.Source, fp.Destination, firmwareFiles))
+		}
+
+		steps = appendHookStep(steps, "pre-load", plan.PreHook)
+		steps = append(steps, modprobe)
+		steps = appendHookStep(steps, "post-load", plan.PostHook)
+	} else {
+		steps = appendHookStep(steps, "pre-unload", plan.PreHook)
+
+		if plan.Blacklist != "" {
+			steps = append(steps, fmt.Sprintf("remove %s, if it exists", plan.Blacklist))
+		}
+
+		steps = append(steps, modprobe)
+
+		if fp := plan.Firmware; fp != nil {
+			steps = append(
+				steps,
+				fmt.Sprintf("remove firmware from %s, unless used by another module: %s", fp.Destination, firmwareFiles),
+			)
+		}
+
+		steps = appendHookStep(steps, "post-unload", plan.PostHook)
+	}
+
+	fmt.Fprintf(out, "Steps to %s the module:\n", plan.Action)
+
+	for i, s := range steps {
+		fmt.Fprintf(out, "  %d. %s\n", i+1, s)
+	}
+}
+
+func appendHookStep(steps []string, name string, hook *kmmv1beta1.ModprobeHook) []string {
+	if hook == nil {
+		return steps
+	}
+
+	return append(steps, fmt.Sprintf("run %s hook: %s", name, strings.Join(append([]string{hook.Path}, hook.Args...), " ")))
+}
+
+func writeStatus(out io.Writer, status *worker.Status) {
+	fmt.Fprintf(out, "Image: %s (mounted at %s)\n\n", status.Image, status.ImageDir)
+
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "MODULE\tLOADED\tSTATE\tVERSION\tIMAGE VERSION\tSRCVERSION\tIMAGE SRCVERSION\tMATCHES IMAGE")
+
+	for _, ms := range status.Modules {
+		imageVersion := ms.ImageVersion
+		imageSrcVersion := ms.ImageSrcVersion
+
+		if ms.ImagePath == "" {
+			imageVersion = "<not in image>"
+			imageSrcVersion = "<not in image>"
+		}
+
+		fmt.Fprintf(
+			tw,
+			"%s\t%t\t%s\t%s\t%s\t%s\t%s\t%t\n",
+			ms.Name,
+			ms.Loaded,
+			ms.InitState,
+			ms.Version,
+			imageVersion,
+			ms.SrcVersion,
+			imageSrcVersion,
+			ms.MatchesImage(),
+		)
+	}
+
+	tw.Flush()
+
+	if fs := status.Firmware; fs != nil {
+		state := "OK"
+		if fs.Error != "" {
+			state = fs.Error
+		}
+
+		fmt.Fprintf(out, "\nFirmware in %s: %s\n", fs.Directory, state)
+	}
 }
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
@@ -94,3 +96,251 @@ var _ = Describe("kmodLoadFunc", func() {
 		Entry("class path defined and not empty, mount path defined", ptr.To("/some/path"), ptr.To("some mount path")),
 	)
 })
+
+var _ = Describe("newModprobeRunner", func() {
+	newCmd := func(value string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String(worker.FlagModprobeRunner, worker.ModprobeRunnerBinary, "")
+
+		Expect(
+			cmd.Flags().Set(worker.FlagModprobeRunner, value),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		return cmd
+	}
+
+	DescribeTable(
+		"should accept supported implementations",
+		func(value string) {
+			mr, err := newModprobeRunner(newCmd(value))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mr).NotTo(BeNil())
+		},
+		Entry(nil, worker.ModprobeRunnerBinary),
+		Entry(nil, worker.ModprobeRunnerNative),
+	)
+
+	It("should return an error for unknown implementations", func() {
+		_, err := newModprobeRunner(newCmd("unknown"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the flag is not defined", func() {
+		_, err := newModprobeRunner(&cobra.Command{})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("kmodVerifyFunc", func() {
+	const configPath = "/some/path"
+
+	It("should verify the module in the config", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch := worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo := worker.NewMockWorker(ctrl)
+		w = wo
+		DeferCleanup(func() {
+			configHelper = worker.NewConfigHelper()
+			w = nil
+		})
+
+		cfg := &kmmv1beta1.ModuleConfig{}
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().VerifyKmod(cfg).Return(errors.New("some error")),
+		)
+
+		Expect(
+			kmodVerifyFunc(&cobra.Command{}, []string{configPath}),
+		).To(
+			MatchError("some error"),
+		)
+	})
+})
+
+var _ = Describe("kmodVerifyFirmwareFunc", func() {
+	It("should verify the firmware in the mount path", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		wo := worker.NewMockWorker(ctrl)
+		w = wo
+		DeferCleanup(func() { w = nil })
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String(worker.FlagFirmwareMountPath, worker.FirmwareMountPath, "")
+
+		wo.EXPECT().VerifyFirmware(worker.FirmwareMountPath).Return(errors.New("some error"))
+
+		Expect(
+			kmodVerifyFirmwareFunc(cmd, nil),
+		).To(
+			MatchError("some error"),
+		)
+	})
+})
+
+var _ = Describe("kmodSetParametersFunc", func() {
+	const configPath = "/some/path"
+
+	It("should update the parameters passed as flags", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch := worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo := worker.NewMockWorker(ctrl)
+		w = wo
+		DeferCleanup(func() {
+			configHelper = worker.NewConfigHelper()
+			w = nil
+		})
+
+		cfg := &kmmv1beta1.ModuleConfig{}
+
+		cmd := &cobra.Command{}
+		cmd.Flags().StringArray(worker.FlagParameter, nil, "")
+		Expect(cmd.Flags().Set(worker.FlagParameter, "a")).To(Succeed())
+		Expect(cmd.Flags().Set(worker.FlagParameter, "b")).To(Succeed())
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().SetParameters(cfg, []string{"a", "b"}),
+		)
+
+		Expect(kmodSetParametersFunc(cmd, []string{configPath})).To(Succeed())
+	})
+})
+
+var _ = Describe("kmodRenderFunc", func() {
+	const configPath = "/some/path"
+
+	var (
+		ch  *worker.MockConfigHelper
+		cmd *cobra.Command
+		out *bytes.Buffer
+		wo  *worker.MockWorker
+	)
+
+	cfg := &kmmv1beta1.ModuleConfig{}
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo = worker.NewMockWorker(ctrl)
+		w = wo
+
+		out = &bytes.Buffer{}
+		cmd = &cobra.Command{}
+		cmd.SetContext(context.Background())
+		cmd.SetOut(out)
+		cmd.Flags().String(worker.FlagAction, string(worker.ActionLoad), "")
+		cmd.Flags().String(worker.FlagFirmwareMountPath, worker.FirmwareMountPath, "")
+		cmd.Flags().String(worker.FlagOutput, worker.OutputText, "")
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		w = nil
+	})
+
+	plan := &worker.Plan{
+		Action:                worker.ActionLoad,
+		Image:                 "some-image",
+		ImageDir:              "/image",
+		InTreeModulesToRemove: []string{"in-tree1", "in-tree2"},
+		Blacklist:             "/etc/modprobe.d/kmm-ns-name.conf",
+		ModprobeArgs:          []string{"-vd", "/image/opt", "mod"},
+		Softdep:               "softdep mod pre: dep\n",
+		Firmware: &worker.FirmwarePlan{
+			Source:      "/image/firmware",
+			Destination: worker.FirmwareMountPath,
+			Files:       []string{"a.bin", "b.bin"},
+		},
+		PostHook: &kmmv1beta1.ModprobeHook{Path: "/hooks/post-load.sh", Args: []string{"arg"}},
+	}
+
+	It("should print the plan as text", func() {
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().Render(context.Background(), cfg, worker.ActionLoad, worker.FirmwareMountPath).Return(plan, nil),
+		)
+
+		Expect(kmodRenderFunc(cmd, []string{configPath})).To(Succeed())
+		Expect(out.String()).To(Equal(`Image: some-image (mounted at /image)
+Softdep configuration:
+  softdep mod pre: dep
+Steps to load the module:
+  1. blacklist in /etc/modprobe.d/kmm-ns-name.conf: in-tree1, in-tree2
+  2. modprobe -rv in-tree1
+  3. modprobe -rv in-tree2
+  4. install firmware from /image/firmware into /var/lib/firmware: a.bin, b.bin
+  5. modprobe -vd /image/opt mod
+  6. run post-load hook: /hooks/post-load.sh arg
+`))
+	})
+
+	It("should print the plan as JSON", func() {
+		Expect(cmd.Flags().Set(worker.FlagOutput, worker.OutputJSON)).To(Succeed())
+		Expect(cmd.Flags().Set(worker.FlagAction, string(worker.ActionUnload))).To(Succeed())
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().Render(context.Background(), cfg, worker.ActionUnload, worker.FirmwareMountPath).Return(plan, nil),
+		)
+
+		Expect(kmodRenderFunc(cmd, []string{configPath})).To(Succeed())
+
+		decoded := &worker.Plan{}
+		Expect(json.Unmarshal(out.Bytes(), decoded)).To(Succeed())
+		Expect(decoded).To(Equal(plan))
+	})
+
+	It("should return an error for unknown output formats", func() {
+		Expect(cmd.Flags().Set(worker.FlagOutput, "yaml")).To(Succeed())
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().Render(context.Background(), cfg, worker.ActionLoad, worker.FirmwareMountPath).Return(plan, nil),
+		)
+
+		Expect(kmodRenderFunc(cmd, []string{configPath})).To(MatchError(ContainSubstring("invalid value")))
+	})
+
+	It("should print the status as text", func() {
+		status := &worker.Status{
+			Image:    "some-image",
+			ImageDir: "/image",
+			Modules: []worker.ModuleStatus{
+				{
+					Name:            "mod",
+					Loaded:          true,
+					InitState:       "live",
+					Version:         "1.0",
+					SrcVersion:      "ABC",
+					ImagePath:       "/image/mod.ko",
+					ImageVersion:    "1.0",
+					ImageSrcVersion: "ABC",
+				},
+				{Name: "dep"},
+			},
+			Firmware: &worker.FirmwareStatus{Directory: worker.FirmwareMountPath, Error: "a.bin: missing"},
+		}
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().Status(context.Background(), cfg, worker.FirmwareMountPath).Return(status, nil),
+		)
+
+		Expect(kmodStatusFunc(cmd, []string{configPath})).To(Succeed())
+		Expect(out.String()).To(Equal(`Image: some-image (mounted at /image)
+
+MODULE  LOADED  STATE  VERSION  IMAGE VERSION   SRCVERSION  IMAGE SRCVERSION  MATCHES IMAGE
+mod     true    live   1.0      1.0             ABC         ABC               true
+dep     false                   <not in image>              <not in image>    false
+
+Firmware in /var/lib/firmware: a.bin: missing
+`))
+	})
+})
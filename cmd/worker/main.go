@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -18,6 +19,7 @@ var (
 	Version = "undefined"
 
 	configHelper = worker.NewConfigHelper()
+	imageCache   worker.ImageCache
 	logger       logr.Logger
 	w            worker.Worker
 )
@@ -50,13 +52,84 @@ var kmodUnloadCmd = &cobra.Command{
 	RunE:  kmodUnloadFunc,
 }
 
+var kmodSetParametersCmd = &cobra.Command{
+	Use:   "set-parameters",
+	Short: "Update the parameters of a loaded kernel module through sysfs, without reloading it",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kmodSetParametersFunc,
+}
+
+var kmodVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify that a kernel module and its dependencies are still live in the kernel, without pulling the image",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kmodVerifyFunc,
+}
+
+var kmodVerifyFirmwareCmd = &cobra.Command{
+	Use:   "verify-firmware",
+	Short: "Verify that the firmware files installed for a kernel module were not modified or removed",
+	Args:  cobra.NoArgs,
+	RunE:  kmodVerifyFirmwareFunc,
+}
+
+var kmodRenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Print the actions that would be taken to load or unload a kernel module, without executing them",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kmodRenderFunc,
+}
+
+var kmodStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Report whether a kernel module and its dependencies are loaded and match the image",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kmodStatusFunc,
+}
+
+var cacheCmd = &cobra.Command{
+	Use:               "cache",
+	Short:             "Manage the image cache",
+	PersistentPreRunE: cacheFuncPreRunE,
+}
+
+var cacheGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove unused images from the cache",
+	Args:  cobra.NoArgs,
+	RunE:  cacheGCFunc,
+}
+
+func addGarbageCollectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray(
+		worker.FlagKeepImage,
+		nil,
+		"an image that must be kept in the cache; can be repeated",
+	)
+
+	cmd.Flags().String(
+		worker.FlagMaxSize,
+		"0",
+		"the size of the cache, as a Kubernetes quantity, above which the least recently used images are removed",
+	)
+}
+
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM)
 	defer cancel()
 
-	rootCmd.AddCommand(kmodCmd)
+	rootCmd.AddCommand(cacheCmd, kmodCmd)
 
-	kmodCmd.AddCommand(kmodLoadCmd, kmodUnloadCmd)
+	cacheCmd.AddCommand(cacheGCCmd)
+	kmodCmd.AddCommand(
+		kmodLoadCmd,
+		kmodRenderCmd,
+		kmodSetParametersCmd,
+		kmodStatusCmd,
+		kmodUnloadCmd,
+		kmodVerifyCmd,
+		kmodVerifyFirmwareCmd,
+	)
 
 	klogFlagSet := flag.NewFlagSet("klog", flag.ContinueOnError)
 
@@ -65,6 +138,22 @@ func main() {
 
 	rootCmd.PersistentFlags().AddGoFlagSet(klogFlagSet)
 
+	kmodCmd.PersistentFlags().String(
+		worker.FlagModprobeRunner,
+		worker.ModprobeRunnerBinary,
+		fmt.Sprintf(
+			"the implementation used to load and unload kernel modules: %q runs the modprobe binary, %q uses system calls directly",
+			worker.ModprobeRunnerBinary,
+			worker.ModprobeRunnerNative,
+		),
+	)
+
+	kmodCmd.PersistentFlags().String(
+		worker.FlagRegistryMirrors,
+		"",
+		"if set, the path to a file listing the registry mirrors to pull images from",
+	)
+
 	kmodLoadCmd.Flags().String(
 		worker.FlagFirmwareClassPath,
 		"",
@@ -81,6 +170,84 @@ func main() {
 		"",
 		"if set, this the value that firmware host path is mounted to")
 
+	kmodRenderCmd.Flags().String(
+		worker.FlagAction,
+		string(worker.ActionLoad),
+		fmt.Sprintf("the action to render: %q or %q", worker.ActionLoad, worker.ActionUnload),
+	)
+
+	for _, c := range []*cobra.Command{kmodRenderCmd, kmodStatusCmd} {
+		c.Flags().String(
+			worker.FlagFirmwareMountPath,
+			worker.FirmwareMountPath,
+			"the path that the firmware host path is mounted to")
+
+		c.Flags().StringP(
+			worker.FlagOutput,
+			"o",
+			worker.OutputText,
+			fmt.Sprintf("the output format: %q or %q", worker.OutputText, worker.OutputJSON),
+		)
+	}
+
+	kmodSetParametersCmd.Flags().StringArray(
+		worker.FlagParameter,
+		nil,
+		"the name of a parameter to update with the value set in the config; can be repeated",
+	)
+
+	kmodVerifyFirmwareCmd.Flags().String(
+		worker.FlagFirmwareMountPath,
+		worker.FirmwareMountPath,
+		"the path that the firmware host path is mounted to")
+
+	kmodUnloadCmd.Flags().Bool(
+		worker.FlagGarbageCollect,
+		false,
+		"if set, remove unused images from the cache after the module is unloaded",
+	)
+
+	kmodUnloadCmd.Flags().Bool(
+		worker.FlagListHolders,
+		false,
+		"if set, list the processes holding the device nodes of the module if it is in use",
+	)
+
+	for _, c := range []*cobra.Command{kmodLoadCmd, kmodUnloadCmd} {
+		c.Flags().Duration(
+			worker.FlagPullTimeout,
+			0,
+			"if set, fail if the image is not pulled within this duration",
+		)
+	}
+
+	kmodLoadCmd.Flags().Duration(
+		worker.FlagLoadTimeout,
+		0,
+		"if set, fail if the module is not loaded within this duration after the image was pulled",
+	)
+
+	kmodUnloadCmd.Flags().Duration(
+		worker.FlagUnloadTimeout,
+		0,
+		"if set, fail if the module is not unloaded within this duration after the image was pulled",
+	)
+
+	kmodUnloadCmd.Flags().Duration(
+		worker.FlagInUseTimeout,
+		worker.DefaultModuleInUseTimeout,
+		"how long to wait for the module to be released with the Wait in-use policy",
+	)
+
+	kmodUnloadCmd.Flags().Duration(
+		worker.FlagInUsePollInterval,
+		worker.DefaultModuleInUsePollInterval,
+		"how often to check whether the module was released with the Wait in-use policy",
+	)
+
+	addGarbageCollectionFlags(cacheGCCmd)
+	addGarbageCollectionFlags(kmodUnloadCmd)
+
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		logger = textlogger.NewLogger(logConfig).WithName("kmm-worker")
 
@@ -95,7 +262,18 @@ func main() {
 	}
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		kmmcmd.FatalError(logger, err, "Fatal error")
+		kernelLog, klErr := worker.ReadKernelLog(worker.KernelLogPath, worker.KernelLogLines)
+		if klErr != nil {
+			logger.Error(klErr, "Could not read the kernel log")
+		}
+
+		tm := worker.NewTerminationMessage(err, kernelLog)
+
+		if tmErr := worker.WriteTerminationMessage(worker.TerminationMessagePath, tm); tmErr != nil {
+			logger.Error(tmErr, "Could not write the termination message")
+		}
+
+		kmmcmd.FatalError(logger, err, "Fatal error", "phase", tm.Phase, "reason", tm.Reason)
 		os.Exit(1)
 	}
 }
@@ -54,6 +54,27 @@ type ModuleLoaderData struct {
 	// InTreeModuleToRemove - in case string not empty, remove the module prior to loading the module specified in moduleName
 	InTreeModuleToRemove string
 
+	// InTreeModulesToRemove - modules to remove prior to loading the module specified in moduleName
+	InTreeModulesToRemove []string
+
+	// Platform pins the image to use in multi-architecture images; empty means the node's platform.
+	Platform string
+
+	// ImageVerification configures the verification of the container image's signature by the worker
+	ImageVerification *kmmv1beta1.ImageVerification
+
+	// Timeouts bound the worker Pods' image pulls, loads and unloads
+	Timeouts *kmmv1beta1.ModuleTimeouts
+
+	// MaintenancePolicy selects the Pods evicted from nodes before the module is unloaded or reloaded
+	MaintenancePolicy *kmmv1beta1.MaintenancePolicy
+
+	// Dependencies are the modules loaded before this one, with their namespace set
+	Dependencies []kmmv1beta1.ModuleDependency
+
+	// NodeMatch restricts the nodes running KernelVersion to which this data applies
+	NodeMatch kmmv1beta1.NodeMatch
+
 	// used for setting the owner field of pods/buildconfigs
 	Owner metav1.Object
 }
@@ -90,7 +90,7 @@ func (m *maker) MakePodTemplate(
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: mld.Name + "-build-",
 			Namespace:    mld.Namespace,
-			Labels:       m.podHelper.PodLabels(mld.Name, mld.KernelVersion, utils.PodTypeBuild),
+			Labels:       m.podHelper.PodLabels(mld.Name, mld.KernelVersion, mld.ContainerImage, utils.PodTypeBuild),
 			Annotations:  map[string]string{constants.PodHashAnnotation: fmt.Sprintf("%d", podSpecHash)},
 			Finalizers:   []string{constants.JobEventFinalizer},
 		},
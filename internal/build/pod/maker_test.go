@@ -238,7 +238,7 @@ var _ = Describe("MakePodTemplate", func() {
 					return nil
 				},
 			),
-			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, utils.PodTypeBuild).Return(labels),
+			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, mld.ContainerImage, utils.PodTypeBuild).Return(labels),
 		)
 
 		actual, err := m.MakePodTemplate(ctx, &mld, mld.Owner, true)
@@ -303,7 +303,7 @@ var _ = Describe("MakePodTemplate", func() {
 					return nil
 				},
 			),
-			podhelper.EXPECT().PodLabels(mod.Name, kernelVersion, utils.PodTypeBuild).Return(map[string]string{}),
+			podhelper.EXPECT().PodLabels(mod.Name, kernelVersion, mld.ContainerImage, utils.PodTypeBuild).Return(map[string]string{}),
 		)
 
 		actual, err := m.MakePodTemplate(ctx, &mld, mld.Owner, pushImage)
@@ -381,7 +381,7 @@ var _ = Describe("MakePodTemplate", func() {
 					return nil
 				},
 			),
-			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, utils.PodTypeBuild).Return(map[string]string{}),
+			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, mld.ContainerImage, utils.PodTypeBuild).Return(map[string]string{}),
 		)
 
 		actual, err := m.MakePodTemplate(ctx, &mld, mld.Owner, false)
@@ -417,7 +417,7 @@ var _ = Describe("MakePodTemplate", func() {
 					return nil
 				},
 			),
-			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, utils.PodTypeBuild).Return(map[string]string{}),
+			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, mld.ContainerImage, utils.PodTypeBuild).Return(map[string]string{}),
 		)
 
 		actual, err := m.MakePodTemplate(ctx, &mld, mld.Owner, true)
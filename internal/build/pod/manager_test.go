@@ -63,7 +63,7 @@ var _ = Describe("ShouldSync", func() {
 		}
 
 		gomock.InOrder(
-			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any()).Return(true, nil),
+			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any(), nil).Return(true, nil),
 		)
 
 		mgr := NewBuildManager(clnt, nil, nil, reg)
@@ -86,7 +86,7 @@ var _ = Describe("ShouldSync", func() {
 		}
 
 		gomock.InOrder(
-			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any()).Return(false, errors.New("generic-registry-error")),
+			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any(), nil).Return(false, errors.New("generic-registry-error")),
 		)
 
 		mgr := NewBuildManager(clnt, nil, nil, reg)
@@ -110,7 +110,7 @@ var _ = Describe("ShouldSync", func() {
 		}
 
 		gomock.InOrder(
-			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any()).Return(false, nil),
+			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any(), nil).Return(false, nil),
 		)
 
 		mgr := NewBuildManager(clnt, nil, nil, reg)
@@ -175,7 +175,7 @@ var _ = Describe("Sync", func() {
 
 			gomock.InOrder(
 				maker.EXPECT().MakePodTemplate(ctx, mld, mld.Owner, true).Return(&j, nil),
-				podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, utils.PodTypeBuild, mld.Owner).Return(&j, nil),
+				podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, mld.ContainerImage, utils.PodTypeBuild, mld.Owner).Return(&j, nil),
 				podhelper.EXPECT().IsPodChanged(&j, &j).Return(false, nil),
 				podhelper.EXPECT().GetPodStatus(&j).Return(podStatus, nil),
 			)
@@ -227,7 +227,7 @@ var _ = Describe("Sync", func() {
 
 		gomock.InOrder(
 			maker.EXPECT().MakePodTemplate(ctx, mld, mld.Owner, true).Return(&j, nil),
-			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, utils.PodTypeBuild, mld.Owner).Return(nil, utils.ErrNoMatchingPod),
+			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, mld.ContainerImage, utils.PodTypeBuild, mld.Owner).Return(nil, utils.ErrNoMatchingPod),
 			podhelper.EXPECT().CreatePod(ctx, &j).Return(errors.New("some error")),
 		)
 
@@ -256,7 +256,7 @@ var _ = Describe("Sync", func() {
 
 		gomock.InOrder(
 			maker.EXPECT().MakePodTemplate(ctx, mld, mld.Owner, true).Return(&j, nil),
-			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, utils.PodTypeBuild, mld.Owner).Return(nil, utils.ErrNoMatchingPod),
+			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, mld.ContainerImage, utils.PodTypeBuild, mld.Owner).Return(nil, utils.ErrNoMatchingPod),
 			podhelper.EXPECT().CreatePod(ctx, &j).Return(nil),
 		)
 
@@ -298,7 +298,7 @@ var _ = Describe("Sync", func() {
 
 		gomock.InOrder(
 			maker.EXPECT().MakePodTemplate(ctx, mld, mld.Owner, true).Return(&newPod, nil),
-			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, utils.PodTypeBuild, mld.Owner).Return(&j, nil),
+			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, mld.ContainerImage, utils.PodTypeBuild, mld.Owner).Return(&j, nil),
 			podhelper.EXPECT().IsPodChanged(&j, &newPod).Return(true, nil),
 			podhelper.EXPECT().DeletePod(ctx, &j).Return(nil),
 		)
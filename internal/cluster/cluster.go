@@ -111,14 +111,15 @@ func (c *clusterAPI) BuildAndSign(
 	logger := log.FromContext(ctx)
 
 	completedSuccessfully := true
-	for kernelVersion, mld := range mldMappings {
+	for _, mld := range mldMappings {
 		buildCompleted, err := c.build(ctx, mld, &mcm)
 		if err != nil {
 			return false, err
 		}
 
 		kernelVersionLogger := logger.WithValues(
-			"kernel version", kernelVersion,
+			"kernel version", mld.KernelVersion,
+			"image", mld.ContainerImage,
 		)
 
 		if !buildCompleted {
@@ -189,23 +190,28 @@ func (c *clusterAPI) kernelMappingsByKernelVersion(
 			"kernel version", kernelVersion,
 		)
 
-		if mld, ok := mldMappings[kernelVersion]; ok {
-			kernelVersionLogger.V(1).Info("Using cached mld mapping", "mld", mld)
-			continue
-		}
-
-		mld, err := c.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
+		// The nodes of the spoke are not known; build and sign for all the mappings they may select.
+		mlds, err := c.kernelAPI.GetModuleLoaderDataForKernelVersion(mod, kernelVersion)
 		if err != nil {
 			kernelVersionLogger.Info("no suitable container image found; skipping kernel version")
 			continue
 		}
 
-		kernelVersionLogger.V(1).Info("Found a valid mapping",
-			"image", mld.ContainerImage,
-			"build", mld.Build != nil,
-		)
+		for _, mld := range mlds {
+			kernelVersionLogger.V(1).Info("Found a valid mapping",
+				"image", mld.ContainerImage,
+				"build", mld.Build != nil,
+			)
+
+			key := module.BuildAndSignKey(mld)
 
-		mldMappings[kernelVersion] = mld
+			if cached, ok := mldMappings[key]; ok {
+				mldMappings[key] = module.BuildAndSignCandidate(cached, mld)
+				continue
+			}
+
+			mldMappings[key] = mld
+		}
 	}
 
 	return mldMappings, nil
@@ -261,7 +261,7 @@ var _ = Describe("BuildAndSign", func() {
 
 	It("should do nothing when no kernel mappings are found", func() {
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(nil, errors.New("generic-error")),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(&mod, kernelVersion).Return(nil, errors.New("generic-error")),
 		)
 
 		completed, err := c.BuildAndSign(ctx, *mcm, clusterList.Items[0])
@@ -290,7 +290,7 @@ var _ = Describe("BuildAndSign", func() {
 
 	It("should do nothing when Build and Sign are not needed", func() {
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(&mod, kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockBM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(false, nil),
 			mockSM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(false, nil),
 		)
@@ -303,7 +303,7 @@ var _ = Describe("BuildAndSign", func() {
 
 	It("should run build sync if needed", func() {
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(&mod, kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockBM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(true, nil),
 			mockBM.EXPECT().Sync(gomock.Any(), &mld, true, mcm).Return(utils.Status(utils.StatusCompleted), nil),
 			mockSM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(false, nil),
@@ -317,7 +317,7 @@ var _ = Describe("BuildAndSign", func() {
 
 	It("should return an error when build sync errors", func() {
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(&mod, kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockBM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(true, nil),
 			mockBM.EXPECT().Sync(gomock.Any(), &mld, true, mcm).Return(utils.Status(""), errors.New("test-error")),
 		)
@@ -330,7 +330,7 @@ var _ = Describe("BuildAndSign", func() {
 
 	It("should run sign sync if needed", func() {
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(&mod, kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockBM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(false, nil),
 			mockSM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(true, nil),
 			mockSM.EXPECT().Sync(gomock.Any(), &mld, "", true, mcm).Return(utils.Status(utils.StatusInProgress), nil),
@@ -344,7 +344,7 @@ var _ = Describe("BuildAndSign", func() {
 
 	It("should return an error when sign sync errors", func() {
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(&mod, kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockBM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(false, nil),
 			mockSM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(true, nil),
 			mockSM.EXPECT().Sync(gomock.Any(), &mld, "", true, mcm).Return(utils.Status(""), errors.New("test-error")),
@@ -358,7 +358,7 @@ var _ = Describe("BuildAndSign", func() {
 
 	It("should not run sign sync when build sync does not complete", func() {
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(&mod, kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockBM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(true, nil),
 			mockBM.EXPECT().Sync(gomock.Any(), &mld, true, mcm).Return(utils.Status(utils.StatusInProgress), nil),
 		)
@@ -371,7 +371,7 @@ var _ = Describe("BuildAndSign", func() {
 
 	It("should run both build sync and sign sync when build is completed", func() {
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(&mod, kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockBM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(true, nil),
 			mockBM.EXPECT().Sync(gomock.Any(), &mld, true, mcm).Return(utils.Status(utils.StatusCompleted), nil),
 			mockSM.EXPECT().ShouldSync(gomock.Any(), &mld).Return(true, nil),
@@ -383,6 +383,25 @@ var _ = Describe("BuildAndSign", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(completed).To(BeTrue())
 	})
+
+	It("should build every image selected for the kernel", func() {
+		prebuilt := api.ModuleLoaderData{ContainerImage: "prebuilt", KernelVersion: kernelVersion}
+		built := api.ModuleLoaderData{ContainerImage: "built", KernelVersion: kernelVersion, Build: &kmmv1beta1.Build{}}
+
+		mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(&mod, kernelVersion).Return([]*api.ModuleLoaderData{&prebuilt, &built}, nil)
+		mockBM.EXPECT().ShouldSync(gomock.Any(), &prebuilt).Return(false, nil)
+		mockSM.EXPECT().ShouldSync(gomock.Any(), &prebuilt).Return(false, nil)
+		gomock.InOrder(
+			mockBM.EXPECT().ShouldSync(gomock.Any(), &built).Return(true, nil),
+			mockBM.EXPECT().Sync(gomock.Any(), &built, true, mcm).Return(utils.Status(utils.StatusCompleted), nil),
+			mockSM.EXPECT().ShouldSync(gomock.Any(), &built).Return(false, nil),
+		)
+
+		completed, err := c.BuildAndSign(ctx, *mcm, clusterList.Items[0])
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(completed).To(BeTrue())
+	})
 })
 
 var _ = Describe("GarbageCollectBuildsAndSigns", func() {
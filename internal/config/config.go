@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mirror"
 	"gopkg.in/yaml.v3"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -11,10 +13,43 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+// WorkerTimeouts bound the steps of worker Pods.
+// 0 means no timeout.
+type WorkerTimeouts struct {
+	Pull   time.Duration `yaml:"pull,omitempty"`
+	Load   time.Duration `yaml:"load,omitempty"`
+	Unload time.Duration `yaml:"unload,omitempty"`
+}
+
 type Worker struct {
+	// DefaultTimeouts apply to Modules that do not set timeouts.
+	DefaultTimeouts WorkerTimeouts `yaml:"defaultTimeouts,omitempty"`
+	// ImageCacheMaxSize is the size, as a Kubernetes quantity, above which unload workers remove the least recently
+	// used kmod images from the node's cache.
+	// Empty means that all images not used by a module on the node are removed.
+	ImageCacheMaxSize string `yaml:"imageCacheMaxSize,omitempty"`
+	// ListHolderProcesses makes unload workers list the processes holding the device nodes of modules that are in
+	// use.
+	// Unload workers then run in the host's PID namespace.
+	ListHolderProcesses bool `yaml:"listHolderProcesses,omitempty"`
+	// MaxLoadAttempts is the number of consecutive failed attempts at loading a module on a node after which KMM
+	// stops trying, until the module's config changes.
+	// 0 means no limit.
+	MaxLoadAttempts int32 `yaml:"maxLoadAttempts,omitempty"`
+	// ModuleInUseTimeout is how long unload workers wait for modules with the Wait in-use policy to be released, and
+	// ModuleInUsePollInterval how often they check whether they were.
+	// 0 means the worker's defaults, 1 minute and 2 seconds.
+	ModuleInUseTimeout      time.Duration `yaml:"moduleInUseTimeout,omitempty"`
+	ModuleInUsePollInterval time.Duration `yaml:"moduleInUsePollInterval,omitempty"`
+	// ReloadOnDrift makes KMM load modules again when verification finds that they are no longer loaded.
+	ReloadOnDrift        bool    `yaml:"reloadOnDrift,omitempty"`
 	RunAsUser            *int64  `yaml:"runAsUser"`
 	SELinuxType          string  `yaml:"seLinuxType"`
 	SetFirmwareClassPath *string `yaml:"setFirmwareClassPath,omitempty"`
+	// VerifyInterval is the period at which worker Pods check that the modules loaded on each node are still live in
+	// the kernel.
+	// 0 disables the verification.
+	VerifyInterval time.Duration `yaml:"verifyInterval,omitempty"`
 }
 
 type LeaderElection struct {
@@ -32,8 +67,10 @@ type Config struct {
 	HealthProbeBindAddress string         `yaml:"healthProbeBindAddress"`
 	LeaderElection         LeaderElection `yaml:"leaderElection"`
 	Metrics                Metrics        `yaml:"metrics"`
-	WebhookPort            int            `yaml:"webhookPort"`
-	Worker                 Worker         `yaml:"worker"`
+	// RegistryMirrors are applied by the operator and by worker Pods to all image pulls.
+	RegistryMirrors []mirror.Registry `yaml:"registryMirrors,omitempty"`
+	WebhookPort     int               `yaml:"webhookPort"`
+	Worker          Worker            `yaml:"worker"`
 }
 
 func ParseFile(path string) (*Config, error) {
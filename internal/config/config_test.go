@@ -1,6 +1,9 @@
 package config
 
 import (
+	"time"
+
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mirror"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/utils/ptr"
@@ -24,11 +27,30 @@ var _ = Describe("ParseFile", func() {
 				EnableAuthnAuthz: true,
 				SecureServing:    true,
 			},
+			RegistryMirrors: []mirror.Registry{
+				{
+					Prefix:   "quay.io/org",
+					Location: "internal.example.com/org",
+					Mirrors:  []mirror.Mirror{{Location: "mirror.example.com/org", Insecure: true}},
+				},
+			},
 			WebhookPort: 9443,
 			Worker: Worker{
-				RunAsUser:            ptr.To[int64](1234),
-				SELinuxType:          "mySELinuxType",
-				SetFirmwareClassPath: ptr.To("/some/path"),
+				DefaultTimeouts: WorkerTimeouts{
+					Pull:   10 * time.Minute,
+					Load:   5 * time.Minute,
+					Unload: 150 * time.Second,
+				},
+				ImageCacheMaxSize:       "2Gi",
+				ListHolderProcesses:     true,
+				MaxLoadAttempts:         5,
+				ModuleInUseTimeout:      3 * time.Minute,
+				ModuleInUsePollInterval: 5 * time.Second,
+				ReloadOnDrift:           true,
+				RunAsUser:               ptr.To[int64](1234),
+				SELinuxType:             "mySELinuxType",
+				SetFirmwareClassPath:    ptr.To("/some/path"),
+				VerifyInterval:          15 * time.Minute,
 			},
 		}
 
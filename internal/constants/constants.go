@@ -4,6 +4,7 @@ const (
 	ModuleNameLabel      = "kmm.node.kubernetes.io/module.name"
 	NodeLabelerFinalizer = "kmm.node.kubernetes.io/node-labeler"
 	TargetKernelTarget   = "kmm.node.kubernetes.io/target-kernel"
+	TargetImageHashLabel = "kmm.node.kubernetes.io/target-image-hash"
 	PodType              = "kmm.node.kubernetes.io/pod-type"
 	PodHashAnnotation    = "kmm.node.kubernetes.io/last-hash"
 	KernelLabel          = "kmm.node.kubernetes.io/kernel-version.full"
@@ -19,6 +19,8 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
@@ -30,6 +32,8 @@ import (
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -95,8 +99,13 @@ func (r *BuildSignReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return res, fmt.Errorf("could get kernel mappings for module %s: %w", mod.Name, err)
 	}
 
-	for kernelVersion, mld := range mldMappings {
-		completedSuccessfully, err := r.reconHelperAPI.handleBuild(ctx, mld)
+	failedBuilds := make([]string, 0)
+	failedSigns := make([]string, 0)
+
+	for _, mld := range mldMappings {
+		kernelVersion := mld.KernelVersion
+
+		buildStatus, err := r.reconHelperAPI.handleBuild(ctx, mld)
 		if err != nil {
 			return res, fmt.Errorf("failed to handle build for kernel version %s: %v", kernelVersion, err)
 		}
@@ -104,16 +113,22 @@ func (r *BuildSignReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			"kernel version", kernelVersion,
 			"mld", mld,
 		)
-		if !completedSuccessfully {
+		if buildStatus == utils.StatusFailed {
+			failedBuilds = append(failedBuilds, kernelVersion)
+		}
+		if buildStatus != utils.StatusCompleted {
 			mldLogger.Info("Build has not finished successfully yet:skipping handling signing for now")
 			continue
 		}
 
-		completedSuccessfully, err = r.reconHelperAPI.handleSigning(ctx, mld)
+		signStatus, err := r.reconHelperAPI.handleSigning(ctx, mld)
 		if err != nil {
 			return res, fmt.Errorf("failed to handle signing for kernel version %s: %v", kernelVersion, err)
 		}
-		if !completedSuccessfully {
+		if signStatus == utils.StatusFailed {
+			failedSigns = append(failedSigns, kernelVersion)
+		}
+		if signStatus != utils.StatusCompleted {
 			mldLogger.Info("Signing has not finished successfully yet")
 		}
 	}
@@ -124,6 +139,11 @@ func (r *BuildSignReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return res, fmt.Errorf("failed to run garbage collection: %v", err)
 	}
 
+	err = r.reconHelperAPI.setBuildSignConditions(ctx, mod, failedBuilds, failedSigns)
+	if err != nil {
+		return res, fmt.Errorf("failed to set the build and sign conditions: %v", err)
+	}
+
 	logger.Info("Reconcile loop finished successfully")
 
 	return res, nil
@@ -135,9 +155,10 @@ type buildSignReconcilerHelperAPI interface {
 	getRequestedModule(ctx context.Context, namespacedName types.NamespacedName) (*kmmv1beta1.Module, error)
 	getNodesListBySelector(ctx context.Context, mod *kmmv1beta1.Module) ([]v1.Node, error)
 	getRelevantKernelMappings(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) (map[string]*api.ModuleLoaderData, error)
-	handleBuild(ctx context.Context, mld *api.ModuleLoaderData) (bool, error)
-	handleSigning(ctx context.Context, mld *api.ModuleLoaderData) (bool, error)
+	handleBuild(ctx context.Context, mld *api.ModuleLoaderData) (utils.Status, error)
+	handleSigning(ctx context.Context, mld *api.ModuleLoaderData) (utils.Status, error)
 	garbageCollect(ctx context.Context, mod *kmmv1beta1.Module, mldMappings map[string]*api.ModuleLoaderData) error
+	setBuildSignConditions(ctx context.Context, mod *kmmv1beta1.Module, failedBuilds, failedSigns []string) error
 }
 
 type buildSignReconcilerHelper struct {
@@ -174,14 +195,16 @@ func (bsrh *buildSignReconcilerHelper) getRelevantKernelMappings(ctx context.Con
 			"kernel version", kernelVersion,
 		)
 
-		if mld, ok := mldMappings[kernelVersion]; ok {
-			nodeLogger.V(1).Info("Using cached mld mapping", "mld", mld)
+		mld, err := bsrh.kernelAPI.GetModuleLoaderDataForKernel(mod, &node)
+		if err != nil {
+			nodeLogger.Error(err, "failed to get and process kernel mapping")
 			continue
 		}
 
-		mld, err := bsrh.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
-		if err != nil {
-			nodeLogger.Error(err, "failed to get and process kernel mapping")
+		key := module.BuildAndSignKey(mld)
+
+		if cached, ok := mldMappings[key]; ok {
+			mldMappings[key] = module.BuildAndSignCandidate(cached, mld)
 			continue
 		}
 
@@ -190,7 +213,7 @@ func (bsrh *buildSignReconcilerHelper) getRelevantKernelMappings(ctx context.Con
 			"build", mld.Build != nil,
 		)
 
-		mldMappings[kernelVersion] = mld
+		mldMappings[key] = mld
 	}
 	return mldMappings, nil
 }
@@ -215,15 +238,15 @@ func (bsrh *buildSignReconcilerHelper) getNodesListBySelector(ctx context.Contex
 	return nodes, nil
 }
 
-// handleBuild returns true if build is not needed or finished successfully
-func (bsrh *buildSignReconcilerHelper) handleBuild(ctx context.Context, mld *api.ModuleLoaderData) (bool, error) {
+// handleBuild returns utils.StatusCompleted if the build is not needed or finished successfully
+func (bsrh *buildSignReconcilerHelper) handleBuild(ctx context.Context, mld *api.ModuleLoaderData) (utils.Status, error) {
 
 	shouldSync, err := bsrh.buildAPI.ShouldSync(ctx, mld)
 	if err != nil {
-		return false, fmt.Errorf("could not check if build synchronization is needed: %w", err)
+		return "", fmt.Errorf("could not check if build synchronization is needed: %w", err)
 	}
 	if !shouldSync {
-		return true, nil
+		return utils.StatusCompleted, nil
 	}
 
 	logger := log.FromContext(ctx).WithValues("kernel version", mld.KernelVersion, "image", mld.ContainerImage)
@@ -231,28 +254,24 @@ func (bsrh *buildSignReconcilerHelper) handleBuild(ctx context.Context, mld *api
 
 	buildStatus, err := bsrh.buildAPI.Sync(buildCtx, mld, true, mld.Owner)
 	if err != nil {
-		return false, fmt.Errorf("could not synchronize the build: %w", err)
+		return "", fmt.Errorf("could not synchronize the build: %w", err)
 	}
 
-	completedSuccessfully := false
-	switch buildStatus {
-	case utils.StatusCompleted:
-		completedSuccessfully = true
-	case utils.StatusFailed:
+	if buildStatus == utils.StatusFailed {
 		logger.Info(utils.WarnString("Build pod has failed. If the fix is not in Module CR, then delete pod after the fix in order to restart the pod"))
 	}
 
-	return completedSuccessfully, nil
+	return buildStatus, nil
 }
 
-// handleSigning returns true if signing is not needed or finished successfully
-func (bsrh *buildSignReconcilerHelper) handleSigning(ctx context.Context, mld *api.ModuleLoaderData) (bool, error) {
+// handleSigning returns utils.StatusCompleted if signing is not needed or finished successfully
+func (bsrh *buildSignReconcilerHelper) handleSigning(ctx context.Context, mld *api.ModuleLoaderData) (utils.Status, error) {
 	shouldSync, err := bsrh.signAPI.ShouldSync(ctx, mld)
 	if err != nil {
-		return false, fmt.Errorf("cound not check if synchronization is needed: %w", err)
+		return "", fmt.Errorf("cound not check if synchronization is needed: %w", err)
 	}
 	if !shouldSync {
-		return true, nil
+		return utils.StatusCompleted, nil
 	}
 
 	// if we need to sign AND we've built, then we must have built the intermediate image so must figure out its name
@@ -266,18 +285,59 @@ func (bsrh *buildSignReconcilerHelper) handleSigning(ctx context.Context, mld *a
 
 	signStatus, err := bsrh.signAPI.Sync(signCtx, mld, previousImage, true, mld.Owner)
 	if err != nil {
-		return false, fmt.Errorf("could not synchronize the signing: %w", err)
+		return "", fmt.Errorf("could not synchronize the signing: %w", err)
 	}
 
-	completedSuccessfully := false
-	switch signStatus {
-	case utils.StatusCompleted:
-		completedSuccessfully = true
-	case utils.StatusFailed:
+	if signStatus == utils.StatusFailed {
 		logger.Info(utils.WarnString("Sign pod has failed. If the fix is not in Module CR, then delete pod after the fix in order to restart the pod"))
 	}
 
-	return completedSuccessfully, nil
+	return signStatus, nil
+}
+
+// setBuildSignConditions sets the BuildFailed and SignFailed conditions of mod for the kernel versions whose build or
+// signing pod failed.
+// The Module's status is only patched if the conditions changed.
+func (bsrh *buildSignReconcilerHelper) setBuildSignConditions(ctx context.Context,
+	mod *kmmv1beta1.Module,
+	failedBuilds,
+	failedSigns []string) error {
+
+	unmodifiedMod := mod.DeepCopy()
+
+	setFailedCondition(mod, kmmv1beta1.ModuleConditionBuildFailed, "BuildPodFailed", "NoFailedBuilds", "build", failedBuilds)
+	setFailedCondition(mod, kmmv1beta1.ModuleConditionSignFailed, "SignPodFailed", "NoFailedSigns", "signing", failedSigns)
+
+	if reflect.DeepEqual(unmodifiedMod.Status.Conditions, mod.Status.Conditions) {
+		return nil
+	}
+
+	// Conditions are also set by the ModuleNMCReconciler; do not overwrite them if the Module changed meanwhile.
+	err := bsrh.client.Status().Patch(ctx, mod, client.MergeFromWithOptions(unmodifiedMod, client.MergeFromWithOptimisticLock{}))
+	if err != nil {
+		return fmt.Errorf("could not patch the status of module %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
+	return nil
+}
+
+func setFailedCondition(mod *kmmv1beta1.Module, condType, trueReason, falseReason, operation string, kernelVersions []string) {
+	cond := metav1.Condition{
+		Type:               condType,
+		Status:             metav1.ConditionFalse,
+		Reason:             falseReason,
+		ObservedGeneration: mod.Generation,
+	}
+
+	if len(kernelVersions) > 0 {
+		sort.Strings(kernelVersions)
+
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = trueReason
+		cond.Message = fmt.Sprintf("%s failed for kernel versions %s", operation, strings.Join(kernelVersions, ", "))
+	}
+
+	apimeta.SetStatusCondition(&mod.Status.Conditions, cond)
 }
 
 func (bsrh *buildSignReconcilerHelper) garbageCollect(ctx context.Context,
@@ -16,6 +16,7 @@ import (
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -58,7 +59,7 @@ var _ = Describe("BuildSignReconciler_Reconcile", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
-	DescribeTable("check error flows", func(getModuleError, getNodesError, getMappingsError, handleBuildError, handleSignError bool) {
+	DescribeTable("check error flows", func(getModuleError, getNodesError, getMappingsError, handleBuildError, handleSignError, garbageCollectError bool) {
 		mod := kmmv1beta1.Module{}
 		selectNodesList := []v1.Node{v1.Node{}}
 		mappings := map[string]*api.ModuleLoaderData{"kernelVersion": &api.ModuleLoaderData{}}
@@ -79,16 +80,21 @@ var _ = Describe("BuildSignReconciler_Reconcile", func() {
 		}
 		mockReconHelper.EXPECT().getRelevantKernelMappings(ctx, &mod, selectNodesList).Return(mappings, nil)
 		if handleBuildError {
-			mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(false, returnedError)
+			mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(utils.Status(""), returnedError)
 			goto executeTestFunction
 		}
-		mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(true, nil)
+		mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(utils.Status(utils.StatusCompleted), nil)
 		if handleSignError {
-			mockReconHelper.EXPECT().handleSigning(ctx, mappings["kernelVersion"]).Return(false, returnedError)
+			mockReconHelper.EXPECT().handleSigning(ctx, mappings["kernelVersion"]).Return(utils.Status(""), returnedError)
 			goto executeTestFunction
 		}
-		mockReconHelper.EXPECT().handleSigning(ctx, mappings["kernelVersion"]).Return(true, nil)
-		mockReconHelper.EXPECT().garbageCollect(ctx, &mod, mappings).Return(returnedError)
+		mockReconHelper.EXPECT().handleSigning(ctx, mappings["kernelVersion"]).Return(utils.Status(utils.StatusCompleted), nil)
+		if garbageCollectError {
+			mockReconHelper.EXPECT().garbageCollect(ctx, &mod, mappings).Return(returnedError)
+			goto executeTestFunction
+		}
+		mockReconHelper.EXPECT().garbageCollect(ctx, &mod, mappings).Return(nil)
+		mockReconHelper.EXPECT().setBuildSignConditions(ctx, &mod, []string{}, []string{}).Return(returnedError)
 
 	executeTestFunction:
 		res, err := bsr.Reconcile(ctx, req)
@@ -97,12 +103,13 @@ var _ = Describe("BuildSignReconciler_Reconcile", func() {
 		Expect(err).To(HaveOccurred())
 
 	},
-		Entry("getRequestedModule failed", true, false, false, false, false),
-		Entry("getNodesListBySelector failed", false, true, false, false, false),
-		Entry("getRelevantKernelMappingsAndNodes failed", false, false, true, false, false),
-		Entry("handleBuild failed ", false, false, false, true, false),
-		Entry("handleSign failed", false, false, false, false, true),
-		Entry("garbageCollect failed", false, false, false, false, false),
+		Entry("getRequestedModule failed", true, false, false, false, false, false),
+		Entry("getNodesListBySelector failed", false, true, false, false, false, false),
+		Entry("getRelevantKernelMappingsAndNodes failed", false, false, true, false, false, false),
+		Entry("handleBuild failed ", false, false, false, true, false, false),
+		Entry("handleSign failed", false, false, false, false, true, false),
+		Entry("garbageCollect failed", false, false, false, false, false, true),
+		Entry("setBuildSignConditions failed", false, false, false, false, false, false),
 	)
 
 	It("Build has not completed successfully", func() {
@@ -113,8 +120,9 @@ var _ = Describe("BuildSignReconciler_Reconcile", func() {
 			mockReconHelper.EXPECT().getRequestedModule(ctx, nsn).Return(&mod, nil),
 			mockReconHelper.EXPECT().getNodesListBySelector(ctx, &mod).Return(selectNodesList, nil),
 			mockReconHelper.EXPECT().getRelevantKernelMappings(ctx, &mod, selectNodesList).Return(mappings, nil),
-			mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(false, nil),
+			mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(utils.Status(utils.StatusInProgress), nil),
 			mockReconHelper.EXPECT().garbageCollect(ctx, &mod, mappings).Return(nil),
+			mockReconHelper.EXPECT().setBuildSignConditions(ctx, &mod, []string{}, []string{}).Return(nil),
 		)
 
 		res, err := bsr.Reconcile(ctx, req)
@@ -132,9 +140,36 @@ var _ = Describe("BuildSignReconciler_Reconcile", func() {
 			mockReconHelper.EXPECT().getRequestedModule(ctx, nsn).Return(&mod, nil),
 			mockReconHelper.EXPECT().getNodesListBySelector(ctx, &mod).Return(selectNodesList, nil),
 			mockReconHelper.EXPECT().getRelevantKernelMappings(ctx, &mod, selectNodesList).Return(mappings, nil),
-			mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(true, nil),
-			mockReconHelper.EXPECT().handleSigning(ctx, mappings["kernelVersion"]).Return(false, nil),
+			mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(utils.Status(utils.StatusCompleted), nil),
+			mockReconHelper.EXPECT().handleSigning(ctx, mappings["kernelVersion"]).Return(utils.Status(utils.StatusInProgress), nil),
 			mockReconHelper.EXPECT().garbageCollect(ctx, &mod, mappings).Return(nil),
+			mockReconHelper.EXPECT().setBuildSignConditions(ctx, &mod, []string{}, []string{}).Return(nil),
+		)
+
+		res, err := bsr.Reconcile(ctx, req)
+
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Build and signing have failed", func() {
+		mod := kmmv1beta1.Module{}
+		selectNodesList := []v1.Node{v1.Node{}}
+		mappings := map[string]*api.ModuleLoaderData{
+			"kernelVersion1": &api.ModuleLoaderData{KernelVersion: "kernelVersion1"},
+			"kernelVersion2": &api.ModuleLoaderData{KernelVersion: "kernelVersion2"},
+		}
+		gomock.InOrder(
+			mockReconHelper.EXPECT().getRequestedModule(ctx, nsn).Return(&mod, nil),
+			mockReconHelper.EXPECT().getNodesListBySelector(ctx, &mod).Return(selectNodesList, nil),
+			mockReconHelper.EXPECT().getRelevantKernelMappings(ctx, &mod, selectNodesList).Return(mappings, nil),
+		)
+		mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion1"]).Return(utils.Status(utils.StatusFailed), nil)
+		mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion2"]).Return(utils.Status(utils.StatusCompleted), nil)
+		mockReconHelper.EXPECT().handleSigning(ctx, mappings["kernelVersion2"]).Return(utils.Status(utils.StatusFailed), nil)
+		gomock.InOrder(
+			mockReconHelper.EXPECT().garbageCollect(ctx, &mod, mappings).Return(nil),
+			mockReconHelper.EXPECT().setBuildSignConditions(ctx, &mod, []string{"kernelVersion1"}, []string{"kernelVersion2"}).Return(nil),
 		)
 
 		res, err := bsr.Reconcile(ctx, req)
@@ -151,9 +186,10 @@ var _ = Describe("BuildSignReconciler_Reconcile", func() {
 			mockReconHelper.EXPECT().getRequestedModule(ctx, nsn).Return(&mod, nil),
 			mockReconHelper.EXPECT().getNodesListBySelector(ctx, &mod).Return(selectNodesList, nil),
 			mockReconHelper.EXPECT().getRelevantKernelMappings(ctx, &mod, selectNodesList).Return(mappings, nil),
-			mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(true, nil),
-			mockReconHelper.EXPECT().handleSigning(ctx, mappings["kernelVersion"]).Return(true, nil),
+			mockReconHelper.EXPECT().handleBuild(ctx, mappings["kernelVersion"]).Return(utils.Status(utils.StatusCompleted), nil),
+			mockReconHelper.EXPECT().handleSigning(ctx, mappings["kernelVersion"]).Return(utils.Status(utils.StatusCompleted), nil),
 			mockReconHelper.EXPECT().garbageCollect(ctx, &mod, mappings).Return(nil),
+			mockReconHelper.EXPECT().setBuildSignConditions(ctx, &mod, []string{}, []string{}).Return(nil),
 		)
 
 		res, err := bsr.Reconcile(ctx, req)
@@ -254,15 +290,16 @@ var _ = Describe("BuildSignReconciler_getRelevantKernelMappings", func() {
 		},
 	}
 
-	mld1 := api.ModuleLoaderData{Name: "name1"}
-	mld2 := api.ModuleLoaderData{Name: "name2"}
+	mld1 := api.ModuleLoaderData{Name: "name1", KernelVersion: "kernelVersion1", ContainerImage: "image1"}
+	mld2 := api.ModuleLoaderData{Name: "name2", KernelVersion: "kernelVersion2", ContainerImage: "image2"}
 
 	It("good flow, all mappings exist", func() {
 		nodes := []v1.Node{node1, node2, node3}
-		expectedMappings := map[string]*api.ModuleLoaderData{"kernelVersion1": &mld1, "kernelVersion2": &mld2}
+		expectedMappings := map[string]*api.ModuleLoaderData{"kernelVersion1@image1": &mld1, "kernelVersion2@image2": &mld2}
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, node1.Status.NodeInfo.KernelVersion).Return(&mld1, nil),
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, node2.Status.NodeInfo.KernelVersion).Return(&mld2, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, &node1).Return(&mld1, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, &node2).Return(&mld2, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, &node3).Return(&mld1, nil),
 		)
 
 		mappings, err := bsrh.getRelevantKernelMappings(context.Background(), &kmmv1beta1.Module{}, nodes)
@@ -274,10 +311,11 @@ var _ = Describe("BuildSignReconciler_getRelevantKernelMappings", func() {
 
 	It("good flow, one mapping does not exist", func() {
 		nodes := []v1.Node{node1, node2, node3}
-		expectedMappings := map[string]*api.ModuleLoaderData{"kernelVersion1": &mld1}
+		expectedMappings := map[string]*api.ModuleLoaderData{"kernelVersion1@image1": &mld1}
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, node1.Status.NodeInfo.KernelVersion).Return(&mld1, nil),
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, node2.Status.NodeInfo.KernelVersion).Return(nil, fmt.Errorf("some error")),
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, &node1).Return(&mld1, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, &node2).Return(nil, fmt.Errorf("some error")),
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, &node3).Return(&mld1, nil),
 		)
 
 		mappings, err := bsrh.getRelevantKernelMappings(context.Background(), &kmmv1beta1.Module{}, nodes)
@@ -287,6 +325,30 @@ var _ = Describe("BuildSignReconciler_getRelevantKernelMappings", func() {
 
 	})
 
+	It("should keep every image selected for a shared kernel", func() {
+		prebuilt := api.ModuleLoaderData{Name: "name1", KernelVersion: "kernelVersion1", ContainerImage: "prebuilt"}
+		built := api.ModuleLoaderData{Name: "name1", KernelVersion: "kernelVersion1", ContainerImage: "built"}
+		builtInCluster := api.ModuleLoaderData{
+			Name:           "name1",
+			KernelVersion:  "kernelVersion1",
+			ContainerImage: "built",
+			Build:          &kmmv1beta1.Build{},
+		}
+
+		gomock.InOrder(
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, &node1).Return(&prebuilt, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, &node3).Return(&built, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(&kmmv1beta1.Module{}, &node3).Return(&builtInCluster, nil),
+		)
+
+		mappings, err := bsrh.getRelevantKernelMappings(context.Background(), &kmmv1beta1.Module{}, []v1.Node{node1, node3, node3})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mappings).To(Equal(map[string]*api.ModuleLoaderData{
+			"kernelVersion1@prebuilt": &prebuilt,
+			"kernelVersion1@built":    &builtInCluster,
+		}))
+	})
 })
 
 var _ = Describe("BuildSignReconciler_handleBuild", func() {
@@ -315,9 +377,9 @@ var _ = Describe("BuildSignReconciler_handleBuild", func() {
 			mockBM.EXPECT().ShouldSync(gomock.Any(), mld).Return(false, nil),
 		)
 
-		completed, err := bsrh.handleBuild(context.Background(), mld)
+		status, err := bsrh.handleBuild(context.Background(), mld)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(completed).To(BeTrue())
+		Expect(status).To(BeEquivalentTo(utils.StatusCompleted))
 	})
 
 	It("should record that a pod was created when the build sync returns StatusCreated", func() {
@@ -334,10 +396,10 @@ var _ = Describe("BuildSignReconciler_handleBuild", func() {
 			mockBM.EXPECT().Sync(gomock.Any(), &mld, true, mld.Owner).Return(utils.Status(utils.StatusCreated), nil),
 		)
 
-		completed, err := bsrh.handleBuild(context.Background(), &mld)
+		status, err := bsrh.handleBuild(context.Background(), &mld)
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(completed).To(BeFalse())
+		Expect(status).To(BeEquivalentTo(utils.StatusCreated))
 	})
 
 	It("should record that a pod was completed, when the build sync returns StatusCompleted", func() {
@@ -354,10 +416,10 @@ var _ = Describe("BuildSignReconciler_handleBuild", func() {
 			mockBM.EXPECT().Sync(gomock.Any(), mld, true, mld.Owner).Return(utils.Status(utils.StatusCompleted), nil),
 		)
 
-		completed, err := bsrh.handleBuild(context.Background(), mld)
+		status, err := bsrh.handleBuild(context.Background(), mld)
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(completed).To(BeTrue())
+		Expect(status).To(BeEquivalentTo(utils.StatusCompleted))
 	})
 })
 
@@ -390,10 +452,10 @@ var _ = Describe("BuildSignReconciler_handleSigning", func() {
 			mockSM.EXPECT().ShouldSync(gomock.Any(), mld).Return(false, nil),
 		)
 
-		completed, err := bsrh.handleSigning(context.Background(), mld)
+		status, err := bsrh.handleSigning(context.Background(), mld)
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(completed).To(BeTrue())
+		Expect(status).To(BeEquivalentTo(utils.StatusCompleted))
 	})
 
 	It("should record that a pod was created when the sign sync returns StatusCreated", func() {
@@ -410,10 +472,10 @@ var _ = Describe("BuildSignReconciler_handleSigning", func() {
 			mockSM.EXPECT().Sync(gomock.Any(), &mld, "", true, mld.Owner).Return(utils.Status(utils.StatusCreated), nil),
 		)
 
-		completed, err := bsrh.handleSigning(context.Background(), &mld)
+		status, err := bsrh.handleSigning(context.Background(), &mld)
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(completed).To(BeFalse())
+		Expect(status).To(BeEquivalentTo(utils.StatusCreated))
 	})
 
 	It("should record that a pod was completed when the sign sync returns StatusCompleted", func() {
@@ -430,10 +492,10 @@ var _ = Describe("BuildSignReconciler_handleSigning", func() {
 			mockSM.EXPECT().Sync(gomock.Any(), &mld, "", true, mld.Owner).Return(utils.Status(utils.StatusCompleted), nil),
 		)
 
-		completed, err := bsrh.handleSigning(context.Background(), &mld)
+		status, err := bsrh.handleSigning(context.Background(), &mld)
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(completed).To(BeTrue())
+		Expect(status).To(BeEquivalentTo(utils.StatusCompleted))
 	})
 
 	It("should run sign sync with the previous image as well when module build and sign are specified", func() {
@@ -453,10 +515,92 @@ var _ = Describe("BuildSignReconciler_handleSigning", func() {
 				Return(utils.Status(utils.StatusCompleted), nil),
 		)
 
-		completed, err := bsrh.handleSigning(context.Background(), mld)
+		status, err := bsrh.handleSigning(context.Background(), mld)
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(completed).To(BeTrue())
+		Expect(status).To(BeEquivalentTo(utils.StatusCompleted))
+	})
+})
+
+var _ = Describe("BuildSignReconciler_setBuildSignConditions", func() {
+	var (
+		ctrl         *gomock.Controller
+		clnt         *client.MockClient
+		statusWriter *client.MockStatusWriter
+		bsrh         buildSignReconcilerHelperAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		bsrh = newBuildSignReconcilerHelper(clnt, nil, nil, nil)
+	})
+
+	ctx := context.Background()
+
+	It("should not patch the Module if the conditions did not change", func() {
+		mod := kmmv1beta1.Module{
+			Status: kmmv1beta1.ModuleStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:               kmmv1beta1.ModuleConditionBuildFailed,
+						Status:             metav1.ConditionFalse,
+						Reason:             "NoFailedBuilds",
+						LastTransitionTime: metav1.Now(),
+					},
+					{
+						Type:               kmmv1beta1.ModuleConditionSignFailed,
+						Status:             metav1.ConditionFalse,
+						Reason:             "NoFailedSigns",
+						LastTransitionTime: metav1.Now(),
+					},
+				},
+			},
+		}
+
+		Expect(
+			bsrh.setBuildSignConditions(ctx, &mod, []string{}, []string{}),
+		).NotTo(HaveOccurred())
+	})
+
+	It("should set the conditions for the failed kernel versions", func() {
+		mod := kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		}
+
+		gomock.InOrder(
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()),
+		)
+
+		Expect(
+			bsrh.setBuildSignConditions(ctx, &mod, []string{"kernel2", "kernel1"}, []string{}),
+		).NotTo(HaveOccurred())
+
+		buildCond := apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionBuildFailed)
+		Expect(buildCond).NotTo(BeNil())
+		Expect(buildCond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(buildCond.Reason).To(Equal("BuildPodFailed"))
+		Expect(buildCond.Message).To(Equal("build failed for kernel versions kernel1, kernel2"))
+		Expect(buildCond.ObservedGeneration).To(BeEquivalentTo(2))
+
+		Expect(
+			apimeta.IsStatusConditionFalse(mod.Status.Conditions, kmmv1beta1.ModuleConditionSignFailed),
+		).To(BeTrue())
+	})
+
+	It("should return an error if the patch failed", func() {
+		mod := kmmv1beta1.Module{}
+
+		gomock.InOrder(
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		Expect(
+			bsrh.setBuildSignConditions(ctx, &mod, []string{}, []string{"kernel1"}),
+		).To(HaveOccurred())
 	})
 })
 
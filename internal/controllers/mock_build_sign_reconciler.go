@@ -14,6 +14,7 @@ import (
 
 	v1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	api "github.com/kubernetes-sigs/kernel-module-management/internal/api"
+	utils "github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	gomock "go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	types "k8s.io/apimachinery/pkg/types"
@@ -102,10 +103,10 @@ func (mr *MockbuildSignReconcilerHelperAPIMockRecorder) getRequestedModule(ctx,
 }
 
 // handleBuild mocks base method.
-func (m *MockbuildSignReconcilerHelperAPI) handleBuild(ctx context.Context, mld *api.ModuleLoaderData) (bool, error) {
+func (m *MockbuildSignReconcilerHelperAPI) handleBuild(ctx context.Context, mld *api.ModuleLoaderData) (utils.Status, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "handleBuild", ctx, mld)
-	ret0, _ := ret[0].(bool)
+	ret0, _ := ret[0].(utils.Status)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -117,10 +118,10 @@ func (mr *MockbuildSignReconcilerHelperAPIMockRecorder) handleBuild(ctx, mld any
 }
 
 // handleSigning mocks base method.
-func (m *MockbuildSignReconcilerHelperAPI) handleSigning(ctx context.Context, mld *api.ModuleLoaderData) (bool, error) {
+func (m *MockbuildSignReconcilerHelperAPI) handleSigning(ctx context.Context, mld *api.ModuleLoaderData) (utils.Status, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "handleSigning", ctx, mld)
-	ret0, _ := ret[0].(bool)
+	ret0, _ := ret[0].(utils.Status)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -130,3 +131,17 @@ func (mr *MockbuildSignReconcilerHelperAPIMockRecorder) handleSigning(ctx, mld a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleSigning", reflect.TypeOf((*MockbuildSignReconcilerHelperAPI)(nil).handleSigning), ctx, mld)
 }
+
+// setBuildSignConditions mocks base method.
+func (m *MockbuildSignReconcilerHelperAPI) setBuildSignConditions(ctx context.Context, mod *v1beta1.Module, failedBuilds, failedSigns []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "setBuildSignConditions", ctx, mod, failedBuilds, failedSigns)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// setBuildSignConditions indicates an expected call of setBuildSignConditions.
+func (mr *MockbuildSignReconcilerHelperAPIMockRecorder) setBuildSignConditions(ctx, mod, failedBuilds, failedSigns any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "setBuildSignConditions", reflect.TypeOf((*MockbuildSignReconcilerHelperAPI)(nil).setBuildSignConditions), ctx, mod, failedBuilds, failedSigns)
+}
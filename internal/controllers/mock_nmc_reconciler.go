@@ -5,6 +5,7 @@
 //
 //	mockgen -source=nmc_reconciler.go -package=controllers -destination=mock_nmc_reconciler.go pullSecretHelper
 //
+
 // Package controllers is a generated GoMock package.
 package controllers
 
@@ -41,6 +42,20 @@ func (m *MocknmcReconcilerHelper) EXPECT() *MocknmcReconcilerHelperMockRecorder
 	return m.recorder
 }
 
+// FinishMaintenance mocks base method.
+func (m *MocknmcReconcilerHelper) FinishMaintenance(ctx context.Context, nmc *v1beta1.NodeModulesConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishMaintenance", ctx, nmc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishMaintenance indicates an expected call of FinishMaintenance.
+func (mr *MocknmcReconcilerHelperMockRecorder) FinishMaintenance(ctx, nmc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishMaintenance", reflect.TypeOf((*MocknmcReconcilerHelper)(nil).FinishMaintenance), ctx, nmc)
+}
+
 // GarbageCollectInUseLabels mocks base method.
 func (m *MocknmcReconcilerHelper) GarbageCollectInUseLabels(ctx context.Context, nmc *v1beta1.NodeModulesConfig) error {
 	m.ctrl.T.Helper()
@@ -162,8 +177,22 @@ func (mr *MockpodManagerMockRecorder) CreateLoaderPod(ctx, nmc, nms any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoaderPod", reflect.TypeOf((*MockpodManager)(nil).CreateLoaderPod), ctx, nmc, nms)
 }
 
+// CreateParametersPod mocks base method.
+func (m *MockpodManager) CreateParametersPod(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleSpec, names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateParametersPod", ctx, nmc, nms, names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateParametersPod indicates an expected call of CreateParametersPod.
+func (mr *MockpodManagerMockRecorder) CreateParametersPod(ctx, nmc, nms, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateParametersPod", reflect.TypeOf((*MockpodManager)(nil).CreateParametersPod), ctx, nmc, nms, names)
+}
+
 // CreateUnloaderPod mocks base method.
-func (m *MockpodManager) CreateUnloaderPod(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleStatus) error {
+func (m *MockpodManager) CreateUnloaderPod(ctx context.Context, nmc *v1beta1.NodeModulesConfig, nms *v1beta1.NodeModuleStatus) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateUnloaderPod", ctx, nmc, nms)
 	ret0, _ := ret[0].(error)
@@ -176,6 +205,20 @@ func (mr *MockpodManagerMockRecorder) CreateUnloaderPod(ctx, nmc, nms any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUnloaderPod", reflect.TypeOf((*MockpodManager)(nil).CreateUnloaderPod), ctx, nmc, nms)
 }
 
+// CreateVerifierPod mocks base method.
+func (m *MockpodManager) CreateVerifierPod(ctx context.Context, nmc *v1beta1.NodeModulesConfig, nms *v1beta1.NodeModuleStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifierPod", ctx, nmc, nms)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVerifierPod indicates an expected call of CreateVerifierPod.
+func (mr *MockpodManagerMockRecorder) CreateVerifierPod(ctx, nmc, nms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifierPod", reflect.TypeOf((*MockpodManager)(nil).CreateVerifierPod), ctx, nmc, nms)
+}
+
 // DeletePod mocks base method.
 func (m *MockpodManager) DeletePod(ctx context.Context, pod *v1.Pod) error {
 	m.ctrl.T.Helper()
@@ -235,8 +278,23 @@ func (mr *MockpodManagerMockRecorder) LoaderPodTemplate(ctx, nmc, nms any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoaderPodTemplate", reflect.TypeOf((*MockpodManager)(nil).LoaderPodTemplate), ctx, nmc, nms)
 }
 
+// ParametersPodTemplate mocks base method.
+func (m *MockpodManager) ParametersPodTemplate(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleSpec, names []string) (*v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParametersPodTemplate", ctx, nmc, nms, names)
+	ret0, _ := ret[0].(*v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParametersPodTemplate indicates an expected call of ParametersPodTemplate.
+func (mr *MockpodManagerMockRecorder) ParametersPodTemplate(ctx, nmc, nms, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParametersPodTemplate", reflect.TypeOf((*MockpodManager)(nil).ParametersPodTemplate), ctx, nmc, nms, names)
+}
+
 // UnloaderPodTemplate mocks base method.
-func (m *MockpodManager) UnloaderPodTemplate(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleStatus) (*v1.Pod, error) {
+func (m *MockpodManager) UnloaderPodTemplate(ctx context.Context, nmc *v1beta1.NodeModulesConfig, nms *v1beta1.NodeModuleStatus) (*v1.Pod, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UnloaderPodTemplate", ctx, nmc, nms)
 	ret0, _ := ret[0].(*v1.Pod)
@@ -250,6 +308,21 @@ func (mr *MockpodManagerMockRecorder) UnloaderPodTemplate(ctx, nmc, nms any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloaderPodTemplate", reflect.TypeOf((*MockpodManager)(nil).UnloaderPodTemplate), ctx, nmc, nms)
 }
 
+// VerifierPodTemplate mocks base method.
+func (m *MockpodManager) VerifierPodTemplate(ctx context.Context, nmc *v1beta1.NodeModulesConfig, nms *v1beta1.NodeModuleStatus) (*v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifierPodTemplate", ctx, nmc, nms)
+	ret0, _ := ret[0].(*v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifierPodTemplate indicates an expected call of VerifierPodTemplate.
+func (mr *MockpodManagerMockRecorder) VerifierPodTemplate(ctx, nmc, nms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifierPodTemplate", reflect.TypeOf((*MockpodManager)(nil).VerifierPodTemplate), ctx, nmc, nms)
+}
+
 // MockpullSecretHelper is a mock of pullSecretHelper interface.
 type MockpullSecretHelper struct {
 	ctrl     *gomock.Controller
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
@@ -16,11 +17,14 @@ import (
 	"github.com/kubernetes-sigs/kernel-module-management/internal/nmc"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/registry"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/platform"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -40,9 +44,19 @@ const (
 )
 
 type schedulingData struct {
-	action string
-	mld    *api.ModuleLoaderData
-	node   *v1.Node
+	action          string
+	mld             *api.ModuleLoaderData
+	node            *v1.Node
+	noKernelMapping bool
+	// heldBack is true if the rolling update keeps the node's current config instead of the Module's new one.
+	heldBack bool
+}
+
+// unscheduledNodes are the targeted nodes on which the Module's current config could not be set.
+type unscheduledNodes struct {
+	noKernelMapping []string
+	waitingForImage []string
+	heldBack        []string
 }
 
 type ModuleNMCReconciler struct {
@@ -105,9 +119,21 @@ func (mnr *ModuleNMCReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	errs := make([]error, 0, len(sdMap)+1)
 	errs = append(errs, prepareErrs...)
 
+	unscheduled := unscheduledNodes{}
+
 	for nodeName, sd := range sdMap {
+		if sd.noKernelMapping {
+			unscheduled.noKernelMapping = append(unscheduled.noKernelMapping, nodeName)
+		}
+		if sd.heldBack {
+			unscheduled.heldBack = append(unscheduled.heldBack, nodeName)
+		}
 		if sd.action == actionAdd {
-			err = mnr.reconHelper.enableModuleOnNode(ctx, sd.mld, sd.node)
+			var configured bool
+			configured, err = mnr.reconHelper.enableModuleOnNode(ctx, sd.mld, sd.node)
+			if err == nil && !configured {
+				unscheduled.waitingForImage = append(unscheduled.waitingForImage, nodeName)
+			}
 		}
 		if sd.action == actionDelete {
 			err = mnr.reconHelper.disableModuleOnNode(ctx, mod.Namespace, mod.Name, nodeName)
@@ -115,7 +141,7 @@ func (mnr *ModuleNMCReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		errs = append(errs, err)
 	}
 
-	err = mnr.reconHelper.moduleUpdateWorkerPodsStatus(ctx, mod, targetedNodes)
+	err = mnr.reconHelper.moduleUpdateWorkerPodsStatus(ctx, mod, targetedNodes, unscheduled)
 	errs = append(errs, err)
 
 	err = errors.Join(errs...)
@@ -154,9 +180,9 @@ type moduleNMCReconcilerHelperAPI interface {
 	getNodesListBySelector(ctx context.Context, mod *kmmv1beta1.Module) ([]v1.Node, error)
 	getNMCsByModuleSet(ctx context.Context, mod *kmmv1beta1.Module) (sets.Set[string], error)
 	prepareSchedulingData(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node, currentNMCs sets.Set[string]) (map[string]schedulingData, []error)
-	enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) error
+	enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) (bool, error)
 	disableModuleOnNode(ctx context.Context, modNamespace, modName, nodeName string) error
-	moduleUpdateWorkerPodsStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error
+	moduleUpdateWorkerPodsStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node, unscheduled unscheduledNodes) error
 }
 
 type moduleNMCReconcilerHelper struct {
@@ -319,7 +345,7 @@ func (mnrh *moduleNMCReconcilerHelper) prepareSchedulingData(ctx context.Context
 	errs := make([]error, 0, len(targetedNodes))
 	for _, node := range targetedNodes {
 		kernelVersion := strings.TrimSuffix(node.Status.NodeInfo.KernelVersion, "+")
-		mld, err := mnrh.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
+		mld, err := mnrh.kernelAPI.GetModuleLoaderDataForKernel(mod, &node)
 		if err != nil && !errors.Is(err, module.ErrNoMatchingKernelMapping) {
 			// deleting earlier, so as not to change NMC in case we failed to determine mld
 			currentNMCs.Delete(node.Name)
@@ -327,35 +353,142 @@ func (mnrh *moduleNMCReconcilerHelper) prepareSchedulingData(ctx context.Context
 			errs = append(errs, err)
 			continue
 		}
-		result[node.Name] = prepareNodeSchedulingData(node, mld, currentNMCs)
+		sd := prepareNodeSchedulingData(node, mld, currentNMCs)
+		sd.noKernelMapping = mld == nil
+		result[node.Name] = sd
 		currentNMCs.Delete(node.Name)
 	}
 	for _, nmcName := range currentNMCs.UnsortedList() {
 		result[nmcName] = schedulingData{action: actionDelete}
 	}
+	if us := mod.Spec.UpdateStrategy; us != nil && us.Type == kmmv1beta1.RollingUpdateModuleUpdateStrategyType {
+		if err := mnrh.limitUpdates(ctx, mod, targetedNodes, result); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	return result, errs
 }
 
-func (mnrh *moduleNMCReconcilerHelper) enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) error {
+// limitUpdates holds back the new config of the Module on the nodes covered by the rolling update's partition, and
+// on the nodes exceeding its maxUnavailable budget.
+// Nodes on which the Module is not configured yet, or is already unavailable, are not held back by maxUnavailable.
+// If the current state of the nodes cannot be determined, all new configs are held back.
+func (mnrh *moduleNMCReconcilerHelper) limitUpdates(ctx context.Context,
+	mod *kmmv1beta1.Module,
+	targetedNodes []v1.Node,
+	sdMap map[string]schedulingData) error {
+
 	logger := log.FromContext(ctx)
-	exists, err := module.ImageExists(ctx, mnrh.client, mnrh.registryAPI, mld, mld.Namespace, mld.ContainerImage)
+
+	holdBackAll := func() {
+		for nodeName, sd := range sdMap {
+			if sd.action == actionAdd {
+				sdMap[nodeName] = schedulingData{heldBack: true}
+			}
+		}
+	}
+
+	nodeNames := make([]string, 0, len(targetedNodes))
+	for _, node := range targetedNodes {
+		nodeNames = append(nodeNames, node.Name)
+	}
+	sort.Strings(nodeNames)
+
+	partition := 0
+	maxUnavailable := 1
+
+	if ru := mod.Spec.UpdateStrategy.RollingUpdate; ru != nil {
+		if ru.Partition != nil {
+			partition = int(*ru.Partition)
+		}
+
+		if ru.MaxUnavailable != nil {
+			v, err := intstr.GetScaledValueFromIntOrPercent(ru.MaxUnavailable, len(nodeNames), true)
+			if err != nil {
+				holdBackAll()
+				return fmt.Errorf("invalid maxUnavailable for module %s/%s: %v", mod.Namespace, mod.Name, err)
+			}
+
+			if v > 1 {
+				maxUnavailable = v
+			}
+		}
+	}
+
+	nmcs, err := mnrh.getNMCsForModule(ctx, mod)
 	if err != nil {
-		return fmt.Errorf("failed to verify is image %s exists: %v", mld.ContainerImage, err)
+		holdBackAll()
+		return fmt.Errorf("failed to get configured NMCs for module %s/%s: %v", mod.Namespace, mod.Name, err)
 	}
-	if !exists {
-		// skip updating NMC, reconciliation will kick in once the build pod is completed
-		return nil
+
+	specs := make(map[string]*kmmv1beta1.NodeModuleSpec, len(nmcs))
+	unavailable := sets.New[string]()
+
+	for i := range nmcs {
+		nmcObj := &nmcs[i]
+
+		modSpec, _ := mnrh.nmcHelper.GetModuleSpecEntry(nmcObj, mod.Namespace, mod.Name)
+		if modSpec == nil {
+			continue
+		}
+
+		specs[nmcObj.Name] = modSpec
+
+		modStatus := mnrh.nmcHelper.GetModuleStatusEntry(nmcObj, mod.Namespace, mod.Name)
+		if modStatus == nil || modStatus.Config == nil || !reflect.DeepEqual(modSpec.Config, *modStatus.Config) {
+			unavailable.Insert(nmcObj.Name)
+		}
 	}
-	moduleConfig := kmmv1beta1.ModuleConfig{
-		KernelVersion:        mld.KernelVersion,
-		ContainerImage:       mld.ContainerImage,
-		InTreeModuleToRemove: mld.InTreeModuleToRemove,
-		Modprobe:             mld.Modprobe,
+
+	budget := maxUnavailable - unavailable.Len()
+
+	for i, nodeName := range nodeNames {
+		sd := sdMap[nodeName]
+		modSpec := specs[nodeName]
+
+		if sd.action != actionAdd || modSpec == nil || reflect.DeepEqual(modSpec.Config, moduleConfigFromMLD(sd.mld)) {
+			continue
+		}
+
+		switch {
+		case i < partition:
+			logger.V(1).Info("Node is in the partition; keeping the current config", "node", nodeName)
+			sdMap[nodeName] = schedulingData{heldBack: true}
+		case unavailable.Has(nodeName):
+			// The module is already unavailable on that node; updating it does not use the budget.
+		case budget > 0:
+			budget--
+		default:
+			logger.V(1).Info("maxUnavailable reached; holding back the new config", "node", nodeName)
+			sdMap[nodeName] = schedulingData{heldBack: true}
+		}
 	}
 
-	if tls := mld.RegistryTLS; tls != nil {
-		moduleConfig.InsecurePull = tls.Insecure || tls.InsecureSkipTLSVerify
+	return nil
+}
+
+// enableModuleOnNode sets the config of mld in the NMC of node.
+// It returns false if the config was not set because its image does not exist yet.
+func (mnrh *moduleNMCReconcilerHelper) enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	// Check the image that the worker will unpack on this node
+	checkMLD := mld
+	if p := platform.ForNode(node); mld.Platform == "" && p != nil {
+		mldCopy := *mld
+		mldCopy.Platform = p.String()
+		checkMLD = &mldCopy
+	}
+
+	exists, err := module.ImageExists(ctx, mnrh.client, mnrh.registryAPI, checkMLD, mld.Namespace, mld.ContainerImage)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify is image %s exists: %v", mld.ContainerImage, err)
+	}
+	if !exists {
+		// skip updating NMC, reconciliation will kick in once the build pod is completed
+		return false, nil
 	}
+	moduleConfig := moduleConfigFromMLD(mld)
 
 	nmcObj := &kmmv1beta1.NodeModulesConfig{
 		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
@@ -374,10 +507,10 @@ func (mnrh *moduleNMCReconcilerHelper) enableModuleOnNode(ctx context.Context, m
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to enable module %s/%s in NMC %s: %v", mld.Namespace, mld.Name, node.Name, err)
+		return false, fmt.Errorf("failed to enable module %s/%s in NMC %s: %v", mld.Namespace, mld.Name, node.Name, err)
 	}
 	logger.Info("Enable module in NMC", "name", mld.Name, "namespace", mld.Namespace, "node", node.Name, "result", opRes)
-	return nil
+	return true, nil
 }
 
 func (mnrh *moduleNMCReconcilerHelper) disableModuleOnNode(ctx context.Context, modNamespace, modName, nodeName string) error {
@@ -408,7 +541,11 @@ func (mnrh *moduleNMCReconcilerHelper) removeModuleFromNMC(ctx context.Context,
 	return nil
 }
 
-func (mnrh *moduleNMCReconcilerHelper) moduleUpdateWorkerPodsStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error {
+func (mnrh *moduleNMCReconcilerHelper) moduleUpdateWorkerPodsStatus(ctx context.Context,
+	mod *kmmv1beta1.Module,
+	targetedNodes []v1.Node,
+	unscheduled unscheduledNodes) error {
+
 	logger := log.FromContext(ctx)
 	// get nmcs with configured
 	nmcs, err := mnrh.getNMCsForModule(ctx, mod)
@@ -416,6 +553,13 @@ func (mnrh *moduleNMCReconcilerHelper) moduleUpdateWorkerPodsStatus(ctx context.
 		return fmt.Errorf("failed to get configured NMCs for module %s/%s: %v", mod.Namespace, mod.Name, err)
 	}
 
+	heldBack := sets.New[string](unscheduled.heldBack...)
+	waitingForImage := sets.New[string](unscheduled.waitingForImage...)
+	failed := make([]kmmv1beta1.NodeFailure, 0)
+	heldBackNodes := make([]string, 0)
+	missingDependencies := make([]string, 0)
+	progressing := make([]string, 0)
+
 	numAvailable := 0
 	for _, nmc := range nmcs {
 		modSpec, _ := mnrh.nmcHelper.GetModuleSpecEntry(&nmc, mod.Namespace, mod.Name)
@@ -425,9 +569,25 @@ func (mnrh *moduleNMCReconcilerHelper) moduleUpdateWorkerPodsStatus(ctx context.
 			continue
 		}
 		modStatus := mnrh.nmcHelper.GetModuleStatusEntry(&nmc, mod.Namespace, mod.Name)
-		if modStatus != nil && reflect.DeepEqual(modSpec.Config, modStatus.Config) {
+		available := modStatus != nil && modStatus.Config != nil && reflect.DeepEqual(modSpec.Config, *modStatus.Config)
+		if available {
 			numAvailable += 1
 		}
+
+		switch cond := loadFailure(modStatus); {
+		case available && !heldBack.Has(nmc.Name):
+		case waitingForImage.Has(nmc.Name):
+			// already reported as waiting for the image of the Module's current config
+		case cond != nil:
+			failed = append(failed, kmmv1beta1.NodeFailure{Name: nmc.Name, Reason: cond.Reason, Message: cond.Message})
+		case heldBack.Has(nmc.Name):
+			// the rolling update keeps the previous config of the Module on that node
+			heldBackNodes = append(heldBackNodes, nmc.Name)
+		case len(mnrh.missingDependencies(&nmc, modSpec)) > 0:
+			missingDependencies = append(missingDependencies, nmc.Name)
+		default:
+			progressing = append(progressing, nmc.Name)
+		}
 	}
 
 	unmodifiedMod := mod.DeepCopy()
@@ -436,7 +596,187 @@ func (mnrh *moduleNMCReconcilerHelper) moduleUpdateWorkerPodsStatus(ctx context.
 	mod.Status.ModuleLoader.DesiredNumber = int32(len(nmcs))
 	mod.Status.ModuleLoader.AvailableNumber = int32(numAvailable)
 
-	return mnrh.client.Status().Patch(ctx, mod, client.MergeFrom(unmodifiedMod))
+	mod.Status.Nodes = kmmv1beta1.ModuleNodesStatus{
+		NoMatchingKernelMapping: boundedNodeList(unscheduled.noKernelMapping),
+		WaitingForImage:         boundedNodeList(unscheduled.waitingForImage),
+		Failed:                  boundedFailedNodeList(failed),
+		HeldBack:                boundedNodeList(heldBackNodes),
+		MissingDependencies:     boundedNodeList(missingDependencies),
+		Progressing:             boundedNodeList(progressing),
+	}
+
+	setModuleLoaderConditions(mod)
+
+	// Conditions are also set by the BuildSignReconciler; do not overwrite them if the Module changed meanwhile.
+	return mnrh.client.Status().Patch(ctx, mod, client.MergeFromWithOptions(unmodifiedMod, client.MergeFromWithOptimisticLock{}))
+}
+
+// missingDependencies returns the namespace/name of the dependencies of spec that have neither a spec nor a status
+// entry in nmcObj: the kernel module waits for them until their Module targets the node.
+func (mnrh *moduleNMCReconcilerHelper) missingDependencies(nmcObj *kmmv1beta1.NodeModulesConfig, spec *kmmv1beta1.NodeModuleSpec) []string {
+	missing := make([]string, 0)
+
+	for _, dep := range spec.Dependencies {
+		depSpec, _ := mnrh.nmcHelper.GetModuleSpecEntry(nmcObj, dep.Namespace, dep.Name)
+		if depSpec != nil || mnrh.nmcHelper.GetModuleStatusEntry(nmcObj, dep.Namespace, dep.Name) != nil {
+			continue
+		}
+
+		missing = append(missing, dep.Namespace+"/"+dep.Name)
+	}
+
+	return missing
+}
+
+// loadFailure returns the Loaded condition of status if the last attempt to load the module failed, and nil otherwise.
+func loadFailure(status *kmmv1beta1.NodeModuleStatus) *metav1.Condition {
+	if status == nil {
+		return nil
+	}
+
+	cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionLoaded)
+	if cond == nil || cond.Status != metav1.ConditionFalse {
+		return nil
+	}
+
+	return cond
+}
+
+// setModuleLoaderConditions sets the conditions of mod that are derived from mod.Status.Nodes.
+func setModuleLoaderConditions(mod *kmmv1beta1.Module) {
+	nodes := mod.Status.Nodes
+
+	setCondition := func(condType string, status bool, trueReason, trueMessage, falseReason, falseMessage string) {
+		cond := metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionFalse,
+			Reason:             falseReason,
+			Message:            falseMessage,
+			ObservedGeneration: mod.Generation,
+		}
+
+		if status {
+			cond.Status = metav1.ConditionTrue
+			cond.Reason = trueReason
+			cond.Message = trueMessage
+		}
+
+		apimeta.SetStatusCondition(&mod.Status.Conditions, cond)
+	}
+
+	setCondition(
+		kmmv1beta1.ModuleConditionNoMatchingKernelMapping,
+		nodes.NoMatchingKernelMapping.Total > 0,
+		"NodesWithoutKernelMapping",
+		fmt.Sprintf("%d nodes run a kernel that matches no kernel mapping", nodes.NoMatchingKernelMapping.Total),
+		"AllKernelsMapped",
+		"",
+	)
+
+	setCondition(
+		kmmv1beta1.ModuleConditionDegraded,
+		nodes.Failed.Total > 0,
+		"LoadFailed",
+		fmt.Sprintf("the kernel module failed to load on %d nodes", nodes.Failed.Total),
+		"NoLoadFailures",
+		"",
+	)
+
+	setCondition(
+		kmmv1beta1.ModuleConditionMissingDependencies,
+		nodes.MissingDependencies.Total > 0,
+		"DependenciesNotConfigured",
+		fmt.Sprintf("%d nodes wait for dependencies that are not configured on them", nodes.MissingDependencies.Total),
+		"AllDependenciesConfigured",
+		"",
+	)
+
+	setCondition(
+		kmmv1beta1.ModuleConditionProgressing,
+		nodes.Progressing.Total+nodes.WaitingForImage.Total+nodes.HeldBack.Total > 0,
+		"NodesProgressing",
+		fmt.Sprintf(
+			"the kernel module is being loaded on %d nodes; %d nodes are waiting for their image; %d nodes are held back by the rolling update",
+			nodes.Progressing.Total,
+			nodes.WaitingForImage.Total,
+			nodes.HeldBack.Total,
+		),
+		"NoNodesProgressing",
+		"",
+	)
+
+	notReady := nodes.Failed.Total + nodes.HeldBack.Total + nodes.MissingDependencies.Total + nodes.Progressing.Total +
+		nodes.WaitingForImage.Total
+
+	setCondition(
+		kmmv1beta1.ModuleConditionReady,
+		notReady == 0,
+		"AllNodesReady",
+		fmt.Sprintf("the kernel module is loaded on %d nodes", mod.Status.ModuleLoader.AvailableNumber),
+		"NodesNotReady",
+		fmt.Sprintf("the current config of the kernel module is not loaded on %d nodes", notReady),
+	)
+}
+
+// boundedNodeList returns a NodeList of names that lists at most MaxModuleStatusNodes of them.
+func boundedNodeList(names []string) kmmv1beta1.NodeList {
+	nl := kmmv1beta1.NodeList{Total: int32(len(names))}
+
+	if len(names) == 0 {
+		return nl
+	}
+
+	sorted := make([]string, len(names))
+	copy(sorted, names)
+	sort.Strings(sorted)
+
+	if len(sorted) > kmmv1beta1.MaxModuleStatusNodes {
+		sorted = sorted[:kmmv1beta1.MaxModuleStatusNodes]
+	}
+
+	nl.Names = sorted
+
+	return nl
+}
+
+// boundedFailedNodeList returns a FailedNodeList of failures that lists at most MaxModuleStatusNodes of them.
+func boundedFailedNodeList(failures []kmmv1beta1.NodeFailure) kmmv1beta1.FailedNodeList {
+	fl := kmmv1beta1.FailedNodeList{Total: int32(len(failures))}
+
+	if len(failures) == 0 {
+		return fl
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Name < failures[j].Name
+	})
+
+	if len(failures) > kmmv1beta1.MaxModuleStatusNodes {
+		failures = failures[:kmmv1beta1.MaxModuleStatusNodes]
+	}
+
+	fl.Nodes = failures
+
+	return fl
+}
+
+// moduleConfigFromMLD returns the ModuleConfig written to the NMC of nodes targeted by mld.
+func moduleConfigFromMLD(mld *api.ModuleLoaderData) kmmv1beta1.ModuleConfig {
+	moduleConfig := kmmv1beta1.ModuleConfig{
+		KernelVersion:         mld.KernelVersion,
+		ContainerImage:        mld.ContainerImage,
+		InTreeModuleToRemove:  mld.InTreeModuleToRemove,
+		InTreeModulesToRemove: mld.InTreeModulesToRemove,
+		Modprobe:              mld.Modprobe,
+		Platform:              mld.Platform,
+		ImageVerification:     mld.ImageVerification,
+	}
+
+	if tls := mld.RegistryTLS; tls != nil {
+		moduleConfig.InsecurePull = tls.Insecure || tls.InsecureSkipTLSVerify
+	}
+
+	return moduleConfig
 }
 
 func prepareNodeSchedulingData(node v1.Node, mld *api.ModuleLoaderData, currentNMCs sets.Set[string]) schedulingData {
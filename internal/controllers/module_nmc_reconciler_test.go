@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	ggcrv1 "github.com/google/go-containerregistry/pkg/v1"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
@@ -17,10 +19,13 @@ import (
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -127,23 +132,23 @@ var _ = Describe("ModuleNMCReconciler_Reconcile", func() {
 		mockReconHelper.EXPECT().prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, []error{})
 		if disableEnableError {
 			if shouldBeOnNode {
-				mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(returnedError)
+				mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(false, returnedError)
 			} else {
 				mockReconHelper.EXPECT().disableModuleOnNode(ctx, mod.Namespace, mod.Name, node.Name).Return(returnedError)
 			}
 			goto moduleStatusUpdateFunction
 		}
 		if shouldBeOnNode {
-			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(nil)
+			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(true, nil)
 		} else {
 			mockReconHelper.EXPECT().disableModuleOnNode(ctx, mod.Namespace, mod.Name, node.Name).Return(nil)
 		}
 
 	moduleStatusUpdateFunction:
 		if moduleUpdateStatusErr {
-			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes).Return(returnedError)
+			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes, unscheduledNodes{}).Return(returnedError)
 		} else {
-			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes).Return(nil)
+			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes, unscheduledNodes{}).Return(nil)
 		}
 
 	executeTestFunction:
@@ -171,8 +176,38 @@ var _ = Describe("ModuleNMCReconciler_Reconcile", func() {
 			mockReconHelper.EXPECT().getNodesListBySelector(ctx, &mod).Return(targetedNodes, nil),
 			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, &mod).Return(currentNMCs, nil),
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
-			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(nil),
-			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes).Return(nil),
+			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(true, nil),
+			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes, unscheduledNodes{}).Return(nil),
+		)
+
+		res, err := mnr.Reconcile(ctx, req)
+
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report the nodes without kernel mapping, waiting for their image or held back", func() {
+		otherNode := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "otherNode"},
+		}
+		nmcMLDConfigs := map[string]schedulingData{
+			nodeName:       enableSchedulingData,
+			otherNode.Name: {noKernelMapping: true},
+			"heldBackNode": {heldBack: true},
+		}
+		expectedUnscheduled := unscheduledNodes{
+			noKernelMapping: []string{otherNode.Name},
+			waitingForImage: []string{nodeName},
+			heldBack:        []string{"heldBackNode"},
+		}
+		gomock.InOrder(
+			mockReconHelper.EXPECT().getRequestedModule(ctx, nsn).Return(&mod, nil),
+			mockReconHelper.EXPECT().setFinalizerAndStatus(ctx, &mod).Return(nil),
+			mockReconHelper.EXPECT().getNodesListBySelector(ctx, &mod).Return(targetedNodes, nil),
+			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, &mod).Return(currentNMCs, nil),
+			mockReconHelper.EXPECT().prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(false, nil),
+			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes, expectedUnscheduled).Return(nil),
 		)
 
 		res, err := mnr.Reconcile(ctx, req)
@@ -190,7 +225,7 @@ var _ = Describe("ModuleNMCReconciler_Reconcile", func() {
 			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, &mod).Return(currentNMCs, nil),
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
 			mockReconHelper.EXPECT().disableModuleOnNode(ctx, mod.Namespace, mod.Name, node.Name).Return(nil),
-			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes).Return(nil),
+			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes, unscheduledNodes{}).Return(nil),
 		)
 
 		res, err := mnr.Reconcile(ctx, req)
@@ -550,7 +585,7 @@ var _ = Describe("prepareSchedulingData", func() {
 
 	It("failed to determine mld", func() {
 		currentNMCs := sets.New[string](nodeName)
-		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(nil, fmt.Errorf("some error"))
+		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &node).Return(nil, fmt.Errorf("some error"))
 
 		scheduleData, errs := mnrh.prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs)
 
@@ -562,13 +597,13 @@ var _ = Describe("prepareSchedulingData", func() {
 		"mld for kernel version does not exists",
 		func(moduleCurrentlyEnabled bool, expectedAction string) {
 			currentNMCs := sets.New[string]()
-			expectedScheduleData := map[string]schedulingData{nodeName: {action: expectedAction}}
+			expectedScheduleData := map[string]schedulingData{nodeName: {action: expectedAction, noKernelMapping: true}}
 
 			if moduleCurrentlyEnabled {
 				currentNMCs.Insert(nodeName)
 			}
 
-			mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(nil, module.ErrNoMatchingKernelMapping)
+			mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &node).Return(nil, module.ErrNoMatchingKernelMapping)
 
 			scheduleData, errs := mnrh.prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs)
 
@@ -582,7 +617,7 @@ var _ = Describe("prepareSchedulingData", func() {
 
 	It("mld exists", func() {
 		currentNMCs := sets.New[string](nodeName)
-		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil)
+		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &node).Return(&mld, nil)
 
 		scheduleData, errs := mnrh.prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs)
 
@@ -591,9 +626,27 @@ var _ = Describe("prepareSchedulingData", func() {
 		Expect(scheduleData).To(Equal(expectedScheduleData))
 	})
 
+	It("should limit updates when the update strategy is RollingUpdate", func() {
+		rollingMod := kmmv1beta1.Module{
+			Spec: kmmv1beta1.ModuleSpec{
+				UpdateStrategy: &kmmv1beta1.ModuleUpdateStrategy{Type: kmmv1beta1.RollingUpdateModuleUpdateStrategyType},
+			},
+		}
+
+		gomock.InOrder(
+			mockKernel.EXPECT().GetModuleLoaderDataForKernel(&rollingMod, &node).Return(&mld, nil),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("some error")),
+		)
+
+		scheduleData, errs := mnrh.prepareSchedulingData(ctx, &rollingMod, targetedNodes, sets.New[string](nodeName))
+
+		Expect(errs).To(HaveLen(1))
+		Expect(scheduleData).To(HaveKeyWithValue(nodeName, schedulingData{heldBack: true}))
+	})
+
 	It("mld exists, nmc exists for other node", func() {
 		currentNMCs := sets.New[string]("some other node")
-		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil)
+		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &node).Return(&mld, nil)
 
 		scheduleData, errs := mnrh.prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs)
 
@@ -604,7 +657,7 @@ var _ = Describe("prepareSchedulingData", func() {
 
 	It("failed to determine mld for one of the nodes/nmcs", func() {
 		currentNMCs := sets.New[string]("some other node")
-		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(nil, fmt.Errorf("some error"))
+		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &node).Return(nil, fmt.Errorf("some error"))
 
 		scheduleData, errs := mnrh.prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs)
 
@@ -629,8 +682,8 @@ var _ = Describe("prepareSchedulingData", func() {
 		otherNodeMLD.KernelVersion = otherNodeKernelVersion
 
 		gomock.InOrder(
-			mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil),
-			mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, otherNodeKernelVersion).Return(&otherNodeMLD, nil),
+			mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &node).Return(&mld, nil),
+			mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &otherNode).Return(&otherNodeMLD, nil),
 		)
 
 		scheduleData, errs := mnrh.prepareSchedulingData(ctx, &mod, targetedNodes, sets.New[string]())
@@ -649,7 +702,7 @@ var _ = Describe("prepareSchedulingData", func() {
 		targetedNodes[0] = node
 		currentNMCs := sets.New[string](nodeName)
 		mld.ModuleVersion = "moduleVersion1"
-		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil)
+		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &node).Return(&mld, nil)
 
 		scheduleData, errs := mnrh.prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs)
 
@@ -662,7 +715,7 @@ var _ = Describe("prepareSchedulingData", func() {
 		targetedNodes[0] = node
 		currentNMCs := sets.New[string](nodeName)
 		mld.ModuleVersion = "moduleVersion2"
-		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil)
+		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &node).Return(&mld, nil)
 
 		scheduleData, errs := mnrh.prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs)
 
@@ -673,7 +726,7 @@ var _ = Describe("prepareSchedulingData", func() {
 	It("module version exists, moduleLoader version label does not exist", func() {
 		currentNMCs := sets.New[string](nodeName)
 		mld.ModuleVersion = "moduleVersion2"
-		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, kernelVersion).Return(&mld, nil)
+		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, &node).Return(&mld, nil)
 
 		scheduleData, errs := mnrh.prepareSchedulingData(ctx, &mod, targetedNodes, currentNMCs)
 
@@ -682,6 +735,221 @@ var _ = Describe("prepareSchedulingData", func() {
 	})
 })
 
+var _ = Describe("limitUpdates", func() {
+	const (
+		moduleName      = "moduleName"
+		moduleNamespace = "moduleNamespace"
+	)
+
+	var (
+		ctx        context.Context
+		ctrl       *gomock.Controller
+		clnt       *client.MockClient
+		mnrh       *moduleNMCReconcilerHelper
+		mod        kmmv1beta1.Module
+		oldMLD     api.ModuleLoaderData
+		newMLD     api.ModuleLoaderData
+		nodes      []v1.Node
+		nodeNames  = []string{"node-a", "node-b", "node-c"}
+		oldConfig  kmmv1beta1.ModuleConfig
+		newConfig  kmmv1beta1.ModuleConfig
+		configured []kmmv1beta1.NodeModulesConfig
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mnrh = &moduleNMCReconcilerHelper{client: clnt, nmcHelper: nmc.NewHelper(clnt)}
+		mod = kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Name: moduleName, Namespace: moduleNamespace},
+			Spec: kmmv1beta1.ModuleSpec{
+				UpdateStrategy: &kmmv1beta1.ModuleUpdateStrategy{Type: kmmv1beta1.RollingUpdateModuleUpdateStrategyType},
+			},
+		}
+		oldMLD = api.ModuleLoaderData{Name: moduleName, Namespace: moduleNamespace, ContainerImage: "old-image"}
+		newMLD = api.ModuleLoaderData{Name: moduleName, Namespace: moduleNamespace, ContainerImage: "new-image"}
+		oldConfig = moduleConfigFromMLD(&oldMLD)
+		newConfig = moduleConfigFromMLD(&newMLD)
+
+		// Listed in reverse order to check that nodes are processed by name.
+		nodes = nil
+		for i := len(nodeNames) - 1; i >= 0; i-- {
+			nodes = append(nodes, v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeNames[i]}})
+		}
+
+		configured = nil
+		for _, name := range nodeNames {
+			configured = append(configured, kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: kmmv1beta1.NodeModulesConfigSpec{
+					Modules: []kmmv1beta1.NodeModuleSpec{
+						{
+							ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: moduleNamespace},
+							Config:     oldConfig,
+						},
+					},
+				},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					Modules: []kmmv1beta1.NodeModuleStatus{
+						{
+							ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: moduleNamespace},
+							Config:     &oldConfig,
+						},
+					},
+				},
+			})
+		}
+	})
+
+	newSDMap := func() map[string]schedulingData {
+		sdMap := make(map[string]schedulingData)
+
+		for i := range nodes {
+			sdMap[nodes[i].Name] = schedulingData{action: actionAdd, mld: &newMLD, node: &nodes[i]}
+		}
+
+		return sdMap
+	}
+
+	expectList := func(nmcs []kmmv1beta1.NodeModulesConfig) {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+				list.Items = nmcs
+				return nil
+			},
+		)
+	}
+
+	updatedNodes := func(sdMap map[string]schedulingData) []string {
+		names := make([]string, 0)
+
+		for name, sd := range sdMap {
+			if sd.action == actionAdd {
+				names = append(names, name)
+			}
+		}
+
+		return names
+	}
+
+	heldBackNodes := func(sdMap map[string]schedulingData) []string {
+		names := make([]string, 0)
+
+		for name, sd := range sdMap {
+			if sd.heldBack {
+				names = append(names, name)
+			}
+		}
+
+		return names
+	}
+
+	It("should hold back all updates if the NMCs cannot be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+
+		sdMap := newSDMap()
+		sdMap["other-node"] = schedulingData{action: actionDelete}
+
+		Expect(
+			mnrh.limitUpdates(ctx, &mod, nodes, sdMap),
+		).To(
+			HaveOccurred(),
+		)
+
+		Expect(updatedNodes(sdMap)).To(BeEmpty())
+		Expect(heldBackNodes(sdMap)).To(ConsistOf(nodeNames))
+		Expect(sdMap).To(HaveKeyWithValue("other-node", schedulingData{action: actionDelete}))
+	})
+
+	It("should not hold back nodes on which the module is not configured yet", func() {
+		expectList(nil)
+
+		sdMap := newSDMap()
+
+		Expect(
+			mnrh.limitUpdates(ctx, &mod, nodes, sdMap),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(updatedNodes(sdMap)).To(ConsistOf(nodeNames))
+	})
+
+	DescribeTable(
+		"should roll out the new config to a bounded number of nodes",
+		func(ru *kmmv1beta1.RollingUpdateModule, mutate func(nmcs []kmmv1beta1.NodeModulesConfig), expected ...string) {
+			mod.Spec.UpdateStrategy.RollingUpdate = ru
+
+			if mutate != nil {
+				mutate(configured)
+			}
+
+			expectList(configured)
+
+			sdMap := newSDMap()
+
+			Expect(
+				mnrh.limitUpdates(ctx, &mod, nodes, sdMap),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(updatedNodes(sdMap)).To(ConsistOf(expected))
+			Expect(heldBackNodes(sdMap)).To(ConsistOf(sets.List(sets.New(nodeNames...).Delete(expected...))))
+		},
+		Entry("default maxUnavailable", nil, nil, "node-a"),
+		Entry(
+			"maxUnavailable as a count",
+			&kmmv1beta1.RollingUpdateModule{MaxUnavailable: ptr.To(intstr.FromInt(2))},
+			nil,
+			"node-a",
+			"node-b",
+		),
+		Entry(
+			"maxUnavailable as a percentage, rounded up",
+			&kmmv1beta1.RollingUpdateModule{MaxUnavailable: ptr.To(intstr.FromString("50%"))},
+			nil,
+			"node-a",
+			"node-b",
+		),
+		Entry(
+			"partition",
+			&kmmv1beta1.RollingUpdateModule{Partition: ptr.To[int32](1)},
+			nil,
+			"node-b",
+		),
+		Entry(
+			"a node is already unavailable",
+			nil,
+			func(nmcs []kmmv1beta1.NodeModulesConfig) { nmcs[1].Status.Modules = nil },
+			"node-b",
+		),
+		Entry(
+			"a node never loaded the module",
+			nil,
+			func(nmcs []kmmv1beta1.NodeModulesConfig) { nmcs[1].Status.Modules[0].Config = nil },
+			"node-b",
+		),
+		Entry(
+			"a node is still loading the new config",
+			nil,
+			func(nmcs []kmmv1beta1.NodeModulesConfig) { nmcs[0].Spec.Modules[0].Config = newConfig },
+			"node-a",
+		),
+		Entry(
+			"a node has loaded the new config",
+			nil,
+			func(nmcs []kmmv1beta1.NodeModulesConfig) {
+				nmcs[0].Spec.Modules[0].Config = newConfig
+				nmcs[0].Status.Modules[0].Config = &newConfig
+			},
+			"node-a",
+			"node-b",
+		),
+	)
+})
+
 var _ = Describe("enableModuleOnNode", func() {
 	const (
 		moduleNamespace = "moduleNamespace"
@@ -729,14 +997,41 @@ var _ = Describe("enableModuleOnNode", func() {
 	})
 
 	It("Image does not exists", func() {
-		rgst.EXPECT().ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any()).Return(false, nil)
-		err := mnrh.enableModuleOnNode(ctx, mld, &node)
+		rgst.EXPECT().ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any(), nil).Return(false, nil)
+		configured, err := mnrh.enableModuleOnNode(ctx, mld, &node)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configured).To(BeFalse())
+	})
+
+	It("should check the image for the node's platform", func() {
+		node.Status.NodeInfo = v1.NodeSystemInfo{OperatingSystem: "linux", Architecture: "arm64"}
+
+		rgst.
+			EXPECT().
+			ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any(), &ggcrv1.Platform{OS: "linux", Architecture: "arm64"}).
+			Return(false, nil)
+
+		_, err := mnrh.enableModuleOnNode(ctx, mld, &node)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mld.Platform).To(BeEmpty())
+	})
+
+	It("should check the image for the pinned platform", func() {
+		node.Status.NodeInfo = v1.NodeSystemInfo{OperatingSystem: "linux", Architecture: "arm64"}
+		mld.Platform = "linux/amd64"
+
+		rgst.
+			EXPECT().
+			ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any(), &ggcrv1.Platform{OS: "linux", Architecture: "amd64"}).
+			Return(false, nil)
+
+		_, err := mnrh.enableModuleOnNode(ctx, mld, &node)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("Failed to check if image exists", func() {
-		rgst.EXPECT().ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any()).Return(false, fmt.Errorf("some error"))
-		err := mnrh.enableModuleOnNode(ctx, mld, &node)
+		rgst.EXPECT().ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any(), nil).Return(false, fmt.Errorf("some error"))
+		_, err := mnrh.enableModuleOnNode(ctx, mld, &node)
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -746,14 +1041,15 @@ var _ = Describe("enableModuleOnNode", func() {
 		}
 
 		gomock.InOrder(
-			rgst.EXPECT().ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any()).Return(true, nil),
+			rgst.EXPECT().ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any(), nil).Return(true, nil),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
 			helper.EXPECT().SetModuleConfig(nmc, mld, expectedModuleConfig).Return(nil),
 			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
 		)
 
-		err := mnrh.enableModuleOnNode(ctx, mld, &node)
+		configured, err := mnrh.enableModuleOnNode(ctx, mld, &node)
 		Expect(err).NotTo(HaveOccurred())
+		Expect(configured).To(BeTrue())
 	})
 
 	It("NMC exists", func() {
@@ -774,7 +1070,7 @@ var _ = Describe("enableModuleOnNode", func() {
 		)
 
 		gomock.InOrder(
-			rgst.EXPECT().ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any()).Return(true, nil),
+			rgst.EXPECT().ImageExists(ctx, mld.ContainerImage, gomock.Any(), gomock.Any(), nil).Return(true, nil),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
 				func(_ interface{}, _ interface{}, nmc *kmmv1beta1.NodeModulesConfig, _ ...ctrlclient.GetOption) error {
 					nmc.SetName(node.Name)
@@ -785,8 +1081,9 @@ var _ = Describe("enableModuleOnNode", func() {
 			clnt.EXPECT().Patch(ctx, &nmcWithLabels, gomock.Any()).Return(nil),
 		)
 
-		err := mnrh.enableModuleOnNode(ctx, mld, &node)
+		configured, err := mnrh.enableModuleOnNode(ctx, mld, &node)
 		Expect(err).NotTo(HaveOccurred())
+		Expect(configured).To(BeTrue())
 	})
 })
 
@@ -946,7 +1243,7 @@ var _ = Describe("moduleUpdateWorkerPodsStatus", func() {
 
 	It("faled to get configured NMCs", func() {
 		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
-		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, nil)
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, nil, unscheduledNodes{})
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -968,11 +1265,12 @@ var _ = Describe("moduleUpdateWorkerPodsStatus", func() {
 			),
 			helper.EXPECT().GetModuleSpecEntry(&nmc1, mod.Namespace, mod.Name).Return(nil, 0),
 			clnt.EXPECT().Status().Return(statusWriter),
-			statusWriter.EXPECT().Patch(ctx, expectedMod, gomock.Any()),
+			statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()),
 		)
 
-		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes)
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes, unscheduledNodes{})
 		Expect(err).NotTo(HaveOccurred())
+		Expect(mod.Status.ModuleLoader).To(Equal(expectedMod.Status.ModuleLoader))
 	})
 
 	DescribeTable("module present in spec", func(numTargetedNodes int,
@@ -1000,9 +1298,9 @@ var _ = Describe("moduleUpdateWorkerPodsStatus", func() {
 		}
 		nmcModuleStatus := kmmv1beta1.NodeModuleStatus{}
 		if configsEqual {
-			nmcModuleStatus.Config = moduleConfig1
+			nmcModuleStatus.Config = &moduleConfig1
 		} else {
-			nmcModuleStatus.Config = moduleConfig2
+			nmcModuleStatus.Config = &moduleConfig2
 		}
 		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
 			func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
@@ -1017,10 +1315,11 @@ var _ = Describe("moduleUpdateWorkerPodsStatus", func() {
 			helper.EXPECT().GetModuleStatusEntry(&nmc1, mod.Namespace, mod.Name).Return(nil)
 		}
 		clnt.EXPECT().Status().Return(statusWriter)
-		statusWriter.EXPECT().Patch(ctx, expectedMod, gomock.Any())
+		statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any())
 
-		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes)
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes, unscheduledNodes{})
 		Expect(err).NotTo(HaveOccurred())
+		Expect(mod.Status.ModuleLoader).To(Equal(expectedMod.Status.ModuleLoader))
 	},
 		Entry("2 targeted nodes, module not in status", 2, false, false, 2, 1, 0),
 		Entry("3 targeted nodes, module in status, configs not equal", 2, true, false, 2, 1, 0),
@@ -1034,7 +1333,7 @@ var _ = Describe("moduleUpdateWorkerPodsStatus", func() {
 			Config: moduleConfig1,
 		}
 		nmcModuleStatus := kmmv1beta1.NodeModuleStatus{
-			Config: moduleConfig1,
+			Config: &moduleConfig1,
 		}
 		nmc1 := kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: "nmc1"},
@@ -1054,11 +1353,243 @@ var _ = Describe("moduleUpdateWorkerPodsStatus", func() {
 			helper.EXPECT().GetModuleSpecEntry(&nmc1, mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0),
 			helper.EXPECT().GetModuleStatusEntry(&nmc1, mod.Namespace, mod.Name).Return(&nmcModuleStatus),
 			clnt.EXPECT().Status().Return(statusWriter),
-			statusWriter.EXPECT().Patch(ctx, expectedMod, gomock.Any()),
+			statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()),
+		)
+
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes, unscheduledNodes{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mod.Status.ModuleLoader).To(Equal(expectedMod.Status.ModuleLoader))
+	})
+
+	It("should summarize the state of the nodes and set the conditions", func() {
+		mod.Generation = 3
+		moduleConfig1 := kmmv1beta1.ModuleConfig{ContainerImage: "some image1"}
+		moduleConfig2 := kmmv1beta1.ModuleConfig{ContainerImage: "some image2"}
+		nmcModuleSpec := kmmv1beta1.NodeModuleSpec{Config: moduleConfig1}
+		loadedStatus := kmmv1beta1.NodeModuleStatus{Config: &moduleConfig1}
+		failedStatus := kmmv1beta1.NodeModuleStatus{
+			Config: &moduleConfig2,
+			Conditions: []metav1.Condition{
+				{
+					Type:    kmmv1beta1.NodeModuleConditionLoaded,
+					Status:  metav1.ConditionFalse,
+					Reason:  "UnknownSymbol",
+					Message: "some message",
+				},
+			},
+		}
+		nmcLoaded := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "loaded"}}
+		nmcFailed := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "failed"}}
+		nmcProgressing := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "progressing"}}
+		nmcWaiting := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "waiting"}}
+		targetedNodes := make([]v1.Node, 5)
+		unscheduled := unscheduledNodes{
+			noKernelMapping: []string{"unmapped"},
+			waitingForImage: []string{"waiting"},
+		}
+
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+				list.Items = []kmmv1beta1.NodeModulesConfig{nmcLoaded, nmcFailed, nmcProgressing, nmcWaiting}
+				return nil
+			},
+		)
+		helper.EXPECT().GetModuleSpecEntry(gomock.Any(), mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0).Times(4)
+		helper.EXPECT().GetModuleStatusEntry(&nmcLoaded, mod.Namespace, mod.Name).Return(&loadedStatus)
+		helper.EXPECT().GetModuleStatusEntry(&nmcFailed, mod.Namespace, mod.Name).Return(&failedStatus)
+		helper.EXPECT().GetModuleStatusEntry(&nmcProgressing, mod.Namespace, mod.Name).Return(nil)
+		helper.EXPECT().GetModuleStatusEntry(&nmcWaiting, mod.Namespace, mod.Name).Return(nil)
+		clnt.EXPECT().Status().Return(statusWriter)
+		statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any())
+
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes, unscheduled)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mod.Status.ModuleLoader.AvailableNumber).To(BeEquivalentTo(1))
+		Expect(mod.Status.Nodes).To(Equal(kmmv1beta1.ModuleNodesStatus{
+			NoMatchingKernelMapping: kmmv1beta1.NodeList{Total: 1, Names: []string{"unmapped"}},
+			WaitingForImage:         kmmv1beta1.NodeList{Total: 1, Names: []string{"waiting"}},
+			Failed: kmmv1beta1.FailedNodeList{
+				Total: 1,
+				Nodes: []kmmv1beta1.NodeFailure{{Name: "failed", Reason: "UnknownSymbol", Message: "some message"}},
+			},
+			Progressing: kmmv1beta1.NodeList{Total: 1, Names: []string{"progressing"}},
+		}))
+
+		conds := mod.Status.Conditions
+		Expect(apimeta.IsStatusConditionFalse(conds, kmmv1beta1.ModuleConditionReady)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionTrue(conds, kmmv1beta1.ModuleConditionProgressing)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionTrue(conds, kmmv1beta1.ModuleConditionDegraded)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionTrue(conds, kmmv1beta1.ModuleConditionNoMatchingKernelMapping)).To(BeTrue())
+		Expect(
+			apimeta.FindStatusCondition(conds, kmmv1beta1.ModuleConditionReady).ObservedGeneration,
+		).To(BeEquivalentTo(3))
+	})
+
+	It("should report a node on which the module never loaded as failed", func() {
+		moduleConfig := kmmv1beta1.ModuleConfig{ContainerImage: "some image"}
+		nmcModuleSpec := kmmv1beta1.NodeModuleSpec{Config: moduleConfig}
+		nmcModuleStatus := kmmv1beta1.NodeModuleStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:    kmmv1beta1.NodeModuleConditionLoaded,
+					Status:  metav1.ConditionFalse,
+					Reason:  "ImagePull",
+					Message: "some message",
+				},
+			},
+		}
+		nmc1 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "nmc1"}}
+
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+					list.Items = []kmmv1beta1.NodeModulesConfig{nmc1}
+					return nil
+				},
+			),
+			helper.EXPECT().GetModuleSpecEntry(&nmc1, mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0),
+			helper.EXPECT().GetModuleStatusEntry(&nmc1, mod.Namespace, mod.Name).Return(&nmcModuleStatus),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()),
+		)
+
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, []v1.Node{{}}, unscheduledNodes{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mod.Status.ModuleLoader.AvailableNumber).To(BeZero())
+		Expect(mod.Status.Nodes).To(Equal(kmmv1beta1.ModuleNodesStatus{
+			Failed: kmmv1beta1.FailedNodeList{
+				Total: 1,
+				Nodes: []kmmv1beta1.NodeFailure{{Name: "nmc1", Reason: "ImagePull", Message: "some message"}},
+			},
+		}))
+		Expect(apimeta.IsStatusConditionTrue(mod.Status.Conditions, kmmv1beta1.ModuleConditionDegraded)).To(BeTrue())
+	})
+
+	It("should be ready when the module is loaded on all nodes", func() {
+		moduleConfig := kmmv1beta1.ModuleConfig{ContainerImage: "some image"}
+		nmcModuleSpec := kmmv1beta1.NodeModuleSpec{Config: moduleConfig}
+		nmcModuleStatus := kmmv1beta1.NodeModuleStatus{Config: &moduleConfig}
+		nmc1 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "nmc1"}}
+
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+					list.Items = []kmmv1beta1.NodeModulesConfig{nmc1}
+					return nil
+				},
+			),
+			helper.EXPECT().GetModuleSpecEntry(&nmc1, mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0),
+			helper.EXPECT().GetModuleStatusEntry(&nmc1, mod.Namespace, mod.Name).Return(&nmcModuleStatus),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()),
+		)
+
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, []v1.Node{{}}, unscheduledNodes{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mod.Status.Nodes).To(Equal(kmmv1beta1.ModuleNodesStatus{}))
+
+		conds := mod.Status.Conditions
+		Expect(apimeta.IsStatusConditionTrue(conds, kmmv1beta1.ModuleConditionReady)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionFalse(conds, kmmv1beta1.ModuleConditionProgressing)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionFalse(conds, kmmv1beta1.ModuleConditionDegraded)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionFalse(conds, kmmv1beta1.ModuleConditionNoMatchingKernelMapping)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionFalse(conds, kmmv1beta1.ModuleConditionMissingDependencies)).To(BeTrue())
+	})
+
+	It("should report the nodes held back by the rolling update", func() {
+		oldConfig := kmmv1beta1.ModuleConfig{ContainerImage: "old image"}
+		nmcModuleSpec := kmmv1beta1.NodeModuleSpec{Config: oldConfig}
+		nmcModuleStatus := kmmv1beta1.NodeModuleStatus{Config: &oldConfig}
+		nmc1 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "nmc1"}}
+
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+					list.Items = []kmmv1beta1.NodeModulesConfig{nmc1}
+					return nil
+				},
+			),
+			helper.EXPECT().GetModuleSpecEntry(&nmc1, mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0),
+			helper.EXPECT().GetModuleStatusEntry(&nmc1, mod.Namespace, mod.Name).Return(&nmcModuleStatus),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()),
 		)
 
-		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes)
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, []v1.Node{{}}, unscheduledNodes{heldBack: []string{"nmc1"}})
 		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mod.Status.ModuleLoader.AvailableNumber).To(BeEquivalentTo(1))
+		Expect(mod.Status.Nodes).To(Equal(kmmv1beta1.ModuleNodesStatus{
+			HeldBack: kmmv1beta1.NodeList{Total: 1, Names: []string{"nmc1"}},
+		}))
+
+		conds := mod.Status.Conditions
+		Expect(apimeta.IsStatusConditionFalse(conds, kmmv1beta1.ModuleConditionReady)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionTrue(conds, kmmv1beta1.ModuleConditionProgressing)).To(BeTrue())
 	})
 
+	It("should report the nodes on which a dependency is not configured", func() {
+		moduleConfig := kmmv1beta1.ModuleConfig{ContainerImage: "some image"}
+		nmcModuleSpec := kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Dependencies: []kmmv1beta1.ModuleDependency{
+					{Name: "configured", Namespace: "depNamespace"},
+					{Name: "missing", Namespace: "depNamespace"},
+				},
+			},
+			Config: moduleConfig,
+		}
+		nmc1 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "nmc1"}}
+
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+					list.Items = []kmmv1beta1.NodeModulesConfig{nmc1}
+					return nil
+				},
+			),
+			helper.EXPECT().GetModuleSpecEntry(&nmc1, mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0),
+			helper.EXPECT().GetModuleStatusEntry(&nmc1, mod.Namespace, mod.Name).Return(nil),
+			helper.EXPECT().GetModuleSpecEntry(&nmc1, "depNamespace", "configured").Return(&kmmv1beta1.NodeModuleSpec{}, 0),
+			helper.EXPECT().GetModuleSpecEntry(&nmc1, "depNamespace", "missing").Return(nil, 0),
+			helper.EXPECT().GetModuleStatusEntry(&nmc1, "depNamespace", "missing").Return(nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()),
+		)
+
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, []v1.Node{{}}, unscheduledNodes{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mod.Status.Nodes).To(Equal(kmmv1beta1.ModuleNodesStatus{
+			MissingDependencies: kmmv1beta1.NodeList{Total: 1, Names: []string{"nmc1"}},
+		}))
+
+		conds := mod.Status.Conditions
+		Expect(apimeta.IsStatusConditionTrue(conds, kmmv1beta1.ModuleConditionMissingDependencies)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionFalse(conds, kmmv1beta1.ModuleConditionReady)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionFalse(conds, kmmv1beta1.ModuleConditionProgressing)).To(BeTrue())
+	})
+})
+
+var _ = Describe("boundedNodeList", func() {
+	It("should list the first nodes in name order", func() {
+		names := make([]string, 0, kmmv1beta1.MaxModuleStatusNodes+2)
+		for i := kmmv1beta1.MaxModuleStatusNodes + 1; i >= 0; i-- {
+			names = append(names, fmt.Sprintf("node%02d", i))
+		}
+
+		nl := boundedNodeList(names)
+
+		Expect(nl.Total).To(BeEquivalentTo(kmmv1beta1.MaxModuleStatusNodes + 2))
+		Expect(nl.Names).To(HaveLen(kmmv1beta1.MaxModuleStatusNodes))
+		Expect(nl.Names[0]).To(Equal("node00"))
+		Expect(nl.Names[kmmv1beta1.MaxModuleStatusNodes-1]).To(Equal("node09"))
+	})
+
+	It("should not list anything if there are no nodes", func() {
+		Expect(boundedNodeList(nil)).To(Equal(kmmv1beta1.NodeList{}))
+	})
 })
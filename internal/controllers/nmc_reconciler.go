@@ -4,21 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/drain"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/filter"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/meta"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mirror"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/nmc"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/localimage"
 	"github.com/mitchellh/hashstructure/v2"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,30 +46,48 @@ import (
 type WorkerAction string
 
 const (
-	WorkerActionLoad   = "Load"
-	WorkerActionUnload = "Unload"
+	WorkerActionLoad          = "Load"
+	WorkerActionSetParameters = "SetParameters"
+	WorkerActionUnload        = "Unload"
+	WorkerActionVerify        = "Verify"
 
 	NodeModulesConfigReconcilerName = "NodeModulesConfig"
 
 	actionLabelKey             = "kmm.node.kubernetes.io/worker-action"
 	configAnnotationKey        = "kmm.node.kubernetes.io/worker-config"
+	deadlineAnnotationKey      = "kmm.node.kubernetes.io/worker-deadline"
+	mirrorsAnnotationKey       = "kmm.node.kubernetes.io/registry-mirrors"
+	mirrorCAAnnotationPrefix   = "kmm.node.kubernetes.io/mirror-ca-"
 	hashAnnotationKey          = "kmm.node.kubernetes.io/worker-hash"
 	modulesOrderKey            = "kmm.node.kubernetes.io/modules-order"
 	nodeModulesConfigFinalizer = "kmm.node.kubernetes.io/nodemodulesconfig-reconciler"
 	volumeNameConfig           = "config"
 	workerContainerName        = "worker"
+
+	// loadBackoffBase is the delay before the first retry after a failed load; it doubles at each failed attempt.
+	loadBackoffBase = 10 * time.Second
+	loadBackoffMax  = 5 * time.Minute
+
+	// workerDeadlineGrace is added to the worker's own timeouts, so that it can report a timeout before the controller
+	// gives up on it.
+	workerDeadlineGrace = time.Minute
+
+	// drainPollInterval is the delay between two checks of the Pods being evicted from a node.
+	drainPollInterval = 10 * time.Second
 )
 
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=nodemodulesconfigs,verbs=get;list;watch
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=nodemodulesconfigs/status,verbs=patch
 //+kubebuilder:rbac:groups="core",resources=pods,verbs=create;delete;get;list;watch
-//+kubebuilder:rbac:groups="core",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="core",resources=pods/eviction,verbs=create
+//+kubebuilder:rbac:groups="core",resources=nodes,verbs=get;list;patch;watch
 //+kubebuilder:rbac:groups="core",resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups="core",resources=serviceaccounts,verbs=get;list;watch
 
 type NMCReconciler struct {
-	client client.Client
-	helper nmcReconcilerHelper
+	client         client.Client
+	helper         nmcReconcilerHelper
+	verifyInterval time.Duration
 }
 
 func NewNMCReconciler(
@@ -71,13 +95,15 @@ func NewNMCReconciler(
 	scheme *runtime.Scheme,
 	workerImage string,
 	workerCfg *config.Worker,
+	mirrors []mirror.Registry,
 	recorder record.EventRecorder,
 ) *NMCReconciler {
-	pm := newPodManager(client, workerImage, scheme, workerCfg)
-	helper := newNMCReconcilerHelper(client, pm, recorder)
+	pm := newPodManager(client, workerImage, scheme, workerCfg, mirrors)
+	helper := newNMCReconcilerHelper(client, pm, recorder, workerCfg, drain.NewDrainer(client))
 	return &NMCReconciler{
-		client: client,
-		helper: helper,
+		client:         client,
+		helper:         helper,
+		verifyInterval: workerCfg.VerifyInterval,
 	}
 }
 
@@ -148,6 +174,10 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 		}
 	}
 
+	if err := r.helper.FinishMaintenance(ctx, &nmcObj); err != nil {
+		errs = append(errs, fmt.Errorf("could not finish the maintenance of node %s: %v", req.Name, err))
+	}
+
 	if err := r.helper.GarbageCollectInUseLabels(ctx, &nmcObj); err != nil {
 		errs = append(errs, fmt.Errorf("failed to GC in-use labels for NMC %s: %v", req.NamespacedName, err))
 	}
@@ -156,7 +186,106 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 		errs = append(errs, fmt.Errorf("could not update node's labels for NMC %s: %v", req.NamespacedName, err))
 	}
 
-	return ctrl.Result{}, errors.Join(errs...)
+	res := ctrl.Result{}
+
+	if d, ok := nextLoadRetry(nmcObj.Status.Modules, time.Now()); ok {
+		logger.Info("Requeuing for the next load attempt", "after", d)
+		res.RequeueAfter = d
+	}
+
+	if d, ok := nextVerification(nmcObj.Status.Modules, r.verifyInterval, time.Now()); ok && (res.RequeueAfter == 0 || d < res.RequeueAfter) {
+		logger.Info("Requeuing for the next verification", "after", d)
+		res.RequeueAfter = d
+	}
+
+	// Evicted Pods are not watched.
+	if draining(nmcObj.Status.Modules) && (res.RequeueAfter == 0 || drainPollInterval < res.RequeueAfter) {
+		logger.Info("Requeuing to check the progress of the drain", "after", drainPollInterval)
+		res.RequeueAfter = drainPollInterval
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// nextLoadRetry returns the time until the earliest NextRetryTime in the future, and false if there is none.
+func nextLoadRetry(statuses []kmmv1beta1.NodeModuleStatus, now time.Time) (time.Duration, bool) {
+	var (
+		found bool
+		next  time.Duration
+	)
+
+	for _, s := range statuses {
+		if s.NextRetryTime == nil || !s.NextRetryTime.After(now) {
+			continue
+		}
+
+		if d := s.NextRetryTime.Sub(now); !found || d < next {
+			found = true
+			next = d
+		}
+	}
+
+	return next, found
+}
+
+// nextVerification returns the time until the earliest verification in the future of a loaded module, and false if
+// there is none.
+func nextVerification(statuses []kmmv1beta1.NodeModuleStatus, interval time.Duration, now time.Time) (time.Duration, bool) {
+	var (
+		found bool
+		next  time.Duration
+	)
+
+	for i := range statuses {
+		t, ok := verificationTime(&statuses[i], interval)
+		if !ok || !t.After(now) {
+			continue
+		}
+
+		if d := t.Sub(now); !found || d < next {
+			found = true
+			next = d
+		}
+	}
+
+	return next, found
+}
+
+// verificationTime returns the time at which the module of status should be verified next, and false if it should not
+// be verified.
+// Loading a module counts as a verification.
+func verificationTime(status *kmmv1beta1.NodeModuleStatus, interval time.Duration) (time.Time, bool) {
+	if interval <= 0 || status.Config == nil {
+		return time.Time{}, false
+	}
+
+	last := status.LastTransitionTime
+
+	if status.LastVerifiedTime != nil && status.LastVerifiedTime.After(last.Time) {
+		last = *status.LastVerifiedTime
+	}
+
+	return last.Add(interval), true
+}
+
+// moduleDrifted returns whether the last verification found that the module of status was no longer loaded.
+// Verifications that failed for another reason, for instance because the verifier Pod was evicted, are not drift: they
+// are only reported in the Verified condition.
+func moduleDrifted(status *kmmv1beta1.NodeModuleStatus) bool {
+	cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionVerified)
+
+	return cond != nil && cond.Status == metav1.ConditionFalse && cond.Reason == string(worker.ReasonModuleNotLoaded)
+}
+
+// draining returns true if Pods are still being evicted from the node before a module is unloaded.
+func draining(statuses []kmmv1beta1.NodeModuleStatus) bool {
+	for _, s := range statuses {
+		if apimeta.IsStatusConditionFalse(s.Conditions, kmmv1beta1.NodeModuleConditionDrained) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (r *NMCReconciler) SetupWithManager(ctx context.Context, mgr manager.Manager) error {
@@ -218,6 +347,7 @@ func FindNodeCondition(cond []v1.NodeCondition, conditionType v1.NodeConditionTy
 //go:generate mockgen -source=nmc_reconciler.go -package=controllers -destination=mock_nmc_reconciler.go workerHelper
 
 type nmcReconcilerHelper interface {
+	FinishMaintenance(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig) error
 	GarbageCollectInUseLabels(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig) error
 	ProcessModuleSpec(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, spec *kmmv1beta1.NodeModuleSpec, status *kmmv1beta1.NodeModuleStatus) error
 	ProcessUnconfiguredModuleStatus(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, status *kmmv1beta1.NodeModuleStatus) error
@@ -227,17 +357,111 @@ type nmcReconcilerHelper interface {
 }
 
 type nmcReconcilerHelperImpl struct {
-	client   client.Client
-	pm       podManager
-	recorder record.EventRecorder
+	client    client.Client
+	drainer   drain.Drainer
+	pm        podManager
+	recorder  record.EventRecorder
+	workerCfg *config.Worker
 }
 
-func newNMCReconcilerHelper(client client.Client, pm podManager, recorder record.EventRecorder) nmcReconcilerHelper {
+func newNMCReconcilerHelper(
+	client client.Client,
+	pm podManager,
+	recorder record.EventRecorder,
+	workerCfg *config.Worker,
+	drainer drain.Drainer,
+) nmcReconcilerHelper {
 	return &nmcReconcilerHelperImpl{
-		client:   client,
-		pm:       pm,
-		recorder: recorder,
+		client:    client,
+		drainer:   drainer,
+		pm:        pm,
+		recorder:  recorder,
+		workerCfg: workerCfg,
+	}
+}
+
+// FinishMaintenance removes the Drained condition of the modules that are loaded with their current config, and
+// uncordons the node if KMM cordoned it and no module with a maintenance policy is still waiting to be unloaded, or
+// loaded with its new config.
+// A config that failed to load the maximum number of times is not waited for, as it is not retried until it changes.
+func (h *nmcReconcilerHelperImpl) FinishMaintenance(ctx context.Context, nmcObj *kmmv1beta1.NodeModulesConfig) error {
+	patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+	changed := false
+	pending := false
+
+	specs := make(map[types.NamespacedName]*kmmv1beta1.NodeModuleSpec, len(nmcObj.Spec.Modules))
+
+	for i := 0; i < len(nmcObj.Spec.Modules); i++ {
+		spec := &nmcObj.Spec.Modules[i]
+
+		specs[types.NamespacedName{Namespace: spec.Namespace, Name: spec.Name}] = spec
+
+		if spec.MaintenancePolicy == nil {
+			continue
+		}
+
+		status := nmc.FindModuleStatus(nmcObj.Status.Modules, spec.Namespace, spec.Name)
+		if status != nil && status.Config != nil && reflect.DeepEqual(spec.Config, *status.Config) {
+			continue
+		}
+
+		exhausted, err := loadAttemptsExhausted(spec, status)
+		if err != nil {
+			return err
+		}
+
+		if exhausted {
+			ctrl.LoggerFrom(ctx).Info(
+				utils.WarnString("Module failed to load too many times; not waiting for it to uncordon the node"),
+				"module",
+				spec.Namespace+"/"+spec.Name,
+			)
+
+			continue
+		}
+
+		pending = true
+	}
+
+	for i := 0; i < len(nmcObj.Status.Modules); i++ {
+		status := &nmcObj.Status.Modules[i]
+
+		spec := specs[types.NamespacedName{Namespace: status.Namespace, Name: status.Name}]
+		if spec == nil {
+			if status.MaintenancePolicy != nil && status.Config != nil {
+				pending = true
+			}
+
+			continue
+		}
+
+		if status.Config != nil && reflect.DeepEqual(spec.Config, *status.Config) {
+			changed = apimeta.RemoveStatusCondition(&status.Conditions, kmmv1beta1.NodeModuleConditionDrained) || changed
+		}
+	}
+
+	if !pending && apimeta.IsStatusConditionTrue(nmcObj.Status.Conditions, kmmv1beta1.NodeModulesConfigConditionCordoned) {
+		node := v1.Node{}
+
+		if err := h.client.Get(ctx, types.NamespacedName{Name: nmcObj.Name}, &node); err != nil {
+			return fmt.Errorf("could not get node %s: %v", nmcObj.Name, err)
+		}
+
+		ctrl.LoggerFrom(ctx).Info("Maintenance is over; uncordoning the node")
+
+		if err := h.drainer.Uncordon(ctx, &node); err != nil {
+			return err
+		}
+
+		apimeta.RemoveStatusCondition(&nmcObj.Status.Conditions, kmmv1beta1.NodeModulesConfigConditionCordoned)
+		changed = true
 	}
+
+	if !changed {
+		return nil
+	}
+
+	return h.client.Status().Patch(ctx, nmcObj, patchFrom)
 }
 
 // GarbageCollectInUseLabels removes all module-in-use labels for which there is no corresponding entry either in
@@ -293,13 +517,18 @@ func (h *nmcReconcilerHelperImpl) GarbageCollectInUseLabels(ctx context.Context,
 // ProcessModuleSpec determines if a worker Pod should be created for a Module entry in a
 // NodeModulesConfig .spec.modules.
 // A loading worker pod is created when:
-//   - there is no corresponding entry in the NodeModulesConfig's .status.modules list;
+//   - there is no corresponding entry in the NodeModulesConfig's .status.modules list, or that entry has no config
+//     because the module could not be loaded yet;
 //   - the lastTransitionTime property in the .status.modules entry is older that the last transition time
 //     of the Ready condition on the node. This makes sure that we always load modules after maintenance operations
 //     that would make a node not Ready, such as a reboot.
 //
+// Loading waits until all the Modules listed in the entry's dependencies are loaded.
+//
 // An unloading worker Pod is created when the entry in .spec.modules has a different config compared to the entry in
-// .status.modules.
+// .status.modules, once no loaded module depends on it anymore, or when one of its dependencies must be reloaded.
+// If only parameters were changed or added, a worker Pod first tries to update them through sysfs without reloading
+// the module; the module is reloaded if that fails.
 func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 	ctx context.Context,
 	nmcObj *kmmv1beta1.NodeModulesConfig,
@@ -316,33 +545,101 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 	}
 
 	if pod == nil {
-		if status == nil {
+		if status == nil || status.Config == nil {
+			if ok, err := h.loadAllowed(ctx, spec, status); err != nil || !ok {
+				return err
+			}
+
+			if ok, err := h.dependenciesLoaded(ctx, nmcObj, spec); err != nil || !ok {
+				return err
+			}
+
 			logger.Info("Missing status; creating loader Pod")
 			return h.pm.CreateLoaderPod(ctx, nmcObj, spec)
 		}
 
-		if !reflect.DeepEqual(spec.Config, status.Config) {
+		if !reflect.DeepEqual(spec.Config, *status.Config) {
+			names, err := parametersToUpdate(spec, status)
+			if err != nil {
+				return err
+			}
+
+			if len(names) > 0 {
+				logger.Info("Only parameters changed; creating Pod to update them", "parameters", names)
+				return h.pm.CreateParametersPod(ctx, nmcObj, spec, names)
+			}
+
+			if dependents := loadedDependents(nmcObj, status); len(dependents) > 0 {
+				logger.Info("Waiting for dependent modules to be unloaded", "dependents", dependents)
+				return nil
+			}
+
+			if ok, err := h.prepareNodeForUnload(ctx, nmcObj, status, spec.MaintenancePolicy); err != nil || !ok {
+				return err
+			}
+
 			logger.Info("Outdated config in status; creating unloader Pod")
 			return h.pm.CreateUnloaderPod(ctx, nmcObj, status)
 		}
 
+		reloading, err := reloadingDependencies(nmcObj, spec)
+		if err != nil {
+			return err
+		}
+
+		if len(reloading) > 0 {
+			if ok, err := h.prepareNodeForUnload(ctx, nmcObj, status, spec.MaintenancePolicy); err != nil || !ok {
+				return err
+			}
+
+			logger.Info("Dependencies must be reloaded; creating unloader Pod", "dependencies", reloading)
+			return h.pm.CreateUnloaderPod(ctx, nmcObj, status)
+		}
+
 		node := v1.Node{}
 
 		if err = h.client.Get(ctx, types.NamespacedName{Name: nmcObj.Name}, &node); err != nil {
 			return fmt.Errorf("could not get node %s: %v", nmcObj.Name, err)
 		}
 
-		readyCondition := FindNodeCondition(node.Status.Conditions, v1.NodeReady)
-		if readyCondition == nil {
-			return fmt.Errorf("node %s has no Ready condition", nmcObj.Name)
+		rebooted, err := nodeRebootedSinceLoad(&node, status)
+		if err != nil {
+			return err
 		}
 
-		if readyCondition.Status == v1.ConditionTrue && status.LastTransitionTime.Before(&readyCondition.LastTransitionTime) {
-			logger.Info("Outdated last transition time status; creating loader Pod")
+		if rebooted {
+			if ok, err := h.loadAllowed(ctx, spec, status); err != nil || !ok {
+				return err
+			}
+
+			if ok, err := h.dependenciesLoaded(ctx, nmcObj, spec); err != nil || !ok {
+				return err
+			}
+
+			logger.Info("Node rebooted since the module was loaded; creating loader Pod")
 
 			return h.pm.CreateLoaderPod(ctx, nmcObj, spec)
 		}
 
+		if moduleDrifted(status) && h.workerCfg.ReloadOnDrift {
+			if ok, err := h.loadAllowed(ctx, spec, status); err != nil || !ok {
+				return err
+			}
+
+			if ok, err := h.dependenciesLoaded(ctx, nmcObj, spec); err != nil || !ok {
+				return err
+			}
+
+			logger.Info("Module is no longer loaded; creating loader Pod")
+
+			return h.pm.CreateLoaderPod(ctx, nmcObj, spec)
+		}
+
+		if t, ok := verificationTime(status, h.workerCfg.VerifyInterval); ok && !t.After(time.Now()) {
+			logger.Info("Verification is due; creating verifier Pod")
+			return h.pm.CreateVerifierPod(ctx, nmcObj, status)
+		}
+
 		return nil
 	}
 
@@ -351,8 +648,9 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 		return nil
 	}
 
-	if GetContainerStatus(pod.Status.ContainerStatuses, workerContainerName).RestartCount == 0 {
-		logger.Info("Worker Loader Pod has not yet restarted; doing nothing")
+	// Loader Pods are never restarted; SyncStatus records the result of completed ones.
+	if phase := pod.Status.Phase; phase == v1.PodSucceeded || phase == v1.PodFailed {
+		logger.Info("Worker Loader Pod has completed; doing nothing")
 		return nil
 	}
 
@@ -369,13 +667,319 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 	return nil
 }
 
+// nodeRebootedSinceLoad returns whether node, once Ready, rebooted since the module of status was loaded.
+// The boot ID recorded at load time is authoritative; the transition time of the Ready condition is only used for
+// statuses recorded without one.
+func nodeRebootedSinceLoad(node *v1.Node, status *kmmv1beta1.NodeModuleStatus) (bool, error) {
+	readyCondition := FindNodeCondition(node.Status.Conditions, v1.NodeReady)
+	if readyCondition == nil {
+		return false, fmt.Errorf("node %s has no Ready condition", node.Name)
+	}
+
+	if readyCondition.Status != v1.ConditionTrue {
+		return false, nil
+	}
+
+	if bootID := node.Status.NodeInfo.BootID; status.BootID != "" && bootID != "" {
+		return bootID != status.BootID, nil
+	}
+
+	return status.LastTransitionTime.Before(&readyCondition.LastTransitionTime), nil
+}
+
+// parametersToUpdate returns the names of the parameters that must be updated through sysfs to go from status.Config
+// to spec.Config.
+// It returns nothing if the module must be reloaded instead: if anything other than parameters changed, if a parameter
+// was removed, if updating the parameters of spec.Config already failed, or if the module was never loaded.
+func parametersToUpdate(spec *kmmv1beta1.NodeModuleSpec, status *kmmv1beta1.NodeModuleStatus) ([]string, error) {
+	if status == nil || status.Config == nil {
+		return nil, nil
+	}
+
+	specCfg := spec.Config
+	statusCfg := *status.Config
+
+	if specCfg.Modprobe.RawArgs != nil || statusCfg.Modprobe.RawArgs != nil {
+		return nil, nil
+	}
+
+	specCfg.Modprobe.Parameters = nil
+	statusCfg.Modprobe.Parameters = nil
+
+	if !reflect.DeepEqual(specCfg, statusCfg) {
+		return nil, nil
+	}
+
+	names, ok := worker.ChangedParameters(status.Config.Modprobe.Parameters, spec.Config.Modprobe.Parameters)
+	if !ok {
+		return nil, nil
+	}
+
+	if status.FailedParametersConfigHash != "" {
+		hash, err := moduleConfigHash(&spec.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		if hash == status.FailedParametersConfigHash {
+			return nil, nil
+		}
+	}
+
+	return names, nil
+}
+
+// loadAttemptsExhausted returns true if loading spec.Config failed the maximum number of times.
+func loadAttemptsExhausted(spec *kmmv1beta1.NodeModuleSpec, status *kmmv1beta1.NodeModuleStatus) (bool, error) {
+	if status == nil || !apimeta.IsStatusConditionTrue(status.Conditions, kmmv1beta1.NodeModuleConditionFailed) {
+		return false, nil
+	}
+
+	hash, err := moduleConfigHash(&spec.Config)
+	if err != nil {
+		return false, err
+	}
+
+	return hash == status.FailedConfigHash, nil
+}
+
+// loadAllowed returns false if previous attempts at loading spec.Config failed and the next attempt should wait for
+// the backoff delay to expire, or should not happen at all because the maximum number of attempts was reached.
+func (h *nmcReconcilerHelperImpl) loadAllowed(
+	ctx context.Context,
+	spec *kmmv1beta1.NodeModuleSpec,
+	status *kmmv1beta1.NodeModuleStatus,
+) (bool, error) {
+	if status == nil || status.FailedAttempts == 0 {
+		return true, nil
+	}
+
+	logger := ctrl.LoggerFrom(ctx)
+
+	hash, err := moduleConfigHash(&spec.Config)
+	if err != nil {
+		return false, err
+	}
+
+	if hash != status.FailedConfigHash {
+		logger.Info("Config changed since the last failed load; not backing off")
+		return true, nil
+	}
+
+	if max := h.workerCfg.MaxLoadAttempts; max > 0 && status.FailedAttempts >= max {
+		logger.Info("Maximum number of load attempts reached; not loading", "attempts", status.FailedAttempts)
+		return false, nil
+	}
+
+	if status.NextRetryTime != nil && status.NextRetryTime.After(time.Now()) {
+		logger.Info("Backing off after a failed load", "attempts", status.FailedAttempts, "next retry", status.NextRetryTime)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// dependenciesLoaded returns false if any of the Modules spec depends on is not loaded on the node with its current
+// config yet.
+// A dependency loaded before the last reboot of the node is not considered loaded.
+func (h *nmcReconcilerHelperImpl) dependenciesLoaded(
+	ctx context.Context,
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	spec *kmmv1beta1.NodeModuleSpec,
+) (bool, error) {
+	if len(spec.Dependencies) == 0 {
+		return true, nil
+	}
+
+	node := v1.Node{}
+
+	if err := h.client.Get(ctx, types.NamespacedName{Name: nmcObj.Name}, &node); err != nil {
+		return false, fmt.Errorf("could not get node %s: %v", nmcObj.Name, err)
+	}
+
+	if pending := pendingDependencies(nmcObj, spec, node.Status.NodeInfo.BootID); len(pending) > 0 {
+		ctrl.LoggerFrom(ctx).Info("Waiting for dependencies to be loaded", "dependencies", pending)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// pendingDependencies returns the namespace/name of the dependencies of spec that are not loaded with their current
+// config since the node booted with bootID.
+func pendingDependencies(nmcObj *kmmv1beta1.NodeModulesConfig, spec *kmmv1beta1.NodeModuleSpec, bootID string) []string {
+	pending := make([]string, 0)
+
+	for _, dep := range spec.Dependencies {
+		status := nmc.FindModuleStatus(nmcObj.Status.Modules, dep.Namespace, dep.Name)
+
+		switch depSpec := findModuleSpec(nmcObj.Spec.Modules, dep.Namespace, dep.Name); {
+		case status == nil || status.Config == nil:
+		case depSpec != nil && !reflect.DeepEqual(depSpec.Config, *status.Config):
+		case status.BootID != "" && bootID != "" && status.BootID != bootID:
+		default:
+			continue
+		}
+
+		pending = append(pending, dep.Namespace+"/"+dep.Name)
+	}
+
+	return pending
+}
+
+// reloadingDependencies returns the namespace/name of the dependencies of spec that are loaded with an outdated config
+// and must be unloaded to be updated.
+// Modules that depend on them must be unloaded first.
+func reloadingDependencies(nmcObj *kmmv1beta1.NodeModulesConfig, spec *kmmv1beta1.NodeModuleSpec) ([]string, error) {
+	reloading := make([]string, 0)
+
+	for _, dep := range spec.Dependencies {
+		status := nmc.FindModuleStatus(nmcObj.Status.Modules, dep.Namespace, dep.Name)
+		if status == nil || status.Config == nil {
+			continue
+		}
+
+		depSpec := findModuleSpec(nmcObj.Spec.Modules, dep.Namespace, dep.Name)
+		if depSpec == nil || reflect.DeepEqual(depSpec.Config, *status.Config) {
+			continue
+		}
+
+		names, err := parametersToUpdate(depSpec, status)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(names) == 0 {
+			reloading = append(reloading, dep.Namespace+"/"+dep.Name)
+		}
+	}
+
+	return reloading, nil
+}
+
+// loadedDependents returns the namespace/name of the modules loaded on the node that depend on the module of status.
+func loadedDependents(nmcObj *kmmv1beta1.NodeModulesConfig, status *kmmv1beta1.NodeModuleStatus) []string {
+	dependents := make([]string, 0)
+
+	for _, s := range nmcObj.Status.Modules {
+		if s.Config == nil {
+			continue
+		}
+
+		for _, dep := range s.Dependencies {
+			if dep.Namespace == status.Namespace && dep.Name == status.Name {
+				dependents = append(dependents, s.Namespace+"/"+s.Name)
+				break
+			}
+		}
+	}
+
+	return dependents
+}
+
+func findModuleSpec(specs []kmmv1beta1.NodeModuleSpec, namespace, name string) *kmmv1beta1.NodeModuleSpec {
+	for i := range specs {
+		if specs[i].Namespace == namespace && specs[i].Name == name {
+			return &specs[i]
+		}
+	}
+
+	return nil
+}
+
+// prepareNodeForUnload cordons the node and evicts the Pods selected by policy before the module of status is
+// unloaded.
+// It returns true once none of those Pods is left on the node, or if policy is nil.
+// The node is only cordoned if it is schedulable; KMM records that it cordoned it in the NodeModulesConfig status
+// first, so that FinishMaintenance uncordons it even if the controller restarts in between.
+func (h *nmcReconcilerHelperImpl) prepareNodeForUnload(
+	ctx context.Context,
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	status *kmmv1beta1.NodeModuleStatus,
+	policy *kmmv1beta1.MaintenancePolicy,
+) (bool, error) {
+	if policy == nil {
+		return true, nil
+	}
+
+	logger := ctrl.LoggerFrom(ctx)
+
+	node := v1.Node{}
+
+	if err := h.client.Get(ctx, types.NamespacedName{Name: nmcObj.Name}, &node); err != nil {
+		return false, fmt.Errorf("could not get node %s: %v", nmcObj.Name, err)
+	}
+
+	cordoned := apimeta.IsStatusConditionTrue(nmcObj.Status.Conditions, kmmv1beta1.NodeModulesConfigConditionCordoned)
+
+	if !cordoned && !node.Spec.Unschedulable {
+		patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+
+		apimeta.SetStatusCondition(
+			&nmcObj.Status.Conditions,
+			metav1.Condition{
+				Type:    kmmv1beta1.NodeModulesConfigConditionCordoned,
+				Status:  metav1.ConditionTrue,
+				Reason:  "Maintenance",
+				Message: fmt.Sprintf("Node cordoned by KMM before unloading module %s/%s", status.Namespace, status.Name),
+			},
+		)
+
+		if err := h.client.Status().Patch(ctx, nmcObj, patchFrom); err != nil {
+			return false, fmt.Errorf("could not record that node %s is cordoned: %v", nmcObj.Name, err)
+		}
+
+		cordoned = true
+	}
+
+	if cordoned {
+		if err := h.drainer.Cordon(ctx, &node); err != nil {
+			return false, err
+		}
+	}
+
+	remaining, err := h.drainer.Drain(ctx, nmcObj.Name, policy)
+	if err != nil {
+		return false, fmt.Errorf("could not drain node %s: %v", nmcObj.Name, err)
+	}
+
+	cond := metav1.Condition{
+		Type:    kmmv1beta1.NodeModuleConditionDrained,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Drained",
+		Message: "All Pods selected by the maintenance policy were evicted",
+	}
+
+	if remaining > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "Draining"
+		cond.Message = fmt.Sprintf("Waiting for %d Pod(s) selected by the maintenance policy to be evicted", remaining)
+	}
+
+	patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+
+	if apimeta.SetStatusCondition(&status.Conditions, cond) {
+		if err = h.client.Status().Patch(ctx, nmcObj, patchFrom); err != nil {
+			return false, fmt.Errorf("could not update the Drained condition: %v", err)
+		}
+	}
+
+	if remaining > 0 {
+		logger.Info("Waiting for Pods to be evicted before unloading the module", "count", remaining)
+		return false, nil
+	}
+
+	return true, nil
+}
+
 // ProcessUnconfiguredModuleStatus cleans up a NodeModuleStatus.
 // It should be called for each status entry for which the NodeModulesConfigs does not have a spec entry; this means
 // that KMM wants the module unloaded from the node.
 // If status.Config field is nil, then it represents a module that could not be loaded by a worker Pod.
 // ProcessUnconfiguredModuleStatus will then remove status from nmcObj's Status.Modules.
 // If status.Config is not nil, it means that the module was successfully loaded.
-// ProcessUnconfiguredModuleStatus will then create a worker pod to unload the module.
+// ProcessUnconfiguredModuleStatus will then create a worker pod to unload the module, once no loaded module depends on
+// it anymore.
 func (h *nmcReconcilerHelperImpl) ProcessUnconfiguredModuleStatus(
 	ctx context.Context,
 	nmcObj *kmmv1beta1.NodeModulesConfig,
@@ -391,13 +995,36 @@ func (h *nmcReconcilerHelperImpl) ProcessUnconfiguredModuleStatus(
 	}
 
 	if pod == nil {
+		if status.Config == nil {
+			logger.Info("Module was never loaded; removing its status")
+
+			patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+
+			nmc.RemoveModuleStatus(&nmcObj.Status.Modules, status.Namespace, status.Name)
+
+			return h.client.Status().Patch(ctx, nmcObj, patchFrom)
+		}
+
+		if dependents := loadedDependents(nmcObj, status); len(dependents) > 0 {
+			logger.Info("Waiting for dependent modules to be unloaded", "dependents", dependents)
+			return nil
+		}
+
+		if ok, err := h.prepareNodeForUnload(ctx, nmcObj, status, status.MaintenancePolicy); err != nil || !ok {
+			return err
+		}
+
 		logger.Info("Worker Pod does not exist; creating it")
 		return h.pm.CreateUnloaderPod(ctx, nmcObj, status)
 	}
 
-	if pod.Labels[actionLabelKey] == WorkerActionLoad {
+	switch pod.Labels[actionLabelKey] {
+	case WorkerActionLoad:
 		logger.Info("Worker Pod is loading the kmod; deleting it")
 		return h.pm.DeletePod(ctx, pod)
+	case WorkerActionVerify:
+		logger.Info("Worker Pod is verifying the kmod; deleting it")
+		return h.pm.DeletePod(ctx, pod)
 	}
 
 	if GetContainerStatus(pod.Status.ContainerStatuses, workerContainerName).RestartCount == 0 {
@@ -483,24 +1110,95 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 
 		logger.Info("Processing worker Pod")
 
+		if terminated := workerFailure(&p); terminated != nil {
+			setWorkerFailureCondition(nmcObj, &p, terminated)
+		}
+
 		status := nmc.FindModuleStatus(nmcObj.Status.Modules, modNamespace, modName)
 
 		switch phase {
-		case v1.PodRunning:
+		case v1.PodPending, v1.PodRunning:
 			// Delete Pod if orphan
-			if !specEntries.Has(types.NamespacedName{Namespace: modNamespace, Name: modName}) && status == nil {
+			if phase == v1.PodRunning &&
+				!specEntries.Has(types.NamespacedName{Namespace: modNamespace, Name: modName}) && status == nil {
 				logger.Info("Orphan pod; deleting")
 				podsToDelete = append(podsToDelete, p)
+				break
+			}
+
+			deadline, exceeded := workerDeadlineExceeded(&p, time.Now())
+			if !exceeded {
+				break
+			}
+
+			logger.Info(utils.WarnString("Worker Pod exceeded its deadline; deleting"), "deadline", deadline)
+
+			h.recordWorkerTimeout(nmcObj, &p, deadline)
+
+			if p.Labels[actionLabelKey] == WorkerActionLoad {
+				if err = h.recordFailedLoad(nmcObj, &p, time.Now()); err != nil {
+					errs = append(errs, fmt.Errorf("%s: could not record the failed load: %v", podNSN, err))
+					continue
+				}
 			}
+
+			podsToDelete = append(podsToDelete, p)
 		case v1.PodFailed:
+			h.recordWorkerFailure(ctx, nmcObj, &p)
+
+			switch p.Labels[actionLabelKey] {
+			case WorkerActionLoad:
+				if err = h.recordFailedLoad(nmcObj, &p, time.Now()); err != nil {
+					errs = append(errs, fmt.Errorf("%s: could not record the failed load: %v", podNSN, err))
+					continue
+				}
+			case WorkerActionSetParameters:
+				if err = recordFailedParametersUpdate(nmcObj, &p); err != nil {
+					errs = append(errs, fmt.Errorf("%s: could not record the failed parameters update: %v", podNSN, err))
+					continue
+				}
+			case WorkerActionVerify:
+				recordVerification(status, &p)
+			}
+
 			podsToDelete = append(podsToDelete, p)
 		case v1.PodSucceeded:
+			if p.Labels[actionLabelKey] == WorkerActionVerify {
+				if status != nil {
+					recordVerification(status, &p)
+
+					apimeta.SetStatusCondition(
+						&status.Conditions,
+						metav1.Condition{
+							Type:               kmmv1beta1.NodeModuleConditionVerified,
+							Status:             metav1.ConditionTrue,
+							Reason:             "Verified",
+							Message:            "Module verified by worker Pod " + p.Name,
+							LastTransitionTime: *status.LastVerifiedTime,
+						},
+					)
+				}
+
+				podsToDelete = append(podsToDelete, p)
+				break
+			}
+
 			if p.Labels[actionLabelKey] == WorkerActionUnload {
 				podsToDelete = append(podsToDelete, p)
 				nmc.RemoveModuleStatus(&nmcObj.Status.Modules, modNamespace, modName)
 				break
 			}
 
+			if p.Labels[actionLabelKey] == WorkerActionSetParameters {
+				if err = setUpdatedParameters(status, &p); err != nil {
+					errs = append(errs, fmt.Errorf("%s: could not record the updated parameters: %v", podNSN, err))
+					continue
+				}
+
+				podsToDelete = append(podsToDelete, p)
+				break
+			}
+
 			if status == nil {
 				status = &kmmv1beta1.NodeModuleStatus{
 					ModuleItem: kmmv1beta1.ModuleItem{
@@ -510,7 +1208,9 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 				}
 			}
 
-			if err = yaml.UnmarshalStrict([]byte(p.Annotations[configAnnotationKey]), &status.Config); err != nil {
+			cfg := kmmv1beta1.ModuleConfig{}
+
+			if err = yaml.UnmarshalStrict([]byte(p.Annotations[configAnnotationKey]), &cfg); err != nil {
 				errs = append(
 					errs,
 					fmt.Errorf("%s: could not unmarshal the ModuleConfig from YAML: %v", podNSN, err),
@@ -519,6 +1219,8 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 				continue
 			}
 
+			status.Config = &cfg
+
 			if irsName, err := getImageRepoSecretName(&p); err != nil {
 				logger.Info(
 					utils.WarnString("Error while looking for the imageRepoSecret volume"),
@@ -530,6 +1232,19 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 			}
 
 			status.ServiceAccountName = p.Spec.ServiceAccountName
+			status.Timeouts = nil
+			status.MaintenancePolicy = nil
+			status.Dependencies = nil
+
+			// Unloader Pods are created from the status, after the spec entry may have been removed.
+			for _, e := range nmcObj.Spec.Modules {
+				if e.Namespace == modNamespace && e.Name == modName {
+					status.Timeouts = e.Timeouts
+					status.MaintenancePolicy = e.MaintenancePolicy
+					status.Dependencies = e.Dependencies
+					break
+				}
+			}
 
 			podLTT := GetContainerStatus(p.Status.ContainerStatuses, workerContainerName).
 				State.
@@ -537,6 +1252,24 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 				FinishedAt
 
 			status.LastTransitionTime = podLTT
+			status.BootID = h.bootIDAtLoad(ctx, nmcObj.Name, podLTT)
+			status.FailedAttempts = 0
+			status.FailedConfigHash = ""
+			status.FailedParametersConfigHash = ""
+			status.NextRetryTime = nil
+
+			apimeta.RemoveStatusCondition(&status.Conditions, kmmv1beta1.NodeModuleConditionFailed)
+			apimeta.RemoveStatusCondition(&status.Conditions, kmmv1beta1.NodeModuleConditionVerified)
+			apimeta.SetStatusCondition(
+				&status.Conditions,
+				metav1.Condition{
+					Type:               kmmv1beta1.NodeModuleConditionLoaded,
+					Status:             metav1.ConditionTrue,
+					Reason:             "Loaded",
+					Message:            "Module loaded by worker Pod " + p.Name,
+					LastTransitionTime: podLTT,
+				},
+			)
 
 			nmc.SetModuleStatus(&nmcObj.Status.Modules, *status)
 
@@ -563,6 +1296,339 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 	return errors.Join(errs...)
 }
 
+// bootIDAtLoad returns the boot ID of the node, or an empty string if the node may have rebooted since loadedAt.
+// An empty boot ID makes ProcessModuleSpec fall back to comparing loadedAt with the Ready condition of the node.
+func (h *nmcReconcilerHelperImpl) bootIDAtLoad(ctx context.Context, nodeName string, loadedAt metav1.Time) string {
+	node := v1.Node{}
+
+	if err := h.client.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		ctrl.LoggerFrom(ctx).Info(utils.WarnString("Could not get the node; not recording its boot ID"), "error", err)
+		return ""
+	}
+
+	if c := FindNodeCondition(node.Status.Conditions, v1.NodeReady); c != nil && loadedAt.Before(&c.LastTransitionTime) {
+		return ""
+	}
+
+	return node.Status.NodeInfo.BootID
+}
+
+// recordVerification sets the LastVerifiedTime of status to the time at which the verifier pod completed.
+func recordVerification(status *kmmv1beta1.NodeModuleStatus, pod *v1.Pod) {
+	if status == nil {
+		return
+	}
+
+	finishedAt := metav1.Now()
+
+	if t := GetContainerStatus(pod.Status.ContainerStatuses, workerContainerName).State.Terminated; t != nil {
+		finishedAt = t.FinishedAt
+	}
+
+	status.LastVerifiedTime = &finishedAt
+}
+
+// recordWorkerFailure emits a warning event on the NodeModulesConfig with the reason reported by the failed worker
+// container of pod, if any.
+func (h *nmcReconcilerHelperImpl) recordWorkerFailure(ctx context.Context, nmcObj *kmmv1beta1.NodeModulesConfig, pod *v1.Pod) {
+	terminated := workerFailure(pod)
+	if terminated == nil || terminated.Message == "" {
+		return
+	}
+
+	logger := ctrl.LoggerFrom(ctx)
+
+	tm, err := worker.ParseTerminationMessage(terminated.Message)
+	if err != nil {
+		logger.Info(utils.WarnString("Could not parse the worker termination message"), "error", err)
+		return
+	}
+
+	reason := workerFailureReason(tm)
+
+	nsn := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Labels[constants.ModuleNameLabel]}
+
+	h.recorder.AnnotatedEventf(
+		nmcObj,
+		map[string]string{"module": nsn.String()},
+		v1.EventTypeWarning,
+		reason,
+		"%s worker for Module %s failed: %s",
+		pod.Labels[actionLabelKey],
+		nsn.String(),
+		tm.Message,
+	)
+}
+
+// workerFailure returns the state of the last failed run of the worker container, or nil if it did not fail.
+func workerFailure(pod *v1.Pod) *v1.ContainerStateTerminated {
+	if pod.Status.Phase == v1.PodSucceeded {
+		return nil
+	}
+
+	cs := GetContainerStatus(pod.Status.ContainerStatuses, workerContainerName)
+
+	if t := cs.State.Terminated; t != nil && t.ExitCode != 0 {
+		return t
+	}
+
+	return cs.LastTerminationState.Terminated
+}
+
+func workerFailureReason(tm *worker.TerminationMessage) string {
+	if tm.Reason == "" {
+		return "WorkerFailed"
+	}
+
+	return string(tm.Reason)
+}
+
+// setWorkerFailureCondition sets the Loaded or Unloaded condition of the module's status entry to False, with the
+// information found in the worker's termination message.
+// It creates the status entry for loader Pods if needed, so that the failure is visible even if the module was never
+// loaded on the node.
+func setWorkerFailureCondition(nmcObj *kmmv1beta1.NodeModulesConfig, pod *v1.Pod, terminated *v1.ContainerStateTerminated) {
+	reason := "WorkerFailed"
+	message := fmt.Sprintf("worker exited with code %d", terminated.ExitCode)
+
+	if terminated.Message != "" {
+		if tm, err := worker.ParseTerminationMessage(terminated.Message); err == nil {
+			reason = workerFailureReason(tm)
+			message = workerFailureMessage(tm)
+		}
+	}
+
+	setWorkerCondition(nmcObj, pod, reason, message, terminated.FinishedAt)
+}
+
+// setWorkerCondition sets the condition matching the action of pod to False in the module's status entry.
+// It creates the status entry for loader Pods if needed.
+func setWorkerCondition(nmcObj *kmmv1beta1.NodeModulesConfig, pod *v1.Pod, reason, message string, ltt metav1.Time) {
+	action := pod.Labels[actionLabelKey]
+
+	conditionType := kmmv1beta1.NodeModuleConditionLoaded
+
+	switch action {
+	case WorkerActionSetParameters:
+		conditionType = kmmv1beta1.NodeModuleConditionParametersUpdated
+	case WorkerActionUnload:
+		conditionType = kmmv1beta1.NodeModuleConditionUnloaded
+	case WorkerActionVerify:
+		conditionType = kmmv1beta1.NodeModuleConditionVerified
+	}
+
+	var status *kmmv1beta1.NodeModuleStatus
+
+	if action == WorkerActionLoad {
+		status = loaderModuleStatus(nmcObj, pod)
+	} else if status = nmc.FindModuleStatus(nmcObj.Status.Modules, pod.Namespace, pod.Labels[constants.ModuleNameLabel]); status == nil {
+		return
+	}
+
+	apimeta.SetStatusCondition(
+		&status.Conditions,
+		metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: ltt,
+		},
+	)
+}
+
+// recordWorkerTimeout sets the condition matching the action of pod to False and emits a warning event on the
+// NodeModulesConfig, because pod's worker did not complete before deadline.
+func (h *nmcReconcilerHelperImpl) recordWorkerTimeout(nmcObj *kmmv1beta1.NodeModulesConfig, pod *v1.Pod, deadline time.Duration) {
+	nsn := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Labels[constants.ModuleNameLabel]}
+	message := fmt.Sprintf("worker Pod %s did not complete within %v", pod.Name, deadline)
+
+	setWorkerCondition(nmcObj, pod, string(worker.ReasonTimeout), message, metav1.Now())
+
+	h.recorder.AnnotatedEventf(
+		nmcObj,
+		map[string]string{"module": nsn.String()},
+		v1.EventTypeWarning,
+		string(worker.ReasonTimeout),
+		"%s worker for Module %s failed: %s",
+		pod.Labels[actionLabelKey],
+		nsn.String(),
+		message,
+	)
+}
+
+// loaderModuleStatus returns the status entry of the module loaded by pod, creating it if needed.
+func loaderModuleStatus(nmcObj *kmmv1beta1.NodeModulesConfig, pod *v1.Pod) *kmmv1beta1.NodeModuleStatus {
+	modNamespace := pod.Namespace
+	modName := pod.Labels[constants.ModuleNameLabel]
+
+	if status := nmc.FindModuleStatus(nmcObj.Status.Modules, modNamespace, modName); status != nil {
+		return status
+	}
+
+	nmc.SetModuleStatus(
+		&nmcObj.Status.Modules,
+		kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:               modName,
+				Namespace:          modNamespace,
+				ServiceAccountName: pod.Spec.ServiceAccountName,
+			},
+		},
+	)
+
+	return nmc.FindModuleStatus(nmcObj.Status.Modules, modNamespace, modName)
+}
+
+// recordFailedLoad increments the failed attempts counter of the module loaded by pod and schedules the next attempt.
+// If the maximum number of attempts is reached, it sets the Failed condition instead.
+// The counter is reset if the config loaded by pod is not the one that previously failed.
+func (h *nmcReconcilerHelperImpl) recordFailedLoad(nmcObj *kmmv1beta1.NodeModulesConfig, pod *v1.Pod, now time.Time) error {
+	cfg := kmmv1beta1.ModuleConfig{}
+
+	if err := yaml.UnmarshalStrict([]byte(pod.Annotations[configAnnotationKey]), &cfg); err != nil {
+		return fmt.Errorf("could not unmarshal the ModuleConfig from YAML: %v", err)
+	}
+
+	hash, err := moduleConfigHash(&cfg)
+	if err != nil {
+		return err
+	}
+
+	status := loaderModuleStatus(nmcObj, pod)
+
+	if status.FailedConfigHash != hash {
+		status.FailedAttempts = 0
+		status.FailedConfigHash = hash
+		apimeta.RemoveStatusCondition(&status.Conditions, kmmv1beta1.NodeModuleConditionFailed)
+	}
+
+	status.FailedAttempts++
+
+	if max := h.workerCfg.MaxLoadAttempts; max > 0 && status.FailedAttempts >= max {
+		status.NextRetryTime = nil
+
+		apimeta.SetStatusCondition(
+			&status.Conditions,
+			metav1.Condition{
+				Type:    kmmv1beta1.NodeModuleConditionFailed,
+				Status:  metav1.ConditionTrue,
+				Reason:  "MaxLoadAttemptsReached",
+				Message: fmt.Sprintf("Loading failed %d times; not retrying until the module's config changes", status.FailedAttempts),
+			},
+		)
+
+		return nil
+	}
+
+	next := metav1.NewTime(now.Add(loadBackoff(status.FailedAttempts)))
+	status.NextRetryTime = &next
+
+	return nil
+}
+
+// recordFailedParametersUpdate records that the config set by pod must be applied by reloading the module.
+func recordFailedParametersUpdate(nmcObj *kmmv1beta1.NodeModulesConfig, pod *v1.Pod) error {
+	status := nmc.FindModuleStatus(nmcObj.Status.Modules, pod.Namespace, pod.Labels[constants.ModuleNameLabel])
+	if status == nil {
+		return nil
+	}
+
+	cfg := kmmv1beta1.ModuleConfig{}
+
+	if err := yaml.UnmarshalStrict([]byte(pod.Annotations[configAnnotationKey]), &cfg); err != nil {
+		return fmt.Errorf("could not unmarshal the ModuleConfig from YAML: %v", err)
+	}
+
+	hash, err := moduleConfigHash(&cfg)
+	if err != nil {
+		return err
+	}
+
+	status.FailedParametersConfigHash = hash
+
+	return nil
+}
+
+// setUpdatedParameters sets the config of status to the one whose parameters pod updated.
+// The last transition time is not changed, as the module was not reloaded.
+func setUpdatedParameters(status *kmmv1beta1.NodeModuleStatus, pod *v1.Pod) error {
+	if status == nil {
+		return errors.New("no status for the module")
+	}
+
+	cfg := kmmv1beta1.ModuleConfig{}
+
+	if err := yaml.UnmarshalStrict([]byte(pod.Annotations[configAnnotationKey]), &cfg); err != nil {
+		return fmt.Errorf("could not unmarshal the ModuleConfig from YAML: %v", err)
+	}
+
+	status.Config = &cfg
+	status.FailedParametersConfigHash = ""
+
+	finishedAt := metav1.Now()
+
+	if t := GetContainerStatus(pod.Status.ContainerStatuses, workerContainerName).State.Terminated; t != nil {
+		finishedAt = t.FinishedAt
+	}
+
+	apimeta.SetStatusCondition(
+		&status.Conditions,
+		metav1.Condition{
+			Type:               kmmv1beta1.NodeModuleConditionParametersUpdated,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ParametersUpdated",
+			Message:            "Parameters updated without reloading the module by worker Pod " + pod.Name,
+			LastTransitionTime: finishedAt,
+		},
+	)
+
+	return nil
+}
+
+// loadBackoff returns the delay before the next load after the given number of consecutive failed attempts.
+func loadBackoff(attempts int32) time.Duration {
+	d := loadBackoffBase
+
+	for i := int32(1); i < attempts; i++ {
+		if d *= 2; d >= loadBackoffMax {
+			return loadBackoffMax
+		}
+	}
+
+	return d
+}
+
+func moduleConfigHash(cfg *kmmv1beta1.ModuleConfig) (string, error) {
+	hash, err := hashstructure.Hash(cfg, hashstructure.FormatV2, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not hash the module config: %v", err)
+	}
+
+	return fmt.Sprintf("%d", hash), nil
+}
+
+func workerFailureMessage(tm *worker.TerminationMessage) string {
+	sb := strings.Builder{}
+
+	if tm.Phase != "" {
+		fmt.Fprintf(&sb, "%s: ", tm.Phase)
+	}
+
+	sb.WriteString(tm.Message)
+
+	if tm.ExitCode != nil {
+		fmt.Fprintf(&sb, " (modprobe exit code %d)", *tm.ExitCode)
+	}
+
+	if len(tm.KernelLog) > 0 {
+		sb.WriteString("\nKernel log:\n")
+		sb.WriteString(strings.Join(tm.KernelLog, "\n"))
+	}
+
+	return sb.String()
+}
+
 func (h *nmcReconcilerHelperImpl) UpdateNodeLabelsAndRecordEvents(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig) error {
 	node := v1.Node{}
 	if err := h.client.Get(ctx, types.NamespacedName{Name: nmc.Name}, &node); err != nil {
@@ -589,14 +1655,25 @@ func (h *nmcReconcilerHelperImpl) UpdateNodeLabelsAndRecordEvents(ctx context.Co
 		specLabels[types.NamespacedName{Namespace: module.Namespace, Name: module.Name}] = module.Config
 	}
 
-	// get status labels and their config
+	// get status labels and their config; modules that were never loaded are ignored
 	statusLabels := make(map[types.NamespacedName]kmmv1beta1.ModuleConfig)
-	for _, module := range nmc.Status.Modules {
+	// modules that are no longer loaded since their status was recorded
+	driftedLabels := sets.New[types.NamespacedName]()
+	for i, module := range nmc.Status.Modules {
+		if module.Config == nil {
+			continue
+		}
+
 		label := types.NamespacedName{Namespace: module.Namespace, Name: module.Name}
-		statusLabels[label] = module.Config
+		statusLabels[label] = *module.Config
+
+		if moduleDrifted(&nmc.Status.Modules[i]) {
+			driftedLabels.Insert(label)
+		}
 	}
 
 	unloaded := make([]types.NamespacedName, 0, len(nodeModuleReadyLabels))
+	drifted := make([]types.NamespacedName, 0, len(driftedLabels))
 	loaded := make([]types.NamespacedName, 0, len(specLabels))
 
 	patchFrom := client.MergeFrom(node.DeepCopy())
@@ -611,7 +1688,19 @@ func (h *nmcReconcilerHelperImpl) UpdateNodeLabelsAndRecordEvents(ctx context.Co
 				utils.GetKernelModuleReadyNodeLabel(nsn.Namespace, nsn.Name),
 			)
 
-			unloaded = append(unloaded, nsn)
+			unloaded = append(unloaded, nsn)
+		}
+	}
+
+	// label in node but module no longer loaded - should be removed
+	for nsn := range driftedLabels {
+		if nodeModuleReadyLabels.Has(nsn) {
+			meta.RemoveLabel(
+				&node,
+				utils.GetKernelModuleReadyNodeLabel(nsn.Namespace, nsn.Name),
+			)
+
+			drifted = append(drifted, nsn)
 		}
 	}
 
@@ -623,7 +1712,7 @@ func (h *nmcReconcilerHelperImpl) UpdateNodeLabelsAndRecordEvents(ctx context.Co
 	// label in spec and status and config equal - should be added
 	for nsn, specConfig := range specLabels {
 		statusConfig, ok := statusLabels[nsn]
-		if ok && reflect.DeepEqual(specConfig, statusConfig) && !nodeModuleReadyLabels.Has(nsn) {
+		if ok && reflect.DeepEqual(specConfig, statusConfig) && !driftedLabels.Has(nsn) && !nodeModuleReadyLabels.Has(nsn) {
 			meta.SetLabel(
 				&node,
 				utils.GetKernelModuleReadyNodeLabel(nsn.Namespace, nsn.Name),
@@ -649,6 +1738,17 @@ func (h *nmcReconcilerHelperImpl) UpdateNodeLabelsAndRecordEvents(ctx context.Co
 		)
 	}
 
+	for _, nsn := range drifted {
+		h.recorder.AnnotatedEventf(
+			&node,
+			map[string]string{"module": nsn.String()},
+			v1.EventTypeWarning,
+			"ModuleDrifted",
+			"Module %s is no longer loaded in the kernel",
+			nsn.String(),
+		)
+	}
+
 	for _, nsn := range loaded {
 		h.recorder.AnnotatedEventf(
 			&node,
@@ -664,8 +1764,10 @@ func (h *nmcReconcilerHelperImpl) UpdateNodeLabelsAndRecordEvents(ctx context.Co
 }
 
 const (
-	configFileName = "config.yaml"
-	configFullPath = volMountPointConfig + "/" + configFileName
+	configFileName  = "config.yaml"
+	configFullPath  = volMountPointConfig + "/" + configFileName
+	mirrorsFileName = "registry-mirrors.yaml"
+	mirrorsFullPath = volMountPointConfig + "/" + mirrorsFileName
 
 	volNameConfig          = "config"
 	volNameImageRepoSecret = "image-repo-secret"
@@ -676,25 +1778,37 @@ const (
 
 type podManager interface {
 	CreateLoaderPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) error
-	CreateUnloaderPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) error
+	CreateParametersPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec, names []string) error
+	CreateUnloaderPod(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, nms *kmmv1beta1.NodeModuleStatus) error
 	DeletePod(ctx context.Context, pod *v1.Pod) error
 	ListWorkerPodsOnNode(ctx context.Context, nodeName string) ([]v1.Pod, error)
 	LoaderPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) (*v1.Pod, error)
+	ParametersPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec, names []string) (*v1.Pod, error)
 	GetWorkerPod(ctx context.Context, podName, namespace string) (*v1.Pod, error)
-	UnloaderPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error)
+	UnloaderPodTemplate(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error)
+	CreateVerifierPod(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, nms *kmmv1beta1.NodeModuleStatus) error
+	VerifierPodTemplate(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error)
 }
 
 type podManagerImpl struct {
 	client      client.Client
+	mirrors     []mirror.Registry
 	psh         pullSecretHelper
 	scheme      *runtime.Scheme
 	workerCfg   *config.Worker
 	workerImage string
 }
 
-func newPodManager(client client.Client, workerImage string, scheme *runtime.Scheme, workerCfg *config.Worker) podManager {
+func newPodManager(
+	client client.Client,
+	workerImage string,
+	scheme *runtime.Scheme,
+	workerCfg *config.Worker,
+	mirrors []mirror.Registry,
+) podManager {
 	return &podManagerImpl{
 		client:      client,
+		mirrors:     mirrors,
 		psh:         &pullSecretHelperImpl{client: client},
 		scheme:      scheme,
 		workerCfg:   workerCfg,
@@ -711,7 +1825,16 @@ func (p *podManagerImpl) CreateLoaderPod(ctx context.Context, nmcObj client.Obje
 	return p.client.Create(ctx, pod)
 }
 
-func (p *podManagerImpl) CreateUnloaderPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) error {
+func (p *podManagerImpl) CreateParametersPod(ctx context.Context, nmcObj client.Object, nms *kmmv1beta1.NodeModuleSpec, names []string) error {
+	pod, err := p.ParametersPodTemplate(ctx, nmcObj, nms, names)
+	if err != nil {
+		return fmt.Errorf("could not get parameters Pod template: %v", err)
+	}
+
+	return p.client.Create(ctx, pod)
+}
+
+func (p *podManagerImpl) CreateUnloaderPod(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, nms *kmmv1beta1.NodeModuleStatus) error {
 	pod, err := p.UnloaderPodTemplate(ctx, nmc, nms)
 	if err != nil {
 		return fmt.Errorf("could not create the Pod template: %v", err)
@@ -720,6 +1843,15 @@ func (p *podManagerImpl) CreateUnloaderPod(ctx context.Context, nmc client.Objec
 	return p.client.Create(ctx, pod)
 }
 
+func (p *podManagerImpl) CreateVerifierPod(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, nms *kmmv1beta1.NodeModuleStatus) error {
+	pod, err := p.VerifierPodTemplate(ctx, nmc, nms)
+	if err != nil {
+		return fmt.Errorf("could not get verifier Pod template: %v", err)
+	}
+
+	return p.client.Create(ctx, pod)
+}
+
 func (p *podManagerImpl) DeletePod(ctx context.Context, pod *v1.Pod) error {
 	logger := ctrl.LoggerFrom(ctx)
 
@@ -814,27 +1946,89 @@ func (p *podManagerImpl) LoaderPodTemplate(ctx context.Context, nmc client.Objec
 		}
 	}
 
+	if err = setLocalImageVolume(pod, nms.Config.ContainerImage); err != nil {
+		return nil, fmt.Errorf("could not mount the local image: %v", err)
+	}
+
+	if err = setImageVerificationVolume(pod, nms.Config.ImageVerification); err != nil {
+		return nil, fmt.Errorf("could not mount the image verification keys: %v", err)
+	}
+
+	timeouts := p.workerTimeouts(nms.Timeouts)
+	args = setWorkerTimeouts(pod, args, timeouts.Pull, timeouts.Load, worker.FlagLoadTimeout)
+
+	if args, err = setRegistryMirrors(pod, args, p.mirrors); err != nil {
+		return nil, fmt.Errorf("could not pass the registry mirrors to the worker: %v", err)
+	}
+
 	if err = setWorkerContainerArgs(pod, args); err != nil {
 		return nil, fmt.Errorf("could not set worker container args: %v", err)
 	}
 
+	// Failed loads are retried by the controller, with a backoff and a maximum number of attempts.
+	pod.Spec.RestartPolicy = v1.RestartPolicyNever
+
 	meta.SetLabel(pod, actionLabelKey, WorkerActionLoad)
 
 	return pod, setHashAnnotation(pod)
 }
 
-func (p *podManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error) {
+// ParametersPodTemplate returns a worker Pod that updates the parameters in names through sysfs, with the values set in
+// nms.Config.
+func (p *podManagerImpl) ParametersPodTemplate(
+	ctx context.Context,
+	nmc client.Object,
+	nms *kmmv1beta1.NodeModuleSpec,
+	names []string,
+) (*v1.Pod, error) {
 	pod, err := p.baseWorkerPod(ctx, nmc.GetName(), &nms.ModuleItem, nmc)
 	if err != nil {
 		return nil, fmt.Errorf("could not create the base Pod: %v", err)
 	}
 
-	args := []string{"kmod", "unload", configFullPath}
+	args := []string{"kmod", "set-parameters", configFullPath}
+
+	for _, name := range names {
+		args = append(args, "--"+worker.FlagParameter, name)
+	}
 
 	if err = setWorkerConfigAnnotation(pod, nms.Config); err != nil {
 		return nil, fmt.Errorf("could not set worker config: %v", err)
 	}
 
+	// sysfs is only writable from privileged containers.
+	if err = setWorkerSecurityContext(pod, p.workerCfg, true); err != nil {
+		return nil, fmt.Errorf("could not set the worker Pod as privileged: %v", err)
+	}
+
+	if err = setWorkerContainerArgs(pod, args); err != nil {
+		return nil, fmt.Errorf("could not set worker container args: %v", err)
+	}
+
+	// A failed update is not retried: the module is reloaded instead.
+	pod.Spec.RestartPolicy = v1.RestartPolicyNever
+
+	meta.SetLabel(pod, actionLabelKey, WorkerActionSetParameters)
+
+	return pod, setHashAnnotation(pod)
+}
+
+func (p *podManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error) {
+	if nms.Config == nil {
+		return nil, fmt.Errorf("status for module %s/%s has no config", nms.Namespace, nms.Name)
+	}
+
+	pod, err := p.baseWorkerPod(ctx, nmc.GetName(), &nms.ModuleItem, nmc)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the base Pod: %v", err)
+	}
+
+	args := []string{"kmod", "unload", configFullPath}
+
+	if err = setWorkerConfigAnnotation(pod, *nms.Config); err != nil {
+		return nil, fmt.Errorf("could not set worker config: %v", err)
+	}
+
 	if err = setWorkerSecurityContext(pod, p.workerCfg, false); err != nil {
 		return nil, fmt.Errorf("could not set the worker Pod's security context: %v", err)
 	}
@@ -852,6 +2046,45 @@ func (p *podManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc client.Obj
 		}
 	}
 
+	if err = setLocalImageVolume(pod, nms.Config.ContainerImage); err != nil {
+		return nil, fmt.Errorf("could not mount the local image: %v", err)
+	}
+
+	if err = setImageVerificationVolume(pod, nms.Config.ImageVerification); err != nil {
+		return nil, fmt.Errorf("could not mount the image verification keys: %v", err)
+	}
+
+	args = append(args, "--"+worker.FlagGarbageCollect)
+
+	for _, image := range imagesInUse(nmc, nms) {
+		args = append(args, "--"+worker.FlagKeepImage, image)
+	}
+
+	if p.workerCfg.ImageCacheMaxSize != "" {
+		args = append(args, "--"+worker.FlagMaxSize, p.workerCfg.ImageCacheMaxSize)
+	}
+
+	if t := p.workerCfg.ModuleInUseTimeout; t > 0 {
+		args = append(args, "--"+worker.FlagInUseTimeout, t.String())
+	}
+
+	if i := p.workerCfg.ModuleInUsePollInterval; i > 0 {
+		args = append(args, "--"+worker.FlagInUsePollInterval, i.String())
+	}
+
+	if p.workerCfg.ListHolderProcesses {
+		// The processes holding the module's device nodes are only visible from the host's PID namespace.
+		args = append(args, "--"+worker.FlagListHolders)
+		pod.Spec.HostPID = true
+	}
+
+	timeouts := p.workerTimeouts(nms.Timeouts)
+	args = setWorkerTimeouts(pod, args, timeouts.Pull, timeouts.Unload, worker.FlagUnloadTimeout)
+
+	if args, err = setRegistryMirrors(pod, args, p.mirrors); err != nil {
+		return nil, fmt.Errorf("could not pass the registry mirrors to the worker: %v", err)
+	}
+
 	if err = setWorkerContainerArgs(pod, args); err != nil {
 		return nil, fmt.Errorf("could not set worker container args: %v", err)
 	}
@@ -861,6 +2094,212 @@ func (p *podManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc client.Obj
 	return pod, setHashAnnotation(pod)
 }
 
+// VerifierPodTemplate returns a worker Pod that checks that the module of nms is still loaded, without pulling its
+// image.
+func (p *podManagerImpl) VerifierPodTemplate(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error) {
+	if nms.Config == nil {
+		return nil, fmt.Errorf("status for module %s/%s has no config", nms.Namespace, nms.Name)
+	}
+
+	pod, err := p.baseWorkerPod(ctx, nmc.GetName(), &nms.ModuleItem, nmc)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the base Pod: %v", err)
+	}
+
+	args := []string{"kmod", "verify", configFullPath}
+
+	if err = setWorkerConfigAnnotation(pod, *nms.Config); err != nil {
+		return nil, fmt.Errorf("could not set worker config: %v", err)
+	}
+
+	if err = setWorkerSecurityContext(pod, p.workerCfg, false); err != nil {
+		return nil, fmt.Errorf("could not set the worker Pod's security context: %v", err)
+	}
+
+	if err = setWorkerContainerArgs(pod, args); err != nil {
+		return nil, fmt.Errorf("could not set worker container args: %v", err)
+	}
+
+	// A failed verification is recorded in the status; the next one happens after the verification interval.
+	pod.Spec.RestartPolicy = v1.RestartPolicyNever
+
+	meta.SetLabel(pod, actionLabelKey, WorkerActionVerify)
+
+	return pod, setHashAnnotation(pod)
+}
+
+// workerTimeouts returns the timeouts set in mt, with the default ones for those it does not set.
+func (p *podManagerImpl) workerTimeouts(mt *kmmv1beta1.ModuleTimeouts) config.WorkerTimeouts {
+	t := p.workerCfg.DefaultTimeouts
+
+	if mt == nil {
+		return t
+	}
+
+	if mt.Pull != nil {
+		t.Pull = mt.Pull.Duration
+	}
+
+	if mt.Load != nil {
+		t.Load = mt.Load.Duration
+	}
+
+	if mt.Unload != nil {
+		t.Unload = mt.Unload.Duration
+	}
+
+	return t
+}
+
+// setWorkerTimeouts returns args with the flags for the pull timeout and the timeout of the action, if they are set.
+// If either is set, it also sets the deadline after which the controller deletes the Pod if its worker has not completed.
+func setWorkerTimeouts(pod *v1.Pod, args []string, pull, action time.Duration, actionFlag string) []string {
+	if pull > 0 {
+		args = append(args, "--"+worker.FlagPullTimeout, pull.String())
+	}
+
+	if action > 0 {
+		args = append(args, "--"+actionFlag, action.String())
+	}
+
+	if pull > 0 || action > 0 {
+		meta.SetAnnotation(pod, deadlineAnnotationKey, (pull + action + workerDeadlineGrace).String())
+	}
+
+	return args
+}
+
+// setRegistryMirrors exposes mirrors to the worker as a file in the config volume, and returns args with the flag
+// pointing to it.
+// The CA bundles of the mirrors are read from the operator's filesystem and exposed in the config volume as well.
+// It does nothing if no mirror is configured.
+func setRegistryMirrors(pod *v1.Pod, args []string, mirrors []mirror.Registry) ([]string, error) {
+	if len(mirrors) == 0 {
+		return args, nil
+	}
+
+	var vol *v1.Volume
+
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].Name == volumeNameConfig {
+			vol = &pod.Spec.Volumes[i]
+			break
+		}
+	}
+
+	if vol == nil {
+		return nil, fmt.Errorf("volume %s not found", volumeNameConfig)
+	}
+
+	addFile := func(path, annotationKey, contents string) {
+		meta.SetAnnotation(pod, annotationKey, contents)
+
+		vol.DownwardAPI.Items = append(
+			vol.DownwardAPI.Items,
+			v1.DownwardAPIVolumeFile{
+				Path: path,
+				FieldRef: &v1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.annotations['%s']", annotationKey),
+				},
+			},
+		)
+	}
+
+	// Do not modify the operator's mirrors when pointing CAFile to the worker's copies.
+	workerMirrors := make([]mirror.Registry, len(mirrors))
+	caCount := 0
+
+	for i, reg := range mirrors {
+		reg.Mirrors = append([]mirror.Mirror(nil), reg.Mirrors...)
+
+		for j := range reg.Mirrors {
+			m := &reg.Mirrors[j]
+
+			if m.CAFile == "" {
+				continue
+			}
+
+			pem, err := os.ReadFile(m.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not read the CA bundle of mirror %s: %v", m.Location, err)
+			}
+
+			fileName := fmt.Sprintf("mirror-ca-%d.pem", caCount)
+			addFile(fileName, fmt.Sprintf("%s%d", mirrorCAAnnotationPrefix, caCount), string(pem))
+			m.CAFile = volMountPointConfig + "/" + fileName
+			caCount++
+		}
+
+		workerMirrors[i] = reg
+	}
+
+	b, err := yaml.Marshal(workerMirrors)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal the registry mirrors to YAML: %v", err)
+	}
+
+	addFile(mirrorsFileName, mirrorsAnnotationKey, string(b))
+
+	return append(args, "--"+worker.FlagRegistryMirrors, mirrorsFullPath), nil
+}
+
+// workerDeadlineExceeded returns the deadline of pod and whether it has passed.
+// The deadline starts when the worker container started running or, if it is not running, when the Pod was scheduled
+// or created, so that Pods stuck in Pending or ImagePullBackOff are also bounded.
+func workerDeadlineExceeded(pod *v1.Pod, now time.Time) (time.Duration, bool) {
+	v, ok := pod.Annotations[deadlineAnnotationKey]
+	if !ok {
+		return 0, false
+	}
+
+	deadline, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, false
+	}
+
+	start := pod.CreationTimestamp.Time
+
+	if running := GetContainerStatus(pod.Status.ContainerStatuses, workerContainerName).State.Running; running != nil {
+		start = running.StartedAt.Time
+	} else {
+		for _, c := range pod.Status.Conditions {
+			if c.Type == v1.PodScheduled && c.Status == v1.ConditionTrue {
+				start = c.LastTransitionTime.Time
+				break
+			}
+		}
+	}
+
+	if start.IsZero() {
+		return deadline, false
+	}
+
+	return deadline, now.After(start.Add(deadline))
+}
+
+// imagesInUse returns the sorted images that the modules on the node use or are about to use, excluding the image of
+// the module being unloaded.
+// They must be kept in the node's image cache.
+func imagesInUse(nmc *kmmv1beta1.NodeModulesConfig, unloading *kmmv1beta1.NodeModuleStatus) []string {
+	images := sets.New[string]()
+
+	for _, spec := range nmc.Spec.Modules {
+		images.Insert(spec.Config.ContainerImage)
+	}
+
+	for _, status := range nmc.Status.Modules {
+		if status.Namespace == unloading.Namespace && status.Name == unloading.Name {
+			continue
+		}
+
+		if status.Config != nil {
+			images.Insert(status.Config.ContainerImage)
+		}
+	}
+
+	return sets.List(images)
+}
+
 var (
 	requests = v1.ResourceList{
 		v1.ResourceCPU:    resource.MustParse("0.5"),
@@ -879,11 +2318,14 @@ func (p *podManagerImpl) baseWorkerPod(
 	owner client.Object,
 ) (*v1.Pod, error) {
 	const (
+		volNameImageCache    = "image-cache"
 		volNameLibModules    = "lib-modules"
+		volNameModprobeD     = "modprobe-d"
 		volNameUsrLibModules = "usr-lib-modules"
 	)
 
 	hostPathDirectory := v1.HostPathDirectory
+	hostPathDirectoryOrCreate := v1.HostPathDirectoryOrCreate
 
 	psv, psvm, err := p.psh.VolumesAndVolumeMounts(ctx, item)
 	if err != nil {
@@ -924,6 +2366,24 @@ func (p *podManagerImpl) baseWorkerPod(
 				},
 			},
 		},
+		{
+			Name: volNameImageCache,
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: worker.ImagesHostDir,
+					Type: &hostPathDirectoryOrCreate,
+				},
+			},
+		},
+		{
+			Name: volNameModprobeD,
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: worker.BlacklistHostDir,
+					Type: &hostPathDirectoryOrCreate,
+				},
+			},
+		},
 	}
 
 	volumeMounts := []v1.VolumeMount{
@@ -942,6 +2402,14 @@ func (p *podManagerImpl) baseWorkerPod(
 			MountPath: "/usr/lib/modules",
 			ReadOnly:  true,
 		},
+		{
+			Name:      volNameImageCache,
+			MountPath: worker.ImagesDir,
+		},
+		{
+			Name:      volNameModprobeD,
+			MountPath: worker.BlacklistDir,
+		},
 	}
 
 	pod := v1.Pod{
@@ -958,8 +2426,12 @@ func (p *podManagerImpl) baseWorkerPod(
 		Spec: v1.PodSpec{
 			Containers: []v1.Container{
 				{
-					Name:         workerContainerName,
-					Image:        p.workerImage,
+					Name:  workerContainerName,
+					Image: p.workerImage,
+					Env: []v1.EnvVar{
+						{Name: worker.EnvModuleName, Value: item.Name},
+						{Name: worker.EnvModuleNamespace, Value: item.Namespace},
+					},
 					VolumeMounts: append(volumeMounts, psvm...),
 					Resources: v1.ResourceRequirements{
 						Requests: requests,
@@ -1028,7 +2500,7 @@ func setWorkerSecurityContext(pod *v1.Pod, workerCfg *config.Worker, privileged
 }
 
 func setWorkerSofdepConfig(pod *v1.Pod, modulesLoadingOrder []string) error {
-	softdepAnnotationValue := getModulesOrderAnnotationValue(modulesLoadingOrder)
+	softdepAnnotationValue := worker.SoftdepConfig(modulesLoadingOrder)
 	meta.SetAnnotation(pod, modulesOrderKey, softdepAnnotationValue)
 
 	softdepVolume := v1.Volume{
@@ -1091,6 +2563,93 @@ func setFirmwareVolume(pod *v1.Pod, hostFirmwarePath *string) error {
 	return nil
 }
 
+// setLocalImageVolume mounts the OCI layout directory or archive of a local image in the worker container, at the
+// same path as on the host.
+// It does nothing for images that are pulled from a registry.
+func setLocalImageVolume(pod *v1.Pod, image string) error {
+	const volNameLocalImage = "local-image"
+
+	if !localimage.IsLocal(image) {
+		return nil
+	}
+
+	ref, err := localimage.Parse(image)
+	if err != nil {
+		return fmt.Errorf("could not parse local image %s: %v", image, err)
+	}
+
+	container, _ := podcmd.FindContainerByName(pod, workerContainerName)
+	if container == nil {
+		return errors.New("could not find the worker container")
+	}
+
+	hostPathType := v1.HostPathDirectory
+	if ref.Transport == localimage.TransportOCIArchive {
+		hostPathType = v1.HostPathFile
+	}
+
+	pod.Spec.Volumes = append(
+		pod.Spec.Volumes,
+		v1.Volume{
+			Name: volNameLocalImage,
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: ref.Path,
+					Type: &hostPathType,
+				},
+			},
+		},
+	)
+
+	container.VolumeMounts = append(
+		container.VolumeMounts,
+		v1.VolumeMount{
+			Name:      volNameLocalImage,
+			ReadOnly:  true,
+			MountPath: ref.Path,
+		},
+	)
+
+	return nil
+}
+
+// setImageVerificationVolume mounts the Secret holding the public keys against which the worker verifies the image.
+func setImageVerificationVolume(pod *v1.Pod, iv *kmmv1beta1.ImageVerification) error {
+	const volNameVerificationKeys = "verification-keys"
+
+	if iv == nil {
+		return nil
+	}
+
+	container, _ := podcmd.FindContainerByName(pod, workerContainerName)
+	if container == nil {
+		return errors.New("could not find the worker container")
+	}
+
+	pod.Spec.Volumes = append(
+		pod.Spec.Volumes,
+		v1.Volume{
+			Name: volNameVerificationKeys,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: iv.KeysSecret.Name,
+				},
+			},
+		},
+	)
+
+	container.VolumeMounts = append(
+		container.VolumeMounts,
+		v1.VolumeMount{
+			Name:      volNameVerificationKeys,
+			ReadOnly:  true,
+			MountPath: worker.VerificationKeysDir,
+		},
+	)
+
+	return nil
+}
+
 func setHashAnnotation(pod *v1.Pod) error {
 	hash, err := hashstructure.Hash(pod, hashstructure.FormatV2, nil)
 	if err != nil {
@@ -1118,14 +2677,6 @@ func getImageRepoSecretName(pod *v1.Pod) (string, error) {
 	return "", nil
 }
 
-func getModulesOrderAnnotationValue(modulesNames []string) string {
-	var softDepData strings.Builder
-	for i := 0; i < len(modulesNames)-1; i++ {
-		fmt.Fprintf(&softDepData, "softdep %s pre: %s\n", modulesNames[i], modulesNames[i+1])
-	}
-	return softDepData.String()
-}
-
 //go:generate mockgen -source=nmc_reconciler.go -package=controllers -destination=mock_nmc_reconciler.go pullSecretHelper
 
 type pullSecretHelper interface {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -14,6 +15,8 @@ import (
 	testclient "github.com/kubernetes-sigs/kernel-module-management/internal/client"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/drain"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mirror"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/nmc"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
@@ -23,6 +26,7 @@ import (
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -33,6 +37,7 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 )
 
 const nmcName = "nmc"
@@ -153,6 +158,7 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 			wh.EXPECT().ProcessModuleSpec(contextWithValueMatch, nmc, &spec0, &status0),
 			wh.EXPECT().ProcessModuleSpec(contextWithValueMatch, nmc, &spec1, nil),
 			wh.EXPECT().ProcessUnconfiguredModuleStatus(contextWithValueMatch, nmc, &status2),
+			wh.EXPECT().FinishMaintenance(ctx, nmc),
 			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc),
 			wh.EXPECT().UpdateNodeLabelsAndRecordEvents(ctx, nmc),
 		)
@@ -215,6 +221,7 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 			wh.EXPECT().SyncStatus(ctx, nmc).Return(nil),
 			wh.EXPECT().ProcessModuleSpec(contextWithValueMatch, nmc, &spec0, &status0).Return(fmt.Errorf("some error")),
 			wh.EXPECT().ProcessUnconfiguredModuleStatus(contextWithValueMatch, nmc, &status2).Return(fmt.Errorf("some error")),
+			wh.EXPECT().FinishMaintenance(ctx, nmc).Return(fmt.Errorf("some error")),
 			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc).Return(fmt.Errorf("some error")),
 			wh.EXPECT().UpdateNodeLabelsAndRecordEvents(ctx, nmc).Return(fmt.Errorf("some error")),
 		)
@@ -222,6 +229,86 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 		_, err := r.Reconcile(ctx, req)
 		Expect(err).ToNot(BeNil())
 	})
+
+	It("should requeue when the next load attempt is due", func() {
+		spec := kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Namespace: namespace,
+				Name:      "mod",
+			},
+		}
+
+		status := kmmv1beta1.NodeModuleStatus{
+			ModuleItem:     spec.ModuleItem,
+			FailedAttempts: 1,
+			NextRetryTime:  &metav1.Time{Time: time.Now().Add(time.Minute)},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{spec},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{status},
+			},
+		}
+
+		gomock.InOrder(
+			kubeClient.
+				EXPECT().
+				Get(ctx, nmcNsn, &kmmv1beta1.NodeModulesConfig{}).
+				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
+					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
+				}),
+			wh.EXPECT().SyncStatus(ctx, nmc),
+			wh.EXPECT().ProcessModuleSpec(gomock.Any(), nmc, &spec, &status),
+			wh.EXPECT().FinishMaintenance(ctx, nmc),
+			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc),
+			wh.EXPECT().UpdateNodeLabelsAndRecordEvents(ctx, nmc),
+		)
+
+		res, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(res.RequeueAfter).To(BeNumerically("<=", time.Minute))
+	})
+	It("should requeue while Pods are being evicted", func() {
+		status := kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Namespace: namespace,
+				Name:      "mod",
+			},
+			Conditions: []metav1.Condition{
+				{Type: kmmv1beta1.NodeModuleConditionDrained, Status: metav1.ConditionFalse, Reason: "Draining"},
+			},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{status},
+			},
+		}
+
+		gomock.InOrder(
+			kubeClient.
+				EXPECT().
+				Get(ctx, nmcNsn, &kmmv1beta1.NodeModulesConfig{}).
+				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
+					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
+				}),
+			wh.EXPECT().SyncStatus(ctx, nmc),
+			wh.EXPECT().ProcessUnconfiguredModuleStatus(gomock.Any(), nmc, &status),
+			wh.EXPECT().FinishMaintenance(ctx, nmc),
+			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc),
+			wh.EXPECT().UpdateNodeLabelsAndRecordEvents(ctx, nmc),
+		)
+
+		res, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(drainPollInterval))
+	})
 })
 
 var moduleConfig = kmmv1beta1.ModuleConfig{
@@ -252,7 +339,7 @@ var _ = Describe("nmcReconcilerHelperImpl_GarbageCollectInUseLabels", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		client = testclient.NewMockClient(ctrl)
 		pm = NewMockpodManager(ctrl)
-		wh = newNMCReconcilerHelper(client, pm, nil)
+		wh = newNMCReconcilerHelper(client, pm, nil, &config.Worker{}, nil)
 	})
 
 	It("should do nothing if no labels should be collected", func() {
@@ -368,7 +455,7 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		client = testclient.NewMockClient(ctrl)
 		pm = NewMockpodManager(ctrl)
-		wh = newNMCReconcilerHelper(client, pm, nil)
+		wh = newNMCReconcilerHelper(client, pm, nil, &config.Worker{}, nil)
 	})
 
 	It("should create a loader Pod if there is no existing Pod and the status is missing", func() {
@@ -394,30 +481,22 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		)
 	})
 
-	It("should create an unloader Pod if the spec is different from the status", func() {
+	It("should create a loader Pod if there is no existing Pod and the module was never loaded", func() {
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
 		}
 
-		spec := &kmmv1beta1.NodeModuleSpec{
-			ModuleItem: kmmv1beta1.ModuleItem{
-				Name:      name,
-				Namespace: namespace,
-			},
-			Config: kmmv1beta1.ModuleConfig{ContainerImage: "old-container-image"},
+		mi := kmmv1beta1.ModuleItem{
+			Name:      name,
+			Namespace: namespace,
 		}
 
-		status := &kmmv1beta1.NodeModuleStatus{
-			ModuleItem: kmmv1beta1.ModuleItem{
-				Name:      name,
-				Namespace: namespace,
-			},
-			Config: kmmv1beta1.ModuleConfig{ContainerImage: "new-container-image"},
-		}
+		spec := &kmmv1beta1.NodeModuleSpec{ModuleItem: mi}
+		status := &kmmv1beta1.NodeModuleStatus{ModuleItem: mi}
 
 		gomock.InOrder(
 			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
-			pm.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+			pm.EXPECT().CreateLoaderPod(ctx, nmc, spec),
 		)
 
 		Expect(
@@ -427,142 +506,157 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		)
 	})
 
-	It("should return an error if we could not get the node", func() {
+	It("should not create a loader Pod while a dependency is not loaded", func() {
+		dep := kmmv1beta1.ModuleItem{Name: "dep", Namespace: namespace}
+
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
-		}
-
-		cfg := kmmv1beta1.ModuleConfig{ContainerImage: "some-image"}
-
-		spec := &kmmv1beta1.NodeModuleSpec{
-			ModuleItem: kmmv1beta1.ModuleItem{
-				Name:      name,
-				Namespace: namespace,
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: dep,
+						Config:     kmmv1beta1.ModuleConfig{ContainerImage: "new-image"},
+					},
+				},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: dep,
+						Config:     &kmmv1beta1.ModuleConfig{ContainerImage: "old-image"},
+					},
+				},
 			},
-			Config: cfg,
 		}
 
-		status := &kmmv1beta1.NodeModuleStatus{
+		spec := &kmmv1beta1.NodeModuleSpec{
 			ModuleItem: kmmv1beta1.ModuleItem{
-				Name:      name,
-				Namespace: namespace,
+				Name:         name,
+				Namespace:    namespace,
+				Dependencies: []kmmv1beta1.ModuleDependency{{Name: dep.Name, Namespace: dep.Namespace}},
 			},
-			Config: cfg,
 		}
 
 		gomock.InOrder(
 			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
-			client.
-				EXPECT().
-				Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
-				Return(errors.New("random error")),
+			client.EXPECT().Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}),
 		)
 
 		Expect(
-			wh.ProcessModuleSpec(ctx, nmc, spec, status),
-		).To(
+			wh.ProcessModuleSpec(ctx, nmc, spec, nil),
+		).NotTo(
 			HaveOccurred(),
 		)
 	})
 
-	It("should return an error if the node has no ready condition", func() {
+	It("should not create a loader Pod while a dependency has never been loaded", func() {
+		dep := kmmv1beta1.ModuleItem{Name: "dep", Namespace: namespace}
+
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: dep,
+						Config:     kmmv1beta1.ModuleConfig{ContainerImage: "image"},
+					},
+				},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{{ModuleItem: dep}},
+			},
 		}
 
 		spec := &kmmv1beta1.NodeModuleSpec{
 			ModuleItem: kmmv1beta1.ModuleItem{
-				Name:      name,
-				Namespace: namespace,
+				Name:         name,
+				Namespace:    namespace,
+				Dependencies: []kmmv1beta1.ModuleDependency{{Name: dep.Name, Namespace: dep.Namespace}},
 			},
-			Config: moduleConfig,
 		}
 
-		status := &kmmv1beta1.NodeModuleStatus{
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			client.EXPECT().Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}),
+		)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, nil),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should create a loader Pod once all dependencies are loaded", func() {
+		dep := kmmv1beta1.ModuleItem{Name: "dep", Namespace: namespace}
+		cfg := kmmv1beta1.ModuleConfig{ContainerImage: "image"}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{ModuleItem: dep, Config: cfg},
+				},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{ModuleItem: dep, Config: &cfg, BootID: "boot-id"},
+				},
+			},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
 			ModuleItem: kmmv1beta1.ModuleItem{
-				Name:      name,
-				Namespace: namespace,
+				Name:         name,
+				Namespace:    namespace,
+				Dependencies: []kmmv1beta1.ModuleDependency{{Name: dep.Name, Namespace: dep.Namespace}},
 			},
-			Config:             moduleConfig,
-			LastTransitionTime: metav1.Now(),
 		}
 
 		gomock.InOrder(
 			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
-			client.EXPECT().Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}),
+			client.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
+				Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node, _ ...ctrlclient.GetOption) {
+					node.Status.NodeInfo.BootID = "boot-id"
+				}),
+			pm.EXPECT().CreateLoaderPod(ctx, nmc, spec),
 		)
 
 		Expect(
-			wh.ProcessModuleSpec(ctx, nmc, spec, status),
-		).To(
+			wh.ProcessModuleSpec(ctx, nmc, spec, nil),
+		).NotTo(
 			HaveOccurred(),
 		)
 	})
 
-	nmc := &kmmv1beta1.NodeModulesConfig{
-		ObjectMeta: metav1.ObjectMeta{Name: nmcName},
-	}
-
-	spec := &kmmv1beta1.NodeModuleSpec{
-		Config: moduleConfig,
-		ModuleItem: kmmv1beta1.ModuleItem{
-			Name:      name,
-			Namespace: namespace,
-		},
-	}
-
-	now := metav1.Now()
+	It("should not create a loader Pod while backing off after a failed load", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
 
-	status := &kmmv1beta1.NodeModuleStatus{
-		Config:             moduleConfig,
-		LastTransitionTime: metav1.Time{Time: now.Add(-1 * time.Minute)},
-		ModuleItem: kmmv1beta1.ModuleItem{
+		mi := kmmv1beta1.ModuleItem{
 			Name:      name,
 			Namespace: namespace,
-		},
-	}
-
-	DescribeTable(
-		"should create a loader Pod if status is older than the Ready condition",
-		func(cs v1.ConditionStatus, shouldCreate bool) {
-
-			getPod := pm.
-				EXPECT().
-				GetWorkerPod(ctx, podName, namespace)
+		}
 
-			getNode := client.
-				EXPECT().
-				Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
-				Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node, _ ...ctrl.Options) {
-					node.Status.Conditions = []v1.NodeCondition{
-						{
-							Type:               v1.NodeReady,
-							Status:             cs,
-							LastTransitionTime: now,
-						},
-					}
-				}).
-				After(getPod)
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     kmmv1beta1.ModuleConfig{ContainerImage: "some-image"},
+		}
 
-			if shouldCreate {
-				pm.EXPECT().CreateLoaderPod(ctx, nmc, spec).After(getNode)
-			}
+		hash, err := moduleConfigHash(&spec.Config)
+		Expect(err).NotTo(HaveOccurred())
 
-			Expect(
-				wh.ProcessModuleSpec(ctx, nmc, spec, status),
-			).NotTo(
-				HaveOccurred(),
-			)
-		},
-		Entry(nil, v1.ConditionFalse, false),
-		Entry(nil, v1.ConditionTrue, true),
-	)
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem:       mi,
+			FailedAttempts:   1,
+			FailedConfigHash: hash,
+			NextRetryTime:    &metav1.Time{Time: time.Now().Add(time.Hour)},
+		}
 
-	It("should do nothing if the pod is not loading a kmod", func() {
-		pm.
-			EXPECT().
-			GetWorkerPod(ctx, podName, namespace).
-			Return(&v1.Pod{}, nil)
+		pm.EXPECT().GetWorkerPod(ctx, podName, namespace)
 
 		Expect(
 			wh.ProcessModuleSpec(ctx, nmc, spec, status),
@@ -571,17 +665,33 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		)
 	})
 
-	It("should do nothing if the worker container has not restarted", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Labels: map[string]string{actionLabelKey: WorkerActionLoad},
-			},
+	It("should not create a loader Pod once the maximum number of attempts is reached", func() {
+		wh = newNMCReconcilerHelper(client, pm, nil, &config.Worker{MaxLoadAttempts: 3}, nil)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
 		}
 
-		pm.
-			EXPECT().
-			GetWorkerPod(ctx, podName, namespace).
-			Return(&pod, nil)
+		mi := kmmv1beta1.ModuleItem{
+			Name:      name,
+			Namespace: namespace,
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     kmmv1beta1.ModuleConfig{ContainerImage: "some-image"},
+		}
+
+		hash, err := moduleConfigHash(&spec.Config)
+		Expect(err).NotTo(HaveOccurred())
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem:       mi,
+			FailedAttempts:   3,
+			FailedConfigHash: hash,
+		}
+
+		pm.EXPECT().GetWorkerPod(ctx, podName, namespace)
 
 		Expect(
 			wh.ProcessModuleSpec(ctx, nmc, spec, status),
@@ -590,70 +700,69 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		)
 	})
 
-	It("should return an error if there was an error making the Pod template", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Labels: map[string]string{actionLabelKey: WorkerActionLoad},
-			},
-			Status: v1.PodStatus{
-				ContainerStatuses: []v1.ContainerStatus{
-					{
-						Name:         workerContainerName,
-						RestartCount: 1,
-					},
-				},
-			},
+	It("should create a loader Pod after failed loads if the config changed", func() {
+		wh = newNMCReconcilerHelper(client, pm, nil, &config.Worker{MaxLoadAttempts: 3}, nil)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		mi := kmmv1beta1.ModuleItem{
+			Name:      name,
+			Namespace: namespace,
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     kmmv1beta1.ModuleConfig{ContainerImage: "new-image"},
+		}
+
+		hash, err := moduleConfigHash(&kmmv1beta1.ModuleConfig{ContainerImage: "old-image"})
+		Expect(err).NotTo(HaveOccurred())
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem:       mi,
+			FailedAttempts:   3,
+			FailedConfigHash: hash,
+			NextRetryTime:    &metav1.Time{Time: time.Now().Add(time.Hour)},
 		}
 
 		gomock.InOrder(
-			pm.
-				EXPECT().
-				GetWorkerPod(ctx, podName, namespace).
-				Return(&pod, nil),
-			pm.
-				EXPECT().
-				LoaderPodTemplate(ctx, nmc, spec).
-				Return(nil, errors.New("random error")),
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			pm.EXPECT().CreateLoaderPod(ctx, nmc, spec),
 		)
 
 		Expect(
 			wh.ProcessModuleSpec(ctx, nmc, spec, status),
-		).To(
+		).NotTo(
 			HaveOccurred(),
 		)
 	})
 
-	It("should delete the existing pod if its hash annotation is outdated", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Labels:      map[string]string{actionLabelKey: WorkerActionLoad},
-				Annotations: map[string]string{hashAnnotationKey: "123"},
-			},
-			Status: v1.PodStatus{
-				ContainerStatuses: []v1.ContainerStatus{
-					{
-						Name:         workerContainerName,
-						RestartCount: 1,
-					},
-				},
+	It("should create an unloader Pod if the spec is different from the status", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
 			},
+			Config: kmmv1beta1.ModuleConfig{ContainerImage: "old-container-image"},
 		}
 
-		podTemplate := pod.DeepCopy()
-		podTemplate.Annotations[hashAnnotationKey] = "456"
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Config: &kmmv1beta1.ModuleConfig{ContainerImage: "new-container-image"},
+		}
 
 		gomock.InOrder(
-			pm.
-				EXPECT().
-				GetWorkerPod(ctx, podName, namespace).
-				Return(&pod, nil),
-			pm.
-				EXPECT().
-				LoaderPodTemplate(ctx, nmc, spec).
-				Return(podTemplate, nil),
-			pm.
-				EXPECT().
-				DeletePod(ctx, &pod),
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			pm.EXPECT().CreateUnloaderPod(ctx, nmc, status),
 		)
 
 		Expect(
@@ -662,245 +771,1879 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 			HaveOccurred(),
 		)
 	})
-})
-
-var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func() {
-	const name = "name"
-
-	var (
-		ctx     = context.TODO()
-		podName = workerPodName(nmcName, name)
 
-		client *testclient.MockClient
-		pm     *MockpodManager
-		helper nmcReconcilerHelper
-	)
+	It("should not create an unloader Pod while a dependent module is loaded", func() {
+		mi := kmmv1beta1.ModuleItem{
+			Name:      name,
+			Namespace: namespace,
+		}
 
-	BeforeEach(func() {
-		ctrl := gomock.NewController(GinkgoT())
-		client = testclient.NewMockClient(ctrl)
-		pm = NewMockpodManager(ctrl)
-		helper = newNMCReconcilerHelper(client, pm, nil)
-	})
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: mi,
+			Config:     &kmmv1beta1.ModuleConfig{ContainerImage: "old-container-image"},
+		}
 
-	nmc := &kmmv1beta1.NodeModulesConfig{
-		ObjectMeta: metav1.ObjectMeta{Name: nmcName},
-	}
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					*status,
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Name:         "dependent",
+							Namespace:    namespace,
+							Dependencies: []kmmv1beta1.ModuleDependency{{Name: name, Namespace: namespace}},
+						},
+						Config: &kmmv1beta1.ModuleConfig{},
+					},
+				},
+			},
+		}
 
-	status := &kmmv1beta1.NodeModuleStatus{
-		ModuleItem: kmmv1beta1.ModuleItem{
-			Name:      name,
-			Namespace: namespace,
-		},
-	}
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     kmmv1beta1.ModuleConfig{ContainerImage: "new-container-image"},
+		}
 
-	It("should create an unloader Pod if no worker Pod exists", func() {
-		gomock.InOrder(
-			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
-			pm.EXPECT().CreateUnloaderPod(ctx, nmc, status),
-		)
+		pm.EXPECT().GetWorkerPod(ctx, podName, namespace)
 
 		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
 		).NotTo(
 			HaveOccurred(),
 		)
 	})
 
-	It("should delete the current worker if it is loading a module", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      podName,
-				Namespace: namespace,
-				Labels:    map[string]string{actionLabelKey: WorkerActionLoad},
+	It("should create an unloader Pod if a dependency must be reloaded", func() {
+		dep := kmmv1beta1.ModuleItem{Name: "dep", Namespace: namespace}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: dep,
+						Config:     kmmv1beta1.ModuleConfig{ContainerImage: "new-image"},
+					},
+				},
 			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: dep,
+						Config:     &kmmv1beta1.ModuleConfig{ContainerImage: "old-image"},
+					},
+				},
+			},
+		}
+
+		mi := kmmv1beta1.ModuleItem{
+			Name:         name,
+			Namespace:    namespace,
+			Dependencies: []kmmv1beta1.ModuleDependency{{Name: dep.Name, Namespace: dep.Namespace}},
 		}
 
+		spec := &kmmv1beta1.NodeModuleSpec{ModuleItem: mi}
+		status := &kmmv1beta1.NodeModuleStatus{ModuleItem: mi, Config: &kmmv1beta1.ModuleConfig{}}
+
 		gomock.InOrder(
-			pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
-			pm.EXPECT().DeletePod(ctx, &pod),
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			pm.EXPECT().CreateUnloaderPod(ctx, nmc, status),
 		)
 
 		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
 		).NotTo(
 			HaveOccurred(),
 		)
 	})
 
-	It("should do nothing if the pod has not restarted yet", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      podName,
-				Namespace: namespace,
-			},
+	It("should not create an unloader Pod while Pods are being evicted", func() {
+		drainer := drain.NewMockDrainer(gomock.NewController(GinkgoT()))
+		sw := testclient.NewMockStatusWriter(gomock.NewController(GinkgoT()))
+		wh = newNMCReconcilerHelper(client, pm, nil, &config.Worker{}, drainer)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
 		}
 
-		pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil)
+		policy := &kmmv1beta1.MaintenancePolicy{ResourceNames: []v1.ResourceName{"example.com/gpu"}}
 
-		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:              name,
+				Namespace:         namespace,
+				MaintenancePolicy: policy,
+			},
+			Config: kmmv1beta1.ModuleConfig{ContainerImage: "new-container-image"},
+		}
 
-	It("should return an error if there was an error while making the unloader Pod", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      podName,
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
 				Namespace: namespace,
 			},
-			Status: v1.PodStatus{
-				ContainerStatuses: []v1.ContainerStatus{
-					{
-						Name:         workerContainerName,
-						RestartCount: 1,
-					},
-				},
-			},
+			Config: &kmmv1beta1.ModuleConfig{ContainerImage: "old-container-image"},
 		}
 
 		gomock.InOrder(
-			pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
-			pm.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(nil, errors.New("random error")),
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			client.EXPECT().Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			drainer.EXPECT().Cordon(ctx, gomock.Any()),
+			drainer.EXPECT().Drain(ctx, nmcName, policy).Return(1, nil),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
 		)
 
 		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
-		).To(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
+		).NotTo(
 			HaveOccurred(),
 		)
 	})
 
-	It("should delete the existing pod if its hash annotation is outdated", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:        podName,
-				Namespace:   namespace,
-				Annotations: map[string]string{hashAnnotationKey: "123"},
-			},
-			Status: v1.PodStatus{
-				ContainerStatuses: []v1.ContainerStatus{
-					{
-						Name:         workerContainerName,
-						RestartCount: 1,
-					},
+	Context("only parameters changed", func() {
+		var (
+			nmc    *kmmv1beta1.NodeModulesConfig
+			spec   *kmmv1beta1.NodeModuleSpec
+			status *kmmv1beta1.NodeModuleStatus
+		)
+
+		BeforeEach(func() {
+			nmc = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			}
+
+			spec = &kmmv1beta1.NodeModuleSpec{
+				ModuleItem: kmmv1beta1.ModuleItem{
+					Name:      name,
+					Namespace: namespace,
 				},
-			},
+				Config: kmmv1beta1.ModuleConfig{
+					ContainerImage: "container-image",
+					Modprobe:       kmmv1beta1.ModprobeSpec{Parameters: []string{"a=1", "b=3", "c=4"}},
+				},
+			}
+
+			status = &kmmv1beta1.NodeModuleStatus{
+				ModuleItem: kmmv1beta1.ModuleItem{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Config: &kmmv1beta1.ModuleConfig{
+					ContainerImage: "container-image",
+					Modprobe:       kmmv1beta1.ModprobeSpec{Parameters: []string{"a=1", "b=2"}},
+				},
+			}
+		})
+
+		It("should create a Pod updating the changed parameters", func() {
+			gomock.InOrder(
+				pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				pm.EXPECT().CreateParametersPod(ctx, nmc, spec, []string{"b", "c"}),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should create an unloader Pod if a parameter was removed", func() {
+			spec.Config.Modprobe.Parameters = []string{"b=3"}
+
+			gomock.InOrder(
+				pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				pm.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should create an unloader Pod if updating the parameters failed", func() {
+			hash, err := moduleConfigHash(&spec.Config)
+			Expect(err).NotTo(HaveOccurred())
+
+			status.FailedParametersConfigHash = hash
+
+			gomock.InOrder(
+				pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				pm.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+	})
+
+	It("should return an error if we could not get the node", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		cfg := kmmv1beta1.ModuleConfig{ContainerImage: "some-image"}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Config: cfg,
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Config: &cfg,
+		}
+
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			client.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
+				Return(errors.New("random error")),
+		)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the node has no ready condition", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Config: moduleConfig,
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Config:             &moduleConfig,
+			LastTransitionTime: metav1.Now(),
+		}
+
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			client.EXPECT().Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}),
+		)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	nmc := &kmmv1beta1.NodeModulesConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+	}
+
+	spec := &kmmv1beta1.NodeModuleSpec{
+		Config: moduleConfig,
+		ModuleItem: kmmv1beta1.ModuleItem{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	now := metav1.Now()
+
+	status := &kmmv1beta1.NodeModuleStatus{
+		Config:             &moduleConfig,
+		LastTransitionTime: metav1.Time{Time: now.Add(-1 * time.Minute)},
+		ModuleItem: kmmv1beta1.ModuleItem{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	DescribeTable(
+		"should create a loader Pod if status is older than the Ready condition",
+		func(cs v1.ConditionStatus, shouldCreate bool) {
+
+			getPod := pm.
+				EXPECT().
+				GetWorkerPod(ctx, podName, namespace)
+
+			getNode := client.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
+				Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node, _ ...ctrl.Options) {
+					node.Status.Conditions = []v1.NodeCondition{
+						{
+							Type:               v1.NodeReady,
+							Status:             cs,
+							LastTransitionTime: now,
+						},
+					}
+				}).
+				After(getPod)
+
+			if shouldCreate {
+				pm.EXPECT().CreateLoaderPod(ctx, nmc, spec).After(getNode)
+			}
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status),
+			).NotTo(
+				HaveOccurred(),
+			)
+		},
+		Entry(nil, v1.ConditionFalse, false),
+		Entry(nil, v1.ConditionTrue, true),
+	)
+
+	DescribeTable(
+		"should create a loader Pod if the boot ID changed",
+		func(nodeBootID string, shouldCreate bool) {
+			// The Ready condition changed after the module was loaded; only the boot ID matters.
+			statusWithBootID := status.DeepCopy()
+			statusWithBootID.BootID = "some-boot-id"
+
+			getPod := pm.
+				EXPECT().
+				GetWorkerPod(ctx, podName, namespace)
+
+			getNode := client.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
+				Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node, _ ...ctrl.Options) {
+					node.Status.Conditions = []v1.NodeCondition{
+						{
+							Type:               v1.NodeReady,
+							Status:             v1.ConditionTrue,
+							LastTransitionTime: now,
+						},
+					}
+					node.Status.NodeInfo.BootID = nodeBootID
+				}).
+				After(getPod)
+
+			if shouldCreate {
+				pm.EXPECT().CreateLoaderPod(ctx, nmc, spec).After(getNode)
+			}
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, statusWithBootID),
+			).NotTo(
+				HaveOccurred(),
+			)
+		},
+		Entry("same boot ID", "some-boot-id", false),
+		Entry("new boot ID", "some-other-boot-id", true),
+		Entry("unknown boot ID, falling back to the Ready condition", "", true),
+	)
+
+	expectNodeReadyBeforeLoad := func() *gomock.Call {
+		getPod := pm.
+			EXPECT().
+			GetWorkerPod(ctx, podName, namespace)
+
+		return client.
+			EXPECT().
+			Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
+			Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node, _ ...ctrl.Options) {
+				node.Status.Conditions = []v1.NodeCondition{
+					{
+						Type:               v1.NodeReady,
+						Status:             v1.ConditionTrue,
+						LastTransitionTime: metav1.Time{Time: now.Add(-2 * time.Minute)},
+					},
+				}
+			}).
+			After(getPod)
+	}
+
+	DescribeTable(
+		"should create a verifier Pod when the verification is due",
+		func(interval time.Duration, lastVerified *metav1.Time, shouldCreate bool) {
+			wh = newNMCReconcilerHelper(client, pm, nil, &config.Worker{VerifyInterval: interval}, nil)
+
+			statusCopy := status.DeepCopy()
+			statusCopy.LastVerifiedTime = lastVerified
+
+			getNode := expectNodeReadyBeforeLoad()
+
+			if shouldCreate {
+				pm.EXPECT().CreateVerifierPod(ctx, nmc, statusCopy).After(getNode)
+			}
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, statusCopy),
+			).NotTo(
+				HaveOccurred(),
+			)
+		},
+		Entry("verification disabled", time.Duration(0), nil, false),
+		Entry("loaded less than an interval ago", time.Hour, nil, false),
+		Entry("loaded more than an interval ago", 30*time.Second, nil, true),
+		Entry("verified less than an interval ago", 30*time.Second, &now, false),
+	)
+
+	DescribeTable(
+		"should reload the module if it drifted and reloading is enabled",
+		func(reloadOnDrift bool) {
+			wh = newNMCReconcilerHelper(client, pm, nil, &config.Worker{ReloadOnDrift: reloadOnDrift}, nil)
+
+			statusCopy := status.DeepCopy()
+			statusCopy.Conditions = []metav1.Condition{
+				{
+					Type:   kmmv1beta1.NodeModuleConditionVerified,
+					Status: metav1.ConditionFalse,
+					Reason: "ModuleNotLoaded",
+				},
+			}
+
+			getNode := expectNodeReadyBeforeLoad()
+
+			if reloadOnDrift {
+				pm.EXPECT().CreateLoaderPod(ctx, nmc, spec).After(getNode)
+			}
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, statusCopy),
+			).NotTo(
+				HaveOccurred(),
+			)
+		},
+		Entry("reload enabled", true),
+		Entry("reload disabled", false),
+	)
+
+	It("should do nothing if the pod is not loading a kmod", func() {
+		pm.
+			EXPECT().
+			GetWorkerPod(ctx, podName, namespace).
+			Return(&v1.Pod{}, nil)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should do nothing if the loader Pod has completed", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{actionLabelKey: WorkerActionLoad},
+			},
+			Status: v1.PodStatus{Phase: v1.PodFailed},
+		}
+
+		pm.
+			EXPECT().
+			GetWorkerPod(ctx, podName, namespace).
+			Return(&pod, nil)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should keep a running loader Pod if its hash annotation is current", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{actionLabelKey: WorkerActionLoad},
+				Annotations: map[string]string{hashAnnotationKey: "123"},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+
+		gomock.InOrder(
+			pm.
+				EXPECT().
+				GetWorkerPod(ctx, podName, namespace).
+				Return(&pod, nil),
+			pm.
+				EXPECT().
+				LoaderPodTemplate(ctx, nmc, spec).
+				Return(pod.DeepCopy(), nil),
+		)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if there was an error making the Pod template", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{actionLabelKey: WorkerActionLoad},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+
+		gomock.InOrder(
+			pm.
+				EXPECT().
+				GetWorkerPod(ctx, podName, namespace).
+				Return(&pod, nil),
+			pm.
+				EXPECT().
+				LoaderPodTemplate(ctx, nmc, spec).
+				Return(nil, errors.New("random error")),
+		)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should delete a pending loader Pod if its hash annotation is outdated", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{actionLabelKey: WorkerActionLoad},
+				Annotations: map[string]string{hashAnnotationKey: "123"},
+			},
+			Status: v1.PodStatus{Phase: v1.PodPending},
+		}
+
+		podTemplate := pod.DeepCopy()
+		podTemplate.Annotations[hashAnnotationKey] = "456"
+
+		gomock.InOrder(
+			pm.
+				EXPECT().
+				GetWorkerPod(ctx, podName, namespace).
+				Return(&pod, nil),
+			pm.
+				EXPECT().
+				LoaderPodTemplate(ctx, nmc, spec).
+				Return(podTemplate, nil),
+			pm.
+				EXPECT().
+				DeletePod(ctx, &pod),
+		)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
+
+var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func() {
+	const name = "name"
+
+	var (
+		ctx     = context.TODO()
+		podName = workerPodName(nmcName, name)
+
+		client *testclient.MockClient
+		pm     *MockpodManager
+		helper nmcReconcilerHelper
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client = testclient.NewMockClient(ctrl)
+		pm = NewMockpodManager(ctrl)
+		helper = newNMCReconcilerHelper(client, pm, nil, &config.Worker{}, nil)
+	})
+
+	nmc := &kmmv1beta1.NodeModulesConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+	}
+
+	status := &kmmv1beta1.NodeModuleStatus{
+		ModuleItem: kmmv1beta1.ModuleItem{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Config: &kmmv1beta1.ModuleConfig{},
+	}
+
+	It("should create an unloader Pod if no worker Pod exists", func() {
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			pm.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should not create an unloader Pod while a dependent module is loaded", func() {
+		nmcWithDependent := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					*status,
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Name:         "dependent",
+							Namespace:    namespace,
+							Dependencies: []kmmv1beta1.ModuleDependency{{Name: name, Namespace: namespace}},
+						},
+						Config: &kmmv1beta1.ModuleConfig{},
+					},
+				},
+			},
+		}
+
+		pm.EXPECT().GetWorkerPod(ctx, podName, namespace)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmcWithDependent, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should create an unloader Pod if the dependent modules were never loaded", func() {
+		nmcWithDependent := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					*status,
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Name:         "dependent",
+							Namespace:    namespace,
+							Dependencies: []kmmv1beta1.ModuleDependency{{Name: name, Namespace: namespace}},
+						},
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			pm.EXPECT().CreateUnloaderPod(ctx, nmcWithDependent, status),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmcWithDependent, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should remove the status if the module was never loaded", func() {
+		failedStatus := kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+
+		nmcWithStatus := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{failedStatus},
+			},
+		}
+
+		sw := testclient.NewMockStatusWriter(gomock.NewController(GinkgoT()))
+
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcWithStatus, gomock.Any()),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmcWithStatus, &failedStatus),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmcWithStatus.Status.Modules).To(BeEmpty())
+	})
+
+	DescribeTable(
+		"should delete the current worker if it is loading or verifying a module",
+		func(action string) {
+			pod := v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podName,
+					Namespace: namespace,
+					Labels:    map[string]string{actionLabelKey: action},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
+			).NotTo(
+				HaveOccurred(),
+			)
+		},
+		Entry("loading", WorkerActionLoad),
+		Entry("verifying", WorkerActionVerify),
+	)
+
+	It("should do nothing if the pod has not restarted yet", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: namespace,
+			},
+		}
+
+		pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if there was an error while making the unloader Pod", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: namespace,
+			},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         workerContainerName,
+						RestartCount: 1,
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
+			pm.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(nil, errors.New("random error")),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should delete the existing pod if its hash annotation is outdated", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        podName,
+				Namespace:   namespace,
+				Annotations: map[string]string{hashAnnotationKey: "123"},
+			},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         workerContainerName,
+						RestartCount: 1,
+					},
+				},
+			},
+		}
+
+		podTemplate := pod.DeepCopy()
+		podTemplate.Annotations[hashAnnotationKey] = "456"
+
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
+			pm.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(podTemplate, nil),
+			pm.EXPECT().DeletePod(ctx, &pod),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+})
+
+var _ = Describe("nmcReconcilerHelperImpl_prepareNodeForUnload", func() {
+	const name = "name"
+
+	var (
+		ctx = context.TODO()
+
+		client  *testclient.MockClient
+		drainer *drain.MockDrainer
+		sw      *testclient.MockStatusWriter
+		h       *nmcReconcilerHelperImpl
+
+		nmcObj *kmmv1beta1.NodeModulesConfig
+		status *kmmv1beta1.NodeModuleStatus
+		policy *kmmv1beta1.MaintenancePolicy
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client = testclient.NewMockClient(ctrl)
+		drainer = drain.NewMockDrainer(ctrl)
+		sw = testclient.NewMockStatusWriter(ctrl)
+		h = newNMCReconcilerHelper(client, nil, nil, &config.Worker{}, drainer).(*nmcReconcilerHelperImpl)
+
+		nmcObj = &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: name, Namespace: namespace},
+						Config:     &kmmv1beta1.ModuleConfig{},
+					},
+				},
+			},
+		}
+		status = &nmcObj.Status.Modules[0]
+		policy = &kmmv1beta1.MaintenancePolicy{ResourceNames: []v1.ResourceName{"example.com/gpu"}}
+	})
+
+	expectNode := func(unschedulable bool) *gomock.Call {
+		return client.
+			EXPECT().
+			Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
+			Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node, _ ...ctrlclient.GetOption) {
+				node.Name = nmcName
+				node.Spec.Unschedulable = unschedulable
+			})
+	}
+
+	It("should do nothing without a maintenance policy", func() {
+		Expect(
+			h.prepareNodeForUnload(ctx, nmcObj, status, nil),
+		).To(
+			BeTrue(),
+		)
+	})
+
+	It("should cordon a schedulable node and wait for the Pods to be evicted", func() {
+		gomock.InOrder(
+			expectNode(false),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()).Do(func(_ context.Context, obj ctrlclient.Object, _ ctrlclient.Patch, _ ...ctrlclient.SubResourcePatchOption) {
+				Expect(
+					apimeta.IsStatusConditionTrue(obj.(*kmmv1beta1.NodeModulesConfig).Status.Conditions, kmmv1beta1.NodeModulesConfigConditionCordoned),
+				).To(
+					BeTrue(),
+				)
+			}),
+			drainer.EXPECT().Cordon(ctx, gomock.Any()),
+			drainer.EXPECT().Drain(ctx, nmcName, policy).Return(2, nil),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+		)
+
+		ok, err := h.prepareNodeForUnload(ctx, nmcObj, status, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+
+		cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionDrained)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("Draining"))
+	})
+
+	It("should resume the maintenance of a node cordoned by KMM", func() {
+		nmcObj.Status.Conditions = []metav1.Condition{
+			{Type: kmmv1beta1.NodeModulesConfigConditionCordoned, Status: metav1.ConditionTrue, Reason: "Maintenance"},
+		}
+
+		gomock.InOrder(
+			expectNode(true),
+			drainer.EXPECT().Cordon(ctx, gomock.Any()),
+			drainer.EXPECT().Drain(ctx, nmcName, policy).Return(0, nil),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+		)
+
+		ok, err := h.prepareNodeForUnload(ctx, nmcObj, status, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(
+			apimeta.IsStatusConditionTrue(status.Conditions, kmmv1beta1.NodeModuleConditionDrained),
+		).To(
+			BeTrue(),
+		)
+	})
+
+	It("should not take over the cordon of a node that was already unschedulable", func() {
+		gomock.InOrder(
+			expectNode(true),
+			drainer.EXPECT().Drain(ctx, nmcName, policy).Return(0, nil),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+		)
+
+		ok, err := h.prepareNodeForUnload(ctx, nmcObj, status, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(nmcObj.Status.Conditions).To(BeEmpty())
+	})
+
+	It("should return an error if the node could not be drained", func() {
+		gomock.InOrder(
+			expectNode(true),
+			drainer.EXPECT().Drain(ctx, nmcName, policy).Return(0, errors.New("random error")),
+		)
+
+		_, err := h.prepareNodeForUnload(ctx, nmcObj, status, policy)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("nmcReconcilerHelperImpl_FinishMaintenance", func() {
+	var (
+		ctx = context.TODO()
+
+		client  *testclient.MockClient
+		drainer *drain.MockDrainer
+		sw      *testclient.MockStatusWriter
+		wh      nmcReconcilerHelper
+
+		policy    *kmmv1beta1.MaintenancePolicy
+		cordoned  metav1.Condition
+		draining  metav1.Condition
+		oldConfig kmmv1beta1.ModuleConfig
+		newConfig kmmv1beta1.ModuleConfig
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client = testclient.NewMockClient(ctrl)
+		drainer = drain.NewMockDrainer(ctrl)
+		sw = testclient.NewMockStatusWriter(ctrl)
+		wh = newNMCReconcilerHelper(client, nil, nil, &config.Worker{}, drainer)
+
+		policy = &kmmv1beta1.MaintenancePolicy{}
+		cordoned = metav1.Condition{Type: kmmv1beta1.NodeModulesConfigConditionCordoned, Status: metav1.ConditionTrue, Reason: "Maintenance"}
+		draining = metav1.Condition{Type: kmmv1beta1.NodeModuleConditionDrained, Status: metav1.ConditionTrue, Reason: "Drained"}
+		oldConfig = kmmv1beta1.ModuleConfig{ContainerImage: "old-image"}
+		newConfig = kmmv1beta1.ModuleConfig{ContainerImage: "new-image"}
+	})
+
+	item := kmmv1beta1.ModuleItem{Name: "name", Namespace: namespace}
+
+	It("should do nothing if the node was not cordoned by KMM", func() {
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		Expect(
+			wh.FinishMaintenance(ctx, nmcObj),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should keep the node cordoned while a module is waiting for its new config", func() {
+		policyItem := item
+		policyItem.MaintenancePolicy = policy
+
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{{ModuleItem: policyItem, Config: newConfig}},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Conditions: []metav1.Condition{cordoned},
+			},
+		}
+
+		Expect(
+			wh.FinishMaintenance(ctx, nmcObj),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmcObj.Status.Conditions).To(HaveLen(1))
+	})
+
+	It("should keep the node cordoned while a module has never been loaded", func() {
+		policyItem := item
+		policyItem.MaintenancePolicy = policy
+
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{{ModuleItem: policyItem, Config: newConfig}},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Conditions: []metav1.Condition{cordoned},
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{ModuleItem: policyItem, Conditions: []metav1.Condition{draining}},
+				},
+			},
+		}
+
+		Expect(
+			wh.FinishMaintenance(ctx, nmcObj),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmcObj.Status.Conditions).To(HaveLen(1))
+		Expect(nmcObj.Status.Modules[0].Conditions).To(HaveLen(1))
+	})
+
+	It("should keep the node cordoned while a module is waiting to be unloaded", func() {
+		policyItem := item
+		policyItem.MaintenancePolicy = policy
+
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Conditions: []metav1.Condition{cordoned},
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{ModuleItem: policyItem, Config: &oldConfig, Conditions: []metav1.Condition{draining}},
+				},
+			},
+		}
+
+		Expect(
+			wh.FinishMaintenance(ctx, nmcObj),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmcObj.Status.Conditions).To(HaveLen(1))
+		Expect(nmcObj.Status.Modules[0].Conditions).To(HaveLen(1))
+	})
+
+	It("should uncordon the node once all modules are loaded with their new config", func() {
+		policyItem := item
+		policyItem.MaintenancePolicy = policy
+
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{{ModuleItem: policyItem, Config: newConfig}},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Conditions: []metav1.Condition{cordoned},
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{ModuleItem: policyItem, Config: &newConfig, Conditions: []metav1.Condition{draining}},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			client.EXPECT().Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}),
+			drainer.EXPECT().Uncordon(ctx, gomock.Any()),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+		)
+
+		Expect(
+			wh.FinishMaintenance(ctx, nmcObj),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmcObj.Status.Conditions).To(BeEmpty())
+		Expect(nmcObj.Status.Modules[0].Conditions).To(BeEmpty())
+	})
+
+	Context("when a module failed to load the maximum number of times", func() {
+		failed := metav1.Condition{Type: kmmv1beta1.NodeModuleConditionFailed, Status: metav1.ConditionTrue, Reason: "MaxLoadAttemptsReached"}
+
+		newNMC := func(failedConfig *kmmv1beta1.ModuleConfig) *kmmv1beta1.NodeModulesConfig {
+			GinkgoHelper()
+
+			policyItem := item
+			policyItem.MaintenancePolicy = policy
+
+			hash, err := moduleConfigHash(failedConfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			return &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Spec: kmmv1beta1.NodeModulesConfigSpec{
+					Modules: []kmmv1beta1.NodeModuleSpec{{ModuleItem: policyItem, Config: newConfig}},
+				},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					Conditions: []metav1.Condition{cordoned},
+					Modules: []kmmv1beta1.NodeModuleStatus{
+						{
+							ModuleItem:       policyItem,
+							Conditions:       []metav1.Condition{draining, failed},
+							FailedAttempts:   3,
+							FailedConfigHash: hash,
+						},
+					},
+				},
+			}
+		}
+
+		It("should uncordon the node if the failed config is the current one", func() {
+			nmcObj := newNMC(&newConfig)
+
+			gomock.InOrder(
+				client.EXPECT().Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}),
+				drainer.EXPECT().Uncordon(ctx, gomock.Any()),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			)
+
+			Expect(
+				wh.FinishMaintenance(ctx, nmcObj),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.Conditions).To(BeEmpty())
+		})
+
+		It("should keep the node cordoned if the config changed since", func() {
+			nmcObj := newNMC(&oldConfig)
+
+			Expect(
+				wh.FinishMaintenance(ctx, nmcObj),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.Conditions).To(HaveLen(1))
+		})
+	})
+
+	It("should return an error if the node could not be uncordoned", func() {
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Conditions: []metav1.Condition{cordoned},
+			},
+		}
+
+		gomock.InOrder(
+			client.EXPECT().Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}),
+			drainer.EXPECT().Uncordon(ctx, gomock.Any()).Return(errors.New("random error")),
+		)
+
+		Expect(
+			wh.FinishMaintenance(ctx, nmcObj),
+		).To(
+			HaveOccurred(),
+		)
+	})
+})
+
+var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
+	var (
+		ctx = context.TODO()
+
+		ctrl       *gomock.Controller
+		kubeClient *testclient.MockClient
+		pm         *MockpodManager
+		sw         *testclient.MockStatusWriter
+		wh         nmcReconcilerHelper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubeClient = testclient.NewMockClient(ctrl)
+		pm = NewMockpodManager(ctrl)
+		wh = newNMCReconcilerHelper(kubeClient, pm, nil, &config.Worker{}, nil)
+		sw = testclient.NewMockStatusWriter(ctrl)
+	})
+
+	const (
+		podName      = "pod-name"
+		podNamespace = "pod-namespace"
+	)
+
+	It("should do nothing if there are no running pods for this NMC", func() {
+		pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		Expect(
+			wh.SyncStatus(ctx, nmc),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("failed pods", func() {
+		podWithStatus := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: podNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: "mod name 1",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodFailed},
+		}
+
+		podWithoutStatus := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: podNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: "mod name 2",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodFailed},
+		}
+
+		pods := []v1.Pod{podWithStatus, podWithoutStatus}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Name:      "mod name 1",
+							Namespace: podNamespace,
+						},
+						Config: &kmmv1beta1.ModuleConfig{ContainerImage: "some image"},
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return(pods, nil),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			pm.EXPECT().DeletePod(ctx, &podWithStatus),
+			pm.EXPECT().DeletePod(ctx, &podWithoutStatus),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmc),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmc.Status.Modules).To(HaveLen(1))
+	})
+
+	Context("failed loader pods", func() {
+		const modName = "module"
+
+		var (
+			cfg  kmmv1beta1.ModuleConfig
+			hash string
+			nmc  *kmmv1beta1.NodeModulesConfig
+			pod  v1.Pod
+		)
+
+		BeforeEach(func() {
+			cfg = kmmv1beta1.ModuleConfig{ContainerImage: "some-image"}
+
+			var err error
+
+			hash, err = moduleConfigHash(&cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			pod = v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: podNamespace,
+					Name:      podName,
+					Labels: map[string]string{
+						actionLabelKey:            WorkerActionLoad,
+						constants.ModuleNameLabel: modName,
+					},
+					Annotations: map[string]string{configAnnotationKey: "containerImage: some-image"},
+				},
+				Status: v1.PodStatus{Phase: v1.PodFailed},
+			}
+
+			nmc = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			}
+		})
+
+		It("should record the attempt and schedule the next one", func() {
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+				{
+					ModuleItem:       kmmv1beta1.ModuleItem{Name: modName, Namespace: podNamespace},
+					FailedAttempts:   2,
+					FailedConfigHash: hash,
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			before := time.Now()
+
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmc.Status.Modules).To(HaveLen(1))
+
+			status := nmc.Status.Modules[0]
+			Expect(status.FailedAttempts).To(BeEquivalentTo(3))
+			Expect(status.NextRetryTime).NotTo(BeNil())
+			Expect(status.NextRetryTime.Time).To(BeTemporally(">=", before.Add(40*time.Second)))
+			Expect(
+				apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionFailed),
+			).To(
+				BeNil(),
+			)
+		})
+
+		It("should reset the counter if the config changed", func() {
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+				{
+					ModuleItem:       kmmv1beta1.ModuleItem{Name: modName, Namespace: podNamespace},
+					FailedAttempts:   5,
+					FailedConfigHash: "some-other-hash",
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmc.Status.Modules[0].FailedAttempts).To(BeEquivalentTo(1))
+			Expect(nmc.Status.Modules[0].FailedConfigHash).To(Equal(hash))
+		})
+
+		It("should mark the module as Failed once the maximum number of attempts is reached", func() {
+			wh = newNMCReconcilerHelper(kubeClient, pm, nil, &config.Worker{MaxLoadAttempts: 3}, nil)
+
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+				{
+					ModuleItem:       kmmv1beta1.ModuleItem{Name: modName, Namespace: podNamespace},
+					FailedAttempts:   2,
+					FailedConfigHash: hash,
+					NextRetryTime:    &metav1.Time{Time: time.Now()},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			status := nmc.Status.Modules[0]
+			Expect(status.FailedAttempts).To(BeEquivalentTo(3))
+			Expect(status.NextRetryTime).To(BeNil())
+
+			cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionFailed)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal("MaxLoadAttemptsReached"))
+		})
+	})
+
+	It("should add a status with a failed Loaded condition and record an event if a loader pod failed", func() {
+		const (
+			modName            = "module"
+			serviceAccountName = "some-sa"
+		)
+
+		fakeRecorder := record.NewFakeRecorder(1)
+		wh = newNMCReconcilerHelper(kubeClient, pm, fakeRecorder, &config.Worker{}, nil)
+
+		finishedAt := metav1.Now()
+
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: podNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					actionLabelKey:            WorkerActionLoad,
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Spec: v1.PodSpec{ServiceAccountName: serviceAccountName},
+			Status: v1.PodStatus{
+				Phase: v1.PodFailed,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								ExitCode:   1,
+								FinishedAt: finishedAt,
+								Message:    `{"phase":"Modprobe","reason":"UnknownSymbol","exitCode":1,"message":"modprobe failed","kernelLog":["line 1","line 2"]}`,
+							},
+						},
+					},
+				},
+			},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: podNamespace},
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			pm.EXPECT().DeletePod(ctx, &pod),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmc),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmc.Status.Modules).To(HaveLen(1))
+
+		status := nmc.Status.Modules[0]
+		Expect(status.ModuleItem).To(Equal(kmmv1beta1.ModuleItem{
+			Name:               modName,
+			Namespace:          podNamespace,
+			ServiceAccountName: serviceAccountName,
+		}))
+		Expect(status.Config).To(BeNil())
+		Expect(status.FailedAttempts).To(BeEquivalentTo(1))
+		Expect(status.Conditions).To(Equal([]metav1.Condition{
+			{
+				Type:               kmmv1beta1.NodeModuleConditionLoaded,
+				Status:             metav1.ConditionFalse,
+				Reason:             "UnknownSymbol",
+				Message:            "Modprobe: modprobe failed (modprobe exit code 1)\nKernel log:\nline 1\nline 2",
+				LastTransitionTime: finishedAt,
+			},
+		}))
+
+		Expect(fakeRecorder.Events).To(
+			Receive(
+				HavePrefix("Warning UnknownSymbol Load worker for Module pod-namespace/module failed: modprobe failed"),
+			),
+		)
+	})
+
+	It("should set a failed Unloaded condition if an unloader pod failed", func() {
+		const modName = "module"
+
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: podNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					actionLabelKey:            WorkerActionUnload,
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodFailed,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{ExitCode: 137},
+						},
+					},
+				},
+			},
+		}
+
+		cfg := kmmv1beta1.ModuleConfig{ContainerImage: "some image"}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: podNamespace},
+						Config:     &cfg,
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			pm.EXPECT().DeletePod(ctx, &pod),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmc),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmc.Status.Modules).To(HaveLen(1))
+		Expect(nmc.Status.Modules[0].Config).To(Equal(&cfg))
+		Expect(nmc.Status.Modules[0].Conditions).To(HaveLen(1))
+
+		cond := nmc.Status.Modules[0].Conditions[0]
+		Expect(cond.Type).To(Equal(kmmv1beta1.NodeModuleConditionUnloaded))
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("WorkerFailed"))
+		Expect(cond.Message).To(Equal("worker exited with code 137"))
+	})
+
+	Context("verifier pods", func() {
+		const modName = "module"
+
+		var (
+			finishedAt = metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+			nmc        *kmmv1beta1.NodeModulesConfig
+		)
+
+		BeforeEach(func() {
+			nmc = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					Modules: []kmmv1beta1.NodeModuleStatus{
+						{
+							ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: podNamespace},
+							Config:     &kmmv1beta1.ModuleConfig{ContainerImage: "some image"},
+						},
+					},
+				},
+			}
+		})
+
+		verifierPod := func(phase v1.PodPhase, terminated v1.ContainerStateTerminated) v1.Pod {
+			terminated.FinishedAt = finishedAt
+
+			return v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: podNamespace,
+					Name:      podName,
+					Labels: map[string]string{
+						actionLabelKey:            WorkerActionVerify,
+						constants.ModuleNameLabel: modName,
+					},
+				},
+				Status: v1.PodStatus{
+					Phase: phase,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name:  workerContainerName,
+							State: v1.ContainerState{Terminated: &terminated},
+						},
+					},
+				},
+			}
+		}
+
+		It("should set a successful Verified condition if the verifier pod was successful", func() {
+			pod := verifierPod(v1.PodSucceeded, v1.ContainerStateTerminated{})
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmc.Status.Modules).To(HaveLen(1))
+
+			status := nmc.Status.Modules[0]
+			Expect(status.LastVerifiedTime).To(Equal(&finishedAt))
+			Expect(status.LastTransitionTime.IsZero()).To(BeTrue())
+
+			cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionVerified)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.LastTransitionTime).To(Equal(finishedAt))
+		})
+
+		It("should set a failed Verified condition if the module is no longer loaded", func() {
+			fakeRecorder := record.NewFakeRecorder(1)
+			wh = newNMCReconcilerHelper(kubeClient, pm, fakeRecorder, &config.Worker{}, nil)
+
+			pod := verifierPod(
+				v1.PodFailed,
+				v1.ContainerStateTerminated{
+					ExitCode: 1,
+					Message:  `{"phase":"VerifyLoaded","reason":"ModuleNotLoaded","exitCode":1,"message":"module is not loaded"}`,
+				},
+			)
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmc.Status.Modules).To(HaveLen(1))
+
+			status := nmc.Status.Modules[0]
+			Expect(status.LastVerifiedTime).To(Equal(&finishedAt))
+			Expect(moduleDrifted(&status)).To(BeTrue())
+
+			cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionVerified)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("ModuleNotLoaded"))
+
+			Expect(fakeRecorder.Events).To(
+				Receive(
+					HavePrefix("Warning ModuleNotLoaded Verify worker for Module pod-namespace/module failed: module is not loaded"),
+				),
+			)
+		})
+
+		It("should not consider the module drifted if the verifier pod was killed", func() {
+			pod := verifierPod(v1.PodFailed, v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"})
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmc.Status.Modules).To(HaveLen(1))
+
+			status := nmc.Status.Modules[0]
+			Expect(moduleDrifted(&status)).To(BeFalse())
+
+			cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionVerified)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("WorkerFailed"))
+		})
+	})
+
+	Context("running pods", func() {
+		const modName = "module"
+
+		var (
+			nmc *kmmv1beta1.NodeModulesConfig
+			pod v1.Pod
+		)
+
+		BeforeEach(func() {
+			nmc = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Spec: kmmv1beta1.NodeModulesConfigSpec{
+					Modules: []kmmv1beta1.NodeModuleSpec{
+						{
+							ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: podNamespace},
+						},
+					},
+				},
+			}
+
+			pod = v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   podNamespace,
+					Name:        podName,
+					Annotations: map[string]string{deadlineAnnotationKey: "10m0s"},
+					Labels: map[string]string{
+						actionLabelKey:            WorkerActionLoad,
+						constants.ModuleNameLabel: modName,
+					},
+				},
+				Status: v1.PodStatus{Phase: v1.PodRunning},
+			}
+
+			Expect(setWorkerConfigAnnotation(&pod, kmmv1beta1.ModuleConfig{ContainerImage: "some image"})).To(Succeed())
+		})
+
+		setStartedAt := func(t time.Time) {
+			pod.Status.ContainerStatuses = []v1.ContainerStatus{
+				{
+					Name: workerContainerName,
+					State: v1.ContainerState{
+						Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(t)},
+					},
+				},
+			}
 		}
 
-		podTemplate := pod.DeepCopy()
-		podTemplate.Annotations[hashAnnotationKey] = "456"
+		It("should not delete a pod within its deadline", func() {
+			setStartedAt(time.Now().Add(-time.Minute))
 
-		gomock.InOrder(
-			pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
-			pm.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(podTemplate, nil),
-			pm.EXPECT().DeletePod(ctx, &pod),
-		)
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
 
-		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
 
-})
+			Expect(nmc.Status.Modules).To(BeEmpty())
+		})
 
-var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
-	var (
-		ctx = context.TODO()
+		It("should record a failed load and delete a pod that exceeded its deadline", func() {
+			fakeRecorder := record.NewFakeRecorder(1)
+			wh = newNMCReconcilerHelper(kubeClient, pm, fakeRecorder, &config.Worker{}, nil)
 
-		ctrl       *gomock.Controller
-		kubeClient *testclient.MockClient
-		pm         *MockpodManager
-		sw         *testclient.MockStatusWriter
-		wh         nmcReconcilerHelper
-	)
+			setStartedAt(time.Now().Add(-time.Hour))
 
-	BeforeEach(func() {
-		ctrl = gomock.NewController(GinkgoT())
-		kubeClient = testclient.NewMockClient(ctrl)
-		pm = NewMockpodManager(ctrl)
-		wh = newNMCReconcilerHelper(kubeClient, pm, nil)
-		sw = testclient.NewMockStatusWriter(ctrl)
-	})
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
 
-	const (
-		podName      = "pod-name"
-		podNamespace = "pod-namespace"
-	)
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
 
-	It("should do nothing if there are no running pods for this NMC", func() {
-		pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName)
+			Expect(nmc.Status.Modules).To(HaveLen(1))
 
-		nmc := &kmmv1beta1.NodeModulesConfig{
-			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
-		}
+			status := nmc.Status.Modules[0]
+			Expect(status.FailedAttempts).To(BeEquivalentTo(1))
+			Expect(status.NextRetryTime).NotTo(BeNil())
 
-		Expect(
-			wh.SyncStatus(ctx, nmc),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+			cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionLoaded)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("Timeout"))
+			Expect(cond.Message).To(Equal("worker Pod pod-name did not complete within 10m0s"))
 
-	It("failed pods", func() {
-		podWithStatus := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: podNamespace,
-				Name:      podName,
-				Labels: map[string]string{
-					constants.ModuleNameLabel: "mod name 1",
-				},
-			},
-			Status: v1.PodStatus{Phase: v1.PodFailed},
-		}
+			Expect(fakeRecorder.Events).To(
+				Receive(
+					HavePrefix("Warning Timeout Load worker for Module pod-namespace/module failed: worker Pod pod-name did not complete within 10m0s"),
+				),
+			)
+		})
 
-		podWithoutStatus := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: podNamespace,
-				Name:      podName,
-				Labels: map[string]string{
-					constants.ModuleNameLabel: "mod name 2",
+		It("should delete a pending pod that exceeded its deadline since it was created", func() {
+			wh = newNMCReconcilerHelper(kubeClient, pm, record.NewFakeRecorder(1), &config.Worker{}, nil)
+
+			pod.Status.Phase = v1.PodPending
+			pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+			pod.Status.ContainerStatuses = []v1.ContainerStatus{
+				{
+					Name: workerContainerName,
+					State: v1.ContainerState{
+						Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+					},
 				},
-			},
-			Status: v1.PodStatus{Phase: v1.PodFailed},
-		}
+			}
 
-		pods := []v1.Pod{podWithStatus, podWithoutStatus}
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
 
-		nmc := &kmmv1beta1.NodeModulesConfig{
-			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
-			Status: kmmv1beta1.NodeModulesConfigStatus{
-				Modules: []kmmv1beta1.NodeModuleStatus{
-					{
-						ModuleItem: kmmv1beta1.ModuleItem{
-							Name:      "mod name 1",
-							Namespace: podNamespace,
-						},
-						Config: kmmv1beta1.ModuleConfig{ContainerImage: "some image"},
-					},
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmc.Status.Modules).To(HaveLen(1))
+			Expect(nmc.Status.Modules[0].FailedAttempts).To(BeEquivalentTo(1))
+		})
+
+		It("should not delete a pending pod scheduled within its deadline", func() {
+			pod.Status.Phase = v1.PodPending
+			pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+			pod.Status.Conditions = []v1.PodCondition{
+				{
+					Type:               v1.PodScheduled,
+					Status:             v1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute)),
 				},
-			},
-		}
+			}
 
-		gomock.InOrder(
-			pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return(pods, nil),
-			kubeClient.EXPECT().Status().Return(sw),
-			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
-			pm.EXPECT().DeletePod(ctx, &podWithStatus),
-			pm.EXPECT().DeletePod(ctx, &podWithoutStatus),
-		)
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
 
-		Expect(
-			wh.SyncStatus(ctx, nmc),
-		).NotTo(
-			HaveOccurred(),
-		)
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
 
-		Expect(nmc.Status.Modules).To(HaveLen(1))
+			Expect(nmc.Status.Modules).To(BeEmpty())
+		})
 	})
 
 	It("should remove the status and label if an unloader pod was successful", func() {
@@ -958,8 +2701,25 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 			serviceAccountName = "some-sa"
 		)
 
+		timeouts := &kmmv1beta1.ModuleTimeouts{Load: &metav1.Duration{Duration: time.Minute}}
+		policy := &kmmv1beta1.MaintenancePolicy{ResourceNames: []v1.ResourceName{"example.com/gpu"}}
+		deps := []kmmv1beta1.ModuleDependency{{Name: "dep", Namespace: modNamespace}}
+
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Name:              modName,
+							Namespace:         modNamespace,
+							Timeouts:          timeouts,
+							MaintenancePolicy: policy,
+							Dependencies:      deps,
+						},
+					},
+				},
+			},
 			Status: kmmv1beta1.NodeModulesConfigStatus{
 				Modules: []kmmv1beta1.NodeModuleStatus{
 					{
@@ -967,6 +2727,14 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 							Name:      modName,
 							Namespace: modNamespace,
 						},
+						// The module is reloaded because it drifted.
+						Conditions: []metav1.Condition{
+							{
+								Type:   kmmv1beta1.NodeModuleConditionVerified,
+								Status: metav1.ConditionFalse,
+								Reason: "ModuleNotLoaded",
+							},
+						},
 					},
 				},
 			},
@@ -1022,6 +2790,19 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 
 		gomock.InOrder(
 			pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+			kubeClient.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
+				Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node, _ ...ctrlclient.GetOption) {
+					node.Status.Conditions = []v1.NodeCondition{
+						{
+							Type:               v1.NodeReady,
+							Status:             v1.ConditionTrue,
+							LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+						},
+					}
+					node.Status.NodeInfo.BootID = "some-boot-id"
+				}),
 			kubeClient.EXPECT().Status().Return(sw),
 			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
 			pm.EXPECT().DeletePod(ctx, &pod),
@@ -1041,14 +2822,150 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 				Name:               modName,
 				Namespace:          modNamespace,
 				ServiceAccountName: serviceAccountName,
+				Timeouts:           timeouts,
+				MaintenancePolicy:  policy,
+				Dependencies:       deps,
+			},
+			BootID: "some-boot-id",
+			Config: &cfg,
+			Conditions: []metav1.Condition{
+				{
+					Type:               kmmv1beta1.NodeModuleConditionLoaded,
+					Status:             metav1.ConditionTrue,
+					Reason:             "Loaded",
+					Message:            "Module loaded by worker Pod " + pod.Name,
+					LastTransitionTime: now,
+				},
 			},
-			Config:             cfg,
 			LastTransitionTime: now,
 		}
 
 		Expect(nmc.Status.Modules[0]).To(BeComparableTo(expectedStatus))
 	})
 
+	Context("parameters pods", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		var (
+			nmc       *kmmv1beta1.NodeModulesConfig
+			oldCfg    kmmv1beta1.ModuleConfig
+			newCfg    kmmv1beta1.ModuleConfig
+			pod       v1.Pod
+			loadedLTT metav1.Time
+		)
+
+		BeforeEach(func() {
+			loadedLTT = metav1.NewTime(time.Now().Add(-time.Hour))
+			oldCfg = kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{Parameters: []string{"a=1"}}}
+			newCfg = kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{Parameters: []string{"a=2"}}}
+
+			nmc = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					Modules: []kmmv1beta1.NodeModuleStatus{
+						{
+							ModuleItem:         kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+							Config:             &oldCfg,
+							LastTransitionTime: loadedLTT,
+						},
+					},
+				},
+			}
+
+			pod = v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: modNamespace,
+					Name:      podName,
+					Labels: map[string]string{
+						actionLabelKey:            WorkerActionSetParameters,
+						constants.ModuleNameLabel: modName,
+					},
+				},
+			}
+
+			Expect(setWorkerConfigAnnotation(&pod, newCfg)).To(Succeed())
+		})
+
+		It("should update the config in the status if the parameters were updated", func() {
+			now := metav1.Now()
+
+			pod.Status = v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{FinishedAt: now},
+						},
+					},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			status := nmc.Status.Modules[0]
+			Expect(status.Config).To(Equal(&newCfg))
+			Expect(status.LastTransitionTime).To(Equal(loadedLTT))
+
+			cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionParametersUpdated)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.LastTransitionTime).To(Equal(now))
+		})
+
+		It("should record the config if the parameters could not be updated", func() {
+			pod.Status = v1.PodStatus{
+				Phase: v1.PodFailed,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{ExitCode: 1},
+						},
+					},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmc),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			hash, err := moduleConfigHash(&newCfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			status := nmc.Status.Modules[0]
+			Expect(status.Config).To(Equal(&oldCfg))
+			Expect(status.FailedParametersConfigHash).To(Equal(hash))
+
+			cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.NodeModuleConditionParametersUpdated)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		})
+	})
+
 	It("pod should not be deleted if NMC patch failed", func() {
 		const (
 			modName      = "module"
@@ -1094,6 +3011,41 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 	})
 })
 
+var _ = Describe("nmcReconcilerHelperImpl_bootIDAtLoad", func() {
+	ctx := context.TODO()
+	loadedAt := metav1.Now()
+
+	DescribeTable(
+		"should work as expected",
+		func(getErr error, readySince time.Duration, expected string) {
+			ctrl := gomock.NewController(GinkgoT())
+			kubeClient := testclient.NewMockClient(ctrl)
+
+			kubeClient.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Name: nmcName}, &v1.Node{}).
+				Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node, _ ...ctrlclient.GetOption) {
+					node.Status.Conditions = []v1.NodeCondition{
+						{
+							Type:               v1.NodeReady,
+							Status:             v1.ConditionTrue,
+							LastTransitionTime: metav1.NewTime(loadedAt.Add(-readySince)),
+						},
+					}
+					node.Status.NodeInfo.BootID = "some-boot-id"
+				}).
+				Return(getErr)
+
+			h := newNMCReconcilerHelper(kubeClient, nil, nil, &config.Worker{}, nil).(*nmcReconcilerHelperImpl)
+
+			Expect(h.bootIDAtLoad(ctx, nmcName, loadedAt)).To(Equal(expected))
+		},
+		Entry("node ready before the load", nil, time.Hour, "some-boot-id"),
+		Entry("node ready again after the load", nil, -time.Minute, ""),
+		Entry("node not found", errors.New("random error"), time.Hour, ""),
+	)
+})
+
 var _ = Describe("nmcReconcilerHelperImpl_RemovePodFinalizers", func() {
 	const nodeName = "node-name"
 
@@ -1109,7 +3061,7 @@ var _ = Describe("nmcReconcilerHelperImpl_RemovePodFinalizers", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		kubeClient = testclient.NewMockClient(ctrl)
 		pm = NewMockpodManager(ctrl)
-		wh = newNMCReconcilerHelper(kubeClient, pm, nil)
+		wh = newNMCReconcilerHelper(kubeClient, pm, nil, &config.Worker{}, nil)
 	})
 
 	It("should do nothing if no pods are present", func() {
@@ -1180,7 +3132,7 @@ var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabelsAndRecordEvents", func
 			ObjectMeta: metav1.ObjectMeta{Name: "nmcName"},
 		}
 		fakeRecorder = record.NewFakeRecorder(10)
-		wh = newNMCReconcilerHelper(client, nil, fakeRecorder)
+		wh = newNMCReconcilerHelper(client, nil, fakeRecorder, &config.Worker{}, nil)
 	})
 
 	moduleConfig := kmmv1beta1.ModuleConfig{
@@ -1216,9 +3168,11 @@ var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabelsAndRecordEvents", func
 		statusPresent       bool
 		statusConfigPresent bool
 		configsEqual        bool
+		drifted             bool
 		resultLabelPresent  bool
 		addsReadyLabel      bool
 		removesReadyLabel   bool
+		driftsReadyLabel    bool
 	}
 
 	DescribeTable(
@@ -1254,7 +3208,16 @@ var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabelsAndRecordEvents", func
 					if !tc.configsEqual {
 						statusConfig.ContainerImage = "some other container image"
 					}
-					nmc.Status.Modules[0].Config = statusConfig
+					nmc.Status.Modules[0].Config = &statusConfig
+				}
+				if tc.drifted {
+					nmc.Status.Modules[0].Conditions = []metav1.Condition{
+						{
+							Type:   kmmv1beta1.NodeModuleConditionVerified,
+							Status: metav1.ConditionFalse,
+							Reason: "ModuleNotLoaded",
+						},
+					}
 				}
 			}
 
@@ -1279,7 +3242,7 @@ var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabelsAndRecordEvents", func
 
 			events := closeAndGetAllEvents(fakeRecorder.Events)
 
-			if !tc.addsReadyLabel && !tc.removesReadyLabel {
+			if !tc.addsReadyLabel && !tc.removesReadyLabel && !tc.driftsReadyLabel {
 				Expect(events).To(BeEmpty())
 				return
 			}
@@ -1293,6 +3256,10 @@ var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabelsAndRecordEvents", func
 			if tc.addsReadyLabel {
 				Expect(events[0]).To(ContainSubstring("Normal ModuleLoaded Module my-module-namespace/my-module loaded into the kernel"))
 			}
+
+			if tc.driftsReadyLabel {
+				Expect(events[0]).To(ContainSubstring("Warning ModuleDrifted Module my-module-namespace/my-module is no longer loaded in the kernel"))
+			}
 		},
 		Entry(
 			"node label present, spec missing, status missing",
@@ -1369,8 +3336,30 @@ var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabelsAndRecordEvents", func
 				statusPresent:       true,
 				statusConfigPresent: true,
 				configsEqual:        true,
-				resultLabelPresent:  true,
-				addsReadyLabel:      true,
+				resultLabelPresent:  true,
+				addsReadyLabel:      true,
+			},
+		),
+		Entry(
+			"node label present, spec present, status present, status config present, configs equal, drifted",
+			testCase{
+				nodeLabelPresent:    true,
+				specPresent:         true,
+				statusPresent:       true,
+				statusConfigPresent: true,
+				configsEqual:        true,
+				drifted:             true,
+				driftsReadyLabel:    true,
+			},
+		),
+		Entry(
+			"node label missing, spec present, status present, status config present, configs equal, drifted",
+			testCase{
+				specPresent:         true,
+				statusPresent:       true,
+				statusConfigPresent: true,
+				configsEqual:        true,
+				drifted:             true,
 			},
 		),
 	)
@@ -1382,8 +3371,9 @@ const (
 )
 
 var workerCfg = &config.Worker{
-	RunAsUser:   ptr.To[int64](1234),
-	SELinuxType: "someType",
+	ImageCacheMaxSize: "1Gi",
+	RunAsUser:         ptr.To[int64](1234),
+	SELinuxType:       "someType",
 }
 
 var _ = Describe("podManagerImpl_CreateLoaderPod", func() {
@@ -1427,6 +3417,8 @@ var _ = Describe("podManagerImpl_CreateLoaderPod", func() {
 
 			controllerutil.AddFinalizer(expected, nodeModulesConfigFinalizer)
 
+			expected.Spec.RestartPolicy = v1.RestartPolicyNever
+
 			container, _ := podcmd.FindContainerByName(expected, "worker")
 			Expect(container).NotTo(BeNil())
 
@@ -1444,99 +3436,477 @@ var _ = Describe("podManagerImpl_CreateLoaderPod", func() {
 				}
 			}
 
-			hash, err := hashstructure.Hash(expected, hashstructure.FormatV2, nil)
-			Expect(err).NotTo(HaveOccurred())
+			hash, err := hashstructure.Hash(expected, hashstructure.FormatV2, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			expected.Annotations[hashAnnotationKey] = fmt.Sprintf("%d", hash)
+
+			ctx := context.TODO()
+
+			gomock.InOrder(
+				psh.EXPECT().VolumesAndVolumeMounts(ctx, &mi),
+				client.EXPECT().Create(ctx, cmpmock.DiffEq(expected)),
+			)
+
+			workerCfg := *workerCfg
+			workerCfg.SetFirmwareClassPath = firmwareClassPath
+
+			pm := &podManagerImpl{
+				client:      client,
+				psh:         psh,
+				scheme:      scheme,
+				workerImage: workerImage,
+				workerCfg:   &workerCfg,
+			}
+
+			Expect(
+				pm.CreateLoaderPod(ctx, nmc, nms),
+			).NotTo(
+				HaveOccurred(),
+			)
+		},
+		Entry("pod without firmwareClassPath, without firmware loading", nil, false),
+		Entry("pod with empty firmwareClassPath, without firmware loading", ptr.To(""), false),
+		Entry("pod with firmwareClassPath, without firmware loading", ptr.To("some-path"), false),
+		Entry("pod with firmwareClassPath, with firmware loading", ptr.To("some-path"), true),
+		Entry("pod without firmwareClassPath, with firmware loading", nil, true),
+	)
+
+	It("should pass the timeouts to the worker and set a deadline", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		psh := NewMockpullSecretHelper(ctrl)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      moduleName,
+				Namespace: namespace,
+				Timeouts: &kmmv1beta1.ModuleTimeouts{
+					Load: &metav1.Duration{Duration: 2 * time.Minute},
+				},
+			},
+			Config: moduleConfig,
+		}
+
+		ctx := context.TODO()
+
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &spec.ModuleItem)
+
+		workerCfg := *workerCfg
+		workerCfg.DefaultTimeouts = config.WorkerTimeouts{Pull: 10 * time.Minute, Load: 5 * time.Minute}
+
+		pm := newPodManager(nil, workerImage, scheme, &workerCfg, nil)
+		pm.(*podManagerImpl).psh = psh
+
+		pod, err := pm.LoaderPodTemplate(ctx, nmc, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Args).To(
+			ContainElements("--"+worker.FlagPullTimeout, "10m0s", "--"+worker.FlagLoadTimeout, "2m0s"),
+		)
+		Expect(pod.Annotations).To(HaveKeyWithValue(deadlineAnnotationKey, "13m0s"))
+	})
+
+	It("should pass the registry mirrors to the worker", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		psh := NewMockpullSecretHelper(ctrl)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+			Config:     moduleConfig,
+		}
+
+		ctx := context.TODO()
+
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &spec.ModuleItem)
+
+		mirrors := []mirror.Registry{
+			{Prefix: "quay.io", Mirrors: []mirror.Mirror{{Location: "mirror.example.com/quay"}}},
+		}
+
+		pm := newPodManager(nil, workerImage, scheme, workerCfg, mirrors)
+		pm.(*podManagerImpl).psh = psh
+
+		pod, err := pm.LoaderPodTemplate(ctx, nmc, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Args).To(ContainElements("--"+worker.FlagRegistryMirrors, mirrorsFullPath))
+		Expect(pod.Annotations).To(
+			HaveKeyWithValue(mirrorsAnnotationKey, "- mirrors:\n  - location: mirror.example.com/quay\n  prefix: quay.io\n"),
+		)
+		Expect(pod.Spec.Volumes[0].DownwardAPI.Items).To(
+			ContainElement(
+				v1.DownwardAPIVolumeFile{
+					Path: mirrorsFileName,
+					FieldRef: &v1.ObjectFieldSelector{
+						FieldPath: "metadata.annotations['kmm.node.kubernetes.io/registry-mirrors']",
+					},
+				},
+			),
+		)
+	})
+
+	It("should pass the CA bundles of the mirrors to the worker", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		psh := NewMockpullSecretHelper(ctrl)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+			Config:     moduleConfig,
+		}
+
+		ctx := context.TODO()
+
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &spec.ModuleItem)
+
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+		Expect(os.WriteFile(caFile, []byte("some PEM"), 0644)).To(Succeed())
+
+		mirrors := []mirror.Registry{
+			{Prefix: "quay.io", Mirrors: []mirror.Mirror{{Location: "mirror.example.com/quay", CAFile: caFile}}},
+		}
+
+		pm := newPodManager(nil, workerImage, scheme, workerCfg, mirrors)
+		pm.(*podManagerImpl).psh = psh
+
+		pod, err := pm.LoaderPodTemplate(ctx, nmc, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mirrors[0].Mirrors[0].CAFile).To(Equal(caFile))
+		Expect(pod.Annotations).To(
+			And(
+				HaveKeyWithValue("kmm.node.kubernetes.io/mirror-ca-0", "some PEM"),
+				HaveKeyWithValue(
+					mirrorsAnnotationKey,
+					"- mirrors:\n  - caFile: /etc/kmm-worker/mirror-ca-0.pem\n    location: mirror.example.com/quay\n  prefix: quay.io\n",
+				),
+			),
+		)
+		Expect(pod.Spec.Volumes[0].DownwardAPI.Items).To(
+			ContainElement(
+				v1.DownwardAPIVolumeFile{
+					Path: "mirror-ca-0.pem",
+					FieldRef: &v1.ObjectFieldSelector{
+						FieldPath: "metadata.annotations['kmm.node.kubernetes.io/mirror-ca-0']",
+					},
+				},
+			),
+		)
+
+		By("returning an error if a CA bundle cannot be read")
+
+		Expect(os.Remove(caFile)).To(Succeed())
+
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &spec.ModuleItem)
+
+		_, err = pm.LoaderPodTemplate(ctx, nmc, spec)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not set a deadline if a timeout is disabled", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		psh := NewMockpullSecretHelper(ctrl)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      moduleName,
+				Namespace: namespace,
+				Timeouts: &kmmv1beta1.ModuleTimeouts{
+					Pull: &metav1.Duration{},
+				},
+			},
+			Config: moduleConfig,
+		}
+
+		ctx := context.TODO()
+
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &spec.ModuleItem)
+
+		workerCfg := *workerCfg
+		workerCfg.DefaultTimeouts = config.WorkerTimeouts{Pull: 10 * time.Minute, Load: 5 * time.Minute}
+
+		pm := newPodManager(nil, workerImage, scheme, &workerCfg, nil)
+		pm.(*podManagerImpl).psh = psh
+
+		pod, err := pm.LoaderPodTemplate(ctx, nmc, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Args).NotTo(ContainElement("--" + worker.FlagPullTimeout))
+		Expect(pod.Spec.Containers[0].Args).To(ContainElements("--"+worker.FlagLoadTimeout, "5m0s"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(deadlineAnnotationKey, "6m0s"))
+	})
+})
+
+var _ = Describe("podManagerImpl_CreateUnloaderPod", func() {
+	It("should work as expected", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client := testclient.NewMockClient(ctrl)
+		psh := NewMockpullSecretHelper(ctrl)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		mi := kmmv1beta1.ModuleItem{
+			Name:               moduleName,
+			Namespace:          namespace,
+			ServiceAccountName: serviceAccountName,
+		}
+
+		moduleConfigToUse := moduleConfig
+		moduleConfigToUse.Modprobe.FirmwarePath = "/firmware-path"
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: mi,
+			Config:     &moduleConfigToUse,
+		}
+
+		expected := getBaseWorkerPod("unload", WorkerActionUnload, nmc, nil, true)
+
+		container, _ := podcmd.FindContainerByName(expected, "worker")
+		Expect(container).NotTo(BeNil())
+
+		container.SecurityContext = &v1.SecurityContext{
+			Capabilities: &v1.Capabilities{
+				Add: []v1.Capability{"SYS_MODULE"},
+			},
+			RunAsUser:      workerCfg.RunAsUser,
+			SELinuxOptions: &v1.SELinuxOptions{Type: workerCfg.SELinuxType},
+		}
+
+		hash, err := hashstructure.Hash(expected, hashstructure.FormatV2, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		expected.Annotations[hashAnnotationKey] = fmt.Sprintf("%d", hash)
+
+		ctx := context.TODO()
+
+		gomock.InOrder(
+			psh.EXPECT().VolumesAndVolumeMounts(ctx, &mi),
+			client.EXPECT().Create(ctx, cmpmock.DiffEq(expected)),
+		)
+
+		pm := newPodManager(client, workerImage, scheme, workerCfg, nil)
+		pm.(*podManagerImpl).psh = psh
+
+		Expect(
+			pm.CreateUnloaderPod(ctx, nmc, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should run in the host's PID namespace if holder processes should be listed", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		psh := NewMockpullSecretHelper(ctrl)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+			Config:     &moduleConfig,
+		}
+
+		ctx := context.TODO()
+
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &status.ModuleItem)
+
+		workerCfg := *workerCfg
+		workerCfg.ListHolderProcesses = true
+
+		pm := newPodManager(nil, workerImage, scheme, &workerCfg, nil)
+		pm.(*podManagerImpl).psh = psh
+
+		pod, err := pm.UnloaderPodTemplate(ctx, nmc, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Spec.HostPID).To(BeTrue())
+		Expect(pod.Spec.Containers[0].Args).To(ContainElement("--" + worker.FlagListHolders))
+	})
+
+	It("should pass the module in use timeout and poll interval to the worker", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		psh := NewMockpullSecretHelper(ctrl)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+			Config:     &moduleConfig,
+		}
+
+		ctx := context.TODO()
+
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &status.ModuleItem)
+
+		workerCfg := *workerCfg
+		workerCfg.ModuleInUseTimeout = 5 * time.Minute
+		workerCfg.ModuleInUsePollInterval = 10 * time.Second
+
+		pm := newPodManager(nil, workerImage, scheme, &workerCfg, nil)
+		pm.(*podManagerImpl).psh = psh
+
+		pod, err := pm.UnloaderPodTemplate(ctx, nmc, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Args).To(
+			ContainElements("--"+worker.FlagInUseTimeout, "5m0s", "--"+worker.FlagInUsePollInterval, "10s"),
+		)
+	})
+
+	It("should pass the unload timeout recorded in the status to the worker", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		psh := NewMockpullSecretHelper(ctrl)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      moduleName,
+				Namespace: namespace,
+				Timeouts: &kmmv1beta1.ModuleTimeouts{
+					Pull:   &metav1.Duration{Duration: time.Minute},
+					Unload: &metav1.Duration{Duration: 30 * time.Second},
+				},
+			},
+			Config: &moduleConfig,
+		}
+
+		ctx := context.TODO()
 
-			expected.Annotations[hashAnnotationKey] = fmt.Sprintf("%d", hash)
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &status.ModuleItem)
 
-			ctx := context.TODO()
+		pm := newPodManager(nil, workerImage, scheme, workerCfg, nil)
+		pm.(*podManagerImpl).psh = psh
 
-			gomock.InOrder(
-				psh.EXPECT().VolumesAndVolumeMounts(ctx, &mi),
-				client.EXPECT().Create(ctx, cmpmock.DiffEq(expected)),
-			)
+		pod, err := pm.UnloaderPodTemplate(ctx, nmc, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Args).To(
+			ContainElements("--"+worker.FlagPullTimeout, "1m0s", "--"+worker.FlagUnloadTimeout, "30s"),
+		)
+		Expect(pod.Annotations).To(HaveKeyWithValue(deadlineAnnotationKey, "2m30s"))
+	})
 
-			workerCfg := *workerCfg
-			workerCfg.SetFirmwareClassPath = firmwareClassPath
+	It("should return an error if the module was never loaded", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
 
-			pm := &podManagerImpl{
-				client:      client,
-				psh:         psh,
-				scheme:      scheme,
-				workerImage: workerImage,
-				workerCfg:   &workerCfg,
-			}
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+		}
 
-			Expect(
-				pm.CreateLoaderPod(ctx, nmc, nms),
-			).NotTo(
-				HaveOccurred(),
-			)
-		},
-		Entry("pod without firmwareClassPath, without firmware loading", nil, false),
-		Entry("pod with empty firmwareClassPath, without firmware loading", ptr.To(""), false),
-		Entry("pod with firmwareClassPath, without firmware loading", ptr.To("some-path"), false),
-		Entry("pod with firmwareClassPath, with firmware loading", ptr.To("some-path"), true),
-		Entry("pod without firmwareClassPath, with firmware loading", nil, true),
-	)
+		pm := newPodManager(nil, workerImage, scheme, workerCfg, nil)
+
+		_, err := pm.UnloaderPodTemplate(context.TODO(), nmc, status)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
-var _ = Describe("podManagerImpl_CreateUnloaderPod", func() {
-	It("should work as expected", func() {
+var _ = Describe("podManagerImpl_ParametersPodTemplate", func() {
+	It("should run a privileged worker updating the parameters", func() {
 		ctrl := gomock.NewController(GinkgoT())
-		client := testclient.NewMockClient(ctrl)
 		psh := NewMockpullSecretHelper(ctrl)
 
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
 		}
 
-		mi := kmmv1beta1.ModuleItem{
-			Name:               moduleName,
-			Namespace:          namespace,
-			ServiceAccountName: serviceAccountName,
-		}
-
-		moduleConfigToUse := moduleConfig
-		moduleConfigToUse.Modprobe.FirmwarePath = "/firmware-path"
-		status := &kmmv1beta1.NodeModuleStatus{
-			ModuleItem: mi,
-			Config:     moduleConfigToUse,
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+			Config:     moduleConfig,
 		}
 
-		expected := getBaseWorkerPod("unload", WorkerActionUnload, nmc, nil, true)
+		ctx := context.TODO()
 
-		container, _ := podcmd.FindContainerByName(expected, "worker")
-		Expect(container).NotTo(BeNil())
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &spec.ModuleItem)
 
-		container.SecurityContext = &v1.SecurityContext{
-			Capabilities: &v1.Capabilities{
-				Add: []v1.Capability{"SYS_MODULE"},
-			},
-			RunAsUser:      workerCfg.RunAsUser,
-			SELinuxOptions: &v1.SELinuxOptions{Type: workerCfg.SELinuxType},
-		}
+		pm := newPodManager(nil, workerImage, scheme, workerCfg, nil)
+		pm.(*podManagerImpl).psh = psh
 
-		hash, err := hashstructure.Hash(expected, hashstructure.FormatV2, nil)
+		pod, err := pm.ParametersPodTemplate(ctx, nmc, spec, []string{"a", "b"})
 		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Labels).To(HaveKeyWithValue(actionLabelKey, WorkerActionSetParameters))
+		Expect(pod.Annotations).To(HaveKey(hashAnnotationKey))
+		Expect(pod.Spec.RestartPolicy).To(Equal(v1.RestartPolicyNever))
+
+		container := pod.Spec.Containers[0]
+		Expect(container.SecurityContext.Privileged).To(Equal(ptr.To(true)))
+		Expect(container.Args).To(Equal([]string{
+			"kmod", "set-parameters", configFullPath, "--" + worker.FlagParameter, "a", "--" + worker.FlagParameter, "b",
+		}))
+
+		cfg := kmmv1beta1.ModuleConfig{}
+		Expect(yaml.UnmarshalStrict([]byte(pod.Annotations[configAnnotationKey]), &cfg)).To(Succeed())
+		Expect(cfg).To(Equal(moduleConfig))
+	})
+})
 
-		expected.Annotations[hashAnnotationKey] = fmt.Sprintf("%d", hash)
+var _ = Describe("podManagerImpl_VerifierPodTemplate", func() {
+	It("should run a worker verifying the module in the status", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		psh := NewMockpullSecretHelper(ctrl)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+			Config:     &moduleConfig,
+		}
 
 		ctx := context.TODO()
 
-		gomock.InOrder(
-			psh.EXPECT().VolumesAndVolumeMounts(ctx, &mi),
-			client.EXPECT().Create(ctx, cmpmock.DiffEq(expected)),
-		)
+		psh.EXPECT().VolumesAndVolumeMounts(ctx, &status.ModuleItem)
 
-		pm := newPodManager(client, workerImage, scheme, workerCfg)
+		pm := newPodManager(nil, workerImage, scheme, workerCfg, nil)
 		pm.(*podManagerImpl).psh = psh
 
-		Expect(
-			pm.CreateUnloaderPod(ctx, nmc, status),
-		).NotTo(
-			HaveOccurred(),
-		)
+		pod, err := pm.VerifierPodTemplate(ctx, nmc, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Name).To(Equal(workerPodName(nmcName, moduleName)))
+		Expect(pod.Labels).To(HaveKeyWithValue(actionLabelKey, WorkerActionVerify))
+		Expect(pod.Annotations).To(HaveKey(hashAnnotationKey))
+		Expect(pod.Annotations).NotTo(HaveKey(deadlineAnnotationKey))
+		Expect(pod.Spec.RestartPolicy).To(Equal(v1.RestartPolicyNever))
+
+		container := pod.Spec.Containers[0]
+		Expect(container.SecurityContext.Privileged).To(BeNil())
+		Expect(container.Args).To(Equal([]string{"kmod", "verify", configFullPath}))
+
+		cfg := kmmv1beta1.ModuleConfig{}
+		Expect(yaml.UnmarshalStrict([]byte(pod.Annotations[configAnnotationKey]), &cfg)).To(Succeed())
+		Expect(cfg).To(Equal(moduleConfig))
+	})
+
+	It("should return an error if the module was never loaded", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+		}
+
+		pm := newPodManager(nil, workerImage, scheme, workerCfg, nil)
+
+		_, err := pm.VerifierPodTemplate(context.TODO(), nmc, status)
+		Expect(err).To(HaveOccurred())
 	})
 })
 
@@ -1567,7 +3937,7 @@ var _ = Describe("podManagerImpl_DeletePod", func() {
 			}
 
 			Expect(
-				newPodManager(kubeclient, workerImage, scheme, workerCfg).DeletePod(ctx, patchedPod),
+				newPodManager(kubeclient, workerImage, scheme, workerCfg, nil).DeletePod(ctx, patchedPod),
 			).NotTo(
 				HaveOccurred(),
 			)
@@ -1590,7 +3960,7 @@ var _ = Describe("podManagerImpl_ListWorkerPodsOnNode", func() {
 	BeforeEach(func() {
 		ctrl := gomock.NewController(GinkgoT())
 		kubeClient = testclient.NewMockClient(ctrl)
-		pm = newPodManager(kubeClient, workerImage, scheme, nil)
+		pm = newPodManager(kubeClient, workerImage, scheme, nil, nil)
 	})
 
 	opts := []interface{}{
@@ -1634,7 +4004,9 @@ func getBaseWorkerPod(subcommand string, action WorkerAction, owner ctrlclient.O
 	GinkgoHelper()
 
 	const (
+		volNameImageCache     = "image-cache"
 		volNameLibModules     = "lib-modules"
+		volNameModprobeD      = "modprobe-d"
 		volNameUsrLibModules  = "usr-lib-modules"
 		volNameVarLibFirmware = "var-lib-firmware"
 	)
@@ -1671,6 +4043,9 @@ softdep b pre: c
 	} else {
 		args = append(args, "--set-firmware-mount-path", "/var/lib/firmware")
 	}
+	if action == WorkerActionUnload {
+		args = append(args, "--gc", "--max-size", "1Gi")
+	}
 	pod := v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      workerPodName(nmcName, moduleName),
@@ -1693,6 +4068,10 @@ softdep b pre: c
 					Name:  "worker",
 					Image: workerImage,
 					Args:  args,
+					Env: []v1.EnvVar{
+						{Name: worker.EnvModuleName, Value: moduleName},
+						{Name: worker.EnvModuleNamespace, Value: namespace},
+					},
 					Resources: v1.ResourceRequirements{
 						Limits:   limits,
 						Requests: requests,
@@ -1713,6 +4092,14 @@ softdep b pre: c
 							MountPath: "/usr/lib/modules",
 							ReadOnly:  true,
 						},
+						{
+							Name:      volNameImageCache,
+							MountPath: "/var/run/kmm/images",
+						},
+						{
+							Name:      volNameModprobeD,
+							MountPath: "/var/run/kmm/modprobe.d",
+						},
 						{
 							Name:      "modules-order",
 							ReadOnly:  true,
@@ -1758,6 +4145,24 @@ softdep b pre: c
 						},
 					},
 				},
+				{
+					Name: volNameImageCache,
+					VolumeSource: v1.VolumeSource{
+						HostPath: &v1.HostPathVolumeSource{
+							Path: "/var/lib/kmm/images",
+							Type: &hostPathDirectoryOrCreate,
+						},
+					},
+				},
+				{
+					Name: volNameModprobeD,
+					VolumeSource: v1.VolumeSource{
+						HostPath: &v1.HostPathVolumeSource{
+							Path: "/etc/modprobe.d",
+							Type: &hostPathDirectoryOrCreate,
+						},
+					},
+				},
 				{
 					Name: "modules-order",
 					VolumeSource: v1.VolumeSource{
@@ -1900,3 +4305,261 @@ var _ = Describe("pullSecretHelperImpl_VolumesAndVolumeMounts", func() {
 		Expect(resVolMounts).To(BeComparableTo(volMounts))
 	})
 })
+
+var _ = DescribeTable(
+	"loadBackoff",
+	func(attempts int32, expected time.Duration) {
+		Expect(loadBackoff(attempts)).To(Equal(expected))
+	},
+	Entry(nil, int32(1), 10*time.Second),
+	Entry(nil, int32(2), 20*time.Second),
+	Entry(nil, int32(5), 160*time.Second),
+	Entry(nil, int32(6), 5*time.Minute),
+	Entry(nil, int32(100), 5*time.Minute),
+)
+
+var _ = Describe("nextLoadRetry", func() {
+	now := time.Now()
+
+	It("should return false if no retry is scheduled in the future", func() {
+		statuses := []kmmv1beta1.NodeModuleStatus{
+			{},
+			{NextRetryTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+		}
+
+		_, ok := nextLoadRetry(statuses, now)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should return the earliest retry", func() {
+		statuses := []kmmv1beta1.NodeModuleStatus{
+			{NextRetryTime: &metav1.Time{Time: now.Add(time.Minute)}},
+			{NextRetryTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+			{NextRetryTime: &metav1.Time{Time: now.Add(20 * time.Second)}},
+		}
+
+		d, ok := nextLoadRetry(statuses, now)
+		Expect(ok).To(BeTrue())
+		Expect(d).To(Equal(20 * time.Second))
+	})
+})
+
+var _ = Describe("parametersToUpdate", func() {
+	spec := &kmmv1beta1.NodeModuleSpec{
+		Config: kmmv1beta1.ModuleConfig{
+			Modprobe: kmmv1beta1.ModprobeSpec{Parameters: []string{"a=2"}},
+		},
+	}
+
+	It("should return nothing if the module was never loaded", func() {
+		names, err := parametersToUpdate(spec, &kmmv1beta1.NodeModuleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(BeEmpty())
+	})
+
+	It("should return the changed parameters", func() {
+		status := &kmmv1beta1.NodeModuleStatus{
+			Config: &kmmv1beta1.ModuleConfig{
+				Modprobe: kmmv1beta1.ModprobeSpec{Parameters: []string{"a=1"}},
+			},
+		}
+
+		names, err := parametersToUpdate(spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(Equal([]string{"a"}))
+	})
+})
+
+var _ = Describe("nextVerification", func() {
+	now := time.Now()
+	cfg := &kmmv1beta1.ModuleConfig{}
+
+	It("should return false if the verification is disabled", func() {
+		statuses := []kmmv1beta1.NodeModuleStatus{
+			{Config: cfg, LastTransitionTime: metav1.NewTime(now)},
+		}
+
+		_, ok := nextVerification(statuses, 0, now)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should return the earliest verification of a loaded module in the future", func() {
+		statuses := []kmmv1beta1.NodeModuleStatus{
+			// never loaded
+			{LastTransitionTime: metav1.NewTime(now)},
+			// overdue
+			{Config: cfg, LastTransitionTime: metav1.NewTime(now.Add(-time.Hour))},
+			// verified after it was loaded
+			{
+				Config:             cfg,
+				LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+				LastVerifiedTime:   &metav1.Time{Time: now.Add(-5 * time.Minute)},
+			},
+			{Config: cfg, LastTransitionTime: metav1.NewTime(now.Add(-time.Minute))},
+		}
+
+		d, ok := nextVerification(statuses, 10*time.Minute, now)
+		Expect(ok).To(BeTrue())
+		Expect(d).To(Equal(5 * time.Minute))
+	})
+})
+
+var _ = Describe("setLocalImageVolume", func() {
+	newPod := func() *v1.Pod {
+		return &v1.Pod{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: workerContainerName},
+				},
+			},
+		}
+	}
+
+	It("should do nothing for registry images", func() {
+		pod := newPod()
+
+		Expect(
+			setLocalImageVolume(pod, "quay.io/org/image:tag"),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(pod).To(Equal(newPod()))
+	})
+
+	DescribeTable(
+		"should mount the local image path",
+		func(image string, hostPathType v1.HostPathType) {
+			pod := newPod()
+
+			Expect(
+				setLocalImageVolume(pod, image),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(pod.Spec.Volumes).To(
+				Equal([]v1.Volume{
+					{
+						Name: "local-image",
+						VolumeSource: v1.VolumeSource{
+							HostPath: &v1.HostPathVolumeSource{
+								Path: "/some/path",
+								Type: &hostPathType,
+							},
+						},
+					},
+				}),
+			)
+
+			Expect(pod.Spec.Containers[0].VolumeMounts).To(
+				Equal([]v1.VolumeMount{
+					{Name: "local-image", ReadOnly: true, MountPath: "/some/path"},
+				}),
+			)
+		},
+		Entry("OCI layout", "oci:/some/path:tag", v1.HostPathDirectory),
+		Entry("OCI archive", "oci-archive:/some/path", v1.HostPathFile),
+	)
+})
+
+var _ = Describe("setImageVerificationVolume", func() {
+	newPod := func() *v1.Pod {
+		return &v1.Pod{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: workerContainerName},
+				},
+			},
+		}
+	}
+
+	It("should do nothing if verification is not configured", func() {
+		pod := newPod()
+
+		Expect(
+			setImageVerificationVolume(pod, nil),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(pod).To(Equal(newPod()))
+	})
+
+	It("should mount the keys Secret", func() {
+		pod := newPod()
+
+		iv := &kmmv1beta1.ImageVerification{
+			KeysSecret: v1.LocalObjectReference{Name: "keys"},
+		}
+
+		Expect(
+			setImageVerificationVolume(pod, iv),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(pod.Spec.Volumes).To(
+			Equal([]v1.Volume{
+				{
+					Name: "verification-keys",
+					VolumeSource: v1.VolumeSource{
+						Secret: &v1.SecretVolumeSource{SecretName: "keys"},
+					},
+				},
+			}),
+		)
+
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(
+			Equal([]v1.VolumeMount{
+				{Name: "verification-keys", ReadOnly: true, MountPath: worker.VerificationKeysDir},
+			}),
+		)
+	})
+})
+
+var _ = Describe("imagesInUse", func() {
+	It("should return the images of all modules except the one being unloaded", func() {
+		unloading := kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: "unloading", Namespace: namespace},
+			Config:     &kmmv1beta1.ModuleConfig{ContainerImage: "old-image"},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: "unloading", Namespace: namespace},
+						Config:     kmmv1beta1.ModuleConfig{ContainerImage: "new-image"},
+					},
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: "other", Namespace: namespace},
+						Config:     kmmv1beta1.ModuleConfig{ContainerImage: "other-image"},
+					},
+				},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					unloading,
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: "other", Namespace: namespace},
+						Config:     &kmmv1beta1.ModuleConfig{ContainerImage: "other-image"},
+					},
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: "being-removed", Namespace: namespace},
+						Config:     &kmmv1beta1.ModuleConfig{ContainerImage: "removed-image"},
+					},
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: "never-loaded", Namespace: namespace},
+					},
+				},
+			},
+		}
+
+		Expect(
+			imagesInUse(nmc, &unloading),
+		).To(
+			Equal([]string{"new-image", "other-image", "removed-image"}),
+		)
+	})
+})
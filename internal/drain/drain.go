@@ -0,0 +1,158 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//go:generate mockgen -source=drain.go -package=drain -destination=mock_drain.go
+
+type Drainer interface {
+	// Cordon marks node as unschedulable.
+	Cordon(ctx context.Context, node *v1.Node) error
+	// Uncordon marks node as schedulable.
+	Uncordon(ctx context.Context, node *v1.Node) error
+	// Drain requests the eviction of the Pods running on nodeName that are selected by policy.
+	// It returns the number of such Pods still present on the node, including those being evicted.
+	// Evictions denied by a PodDisruptionBudget are retried at the next call.
+	Drain(ctx context.Context, nodeName string, policy *kmmv1beta1.MaintenancePolicy) (int, error)
+}
+
+type drainer struct {
+	client client.Client
+}
+
+// NewDrainer returns a Drainer that lists Pods through the ".spec.nodeName" field index of client.
+func NewDrainer(client client.Client) Drainer {
+	return &drainer{client: client}
+}
+
+func (d *drainer) Cordon(ctx context.Context, node *v1.Node) error {
+	return d.setUnschedulable(ctx, node, true)
+}
+
+func (d *drainer) Uncordon(ctx context.Context, node *v1.Node) error {
+	return d.setUnschedulable(ctx, node, false)
+}
+
+func (d *drainer) setUnschedulable(ctx context.Context, node *v1.Node, unschedulable bool) error {
+	if node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Patching node", "name", node.Name, "unschedulable", unschedulable)
+
+	patchFrom := client.MergeFrom(node.DeepCopy())
+
+	node.Spec.Unschedulable = unschedulable
+
+	if err := d.client.Patch(ctx, node, patchFrom); err != nil {
+		return fmt.Errorf("could not patch node %s: %v", node.Name, err)
+	}
+
+	return nil
+}
+
+func (d *drainer) Drain(ctx context.Context, nodeName string, policy *kmmv1beta1.MaintenancePolicy) (int, error) {
+	logger := ctrl.LoggerFrom(ctx)
+
+	selector := labels.Nothing()
+
+	if policy.PodSelector != nil {
+		var err error
+
+		if selector, err = metav1.LabelSelectorAsSelector(policy.PodSelector); err != nil {
+			return 0, fmt.Errorf("invalid podSelector: %v", err)
+		}
+	}
+
+	pl := v1.PodList{}
+
+	if err := d.client.List(ctx, &pl, client.MatchingFields{".spec.nodeName": nodeName}); err != nil {
+		return 0, fmt.Errorf("could not list Pods on node %s: %v", nodeName, err)
+	}
+
+	remaining := 0
+	errs := make([]error, 0)
+
+	for i := 0; i < len(pl.Items); i++ {
+		pod := &pl.Items[i]
+
+		if !mustEvict(pod, policy.ResourceNames, selector) {
+			continue
+		}
+
+		remaining++
+
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		logger.Info("Evicting Pod", "namespace", pod.Namespace, "name", pod.Name)
+
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		}
+
+		err := d.client.SubResource("eviction").Create(ctx, pod, eviction)
+
+		switch {
+		case err == nil || k8serrors.IsNotFound(err):
+		case k8serrors.IsTooManyRequests(err):
+			logger.Info("Eviction denied by a PodDisruptionBudget; retrying later", "namespace", pod.Namespace, "name", pod.Name)
+		default:
+			errs = append(errs, fmt.Errorf("could not evict Pod %s/%s: %v", pod.Namespace, pod.Name, err))
+		}
+	}
+
+	return remaining, errors.Join(errs...)
+}
+
+// mustEvict returns true if pod is still running, can be evicted, and either matches selector or requests one of
+// resourceNames.
+// DaemonSet Pods are ignored, as they would be scheduled on the node again right away.
+func mustEvict(pod *v1.Pod, resourceNames []v1.ResourceName, selector labels.Selector) bool {
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return false
+	}
+
+	if _, ok := pod.Annotations[v1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+
+	if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+		return false
+	}
+
+	return selector.Matches(labels.Set(pod.Labels)) || requestsAny(pod, resourceNames)
+}
+
+func requestsAny(pod *v1.Pod, resourceNames []v1.ResourceName) bool {
+	containers := make([]v1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	containers = append(containers, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+
+	for _, c := range containers {
+		for _, rn := range resourceNames {
+			if _, ok := c.Resources.Requests[rn]; ok {
+				return true
+			}
+
+			if _, ok := c.Resources.Limits[rn]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
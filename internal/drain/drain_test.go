@@ -0,0 +1,252 @@
+package drain
+
+import (
+	"context"
+	"errors"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	nodeName     = "node-name"
+	resourceName = "example.com/gpu"
+)
+
+var _ = Describe("drainer_Cordon", func() {
+	var (
+		ctx  context.Context
+		clnt *client.MockClient
+		d    Drainer
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		d = NewDrainer(clnt)
+	})
+
+	It("should patch a schedulable node", func() {
+		node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+
+		clnt.EXPECT().Patch(ctx, &node, gomock.Any())
+
+		Expect(
+			d.Cordon(ctx, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+	})
+
+	It("should do nothing if the node is already unschedulable", func() {
+		node := v1.Node{Spec: v1.NodeSpec{Unschedulable: true}}
+
+		Expect(
+			d.Cordon(ctx, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the node could not be patched", func() {
+		clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(errors.New("random error"))
+
+		Expect(
+			d.Cordon(ctx, &v1.Node{}),
+		).To(
+			HaveOccurred(),
+		)
+	})
+})
+
+var _ = Describe("drainer_Uncordon", func() {
+	It("should patch an unschedulable node", func() {
+		ctx := context.Background()
+		clnt := client.NewMockClient(gomock.NewController(GinkgoT()))
+		node := v1.Node{Spec: v1.NodeSpec{Unschedulable: true}}
+
+		clnt.EXPECT().Patch(ctx, &node, gomock.Any())
+
+		Expect(
+			NewDrainer(clnt).Uncordon(ctx, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(node.Spec.Unschedulable).To(BeFalse())
+	})
+})
+
+var _ = Describe("drainer_Drain", func() {
+	var (
+		ctx       context.Context
+		clnt      *client.MockClient
+		subClient *client.MockSubResourceClient
+		d         Drainer
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ctx = context.Background()
+		clnt = client.NewMockClient(ctrl)
+		subClient = client.NewMockSubResourceClient(ctrl)
+		d = NewDrainer(clnt)
+	})
+
+	podRequesting := func(name string, rn v1.ResourceName) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Resources: v1.ResourceRequirements{
+							Limits: v1.ResourceList{rn: resource.MustParse("1")},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	expectList := func(pods ...v1.Pod) *gomock.Call {
+		return clnt.
+			EXPECT().
+			List(ctx, &v1.PodList{}, ctrlclient.MatchingFields{".spec.nodeName": nodeName}).
+			Do(func(_ context.Context, pl *v1.PodList, _ ...ctrlclient.ListOption) {
+				pl.Items = pods
+			})
+	}
+
+	It("should return an error if the Pods could not be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("random error"))
+
+		_, err := d.Drain(ctx, nodeName, &kmmv1beta1.MaintenancePolicy{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the podSelector is invalid", func() {
+		policy := kmmv1beta1.MaintenancePolicy{
+			PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "key", Operator: "invalid"}},
+			},
+		}
+
+		_, err := d.Drain(ctx, nodeName, &policy)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should evict the selected Pods", func() {
+		gpuPod := podRequesting("gpu", resourceName)
+
+		initPod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "init", Namespace: "ns"},
+			Spec: v1.PodSpec{
+				InitContainers: []v1.Container{
+					{
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{resourceName: resource.MustParse("1")},
+						},
+					},
+				},
+			},
+		}
+
+		labeledPod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "labeled",
+				Namespace: "ns",
+				Labels:    map[string]string{"app": "uses-module"},
+			},
+		}
+
+		terminatingPod := podRequesting("terminating", resourceName)
+		terminatingPod.DeletionTimestamp = &metav1.Time{}
+
+		completedPod := podRequesting("completed", resourceName)
+		completedPod.Status.Phase = v1.PodSucceeded
+
+		dsPod := podRequesting("daemonset", resourceName)
+		dsPod.OwnerReferences = []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "ds", Controller: ptr.To(true)},
+		}
+
+		policy := kmmv1beta1.MaintenancePolicy{
+			ResourceNames: []v1.ResourceName{resourceName},
+			PodSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "uses-module"}},
+		}
+
+		evicted := make([]string, 0)
+
+		gomock.InOrder(
+			expectList(
+				gpuPod,
+				podRequesting("other", "example.com/other"),
+				initPod,
+				labeledPod,
+				terminatingPod,
+				completedPod,
+				dsPod,
+			),
+			clnt.EXPECT().SubResource("eviction").Return(subClient).Times(3),
+		)
+
+		subClient.
+			EXPECT().
+			Create(ctx, gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, pod ctrlclient.Object, sub ctrlclient.Object, _ ...ctrlclient.SubResourceCreateOption) {
+				Expect(sub.(*policyv1.Eviction).Name).To(Equal(pod.GetName()))
+				evicted = append(evicted, pod.GetName())
+			}).
+			Times(3)
+
+		remaining, err := d.Drain(ctx, nodeName, &policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(Equal(4))
+		Expect(evicted).To(ConsistOf("gpu", "init", "labeled"))
+	})
+
+	It("should keep Pods protected by a PodDisruptionBudget", func() {
+		policy := kmmv1beta1.MaintenancePolicy{ResourceNames: []v1.ResourceName{resourceName}}
+		tooManyRequests := k8serrors.NewTooManyRequests("budget", 10)
+
+		gomock.InOrder(
+			expectList(podRequesting("gpu", resourceName)),
+			clnt.EXPECT().SubResource("eviction").Return(subClient),
+			subClient.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(tooManyRequests),
+		)
+
+		remaining, err := d.Drain(ctx, nodeName, &policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(Equal(1))
+	})
+
+	It("should ignore Pods that no longer exist and report other errors", func() {
+		policy := kmmv1beta1.MaintenancePolicy{ResourceNames: []v1.ResourceName{resourceName}}
+		notFound := k8serrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "gone")
+
+		gomock.InOrder(
+			expectList(podRequesting("gone", resourceName), podRequesting("error", resourceName)),
+			clnt.EXPECT().SubResource("eviction").Return(subClient),
+			subClient.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(notFound),
+			clnt.EXPECT().SubResource("eviction").Return(subClient),
+			subClient.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(errors.New("random error")),
+		)
+
+		remaining, err := d.Drain(ctx, nodeName, &policy)
+		Expect(err).To(HaveOccurred())
+		Expect(remaining).To(Equal(2))
+	})
+})
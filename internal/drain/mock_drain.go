@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: drain.go
+//
+// Generated by this command:
+//
+//	mockgen -source=drain.go -package=drain -destination=mock_drain.go
+//
+// Package drain is a generated GoMock package.
+package drain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	gomock "go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+)
+
+// MockDrainer is a mock of Drainer interface.
+type MockDrainer struct {
+	ctrl     *gomock.Controller
+	recorder *MockDrainerMockRecorder
+}
+
+// MockDrainerMockRecorder is the mock recorder for MockDrainer.
+type MockDrainerMockRecorder struct {
+	mock *MockDrainer
+}
+
+// NewMockDrainer creates a new mock instance.
+func NewMockDrainer(ctrl *gomock.Controller) *MockDrainer {
+	mock := &MockDrainer{ctrl: ctrl}
+	mock.recorder = &MockDrainerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDrainer) EXPECT() *MockDrainerMockRecorder {
+	return m.recorder
+}
+
+// Cordon mocks base method.
+func (m *MockDrainer) Cordon(ctx context.Context, node *v1.Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cordon", ctx, node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cordon indicates an expected call of Cordon.
+func (mr *MockDrainerMockRecorder) Cordon(ctx, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cordon", reflect.TypeOf((*MockDrainer)(nil).Cordon), ctx, node)
+}
+
+// Drain mocks base method.
+func (m *MockDrainer) Drain(ctx context.Context, nodeName string, policy *v1beta1.MaintenancePolicy) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx, nodeName, policy)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockDrainerMockRecorder) Drain(ctx, nodeName, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockDrainer)(nil).Drain), ctx, nodeName, policy)
+}
+
+// Uncordon mocks base method.
+func (m *MockDrainer) Uncordon(ctx context.Context, node *v1.Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Uncordon", ctx, node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Uncordon indicates an expected call of Uncordon.
+func (mr *MockDrainerMockRecorder) Uncordon(ctx, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Uncordon", reflect.TypeOf((*MockDrainer)(nil).Uncordon), ctx, node)
+}
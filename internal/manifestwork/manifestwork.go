@@ -25,6 +25,7 @@ import (
 	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/module"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/registry"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/localimage"
 )
 
 var moduleStatusJSONPaths = []workv1.JsonPath{
@@ -222,36 +223,39 @@ func (mwg *manifestWorkGenerator) managedClusterKernelMappings(
 			"kernel version", kernelVersion,
 		)
 
-		mld, err := mwg.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
+		// Each mapping keeps its NodeMatch, so that the spoke selects among them like it would among the original ones.
+		mlds, err := mwg.kernelAPI.GetModuleLoaderDataForKernelVersion(mod, kernelVersion)
 		if err != nil {
 			kernelVersionLogger.Info("no suitable container image found; skipping kernel version")
 			continue
 		}
 
-		kernelVersionLogger.V(1).Info("Found a valid mapping",
-			"image", mld.ContainerImage,
-		)
+		for _, mld := range mlds {
+			kernelVersionLogger.V(1).Info("Found a valid mapping",
+				"image", mld.ContainerImage,
+			)
 
-		mapping := mwg.mappingFromModuleLoaderData(mld)
-
-		digest, err := mwg.imageDigestForModuleLoaderData(ctx, mld)
-		if err != nil {
-			kernelVersionLogger.Error(err, "skipping image tag replacement")
-		}
-		if digest != "" {
-			mapping.ContainerImage, err = mwg.replaceTagWithDigest(mapping.ContainerImage, digest)
+			mapping := mwg.mappingFromModuleLoaderData(mld)
 
+			digest, err := mwg.imageDigestForModuleLoaderData(ctx, mld)
 			if err != nil {
 				kernelVersionLogger.Error(err, "skipping image tag replacement")
-			} else {
-				kernelVersionLogger.V(1).Info("replaced container image tag with digest",
-					"image", mld.ContainerImage,
-					"imageWithDigest", mapping.ContainerImage,
-				)
 			}
-		}
+			if digest != "" {
+				mapping.ContainerImage, err = mwg.replaceTagWithDigest(mapping.ContainerImage, digest)
+
+				if err != nil {
+					kernelVersionLogger.Error(err, "skipping image tag replacement")
+				} else {
+					kernelVersionLogger.V(1).Info("replaced container image tag with digest",
+						"image", mld.ContainerImage,
+						"imageWithDigest", mapping.ContainerImage,
+					)
+				}
+			}
 
-		mappings = append(mappings, mapping)
+			mappings = append(mappings, mapping)
+		}
 	}
 
 	return mappings
@@ -267,13 +271,21 @@ func (mwg *manifestWorkGenerator) replaceTagWithDigest(image, digest string) (st
 
 func (mwg *manifestWorkGenerator) mappingFromModuleLoaderData(mld *api.ModuleLoaderData) kmmv1beta1.KernelMapping {
 	return kmmv1beta1.KernelMapping{
-		ContainerImage:       mld.ContainerImage,
-		Literal:              mld.KernelVersion,
-		InTreeModuleToRemove: mld.InTreeModuleToRemove,
+		ContainerImage:        mld.ContainerImage,
+		Literal:               mld.KernelVersion,
+		InTreeModuleToRemove:  mld.InTreeModuleToRemove,
+		InTreeModulesToRemove: mld.InTreeModulesToRemove,
+		NodeMatch:             mld.NodeMatch,
+		Platform:              mld.Platform,
 	}
 }
 
 func (mwg *manifestWorkGenerator) imageDigestForModuleLoaderData(ctx context.Context, mld *api.ModuleLoaderData) (string, error) {
+	// Local images are not in a registry; keep the reference as is.
+	if localimage.IsLocal(mld.ContainerImage) {
+		return "", nil
+	}
+
 	ref, err := name.ParseReference(mld.ContainerImage, name.WithDefaultRegistry(""))
 	if err != nil {
 		return "", fmt.Errorf("could not parse the container image name: %v", err)
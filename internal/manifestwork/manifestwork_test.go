@@ -230,7 +230,7 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 
 	It("should empty the Module's kernel mappings when no kernel mappings match", func() {
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).Return(nil, errors.New("no-mappings-found")),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(gomock.Any(), kernelVersion).Return(nil, errors.New("no-mappings-found")),
 		)
 
 		mwc := NewCreator(clnt, scheme, mockKM, mockRegistry, mockCache, "")
@@ -254,7 +254,7 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 		}
 
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(gomock.Any(), kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 		)
 
 		mwc := NewCreator(clnt, scheme, mockKM, mockRegistry, mockCache, "")
@@ -278,7 +278,7 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 		}
 
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(gomock.Any(), kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockCache.EXPECT().Get(mld.ContainerImage).Return(digest, true),
 		)
 
@@ -300,9 +300,9 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 		}
 
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(gomock.Any(), kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockCache.EXPECT().Get(mld.ContainerImage).Return(nil, false),
-			mockRegistry.EXPECT().GetDigest(ctx, mld.ContainerImage+":latest", gomock.Any(), nil).Return("", errors.New("generic-error")),
+			mockRegistry.EXPECT().GetDigest(ctx, mld.ContainerImage+":latest", gomock.Any(), nil, nil).Return("", errors.New("generic-error")),
 		)
 
 		mwc := NewCreator(clnt, scheme, mockKM, mockRegistry, mockCache, "")
@@ -318,6 +318,46 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 		Expect(manifestModuleSpec.ModuleLoader.Container.KernelMappings[0]).To(Equal(expectedKernelMapping))
 	})
 
+	It("should keep the node matching properties of each mapping", func() {
+		mld.ContainerImage = imageName + "@digest"
+		mld.NodeMatch = kmmv1beta1.NodeMatch{Architecture: "arm64"}
+		otherMLD := api.ModuleLoaderData{ContainerImage: "other-image@digest", KernelVersion: kernelVersion}
+
+		gomock.InOrder(
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(gomock.Any(), kernelVersion).Return([]*api.ModuleLoaderData{&mld, &otherMLD}, nil),
+		)
+
+		mwc := NewCreator(clnt, scheme, mockKM, mockRegistry, mockCache, "")
+
+		err := mwc.SetManifestWorkAsDesired(context.Background(), mw, mcm, []string{kernelVersion})
+		Expect(err).NotTo(HaveOccurred())
+
+		manifestModuleSpec := (mw.Spec.Workload.Manifests[0].RawExtension.Object).(*kmmv1beta1.Module).Spec
+		Expect(manifestModuleSpec.ModuleLoader.Container.KernelMappings).To(Equal([]kmmv1beta1.KernelMapping{
+			{Literal: kernelVersion, ContainerImage: mld.ContainerImage, NodeMatch: mld.NodeMatch},
+			{Literal: kernelVersion, ContainerImage: otherMLD.ContainerImage},
+		}))
+	})
+
+	It("should keep the platform pinned for each mapping", func() {
+		mld.ContainerImage = imageName + "@digest"
+		mld.Platform = "linux/arm64"
+
+		gomock.InOrder(
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(gomock.Any(), kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
+		)
+
+		mwc := NewCreator(clnt, scheme, mockKM, mockRegistry, mockCache, "")
+
+		err := mwc.SetManifestWorkAsDesired(context.Background(), mw, mcm, []string{kernelVersion})
+		Expect(err).NotTo(HaveOccurred())
+
+		manifestModuleSpec := (mw.Spec.Workload.Manifests[0].RawExtension.Object).(*kmmv1beta1.Module).Spec
+		Expect(manifestModuleSpec.ModuleLoader.Container.KernelMappings).To(Equal([]kmmv1beta1.KernelMapping{
+			{Literal: kernelVersion, ContainerImage: mld.ContainerImage, Platform: "linux/arm64"},
+		}))
+	})
+
 	It("should work as expected", func() {
 		expectedResourceIdentifier := workv1.ResourceIdentifier{
 			Group:     "kmm.sigs.x-k8s.io",
@@ -344,9 +384,9 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 		}
 
 		gomock.InOrder(
-			mockKM.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).Return(&mld, nil),
+			mockKM.EXPECT().GetModuleLoaderDataForKernelVersion(gomock.Any(), kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil),
 			mockCache.EXPECT().Get(mld.ContainerImage).Return(nil, false),
-			mockRegistry.EXPECT().GetDigest(ctx, mld.ContainerImage+":latest", gomock.Any(), nil).Return(digest, nil),
+			mockRegistry.EXPECT().GetDigest(ctx, mld.ContainerImage+":latest", gomock.Any(), nil, nil).Return(digest, nil),
 			mockCache.EXPECT().Set(mld.ContainerImage, digest),
 		)
 
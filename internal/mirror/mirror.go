@@ -0,0 +1,171 @@
+// Package mirror rewrites image references to pull them from mirrors, in the spirit of containers-registries.conf(5).
+// The worker and the controllers use the same rules, so that the image checked by the controllers is the one pulled
+// on the node.
+package mirror
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Mirror is an alternative location for the images of a Registry.
+type Mirror struct {
+	// Location replaces the prefix of the Registry in image references, e.g. "mirror.example.com/quay".
+	Location string `json:"location" yaml:"location"`
+	// Insecure allows pulling from the mirror over plain HTTP.
+	Insecure bool `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	// InsecureSkipTLSVerify disables the verification of the mirror's certificate.
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty" yaml:"insecureSkipTLSVerify,omitempty"`
+	// CAFile is the path to a PEM bundle of the certificate authorities trusted for the mirror, in addition to the
+	// system ones.
+	CAFile string `json:"caFile,omitempty" yaml:"caFile,omitempty"`
+}
+
+// Transport returns the transport to use to pull from m, or nil if the default one can be used.
+func (m *Mirror) Transport() (*http.Transport, error) {
+	if !m.InsecureSkipTLSVerify && m.CAFile == "" {
+		return nil, nil
+	}
+
+	rt := http.DefaultTransport.(*http.Transport).Clone()
+
+	if rt.TLSClientConfig == nil {
+		rt.TLSClientConfig = &tls.Config{}
+	}
+
+	rt.TLSClientConfig.InsecureSkipVerify = m.InsecureSkipTLSVerify
+
+	if m.CAFile != "" {
+		pem, err := os.ReadFile(m.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the CA bundle of mirror %s: %v", m.Location, err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("could not load the system certificate pool: %v", err)
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", m.CAFile)
+		}
+
+		rt.TLSClientConfig.RootCAs = pool
+	}
+
+	return rt, nil
+}
+
+// Registry rewrites the references of the images whose repository starts with Prefix.
+type Registry struct {
+	// Prefix is matched against the repository of image references, on path component boundaries: "quay.io/org"
+	// matches "quay.io/org/image:tag" but not "quay.io/organization/image:tag".
+	Prefix string `json:"prefix" yaml:"prefix"`
+	// Location, if set, replaces Prefix in image references; images are then never pulled from their original location.
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+	// Mirrors are tried in order, before the location of the image.
+	Mirrors []Mirror `json:"mirrors,omitempty" yaml:"mirrors,omitempty"`
+}
+
+// Source is a location to pull an image from.
+type Source struct {
+	Image string
+	// Mirror is nil if Image is the location of the image rather than one of its mirrors.
+	Mirror *Mirror
+}
+
+// Sources returns the locations to try, in order, to pull image.
+// The Registry with the longest matching prefix applies; if none matches, image is the only location.
+func Sources(registries []Registry, image string) []Source {
+	reg := match(registries, image)
+	if reg == nil {
+		return []Source{{Image: image}}
+	}
+
+	rest := image[len(reg.Prefix):]
+
+	sources := make([]Source, 0, len(reg.Mirrors)+1)
+
+	for i := range reg.Mirrors {
+		m := &reg.Mirrors[i]
+		sources = append(sources, Source{Image: m.Location + rest, Mirror: m})
+	}
+
+	location := image
+
+	if reg.Location != "" {
+		location = reg.Location + rest
+	}
+
+	return append(sources, Source{Image: location})
+}
+
+// ParseFile reads registries from a YAML or JSON file.
+func ParseFile(path string) ([]Registry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	var registries []Registry
+
+	if err = yaml.UnmarshalStrict(b, &registries); err != nil {
+		return nil, fmt.Errorf("could not decode %s: %v", path, err)
+	}
+
+	return registries, nil
+}
+
+func match(registries []Registry, image string) *Registry {
+	repo := repository(image)
+
+	var best *Registry
+
+	for i := range registries {
+		r := &registries[i]
+
+		prefix := strings.TrimSuffix(r.Prefix, "/")
+		if prefix == "" || (repo != prefix && !strings.HasPrefix(repo, prefix+"/")) {
+			continue
+		}
+
+		if best == nil || len(prefix) > len(best.Prefix) {
+			best = &Registry{Prefix: prefix, Location: strings.TrimSuffix(r.Location, "/"), Mirrors: r.Mirrors}
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	mirrors := make([]Mirror, len(best.Mirrors))
+
+	for i, m := range best.Mirrors {
+		m.Location = strings.TrimSuffix(m.Location, "/")
+		mirrors[i] = m
+	}
+
+	best.Mirrors = mirrors
+
+	return best
+}
+
+// repository returns image without its tag or digest.
+func repository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	// The registry host may contain a port, so only consider a colon after the last slash
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	return image
+}
@@ -0,0 +1,168 @@
+package mirror
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sources", func() {
+	registries := []Registry{
+		{
+			Prefix: "quay.io",
+			Mirrors: []Mirror{
+				{Location: "mirror-a.example.com/quay"},
+				{Location: "mirror-b.example.com/quay/", Insecure: true},
+			},
+		},
+		{
+			Prefix:   "quay.io/org",
+			Location: "internal.example.com:5000/org",
+		},
+		{
+			Prefix:  "registry.example.com:5000/image",
+			Mirrors: []Mirror{{Location: "mirror-a.example.com/image"}},
+		},
+	}
+
+	DescribeTable(
+		"should work as expected",
+		func(image string, expected []Source) {
+			Expect(Sources(registries, image)).To(Equal(expected))
+		},
+		Entry(
+			"no matching prefix",
+			"docker.io/org/image:tag",
+			[]Source{{Image: "docker.io/org/image:tag"}},
+		),
+		Entry(
+			"prefix only matching part of a path component",
+			"quay.io.example.com/org/image:tag",
+			[]Source{{Image: "quay.io.example.com/org/image:tag"}},
+		),
+		Entry(
+			"mirrors before the original location",
+			"quay.io/other/image:tag",
+			[]Source{
+				{Image: "mirror-a.example.com/quay/other/image:tag", Mirror: &Mirror{Location: "mirror-a.example.com/quay"}},
+				{Image: "mirror-b.example.com/quay/other/image:tag", Mirror: &Mirror{Location: "mirror-b.example.com/quay", Insecure: true}},
+				{Image: "quay.io/other/image:tag"},
+			},
+		),
+		Entry(
+			"longest prefix with a location",
+			"quay.io/org/image@sha256:1234",
+			[]Source{{Image: "internal.example.com:5000/org/image@sha256:1234"}},
+		),
+		Entry(
+			"prefix matching the whole repository",
+			"registry.example.com:5000/image:tag",
+			[]Source{
+				{Image: "mirror-a.example.com/image:tag", Mirror: &Mirror{Location: "mirror-a.example.com/image"}},
+				{Image: "registry.example.com:5000/image:tag"},
+			},
+		),
+	)
+})
+
+var _ = Describe("ParseFile", func() {
+	It("should return an error if the file does not exist", func() {
+		_, err := ParseFile(filepath.Join(GinkgoT().TempDir(), "mirrors.yaml"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error for unknown fields", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "mirrors.yaml")
+		Expect(os.WriteFile(path, []byte("- prefix: quay.io\n  unknown: true\n"), 0644)).To(Succeed())
+
+		_, err := ParseFile(path)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read the registries", func() {
+		const contents = `
+- prefix: quay.io
+  location: internal.example.com/quay
+  mirrors:
+  - location: mirror.example.com/quay
+    insecureSkipTLSVerify: true
+`
+
+		path := filepath.Join(GinkgoT().TempDir(), "mirrors.yaml")
+		Expect(os.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+
+		Expect(
+			ParseFile(path),
+		).To(
+			Equal([]Registry{
+				{
+					Prefix:   "quay.io",
+					Location: "internal.example.com/quay",
+					Mirrors:  []Mirror{{Location: "mirror.example.com/quay", InsecureSkipTLSVerify: true}},
+				},
+			}),
+		)
+	})
+})
+
+var _ = Describe("Mirror_Transport", func() {
+	It("should return nil if the mirror uses the default TLS settings", func() {
+		m := Mirror{Location: "mirror.example.com"}
+
+		Expect(m.Transport()).To(BeNil())
+	})
+
+	It("should skip the verification of the certificate", func() {
+		m := Mirror{Location: "mirror.example.com", InsecureSkipTLSVerify: true}
+
+		rt, err := m.Transport()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rt.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+		Expect(rt.TLSClientConfig.RootCAs).To(BeNil())
+	})
+
+	It("should return an error if the CA bundle does not exist", func() {
+		m := Mirror{Location: "mirror.example.com", CAFile: filepath.Join(GinkgoT().TempDir(), "ca.pem")}
+
+		_, err := m.Transport()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the CA bundle has no certificate", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+		Expect(os.WriteFile(path, []byte("not a certificate"), 0644)).To(Succeed())
+
+		m := Mirror{Location: "mirror.example.com", CAFile: path}
+
+		_, err := m.Transport()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should trust the certificates of the CA bundle", func() {
+		server := httptest.NewTLSServer(http.NotFoundHandler())
+		DeferCleanup(server.Close)
+
+		path := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+		Expect(
+			os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644),
+		).To(
+			Succeed(),
+		)
+
+		m := Mirror{Location: server.Listener.Addr().String(), CAFile: path}
+
+		rt, err := m.Transport()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rt.TLSClientConfig.InsecureSkipVerify).To(BeFalse())
+
+		res, err := (&http.Client{Transport: rt}).Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Body.Close()).To(Succeed())
+		Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+	})
+})
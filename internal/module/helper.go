@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"strings"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/auth"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/registry"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/localimage"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/platform"
 )
 
 // AppendToTag adds the specified tag to the image name cleanly, i.e. by avoiding messing up
@@ -40,6 +43,32 @@ func ShouldBeSigned(mld *api.ModuleLoaderData) bool {
 	return mld.Sign != nil
 }
 
+// BuildAndSignKey returns the key of the image that mld builds or signs in-cluster.
+// Build and sign Pods are tracked per kernel version and target image, so that kernel mappings selecting different
+// images for the same kernel version on different nodes are all built and signed.
+func BuildAndSignKey(mld *api.ModuleLoaderData) string {
+	return mld.KernelVersion + "@" + mld.ContainerImage
+}
+
+// BuildAndSignCandidate returns which of current and candidate, which have the same BuildAndSignKey, should be built
+// and signed in-cluster.
+func BuildAndSignCandidate(current, candidate *api.ModuleLoaderData) *api.ModuleLoaderData {
+	if !ShouldBeBuilt(current) && !ShouldBeSigned(current) {
+		return candidate
+	}
+
+	return current
+}
+
+// Platform returns the platform pinned in mld, or nil if the image matching the node's platform should be used.
+func Platform(mld *api.ModuleLoaderData) (*v1.Platform, error) {
+	if mld.Platform == "" {
+		return nil, nil
+	}
+
+	return platform.Parse(mld.Platform)
+}
+
 func ImageDigest(
 	ctx context.Context,
 	client client.Client,
@@ -56,7 +85,12 @@ func ImageDigest(
 		})
 	}
 
-	digest, err := reg.GetDigest(ctx, imageName, mld.RegistryTLS, registryAuthGetter)
+	p, err := Platform(mld)
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := reg.GetDigest(ctx, imageName, mld.RegistryTLS, registryAuthGetter, p)
 	if err != nil {
 		return "", fmt.Errorf("could not get image digest: %v", err)
 	}
@@ -72,6 +106,11 @@ func ImageExists(
 	namespace string,
 	imageName string) (bool, error) {
 
+	// Local images are only present on the nodes; the worker reports an error if they are missing.
+	if localimage.IsLocal(imageName) {
+		return true, nil
+	}
+
 	var registryAuthGetter auth.RegistryAuthGetter
 	if mld.ImageRepoSecret != nil {
 		registryAuthGetter = auth.NewRegistryAuthGetter(client, types.NamespacedName{
@@ -80,7 +119,12 @@ func ImageExists(
 		})
 	}
 
-	exists, err := reg.ImageExists(ctx, imageName, mld.RegistryTLS, registryAuthGetter)
+	p, err := Platform(mld)
+	if err != nil {
+		return false, err
+	}
+
+	exists, err := reg.ImageExists(ctx, imageName, mld.RegistryTLS, registryAuthGetter, p)
 	if err != nil {
 		return false, fmt.Errorf("could not check if the image is available: %v", err)
 	}
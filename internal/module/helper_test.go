@@ -7,9 +7,11 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	ggcrv1 "github.com/google/go-containerregistry/pkg/v1"
 	gomock "go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/registry"
@@ -49,6 +51,57 @@ var _ = Describe("IntermediateImageName", func() {
 	})
 })
 
+var _ = Describe("BuildAndSignKey", func() {
+	It("should differ for different images built for the same kernel", func() {
+		Expect(
+			BuildAndSignKey(&api.ModuleLoaderData{KernelVersion: "1.2.3", ContainerImage: "a"}),
+		).NotTo(
+			Equal(BuildAndSignKey(&api.ModuleLoaderData{KernelVersion: "1.2.3", ContainerImage: "b"})),
+		)
+	})
+})
+
+var _ = Describe("BuildAndSignCandidate", func() {
+	build := &kmmv1beta1.Build{}
+
+	DescribeTable(
+		"should work as expected",
+		func(current, candidate *api.ModuleLoaderData, expectCandidate bool) {
+			selected := BuildAndSignCandidate(current, candidate)
+
+			if expectCandidate {
+				Expect(selected).To(BeIdenticalTo(candidate))
+			} else {
+				Expect(selected).To(BeIdenticalTo(current))
+			}
+		},
+		Entry(
+			"both are built",
+			&api.ModuleLoaderData{ContainerImage: "a", Build: build},
+			&api.ModuleLoaderData{ContainerImage: "a", Build: build},
+			false,
+		),
+		Entry(
+			"candidate is not built",
+			&api.ModuleLoaderData{ContainerImage: "a", Build: build},
+			&api.ModuleLoaderData{ContainerImage: "a"},
+			false,
+		),
+		Entry(
+			"only the candidate is built",
+			&api.ModuleLoaderData{ContainerImage: "a"},
+			&api.ModuleLoaderData{ContainerImage: "a", Build: build},
+			true,
+		),
+		Entry(
+			"only the candidate is signed",
+			&api.ModuleLoaderData{ContainerImage: "a"},
+			&api.ModuleLoaderData{ContainerImage: "a", Sign: &kmmv1beta1.Sign{}},
+			true,
+		),
+	)
+})
+
 var _ = Describe("ImageDigest", func() {
 	const (
 		imageName = "image-name"
@@ -79,7 +132,7 @@ var _ = Describe("ImageDigest", func() {
 		expectedDigest := "sha256:a-digest"
 
 		gomock.InOrder(
-			mockRegistry.EXPECT().GetDigest(ctx, imageName, gomock.Any(), nil).Return(expectedDigest, nil),
+			mockRegistry.EXPECT().GetDigest(ctx, imageName, gomock.Any(), nil, nil).Return(expectedDigest, nil),
 		)
 
 		digest, err := ImageDigest(ctx, clnt, mockRegistry, &mld, namespace, imageName)
@@ -90,7 +143,7 @@ var _ = Describe("ImageDigest", func() {
 
 	It("should return an error if the registry call fails", func() {
 		gomock.InOrder(
-			mockRegistry.EXPECT().GetDigest(ctx, imageName, gomock.Any(), nil).Return("", errors.New("some-error")),
+			mockRegistry.EXPECT().GetDigest(ctx, imageName, gomock.Any(), nil, nil).Return("", errors.New("some-error")),
 		)
 
 		digest, err := ImageDigest(ctx, clnt, mockRegistry, &mld, namespace, imageName)
@@ -108,7 +161,7 @@ var _ = Describe("ImageDigest", func() {
 		}
 
 		gomock.InOrder(
-			mockRegistry.EXPECT().GetDigest(ctx, imageName, gomock.Any(), gomock.Not(gomock.Nil())).Return(expectedDigest, nil),
+			mockRegistry.EXPECT().GetDigest(ctx, imageName, gomock.Any(), gomock.Not(gomock.Nil()), nil).Return(expectedDigest, nil),
 		)
 
 		digest, err := ImageDigest(ctx, clnt, mockRegistry, &mld, namespace, imageName)
@@ -146,7 +199,23 @@ var _ = Describe("ImageExists", func() {
 
 	It("should return true if the image exists", func() {
 		gomock.InOrder(
-			mockRegistry.EXPECT().ImageExists(ctx, imageName, gomock.Any(), nil).Return(true, nil),
+			mockRegistry.EXPECT().ImageExists(ctx, imageName, gomock.Any(), nil, nil).Return(true, nil),
+		)
+
+		exists, err := ImageExists(ctx, clnt, mockRegistry, &mld, namespace, imageName)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+	It("should check the pinned platform", func() {
+		mld.Platform = "linux/arm64"
+
+		gomock.InOrder(
+			mockRegistry.
+				EXPECT().
+				ImageExists(ctx, imageName, gomock.Any(), nil, &ggcrv1.Platform{OS: "linux", Architecture: "arm64"}).
+				Return(true, nil),
 		)
 
 		exists, err := ImageExists(ctx, clnt, mockRegistry, &mld, namespace, imageName)
@@ -157,7 +226,7 @@ var _ = Describe("ImageExists", func() {
 
 	It("should return false if the image does not exist", func() {
 		gomock.InOrder(
-			mockRegistry.EXPECT().ImageExists(ctx, imageName, gomock.Any(), nil).Return(false, nil),
+			mockRegistry.EXPECT().ImageExists(ctx, imageName, gomock.Any(), nil, nil).Return(false, nil),
 		)
 
 		exists, err := ImageExists(ctx, clnt, mockRegistry, &mld, namespace, imageName)
@@ -166,9 +235,16 @@ var _ = Describe("ImageExists", func() {
 		Expect(exists).To(BeFalse())
 	})
 
+	It("should return true without calling the registry for local images", func() {
+		exists, err := ImageExists(ctx, clnt, mockRegistry, &mld, namespace, "oci:/some/path")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
 	It("should return an error if the registry call fails", func() {
 		gomock.InOrder(
-			mockRegistry.EXPECT().ImageExists(ctx, imageName, gomock.Any(), nil).Return(false, errors.New("some-error")),
+			mockRegistry.EXPECT().ImageExists(ctx, imageName, gomock.Any(), nil, nil).Return(false, errors.New("some-error")),
 		)
 
 		exists, err := ImageExists(ctx, clnt, mockRegistry, &mld, namespace, imageName)
@@ -184,7 +260,7 @@ var _ = Describe("ImageExists", func() {
 		}
 
 		gomock.InOrder(
-			mockRegistry.EXPECT().ImageExists(ctx, imageName, gomock.Any(), gomock.Not(gomock.Nil())).Return(false, nil),
+			mockRegistry.EXPECT().ImageExists(ctx, imageName, gomock.Any(), gomock.Not(gomock.Nil()), nil).Return(false, nil),
 		)
 
 		exists, err := ImageExists(ctx, clnt, mockRegistry, &mld, namespace, imageName)
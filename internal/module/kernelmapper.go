@@ -4,12 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/build"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/sign"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 var ErrNoMatchingKernelMapping = errors.New("kernel mapping not found")
@@ -17,7 +21,8 @@ var ErrNoMatchingKernelMapping = errors.New("kernel mapping not found")
 //go:generate mockgen -source=kernelmapper.go -package=module -destination=mock_kernelmapper.go KernelMapper,kernelMapperHelperAPI
 
 type KernelMapper interface {
-	GetModuleLoaderDataForKernel(mod *kmmv1beta1.Module, kernelVersion string) (*api.ModuleLoaderData, error)
+	GetModuleLoaderDataForKernel(mod *kmmv1beta1.Module, node *v1.Node) (*api.ModuleLoaderData, error)
+	GetModuleLoaderDataForKernelVersion(mod *kmmv1beta1.Module, kernelVersion string) ([]*api.ModuleLoaderData, error)
 }
 
 type kernelMapper struct {
@@ -30,13 +35,44 @@ func NewKernelMapper(buildHelper build.Helper, signHelper sign.Helper) KernelMap
 	}
 }
 
-func (k *kernelMapper) GetModuleLoaderDataForKernel(mod *kmmv1beta1.Module, kernelVersion string) (*api.ModuleLoaderData, error) {
+// GetModuleLoaderDataForKernel returns the data of the first mapping of mod that matches node.
+func (k *kernelMapper) GetModuleLoaderDataForKernel(mod *kmmv1beta1.Module, node *v1.Node) (*api.ModuleLoaderData, error) {
+	kernelVersion := strings.TrimSuffix(node.Status.NodeInfo.KernelVersion, "+")
+
 	mappings := mod.Spec.ModuleLoader.Container.KernelMappings
-	foundMapping, err := k.helper.findKernelMapping(mappings, kernelVersion)
+	foundMapping, err := k.helper.findKernelMapping(mappings, node)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find mapping for kernel %s: %w", kernelVersion, err)
 	}
-	mld, err := k.helper.prepareModuleLoaderData(foundMapping, mod, kernelVersion)
+
+	return k.moduleLoaderData(foundMapping, mod, kernelVersion)
+}
+
+// GetModuleLoaderDataForKernelVersion returns the data of all mappings of mod that may be selected for nodes running
+// kernelVersion, for callers that do not know those nodes.
+// The data of each mapping carries its NodeMatch.
+func (k *kernelMapper) GetModuleLoaderDataForKernelVersion(mod *kmmv1beta1.Module, kernelVersion string) ([]*api.ModuleLoaderData, error) {
+	mappings, err := k.helper.findKernelMappings(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find mappings for kernel %s: %w", kernelVersion, err)
+	}
+
+	mlds := make([]*api.ModuleLoaderData, 0, len(mappings))
+
+	for i := range mappings {
+		mld, err := k.moduleLoaderData(&mappings[i], mod, kernelVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		mlds = append(mlds, mld)
+	}
+
+	return mlds, nil
+}
+
+func (k *kernelMapper) moduleLoaderData(mapping *kmmv1beta1.KernelMapping, mod *kmmv1beta1.Module, kernelVersion string) (*api.ModuleLoaderData, error) {
+	mld, err := k.helper.prepareModuleLoaderData(mapping, mod, kernelVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare module loader data for kernel %s: %v", kernelVersion, err)
 	}
@@ -49,7 +85,8 @@ func (k *kernelMapper) GetModuleLoaderDataForKernel(mod *kmmv1beta1.Module, kern
 }
 
 type kernelMapperHelperAPI interface {
-	findKernelMapping(mappings []kmmv1beta1.KernelMapping, kernelVersion string) (*kmmv1beta1.KernelMapping, error)
+	findKernelMapping(mappings []kmmv1beta1.KernelMapping, node *v1.Node) (*kmmv1beta1.KernelMapping, error)
+	findKernelMappings(mappings []kmmv1beta1.KernelMapping, kernelVersion string) ([]kmmv1beta1.KernelMapping, error)
 	prepareModuleLoaderData(mapping *kmmv1beta1.KernelMapping, mod *kmmv1beta1.Module, kernelVersion string) (*api.ModuleLoaderData, error)
 	replaceTemplates(mld *api.ModuleLoaderData) error
 }
@@ -66,18 +103,22 @@ func newKernelMapperHelper(buildHelper build.Helper, signHelper sign.Helper) ker
 	}
 }
 
-func (kh *kernelMapperHelper) findKernelMapping(mappings []kmmv1beta1.KernelMapping, kernelVersion string) (*kmmv1beta1.KernelMapping, error) {
+// findKernelMapping returns the first mapping that matches the kernel version and the properties of node.
+func (kh *kernelMapperHelper) findKernelMapping(mappings []kmmv1beta1.KernelMapping, node *v1.Node) (*kmmv1beta1.KernelMapping, error) {
+	kernelVersion := strings.TrimSuffix(node.Status.NodeInfo.KernelVersion, "+")
+
 	for _, m := range mappings {
-		if m.Literal != "" && m.Literal == kernelVersion {
-			return &m, nil
+		matches, err := kernelMatches(&m, kernelVersion)
+		if err != nil {
+			return nil, err
 		}
 
-		if m.Regexp == "" {
+		if !matches {
 			continue
 		}
 
-		if matches, err := regexp.MatchString(m.Regexp, kernelVersion); err != nil {
-			return nil, fmt.Errorf("could not match regexp %q against kernel %q: %v", m.Regexp, kernelVersion, err)
+		if matches, err = NodeMatches(&m.NodeMatch, node); err != nil {
+			return nil, err
 		} else if matches {
 			return &m, nil
 		}
@@ -86,6 +127,93 @@ func (kh *kernelMapperHelper) findKernelMapping(mappings []kmmv1beta1.KernelMapp
 	return nil, ErrNoMatchingKernelMapping
 }
 
+// findKernelMappings returns the mappings that match kernelVersion and that may be selected for some node running it.
+// Mappings following the first one that matches all nodes are never selected.
+func (kh *kernelMapperHelper) findKernelMappings(mappings []kmmv1beta1.KernelMapping, kernelVersion string) ([]kmmv1beta1.KernelMapping, error) {
+	found := make([]kmmv1beta1.KernelMapping, 0)
+
+	for _, m := range mappings {
+		matches, err := kernelMatches(&m, kernelVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matches {
+			continue
+		}
+
+		found = append(found, m)
+
+		if m.NodeMatch.IsEmpty() {
+			break
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, ErrNoMatchingKernelMapping
+	}
+
+	return found, nil
+}
+
+func kernelMatches(m *kmmv1beta1.KernelMapping, kernelVersion string) (bool, error) {
+	if m.Literal != "" && m.Literal == kernelVersion {
+		return true, nil
+	}
+
+	if m.Regexp == "" {
+		return false, nil
+	}
+
+	matches, err := regexp.MatchString(m.Regexp, kernelVersion)
+	if err != nil {
+		return false, fmt.Errorf("could not match regexp %q against kernel %q: %v", m.Regexp, kernelVersion, err)
+	}
+
+	return matches, nil
+}
+
+// NodeMatches returns true if node matches all the fields of nm that are set.
+func NodeMatches(nm *kmmv1beta1.NodeMatch, node *v1.Node) (bool, error) {
+	if nm.NodeSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(nm.NodeSelector)
+		if err != nil {
+			return false, fmt.Errorf("invalid node selector: %v", err)
+		}
+
+		if !sel.Matches(labels.Set(node.Labels)) {
+			return false, nil
+		}
+	}
+
+	nodeInfo := node.Status.NodeInfo
+
+	fields := []struct {
+		name, expr, value string
+	}{
+		{name: "OS image", expr: nm.OSImage, value: nodeInfo.OSImage},
+		{name: "architecture", expr: nm.Architecture, value: nodeInfo.Architecture},
+		{name: "container runtime version", expr: nm.ContainerRuntimeVersion, value: nodeInfo.ContainerRuntimeVersion},
+	}
+
+	for _, f := range fields {
+		if f.expr == "" {
+			continue
+		}
+
+		matches, err := regexp.MatchString(f.expr, f.value)
+		if err != nil {
+			return false, fmt.Errorf("could not match regexp %q against %s %q: %v", f.expr, f.name, f.value, err)
+		}
+
+		if !matches {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (kh *kernelMapperHelper) prepareModuleLoaderData(mapping *kmmv1beta1.KernelMapping, mod *kmmv1beta1.Module, kernelVersion string) (*api.ModuleLoaderData, error) {
 	var err error
 
@@ -115,9 +243,17 @@ func (kh *kernelMapperHelper) prepareModuleLoaderData(mapping *kmmv1beta1.Kernel
 		mld.ContainerImage = mod.Spec.ModuleLoader.Container.ContainerImage
 	}
 
+	// The deprecated field is passed through as is, so that the NMC config of existing Modules does not change.
 	mld.InTreeModuleToRemove = mod.Spec.ModuleLoader.Container.InTreeModuleToRemove
-	if mapping.InTreeModuleToRemove != "" {
+	mld.InTreeModulesToRemove = mod.Spec.ModuleLoader.Container.InTreeModulesToRemove
+	if mapping.InTreeModuleToRemove != "" || len(mapping.InTreeModulesToRemove) > 0 {
 		mld.InTreeModuleToRemove = mapping.InTreeModuleToRemove
+		mld.InTreeModulesToRemove = mapping.InTreeModulesToRemove
+	}
+
+	mld.Platform = mod.Spec.ModuleLoader.Container.Platform
+	if mapping.Platform != "" {
+		mld.Platform = mapping.Platform
 	}
 
 	mld.KernelVersion = kernelVersion
@@ -129,6 +265,11 @@ func (kh *kernelMapperHelper) prepareModuleLoaderData(mapping *kmmv1beta1.Kernel
 	mld.Modprobe = mod.Spec.ModuleLoader.Container.Modprobe
 	mld.ModuleVersion = mod.Spec.ModuleLoader.Container.Version
 	mld.ImagePullPolicy = mod.Spec.ModuleLoader.Container.ImagePullPolicy
+	mld.ImageVerification = mod.Spec.ModuleLoader.Container.ImageVerification
+	mld.Timeouts = mod.Spec.ModuleLoader.Container.Timeouts
+	mld.MaintenancePolicy = mod.Spec.MaintenancePolicy
+	mld.Dependencies = dependencies(mod)
+	mld.NodeMatch = mapping.NodeMatch
 	mld.Owner = mod
 
 	return mld, nil
@@ -146,3 +287,22 @@ func (kh *kernelMapperHelper) replaceTemplates(mld *api.ModuleLoaderData) error
 
 	return nil
 }
+
+// dependencies returns the dependencies of mod, with their namespace defaulting to mod's.
+func dependencies(mod *kmmv1beta1.Module) []kmmv1beta1.ModuleDependency {
+	if len(mod.Spec.Dependencies) == 0 {
+		return nil
+	}
+
+	deps := make([]kmmv1beta1.ModuleDependency, 0, len(mod.Spec.Dependencies))
+
+	for _, d := range mod.Spec.Dependencies {
+		if d.Namespace == "" {
+			d.Namespace = mod.Namespace
+		}
+
+		deps = append(deps, d)
+	}
+
+	return deps
+}
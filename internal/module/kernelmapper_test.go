@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("GetModuleLoaderDataForKernel", func() {
@@ -25,6 +26,7 @@ var _ = Describe("GetModuleLoaderDataForKernel", func() {
 		kh   *MockkernelMapperHelperAPI
 		km   *kernelMapper
 		mod  kmmv1beta1.Module
+		node v1.Node
 	)
 
 	BeforeEach(func() {
@@ -32,6 +34,11 @@ var _ = Describe("GetModuleLoaderDataForKernel", func() {
 		kh = NewMockkernelMapperHelperAPI(ctrl)
 		km = &kernelMapper{helper: kh}
 		mod = kmmv1beta1.Module{}
+		node = v1.Node{
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{KernelVersion: kernelVersion + "+"},
+			},
+		}
 	})
 
 	AfterEach(func() {
@@ -41,33 +48,33 @@ var _ = Describe("GetModuleLoaderDataForKernel", func() {
 	It("good flow", func() {
 		mapping := kmmv1beta1.KernelMapping{}
 		mld := api.ModuleLoaderData{KernelVersion: kernelVersion}
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(&mapping, nil)
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, &node).Return(&mapping, nil)
 		kh.EXPECT().prepareModuleLoaderData(&mapping, &mod, kernelVersion).Return(&mld, nil)
 		kh.EXPECT().replaceTemplates(&mld).Return(nil)
-		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
+		res, err := km.GetModuleLoaderDataForKernel(&mod, &node)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(res).To(Equal(&mld))
 	})
 
 	It("failed to find kernel mapping, internal error", func() {
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(nil, fmt.Errorf("some error"))
-		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, &node).Return(nil, fmt.Errorf("some error"))
+		res, err := km.GetModuleLoaderDataForKernel(&mod, &node)
 		Expect(err).To(HaveOccurred())
 		Expect(res).To(BeNil())
 	})
 
 	It("failed to find kernel mapping, mapping not present", func() {
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(nil, ErrNoMatchingKernelMapping)
-		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, &node).Return(nil, ErrNoMatchingKernelMapping)
+		res, err := km.GetModuleLoaderDataForKernel(&mod, &node)
 		Expect(errors.Is(err, ErrNoMatchingKernelMapping)).To(BeTrue())
 		Expect(res).To(BeNil())
 	})
 
 	It("failed to merge mapping data", func() {
 		mapping := kmmv1beta1.KernelMapping{}
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(&mapping, nil)
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, &node).Return(&mapping, nil)
 		kh.EXPECT().prepareModuleLoaderData(&mapping, &mod, kernelVersion).Return(nil, fmt.Errorf("some error"))
-		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
+		res, err := km.GetModuleLoaderDataForKernel(&mod, &node)
 		Expect(err).To(HaveOccurred())
 		Expect(res).To(BeNil())
 	})
@@ -75,15 +82,59 @@ var _ = Describe("GetModuleLoaderDataForKernel", func() {
 	It("failed to replace templates", func() {
 		mapping := kmmv1beta1.KernelMapping{}
 		mld := api.ModuleLoaderData{KernelVersion: kernelVersion}
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(&mapping, nil)
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, &node).Return(&mapping, nil)
 		kh.EXPECT().prepareModuleLoaderData(&mapping, &mod, kernelVersion).Return(&mld, nil)
 		kh.EXPECT().replaceTemplates(&mld).Return(fmt.Errorf("some error"))
-		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
+		res, err := km.GetModuleLoaderDataForKernel(&mod, &node)
 		Expect(err).To(HaveOccurred())
 		Expect(res).To(BeNil())
 	})
 })
 
+var _ = Describe("GetModuleLoaderDataForKernelVersion", func() {
+	const kernelVersion = "1.2.3"
+
+	var (
+		kh  *MockkernelMapperHelperAPI
+		km  *kernelMapper
+		mod kmmv1beta1.Module
+	)
+
+	BeforeEach(func() {
+		kh = NewMockkernelMapperHelperAPI(gomock.NewController(GinkgoT()))
+		km = &kernelMapper{helper: kh}
+		mod = kmmv1beta1.Module{}
+	})
+
+	It("should return the data of all the mappings", func() {
+		mappings := []kmmv1beta1.KernelMapping{{ContainerImage: "a"}, {ContainerImage: "b"}}
+		mldA := api.ModuleLoaderData{ContainerImage: "a"}
+		mldB := api.ModuleLoaderData{ContainerImage: "b"}
+
+		gomock.InOrder(
+			kh.EXPECT().findKernelMappings(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(mappings, nil),
+			kh.EXPECT().prepareModuleLoaderData(&mappings[0], &mod, kernelVersion).Return(&mldA, nil),
+			kh.EXPECT().replaceTemplates(&mldA),
+			kh.EXPECT().prepareModuleLoaderData(&mappings[1], &mod, kernelVersion).Return(&mldB, nil),
+			kh.EXPECT().replaceTemplates(&mldB),
+		)
+
+		res, err := km.GetModuleLoaderDataForKernelVersion(&mod, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal([]*api.ModuleLoaderData{&mldA, &mldB}))
+	})
+
+	It("should return an error if no mapping matches", func() {
+		kh.
+			EXPECT().
+			findKernelMappings(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).
+			Return(nil, ErrNoMatchingKernelMapping)
+
+		_, err := km.GetModuleLoaderDataForKernelVersion(&mod, kernelVersion)
+		Expect(errors.Is(err, ErrNoMatchingKernelMapping)).To(BeTrue())
+	})
+})
+
 var _ = Describe("findKernelMapping", func() {
 	const (
 		kernelVersion = "1.2.3"
@@ -92,11 +143,25 @@ var _ = Describe("findKernelMapping", func() {
 	var (
 		ctrl *gomock.Controller
 		kh   kernelMapperHelperAPI
+		node *v1.Node
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		kh = newKernelMapperHelper(nil, nil)
+		node = &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"gpu": "a100"},
+			},
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{
+					Architecture:            "arm64",
+					ContainerRuntimeVersion: "cri-o://1.28.1",
+					KernelVersion:           kernelVersion,
+					OSImage:                 "Ubuntu 22.04.3 LTS",
+				},
+			},
+		}
 	})
 
 	AfterEach(func() {
@@ -108,7 +173,7 @@ var _ = Describe("findKernelMapping", func() {
 			Literal: "1.2.3",
 		}
 
-		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
+		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, node)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(m).To(Equal(&mapping))
 	})
@@ -118,7 +183,7 @@ var _ = Describe("findKernelMapping", func() {
 			Regexp: `1\..*`,
 		}
 
-		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
+		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, node)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(m).To(Equal(&mapping))
 	})
@@ -128,7 +193,7 @@ var _ = Describe("findKernelMapping", func() {
 			Regexp: "invalid)",
 		}
 
-		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
+		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, node)
 		Expect(err).To(HaveOccurred())
 		Expect(m).To(BeNil())
 	})
@@ -143,7 +208,80 @@ var _ = Describe("findKernelMapping", func() {
 			},
 		}
 
-		m, err := kh.findKernelMapping(mappings, kernelVersion)
+		m, err := kh.findKernelMapping(mappings, node)
+		Expect(errors.Is(err, ErrNoMatchingKernelMapping)).To(BeTrue())
+		Expect(m).To(BeNil())
+	})
+
+	It("should skip mappings that do not match the node", func() {
+		mappings := []kmmv1beta1.KernelMapping{
+			{
+				Literal:        kernelVersion,
+				ContainerImage: "wrong-selector",
+				NodeMatch: kmmv1beta1.NodeMatch{
+					NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "h100"}},
+				},
+			},
+			{Literal: kernelVersion, ContainerImage: "wrong-os-image", NodeMatch: kmmv1beta1.NodeMatch{OSImage: "^Red Hat"}},
+			{Literal: kernelVersion, ContainerImage: "wrong-architecture", NodeMatch: kmmv1beta1.NodeMatch{Architecture: "^amd64$"}},
+			{
+				Literal:        kernelVersion,
+				ContainerImage: "wrong-runtime",
+				NodeMatch:      kmmv1beta1.NodeMatch{ContainerRuntimeVersion: "^containerd://"},
+			},
+			{
+				Regexp:         `^1\.2\.`,
+				ContainerImage: "match",
+				NodeMatch: kmmv1beta1.NodeMatch{
+					NodeSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "gpu", Operator: metav1.LabelSelectorOpIn, Values: []string{"a100", "h100"}},
+						},
+					},
+					OSImage:                 "Ubuntu 22.04",
+					Architecture:            "arm64",
+					ContainerRuntimeVersion: `^cri-o://1\.28`,
+				},
+			},
+		}
+
+		m, err := kh.findKernelMapping(mappings, node)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m).To(Equal(&mappings[4]))
+	})
+
+	It("should return an error if a node property regexp is invalid", func() {
+		mapping := kmmv1beta1.KernelMapping{
+			Literal:   kernelVersion,
+			NodeMatch: kmmv1beta1.NodeMatch{OSImage: "invalid)"},
+		}
+
+		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, node)
+		Expect(err).To(HaveOccurred())
+		Expect(m).To(BeNil())
+	})
+})
+
+var _ = Describe("findKernelMappings", func() {
+	const kernelVersion = "1.2.3"
+
+	kh := newKernelMapperHelper(nil, nil)
+
+	It("should return all mappings up to the first one matching all nodes", func() {
+		mappings := []kmmv1beta1.KernelMapping{
+			{Literal: kernelVersion, ContainerImage: "a100", NodeMatch: kmmv1beta1.NodeMatch{Architecture: "amd64"}},
+			{Literal: "1.2.4", ContainerImage: "other-kernel"},
+			{Regexp: `^1\.`, ContainerImage: "default"},
+			{Literal: kernelVersion, ContainerImage: "shadowed"},
+		}
+
+		m, err := kh.findKernelMappings(mappings, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m).To(Equal([]kmmv1beta1.KernelMapping{mappings[0], mappings[2]}))
+	})
+
+	It("should return an error if no mapping matches", func() {
+		m, err := kh.findKernelMappings([]kmmv1beta1.KernelMapping{{Literal: "1.2.4"}}, kernelVersion)
 		Expect(errors.Is(err, ErrNoMatchingKernelMapping)).To(BeTrue())
 		Expect(m).To(BeNil())
 	})
@@ -172,6 +310,16 @@ var _ = Describe("prepareModuleLoaderData", func() {
 		mod = kmmv1beta1.Module{}
 		mod.Spec.ModuleLoader.Container.ContainerImage = "spec container image"
 		mod.Spec.ModuleLoader.Container.ImagePullPolicy = "Always"
+		mod.Spec.ModuleLoader.Container.Platform = "linux/amd64"
+		mod.Spec.ModuleLoader.Container.ImageVerification = &kmmv1beta1.ImageVerification{
+			KeysSecret: v1.LocalObjectReference{Name: "keys"},
+		}
+		mod.Spec.MaintenancePolicy = &kmmv1beta1.MaintenancePolicy{ResourceNames: []v1.ResourceName{"example.com/gpu"}}
+		mod.Namespace = "some-namespace"
+		mod.Spec.Dependencies = []kmmv1beta1.ModuleDependency{
+			{Name: "dep-a"},
+			{Name: "dep-b", Namespace: "other-namespace"},
+		}
 		mapping = kmmv1beta1.KernelMapping{}
 	})
 
@@ -180,7 +328,7 @@ var _ = Describe("prepareModuleLoaderData", func() {
 	})
 
 	DescribeTable("prepare mapping", func(buildExistsInMapping, buildExistsInModuleSpec, signExistsInMapping, SignExistsInModuleSpec,
-		registryTLSExistsInMapping, containerImageExistsInMapping, inTreeModuleToRemoveExistsInMapping bool) {
+		registryTLSExistsInMapping, containerImageExistsInMapping, inTreeModuleToRemoveExistsInMapping, platformExistsInMapping bool) {
 		build := &kmmv1beta1.Build{
 			DockerfileConfigMap: &v1.LocalObjectReference{
 				Name: "some name",
@@ -203,6 +351,13 @@ var _ = Describe("prepareModuleLoaderData", func() {
 			Modprobe:           mod.Spec.ModuleLoader.Container.Modprobe,
 			ImagePullPolicy:    mod.Spec.ModuleLoader.Container.ImagePullPolicy,
 			KernelVersion:      kernelVersion,
+			Platform:           mod.Spec.ModuleLoader.Container.Platform,
+			ImageVerification:  mod.Spec.ModuleLoader.Container.ImageVerification,
+			MaintenancePolicy:  mod.Spec.MaintenancePolicy,
+			Dependencies: []kmmv1beta1.ModuleDependency{
+				{Name: "dep-a", Namespace: "some-namespace"},
+				{Name: "dep-b", Namespace: "other-namespace"},
+			},
 		}
 
 		if buildExistsInMapping {
@@ -240,18 +395,23 @@ var _ = Describe("prepareModuleLoaderData", func() {
 			mld.InTreeModuleToRemove = "some module"
 			mapping.InTreeModuleToRemove = "some module"
 		}
+		if platformExistsInMapping {
+			mld.Platform = "linux/arm64"
+			mapping.Platform = "linux/arm64"
+		}
 
 		res, err := kh.prepareModuleLoaderData(&mapping, &mod, kernelVersion)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(*res).To(Equal(mld))
 	},
-		Entry("build in mapping only", true, false, false, false, false, false, false),
-		Entry("build in spec only", false, true, false, false, false, false, false),
-		Entry("sign in mapping only", false, false, true, false, false, false, false),
-		Entry("sign in spec only", false, false, false, true, false, false, false),
-		Entry("registryTLS in mapping", false, false, false, false, true, false, false),
-		Entry("containerImage in mapping", false, false, false, false, false, true, false),
-		Entry("inTreeModuleToRemove in mapping", false, false, false, false, false, false, true),
+		Entry("build in mapping only", true, false, false, false, false, false, false, false),
+		Entry("build in spec only", false, true, false, false, false, false, false, false),
+		Entry("sign in mapping only", false, false, true, false, false, false, false, false),
+		Entry("sign in spec only", false, false, false, true, false, false, false, false),
+		Entry("registryTLS in mapping", false, false, false, false, true, false, false, false),
+		Entry("containerImage in mapping", false, false, false, false, false, true, false, false),
+		Entry("inTreeModuleToRemove in mapping", false, false, false, false, false, false, true, false),
+		Entry("platform in mapping", false, false, false, false, false, false, false, true),
 	)
 })
 
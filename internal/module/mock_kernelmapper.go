@@ -14,6 +14,7 @@ import (
 	v1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	api "github.com/kubernetes-sigs/kernel-module-management/internal/api"
 	gomock "go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
 )
 
 // MockKernelMapper is a mock of KernelMapper interface.
@@ -40,18 +41,33 @@ func (m *MockKernelMapper) EXPECT() *MockKernelMapperMockRecorder {
 }
 
 // GetModuleLoaderDataForKernel mocks base method.
-func (m *MockKernelMapper) GetModuleLoaderDataForKernel(mod *v1beta1.Module, kernelVersion string) (*api.ModuleLoaderData, error) {
+func (m *MockKernelMapper) GetModuleLoaderDataForKernel(mod *v1beta1.Module, node *v1.Node) (*api.ModuleLoaderData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModuleLoaderDataForKernel", mod, kernelVersion)
+	ret := m.ctrl.Call(m, "GetModuleLoaderDataForKernel", mod, node)
 	ret0, _ := ret[0].(*api.ModuleLoaderData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetModuleLoaderDataForKernel indicates an expected call of GetModuleLoaderDataForKernel.
-func (mr *MockKernelMapperMockRecorder) GetModuleLoaderDataForKernel(mod, kernelVersion any) *gomock.Call {
+func (mr *MockKernelMapperMockRecorder) GetModuleLoaderDataForKernel(mod, node any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleLoaderDataForKernel", reflect.TypeOf((*MockKernelMapper)(nil).GetModuleLoaderDataForKernel), mod, kernelVersion)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleLoaderDataForKernel", reflect.TypeOf((*MockKernelMapper)(nil).GetModuleLoaderDataForKernel), mod, node)
+}
+
+// GetModuleLoaderDataForKernelVersion mocks base method.
+func (m *MockKernelMapper) GetModuleLoaderDataForKernelVersion(mod *v1beta1.Module, kernelVersion string) ([]*api.ModuleLoaderData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModuleLoaderDataForKernelVersion", mod, kernelVersion)
+	ret0, _ := ret[0].([]*api.ModuleLoaderData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModuleLoaderDataForKernelVersion indicates an expected call of GetModuleLoaderDataForKernelVersion.
+func (mr *MockKernelMapperMockRecorder) GetModuleLoaderDataForKernelVersion(mod, kernelVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleLoaderDataForKernelVersion", reflect.TypeOf((*MockKernelMapper)(nil).GetModuleLoaderDataForKernelVersion), mod, kernelVersion)
 }
 
 // MockkernelMapperHelperAPI is a mock of kernelMapperHelperAPI interface.
@@ -78,18 +94,33 @@ func (m *MockkernelMapperHelperAPI) EXPECT() *MockkernelMapperHelperAPIMockRecor
 }
 
 // findKernelMapping mocks base method.
-func (m *MockkernelMapperHelperAPI) findKernelMapping(mappings []v1beta1.KernelMapping, kernelVersion string) (*v1beta1.KernelMapping, error) {
+func (m *MockkernelMapperHelperAPI) findKernelMapping(mappings []v1beta1.KernelMapping, node *v1.Node) (*v1beta1.KernelMapping, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "findKernelMapping", mappings, kernelVersion)
+	ret := m.ctrl.Call(m, "findKernelMapping", mappings, node)
 	ret0, _ := ret[0].(*v1beta1.KernelMapping)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // findKernelMapping indicates an expected call of findKernelMapping.
-func (mr *MockkernelMapperHelperAPIMockRecorder) findKernelMapping(mappings, kernelVersion any) *gomock.Call {
+func (mr *MockkernelMapperHelperAPIMockRecorder) findKernelMapping(mappings, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findKernelMapping", reflect.TypeOf((*MockkernelMapperHelperAPI)(nil).findKernelMapping), mappings, node)
+}
+
+// findKernelMappings mocks base method.
+func (m *MockkernelMapperHelperAPI) findKernelMappings(mappings []v1beta1.KernelMapping, kernelVersion string) ([]v1beta1.KernelMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "findKernelMappings", mappings, kernelVersion)
+	ret0, _ := ret[0].([]v1beta1.KernelMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// findKernelMappings indicates an expected call of findKernelMappings.
+func (mr *MockkernelMapperHelperAPIMockRecorder) findKernelMappings(mappings, kernelVersion any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findKernelMapping", reflect.TypeOf((*MockkernelMapperHelperAPI)(nil).findKernelMapping), mappings, kernelVersion)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findKernelMappings", reflect.TypeOf((*MockkernelMapperHelperAPI)(nil).findKernelMappings), mappings, kernelVersion)
 }
 
 // prepareModuleLoaderData mocks base method.
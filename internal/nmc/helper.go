@@ -69,6 +69,9 @@ func (h *helper) SetModuleConfig(
 	foundEntry.Config = *moduleConfig
 	foundEntry.ImageRepoSecret = mld.ImageRepoSecret
 	foundEntry.ServiceAccountName = saName
+	foundEntry.Timeouts = mld.Timeouts
+	foundEntry.MaintenancePolicy = mld.MaintenancePolicy
+	foundEntry.Dependencies = mld.Dependencies
 
 	return nil
 }
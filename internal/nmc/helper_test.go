@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -123,10 +124,14 @@ var _ = Describe("SetModuleConfig", func() {
 		}
 
 		moduleConfig := kmmv1beta1.ModuleConfig{InTreeModuleToRemove: "in-tree-module"}
+		policy := &kmmv1beta1.MaintenancePolicy{ResourceNames: []v1.ResourceName{"example.com/gpu"}}
+		deps := []kmmv1beta1.ModuleDependency{{Name: "dep", Namespace: namespace}}
 		mld := api.ModuleLoaderData{
 			Name:               name,
 			Namespace:          namespace,
 			ServiceAccountName: saName,
+			MaintenancePolicy:  policy,
+			Dependencies:       deps,
 		}
 
 		err := nmcHelper.SetModuleConfig(&nmc, &mld, &moduleConfig)
@@ -135,6 +140,8 @@ var _ = Describe("SetModuleConfig", func() {
 		Expect(len(nmc.Spec.Modules)).To(Equal(2))
 		Expect(nmc.Spec.Modules[1].Config.InTreeModuleToRemove).To(Equal("in-tree-module"))
 		Expect(nmc.Spec.Modules[1].ServiceAccountName).To(Equal(saName))
+		Expect(nmc.Spec.Modules[1].MaintenancePolicy).To(Equal(policy))
+		Expect(nmc.Spec.Modules[1].Dependencies).To(Equal(deps))
 	})
 })
 
@@ -391,7 +398,7 @@ var _ = Describe("SetModuleStatus", func() {
 			Namespace:          namespace,
 			ServiceAccountName: "sa",
 		},
-		Config: kmmv1beta1.ModuleConfig{
+		Config: &kmmv1beta1.ModuleConfig{
 			KernelVersion:        "some-kver",
 			ContainerImage:       "some-kernel-image",
 			InsecurePull:         true,
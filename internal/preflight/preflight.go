@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/auth"
@@ -13,6 +14,10 @@ import (
 	"github.com/kubernetes-sigs/kernel-module-management/internal/sign"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/statusupdater"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/localimage"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/platform"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	ctrlruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -52,15 +57,32 @@ type preflight struct {
 	helper        preflightHelperAPI
 }
 
+// PreflightUpgradeCheck verifies all the mappings of mod that nodes running the kernel version of pv may select.
 func (p *preflight) PreflightUpgradeCheck(ctx context.Context, pv *kmmv1beta1.PreflightValidation, mod *kmmv1beta1.Module) (bool, string) {
-	log := ctrlruntime.LoggerFrom(ctx)
 	kernelVersion := pv.Spec.KernelVersion
-	mld, err := p.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
+	mlds, err := p.kernelAPI.GetModuleLoaderDataForKernelVersion(mod, kernelVersion)
 	if err != nil {
 		return false, fmt.Sprintf("failed to process kernel mapping in the module %s for kernel version %s", mod.Name, kernelVersion)
 	}
 
-	err = p.statusUpdater.PreflightSetVerificationStage(ctx, pv, mld.Name, kmmv1beta1.VerificationStageImage)
+	var (
+		verified bool
+		msg      string
+	)
+
+	for _, mld := range mlds {
+		if verified, msg = p.verifyModuleLoaderData(ctx, pv, mld); !verified {
+			return false, msg
+		}
+	}
+
+	return verified, msg
+}
+
+func (p *preflight) verifyModuleLoaderData(ctx context.Context, pv *kmmv1beta1.PreflightValidation, mld *api.ModuleLoaderData) (bool, string) {
+	log := ctrlruntime.LoggerFrom(ctx)
+
+	err := p.statusUpdater.PreflightSetVerificationStage(ctx, pv, mld.Name, kmmv1beta1.VerificationStageImage)
 	if err != nil {
 		log.Info(utils.WarnString("failed to update the stage of Module CR in preflight to image stage"), "module", mld.Name, "error", err)
 	}
@@ -123,12 +145,87 @@ func newPreflightHelper(client client.Client, buildAPI build.Manager, signAPI si
 func (p *preflightHelper) verifyImage(ctx context.Context, mld *api.ModuleLoaderData) (bool, string) {
 	log := ctrlruntime.LoggerFrom(ctx)
 	image := mld.ContainerImage
+
+	if localimage.IsLocal(image) {
+		log.Info("image is stored on the nodes and cannot be verified", "module name", mld.Name, "image", image)
+		return false, fmt.Sprintf("image %s is stored on the nodes and cannot be verified", image)
+	}
+
+	platforms, err := p.targetPlatforms(ctx, mld)
+	if err != nil {
+		return false, fmt.Sprintf("could not determine the platforms of image %s: %v", image, err)
+	}
+
+	var (
+		verified bool
+		msg      string
+	)
+
+	for _, imgPlatform := range platforms {
+		if verified, msg = p.verifyImageForPlatform(ctx, mld, imgPlatform); !verified {
+			return false, msg
+		}
+	}
+
+	return verified, msg
+}
+
+// targetPlatforms returns the platforms for which the image of mld must be verified: the platform pinned in mld, or the
+// distinct platforms of the nodes that mld targets.
+// It returns a single nil platform if no node is targeted, so that only single-architecture images can be verified.
+func (p *preflightHelper) targetPlatforms(ctx context.Context, mld *api.ModuleLoaderData) ([]*v1.Platform, error) {
+	imgPlatform, err := module.Platform(mld)
+	if err != nil {
+		return nil, fmt.Errorf("invalid platform: %v", err)
+	}
+
+	if imgPlatform != nil {
+		return []*v1.Platform{imgPlatform}, nil
+	}
+
+	nodes := corev1.NodeList{}
+
+	if err = p.client.List(ctx, &nodes, client.MatchingLabels(mld.Selector)); err != nil {
+		return nil, fmt.Errorf("could not list nodes: %v", err)
+	}
+
+	platforms := make([]*v1.Platform, 0)
+	seen := sets.New[string]()
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+
+		matches, err := module.NodeMatches(&mld.NodeMatch, node)
+		if err != nil {
+			return nil, err
+		}
+
+		np := platform.ForNode(node)
+
+		if !matches || np == nil || seen.Has(np.String()) {
+			continue
+		}
+
+		seen.Insert(np.String())
+		platforms = append(platforms, np)
+	}
+
+	if len(platforms) == 0 {
+		return []*v1.Platform{nil}, nil
+	}
+
+	return platforms, nil
+}
+
+func (p *preflightHelper) verifyImageForPlatform(ctx context.Context, mld *api.ModuleLoaderData, imgPlatform *v1.Platform) (bool, string) {
+	log := ctrlruntime.LoggerFrom(ctx)
+	image := mld.ContainerImage
 	moduleFileName := mld.Modprobe.ModuleName + ".ko"
 	baseDir := mld.Modprobe.DirName
 	kernelVersion := mld.KernelVersion
 
 	registryAuthGetter := auth.NewRegistryAuthGetterFrom(p.client, mld)
-	digests, repoConfig, err := p.registryAPI.GetLayersDigests(ctx, image, mld.RegistryTLS, registryAuthGetter)
+	digests, repoConfig, err := p.registryAPI.GetLayersDigests(ctx, image, mld.RegistryTLS, registryAuthGetter, imgPlatform)
 	if err != nil {
 		log.Info("image layers inaccessible, image probably does not exists", "module name", mld.Name, "image", image)
 		return false, fmt.Sprintf("image %s inaccessible or does not exists", image)
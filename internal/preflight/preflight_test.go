@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	v1stream "github.com/google/go-containerregistry/pkg/v1/stream"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
@@ -18,7 +19,9 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -100,7 +103,7 @@ var _ = Describe("preflight_PreflightUpgradeCheck", func() {
 
 	It("Failed to process mapping", func() {
 		mod.Spec.ModuleLoader.Container.KernelMappings = []kmmv1beta1.KernelMapping{}
-		mockKernelAPI.EXPECT().GetModuleLoaderDataForKernel(mod, kernelVersion).Return(nil, fmt.Errorf("some error"))
+		mockKernelAPI.EXPECT().GetModuleLoaderDataForKernelVersion(mod, kernelVersion).Return(nil, fmt.Errorf("some error"))
 
 		res, message := p.PreflightUpgradeCheck(context.Background(), pv, mod)
 
@@ -108,6 +111,24 @@ var _ = Describe("preflight_PreflightUpgradeCheck", func() {
 		Expect(message).To(Equal(fmt.Sprintf("failed to process kernel mapping in the module %s for kernel version %s", mod.Name, kernelVersion)))
 	})
 
+	It("should verify all the mappings nodes may select", func() {
+		ctx := context.Background()
+		mld1 := api.ModuleLoaderData{Name: mod.Name, ContainerImage: "image1"}
+		mld2 := api.ModuleLoaderData{Name: mod.Name, ContainerImage: "image2"}
+
+		gomock.InOrder(
+			mockKernelAPI.EXPECT().GetModuleLoaderDataForKernelVersion(mod, kernelVersion).Return([]*api.ModuleLoaderData{&mld1, &mld2}, nil),
+			mockStatusUpdater.EXPECT().PreflightSetVerificationStage(ctx, pv, mod.Name, kmmv1beta1.VerificationStageImage),
+			preflightHelper.EXPECT().verifyImage(ctx, &mld1).Return(true, "image1 verified"),
+			mockStatusUpdater.EXPECT().PreflightSetVerificationStage(ctx, pv, mod.Name, kmmv1beta1.VerificationStageImage),
+			preflightHelper.EXPECT().verifyImage(ctx, &mld2).Return(false, "image2 not verified"),
+		)
+
+		res, msg := p.PreflightUpgradeCheck(ctx, pv, mod)
+		Expect(res).To(BeFalse())
+		Expect(msg).To(Equal("image2 not verified"))
+	})
+
 	DescribeTable("correct flow of the image/build/sign verification", func(buildExists, signExists, imageVerified, buildVerified, signVerified,
 		returnedResult bool, returnedMessage string) {
 		ctx := context.Background()
@@ -125,7 +146,7 @@ var _ = Describe("preflight_PreflightUpgradeCheck", func() {
 			mld.Sign = &kmmv1beta1.Sign{}
 		}
 
-		mockKernelAPI.EXPECT().GetModuleLoaderDataForKernel(mod, kernelVersion).Return(&mld, nil)
+		mockKernelAPI.EXPECT().GetModuleLoaderDataForKernelVersion(mod, kernelVersion).Return([]*api.ModuleLoaderData{&mld}, nil)
 		mockStatusUpdater.EXPECT().PreflightSetVerificationStage(context.Background(), pv, mld.Name, kmmv1beta1.VerificationStageImage).Return(nil)
 		preflightHelper.EXPECT().verifyImage(ctx, &mld).Return(imageVerified, "image message")
 		if !imageVerified {
@@ -229,8 +250,9 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 		repoConfig := &registry.RepoPullConfig{}
 		digestLayer := v1stream.Layer{}
 		gomock.InOrder(
+			clnt.EXPECT().List(context.Background(), &corev1.NodeList{}, ctrlclient.MatchingLabels(nil)),
 			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(),
-				gomock.Any()).Return(digests, repoConfig, nil),
+				gomock.Any(), nil).Return(digests, repoConfig, nil),
 			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[1], repoConfig).Return(&digestLayer, nil),
 			mockRegistryAPI.EXPECT().VerifyModuleExists(&digestLayer, "/opt", kernelVersion, "simple-kmod.ko").Return(true),
 		)
@@ -247,8 +269,9 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 			KernelVersion:  kernelVersion,
 		}
 
+		clnt.EXPECT().List(context.Background(), &corev1.NodeList{}, ctrlclient.MatchingLabels(nil))
 		mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(),
-			gomock.Any()).Return(nil, nil, fmt.Errorf("some error"))
+			gomock.Any(), nil).Return(nil, nil, fmt.Errorf("some error"))
 
 		res, message := ph.verifyImage(context.Background(), &mld)
 
@@ -256,6 +279,20 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 		Expect(message).To(Equal(fmt.Sprintf("image %s inaccessible or does not exists", containerImage)))
 	})
 
+	It("local image", func() {
+		const localImage = "oci:/some/path"
+
+		mld := api.ModuleLoaderData{
+			ContainerImage: localImage,
+			KernelVersion:  kernelVersion,
+		}
+
+		res, message := ph.verifyImage(context.Background(), &mld)
+
+		Expect(res).To(BeFalse())
+		Expect(message).To(Equal(fmt.Sprintf("image %s is stored on the nodes and cannot be verified", localImage)))
+	})
+
 	It("failed to get specific layer", func() {
 		mld := api.ModuleLoaderData{
 			ContainerImage: containerImage,
@@ -264,8 +301,9 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 		digests := []string{"digest0", "digest1"}
 		repoConfig := &registry.RepoPullConfig{}
 		gomock.InOrder(
+			clnt.EXPECT().List(context.Background(), &corev1.NodeList{}, ctrlclient.MatchingLabels(nil)),
 			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(),
-				gomock.Any()).Return(digests, repoConfig, nil),
+				gomock.Any(), nil).Return(digests, repoConfig, nil),
 			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[1], repoConfig).Return(nil, fmt.Errorf("some error")),
 		)
 
@@ -285,7 +323,8 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 		repoConfig := &registry.RepoPullConfig{}
 		digestLayer := v1stream.Layer{}
 		gomock.InOrder(
-			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(), gomock.Any()).Return(digests, repoConfig, nil),
+			clnt.EXPECT().List(context.Background(), &corev1.NodeList{}, ctrlclient.MatchingLabels(nil)),
+			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(), gomock.Any(), nil).Return(digests, repoConfig, nil),
 			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[0], repoConfig).Return(&digestLayer, nil),
 			mockRegistryAPI.EXPECT().VerifyModuleExists(&digestLayer, "/opt", kernelVersion, "simple-kmod.ko").Return(false),
 		)
@@ -296,6 +335,66 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 		Expect(message).To(Equal(fmt.Sprintf("image %s does not contain kernel module for kernel %s on any layer", containerImage, kernelVersion)))
 	})
 
+	It("should verify the image for the platform of each targeted node", func() {
+		mld := api.ModuleLoaderData{
+			ContainerImage: containerImage,
+			Modprobe:       mod.Spec.ModuleLoader.Container.Modprobe,
+			KernelVersion:  kernelVersion,
+			Selector:       map[string]string{"key": "value"},
+			NodeMatch:      kmmv1beta1.NodeMatch{OSImage: "^RHEL"},
+		}
+
+		nodeFor := func(osImage, arch string) corev1.Node {
+			return corev1.Node{
+				Status: corev1.NodeStatus{
+					NodeInfo: corev1.NodeSystemInfo{OSImage: osImage, OperatingSystem: "linux", Architecture: arch},
+				},
+			}
+		}
+
+		digests := []string{"digest0"}
+		repoConfig := &registry.RepoPullConfig{}
+		digestLayer := v1stream.Layer{}
+		gomock.InOrder(
+			clnt.EXPECT().List(context.Background(), &corev1.NodeList{}, ctrlclient.MatchingLabels(mld.Selector)).DoAndReturn(
+				func(_ context.Context, list *corev1.NodeList, _ ...ctrlclient.ListOption) error {
+					list.Items = []corev1.Node{
+						nodeFor("RHEL 9", "amd64"),
+						nodeFor("RHEL 9", "arm64"),
+						nodeFor("RHEL 9", "amd64"),
+						nodeFor("Ubuntu", "s390x"),
+					}
+					return nil
+				},
+			),
+			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(),
+				gomock.Any(), &v1.Platform{OS: "linux", Architecture: "amd64"}).Return(digests, repoConfig, nil),
+			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[0], repoConfig).Return(&digestLayer, nil),
+			mockRegistryAPI.EXPECT().VerifyModuleExists(&digestLayer, "/opt", kernelVersion, "simple-kmod.ko").Return(true),
+			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(),
+				gomock.Any(), &v1.Platform{OS: "linux", Architecture: "arm64"}).Return(nil, nil, fmt.Errorf("some error")),
+		)
+
+		res, message := ph.verifyImage(context.Background(), &mld)
+
+		Expect(res).To(BeFalse())
+		Expect(message).To(Equal(fmt.Sprintf("image %s inaccessible or does not exists", containerImage)))
+	})
+
+	It("should only verify the image for the pinned platform", func() {
+		mld := api.ModuleLoaderData{
+			ContainerImage: containerImage,
+			KernelVersion:  kernelVersion,
+			Platform:       "linux/arm64",
+		}
+
+		mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(),
+			gomock.Any(), &v1.Platform{OS: "linux", Architecture: "arm64"}).Return(nil, nil, fmt.Errorf("some error"))
+
+		res, _ := ph.verifyImage(context.Background(), &mld)
+
+		Expect(res).To(BeFalse())
+	})
 })
 
 var _ = Describe("preflightHelper_verifyBuild", func() {
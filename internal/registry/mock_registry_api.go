@@ -42,18 +42,18 @@ func (m *MockRegistry) EXPECT() *MockRegistryMockRecorder {
 }
 
 // GetDigest mocks base method.
-func (m *MockRegistry) GetDigest(ctx context.Context, image string, tlsOptions *v1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (string, error) {
+func (m *MockRegistry) GetDigest(ctx context.Context, image string, tlsOptions *v1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDigest", ctx, image, tlsOptions, registryAuthGetter)
+	ret := m.ctrl.Call(m, "GetDigest", ctx, image, tlsOptions, registryAuthGetter, platform)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetDigest indicates an expected call of GetDigest.
-func (mr *MockRegistryMockRecorder) GetDigest(ctx, image, tlsOptions, registryAuthGetter any) *gomock.Call {
+func (mr *MockRegistryMockRecorder) GetDigest(ctx, image, tlsOptions, registryAuthGetter, platform any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigest", reflect.TypeOf((*MockRegistry)(nil).GetDigest), ctx, image, tlsOptions, registryAuthGetter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigest", reflect.TypeOf((*MockRegistry)(nil).GetDigest), ctx, image, tlsOptions, registryAuthGetter, platform)
 }
 
 // GetLayerByDigest mocks base method.
@@ -72,9 +72,9 @@ func (mr *MockRegistryMockRecorder) GetLayerByDigest(digest, pullConfig any) *go
 }
 
 // GetLayersDigests mocks base method.
-func (m *MockRegistry) GetLayersDigests(ctx context.Context, image string, tlsOptions *v1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) ([]string, *RepoPullConfig, error) {
+func (m *MockRegistry) GetLayersDigests(ctx context.Context, image string, tlsOptions *v1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) ([]string, *RepoPullConfig, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLayersDigests", ctx, image, tlsOptions, registryAuthGetter)
+	ret := m.ctrl.Call(m, "GetLayersDigests", ctx, image, tlsOptions, registryAuthGetter, platform)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(*RepoPullConfig)
 	ret2, _ := ret[2].(error)
@@ -82,24 +82,24 @@ func (m *MockRegistry) GetLayersDigests(ctx context.Context, image string, tlsOp
 }
 
 // GetLayersDigests indicates an expected call of GetLayersDigests.
-func (mr *MockRegistryMockRecorder) GetLayersDigests(ctx, image, tlsOptions, registryAuthGetter any) *gomock.Call {
+func (mr *MockRegistryMockRecorder) GetLayersDigests(ctx, image, tlsOptions, registryAuthGetter, platform any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLayersDigests", reflect.TypeOf((*MockRegistry)(nil).GetLayersDigests), ctx, image, tlsOptions, registryAuthGetter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLayersDigests", reflect.TypeOf((*MockRegistry)(nil).GetLayersDigests), ctx, image, tlsOptions, registryAuthGetter, platform)
 }
 
 // ImageExists mocks base method.
-func (m *MockRegistry) ImageExists(ctx context.Context, image string, tlsOptions *v1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (bool, error) {
+func (m *MockRegistry) ImageExists(ctx context.Context, image string, tlsOptions *v1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ImageExists", ctx, image, tlsOptions, registryAuthGetter)
+	ret := m.ctrl.Call(m, "ImageExists", ctx, image, tlsOptions, registryAuthGetter, platform)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ImageExists indicates an expected call of ImageExists.
-func (mr *MockRegistryMockRecorder) ImageExists(ctx, image, tlsOptions, registryAuthGetter any) *gomock.Call {
+func (mr *MockRegistryMockRecorder) ImageExists(ctx, image, tlsOptions, registryAuthGetter, platform any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageExists", reflect.TypeOf((*MockRegistry)(nil).ImageExists), ctx, image, tlsOptions, registryAuthGetter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageExists", reflect.TypeOf((*MockRegistry)(nil).ImageExists), ctx, image, tlsOptions, registryAuthGetter, platform)
 }
 
 // VerifyModuleExists mocks base method.
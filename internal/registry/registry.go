@@ -10,15 +10,17 @@ import (
 	"io"
 	"net/http"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/google/go-containerregistry/pkg/crane"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/auth"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mirror"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/platform"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -40,45 +42,94 @@ type RepoPullConfig struct {
 
 //go:generate mockgen -source=registry.go -package=registry -destination=mock_registry_api.go
 
+// Registry accesses images in registries.
+// Images are looked up in the mirrors configured for them first, in order; the TLS options of a mirror replace the
+// ones passed for the image.
+// When an image is a multi-architecture index, platform selects the image of the index to use.
+// A nil platform means that no image is selected: ImageExists checks that the index exists, GetDigest returns the digest
+// of the index, and GetLayersDigests returns an error as an index has no layers.
 type Registry interface {
-	ImageExists(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (bool, error)
+	ImageExists(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) (bool, error)
 	VerifyModuleExists(layer v1.Layer, pathPrefix, kernelVersion, moduleFileName string) bool
-	GetLayersDigests(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) ([]string, *RepoPullConfig, error)
+	GetLayersDigests(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) ([]string, *RepoPullConfig, error)
 	GetLayerByDigest(digest string, pullConfig *RepoPullConfig) (v1.Layer, error)
-	GetDigest(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (string, error)
+	GetDigest(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) (string, error)
 }
 
 type registry struct {
+	mirrors []mirror.Registry
 }
 
-func NewRegistry() Registry {
-	return &registry{}
+func NewRegistry(mirrors []mirror.Registry) Registry {
+	return &registry{mirrors: mirrors}
 }
 
-func (r *registry) ImageExists(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (bool, error) {
-	_, _, err := r.getImageManifest(ctx, image, tlsOptions, registryAuthGetter)
-	if err != nil {
+type source struct {
+	image string
+	// tlsOptions apply to the original location of the image; mirror, if set, has its own TLS settings.
+	tlsOptions *kmmv1beta1.TLSOptions
+	mirror     *mirror.Mirror
+}
+
+// sources returns the locations of image, with the TLS settings to use for each of them.
+func (r *registry) sources(image string, tlsOptions *kmmv1beta1.TLSOptions) []source {
+	ms := mirror.Sources(r.mirrors, image)
+
+	sources := make([]source, 0, len(ms))
+
+	for _, s := range ms {
+		src := source{image: s.Image, mirror: s.Mirror}
+
+		if s.Mirror == nil {
+			src.tlsOptions = tlsOptions
+		}
+
+		sources = append(sources, src)
+	}
+
+	return sources
+}
+
+func (r *registry) ImageExists(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) (bool, error) {
+	var errs []error
+
+	for _, s := range r.sources(image, tlsOptions) {
+		_, _, err := r.getImageManifest(ctx, s, registryAuthGetter, platform)
+		if err == nil {
+			return true, nil
+		}
+
 		te := &transport.Error{}
 		if errors.As(err, &te) && te.StatusCode == http.StatusNotFound {
-			return false, nil
+			continue
 		}
-		return false, fmt.Errorf("could not get image %s: %w", image, err)
+
+		errs = append(errs, fmt.Errorf("could not get image %s: %w", s.image, err))
 	}
-	return true, nil
+
+	return false, errors.Join(errs...)
 }
 
-func (r *registry) GetLayersDigests(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) ([]string, *RepoPullConfig, error) {
-	manifest, pullConfig, err := r.getImageManifest(ctx, image, tlsOptions, registryAuthGetter)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get manifest from image %s: %w", image, err)
-	}
+func (r *registry) GetLayersDigests(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) ([]string, *RepoPullConfig, error) {
+	var errs []error
 
-	digests, err := r.getLayersDigestsFromManifestStream(manifest)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get layers digests from manifest of the image %s: %w", image, err)
+	for _, s := range r.sources(image, tlsOptions) {
+		manifest, pullConfig, err := r.getImageManifest(ctx, s, registryAuthGetter, platform)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get manifest from image %s: %w", s.image, err))
+			continue
+		}
+
+		digests, err := r.getLayersDigestsFromManifestStream(manifest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get layers digests from manifest of the image %s: %w", s.image, err)
+		}
+
+		// pullConfig points to the source the manifest was found in, so that the layers are pulled from it too.
+		return digests, pullConfig, nil
 	}
 
-	return digests, pullConfig, nil
+	return nil, nil, errors.Join(errs...)
 }
 
 func (r *registry) GetLayerByDigest(digest string, pullConfig *RepoPullConfig) (v1.Layer, error) {
@@ -92,25 +143,51 @@ func (r *registry) VerifyModuleExists(layer v1.Layer, pathPrefix, kernelVersion,
 	return err == nil
 }
 
-func (r *registry) GetDigest(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (string, error) {
-	pullConfig, err := r.getPullOptions(ctx, image, tlsOptions, registryAuthGetter)
+func (r *registry) GetDigest(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) (string, error) {
+	var errs []error
+
+	for _, s := range r.sources(image, tlsOptions) {
+		digest, err := r.getDigest(ctx, s, registryAuthGetter, platform)
+		if err == nil {
+			return digest, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return "", errors.Join(errs...)
+}
+
+func (r *registry) getDigest(ctx context.Context, s source, registryAuthGetter auth.RegistryAuthGetter, platform *v1.Platform) (string, error) {
+	image := s.image
+
+	pullConfig, err := r.getPullOptions(ctx, s, registryAuthGetter)
 	if err != nil {
 		return "", fmt.Errorf("failed to get pull options for image %s: %v", image, err)
 	}
 
-	digest, err := crane.Digest(image, pullConfig.authOptions...)
+	opts := pullConfig.authOptions
+
+	if platform != nil {
+		opts = append(opts, crane.WithPlatform(platform))
+	}
+
+	digest, err := crane.Digest(image, opts...)
 	if err != nil {
 		return "", fmt.Errorf("failed to get digest for image %s: %v", image, err)
 	}
 	return digest, err
 }
 
-func (r *registry) getPullOptions(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (*RepoPullConfig, error) {
+func (r *registry) getPullOptions(ctx context.Context, s source, registryAuthGetter auth.RegistryAuthGetter) (*RepoPullConfig, error) {
+	image := s.image
+
 	var repo string
 	if hash := strings.Split(image, "@"); len(hash) > 1 {
 		repo = hash[0]
-	} else if tag := strings.Split(image, ":"); len(tag) > 1 {
-		repo = tag[0]
+	} else if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		// The registry host may contain a port, so only consider a colon after the last slash
+		repo = image[:i]
 	}
 
 	if repo == "" {
@@ -121,7 +198,20 @@ func (r *registry) getPullOptions(ctx context.Context, image string, tlsOptions
 		crane.WithContext(ctx),
 	}
 
-	if tlsOptions != nil {
+	if m := s.mirror; m != nil {
+		if m.Insecure {
+			options = append(options, crane.Insecure)
+		}
+
+		rt, err := m.Transport()
+		if err != nil {
+			return nil, fmt.Errorf("could not configure the transport for mirror %s: %v", m.Location, err)
+		}
+
+		if rt != nil {
+			options = append(options, crane.WithTransport(rt))
+		}
+	} else if tlsOptions := s.tlsOptions; tlsOptions != nil {
 		if tlsOptions.Insecure {
 			options = append(options, crane.Insecure)
 		}
@@ -151,12 +241,15 @@ func (r *registry) getPullOptions(ctx context.Context, image string, tlsOptions
 	return &RepoPullConfig{repo: repo, authOptions: options}, nil
 }
 
-func (r *registry) getImageManifest(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) ([]byte, *RepoPullConfig, error) {
-	pullConfig, err := r.getPullOptions(ctx, image, tlsOptions, registryAuthGetter)
+func (r *registry) getImageManifest(ctx context.Context, s source, registryAuthGetter auth.RegistryAuthGetter, p *v1.Platform) ([]byte, *RepoPullConfig, error) {
+	image := s.image
+
+	pullConfig, err := r.getPullOptions(ctx, s, registryAuthGetter)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get pull options for image %s: %w", image, err)
 	}
-	manifest, err := r.getManifestStreamFromImage(image, pullConfig.repo, pullConfig.authOptions)
+
+	manifest, err := r.getManifestStreamFromImage(image, pullConfig.repo, pullConfig.authOptions, p)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get manifest stream from image %s: %w", image, err)
 	}
@@ -164,7 +257,7 @@ func (r *registry) getImageManifest(ctx context.Context, image string, tlsOption
 	return manifest, pullConfig, nil
 }
 
-func (r *registry) getManifestStreamFromImage(image, repo string, options []crane.Option) ([]byte, error) {
+func (r *registry) getManifestStreamFromImage(image, repo string, options []crane.Option, p *v1.Platform) ([]byte, error) {
 	manifest, err := crane.Manifest(image, options...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get crane manifest from image %s: %w", image, err)
@@ -183,8 +276,8 @@ func (r *registry) getManifestStreamFromImage(image, repo string, options []cran
 		return nil, fmt.Errorf("mediaType is missing from the image %s manifest", image)
 	}
 
-	if strings.Contains(imageMediaType, "manifest.list") {
-		archDigest, err := r.getImageDigestFromMultiImage(manifest)
+	if types.MediaType(imageMediaType).IsIndex() && p != nil {
+		archDigest, err := r.getImageDigestFromMultiImage(manifest, *p)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get arch digets from multi arch image: %w", err)
 		}
@@ -204,6 +297,10 @@ func (r *registry) getLayersDigestsFromManifestStream(manifestStream []byte) ([]
 		return nil, fmt.Errorf("failed to unmarshal manifest stream: %w", err)
 	}
 
+	if manifest.MediaType.IsIndex() {
+		return nil, errors.New("the image is a multi-architecture index; a platform is required to select one of its images")
+	}
+
 	digests := make([]string, len(manifest.Layers))
 	for i, layer := range manifest.Layers {
 		digests[i] = layer.Digest.Algorithm + ":" + layer.Digest.Hex
@@ -246,17 +343,17 @@ func (r *registry) getHeaderStreamFromLayer(layer v1.Layer, headerName string) (
 	return nil, fmt.Errorf("header %s not found in the layer", headerName)
 }
 
-func (r *registry) getImageDigestFromMultiImage(manifestListStream []byte) (string, error) {
-	arch := runtime.GOARCH
+func (r *registry) getImageDigestFromMultiImage(manifestListStream []byte, p v1.Platform) (string, error) {
 	manifestList := v1.IndexManifest{}
 
 	if err := json.Unmarshal(manifestListStream, &manifestList); err != nil {
 		return "", fmt.Errorf("failed to unmarshal manifest stream: %w", err)
 	}
-	for _, manifest := range manifestList.Manifests {
-		if manifest.Platform != nil && manifest.Platform.Architecture == arch {
-			return manifest.Digest.Algorithm + ":" + manifest.Digest.Hex, nil
-		}
+
+	manifest, err := platform.SelectManifest(&manifestList, p)
+	if err != nil {
+		return "", fmt.Errorf("failed to find manifest for platform %s: %w", p.String(), err)
 	}
-	return "", fmt.Errorf("Failed to find manifest for architecture %s", arch)
+
+	return manifest.Digest.Algorithm + ":" + manifest.Digest.Hex, nil
 }
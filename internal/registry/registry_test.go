@@ -4,16 +4,26 @@ import (
 	context "context"
 	"crypto/sha256"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 
 	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/auth"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mirror"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
@@ -42,7 +52,7 @@ var _ = Describe("ImageExists", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		ctx = context.TODO()
 		mockRegistryAuthGetter = auth.NewMockRegistryAuthGetter(ctrl)
-		reg = NewRegistry()
+		reg = NewRegistry(nil)
 	})
 
 	AfterEach(func() {
@@ -59,7 +69,7 @@ var _ = Describe("ImageExists", func() {
 
 		It("should fail if the image name isn't valid", func() {
 
-			_, err = reg.ImageExists(ctx, invalidImage, &kmmv1beta1.TLSOptions{}, nil)
+			_, err = reg.ImageExists(ctx, invalidImage, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("does not contain hash or tag"))
@@ -69,7 +79,7 @@ var _ = Describe("ImageExists", func() {
 
 			mockRegistryAuthGetter.EXPECT().GetKeyChain(ctx).Return(nil, errors.New("some error"))
 
-			_, err = reg.ImageExists(ctx, validImage, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter)
+			_, err = reg.ImageExists(ctx, validImage, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("cannot get keychain from the registry auth getter"))
@@ -94,7 +104,7 @@ var _ = Describe("ImageExists", func() {
 			u := mustParseURL(server.URL)
 
 			image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
-			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to get crane manifest from image"))
@@ -109,7 +119,7 @@ var _ = Describe("ImageExists", func() {
 			u := mustParseURL(server.URL)
 
 			image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
-			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to unmarshal crane manifest"))
@@ -135,7 +145,7 @@ var _ = Describe("ImageExists", func() {
 			u := mustParseURL(server.URL)
 
 			image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
-			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("mediaType is missing from the image"))
@@ -151,7 +161,7 @@ var _ = Describe("ImageExists", func() {
 		u := mustParseURL(server.URL)
 
 		image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
-		_, err := reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+		_, err := reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 		Expect(err).ToNot(HaveOccurred())
 	})
@@ -174,9 +184,9 @@ var _ = Describe("ImageExists", func() {
 		var err error
 		image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
 		if withRegistryAuthGetter {
-			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter)
+			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter, nil)
 		} else {
-			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, err = reg.ImageExists(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 		}
 		Expect(err).ToNot(HaveOccurred())
 	},
@@ -207,7 +217,7 @@ var _ = Describe("GetLayersDigests", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		ctx = context.TODO()
 		mockRegistryAuthGetter = auth.NewMockRegistryAuthGetter(ctrl)
-		reg = NewRegistry()
+		reg = NewRegistry(nil)
 	})
 
 	AfterEach(func() {
@@ -224,7 +234,7 @@ var _ = Describe("GetLayersDigests", func() {
 
 		It("should fail if the image name isn't valid", func() {
 
-			_, err = reg.ImageExists(ctx, invalidImage, &kmmv1beta1.TLSOptions{}, nil)
+			_, err = reg.ImageExists(ctx, invalidImage, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("does not contain hash or tag"))
@@ -234,7 +244,7 @@ var _ = Describe("GetLayersDigests", func() {
 
 			mockRegistryAuthGetter.EXPECT().GetKeyChain(ctx).Return(nil, errors.New("some error"))
 
-			_, err = reg.ImageExists(ctx, validImage, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter)
+			_, err = reg.ImageExists(ctx, validImage, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("cannot get keychain from the registry auth getter"))
@@ -259,7 +269,7 @@ var _ = Describe("GetLayersDigests", func() {
 			u := mustParseURL(server.URL)
 
 			image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
-			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to get crane manifest from image"))
@@ -274,7 +284,7 @@ var _ = Describe("GetLayersDigests", func() {
 			u := mustParseURL(server.URL)
 
 			image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
-			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to unmarshal crane manifest"))
@@ -300,7 +310,7 @@ var _ = Describe("GetLayersDigests", func() {
 			u := mustParseURL(server.URL)
 
 			image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
-			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("mediaType is missing from the image"))
@@ -325,9 +335,9 @@ var _ = Describe("GetLayersDigests", func() {
 		var err error
 		image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
 		if withRegistryAuthGetter {
-			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter)
+			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter, nil)
 		} else {
-			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, _, err = reg.GetLayersDigests(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 		}
 		Expect(err).ToNot(HaveOccurred())
 	},
@@ -337,7 +347,7 @@ var _ = Describe("GetLayersDigests", func() {
 })
 
 var _ = Describe("VerifyModuleExists", func() {
-	reg := NewRegistry()
+	reg := NewRegistry(nil)
 
 	It("file is not present", func() {
 		const fileName = "etc/fileName"
@@ -358,6 +368,26 @@ var _ = Describe("VerifyModuleExists", func() {
 	})
 })
 
+var _ = Describe("getPullOptions", func() {
+	r := &registry{}
+
+	DescribeTable("should find the repository of the image",
+		func(image, expectedRepo string) {
+			pullConfig, err := r.getPullOptions(context.Background(), source{image: image}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pullConfig.repo).To(Equal(expectedRepo))
+		},
+		Entry("tag", "example.org/org/image:tag", "example.org/org/image"),
+		Entry("digest", "example.org/org/image@sha256:0123", "example.org/org/image"),
+		Entry("registry with a port and a tag", "example.org:5000/org/image:tag", "example.org:5000/org/image"),
+	)
+
+	It("should not take the port of the registry for a tag", func() {
+		_, err := r.getPullOptions(context.Background(), source{image: "example.org:5000/org/image"}, nil)
+		Expect(err).To(MatchError(ContainSubstring("does not contain hash or tag")))
+	})
+})
+
 var _ = Describe("GetDigest", func() {
 	const (
 		validImageHost = "gcr.io"
@@ -379,7 +409,7 @@ var _ = Describe("GetDigest", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		ctx = context.TODO()
 		mockRegistryAuthGetter = auth.NewMockRegistryAuthGetter(ctrl)
-		reg = NewRegistry()
+		reg = NewRegistry(nil)
 	})
 
 	AfterEach(func() {
@@ -394,7 +424,7 @@ var _ = Describe("GetDigest", func() {
 		})
 
 		It("should fail if the image name isn't valid", func() {
-			_, err = reg.GetDigest(ctx, invalidImage, &kmmv1beta1.TLSOptions{}, nil)
+			_, err = reg.GetDigest(ctx, invalidImage, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("does not contain hash or tag"))
@@ -403,7 +433,7 @@ var _ = Describe("GetDigest", func() {
 		It("should fail if it cannot get key chain from secret", func() {
 			mockRegistryAuthGetter.EXPECT().GetKeyChain(ctx).Return(nil, errors.New("some error"))
 
-			_, err = reg.GetDigest(ctx, validImage, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter)
+			_, err = reg.GetDigest(ctx, validImage, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter, nil)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("cannot get keychain from the registry auth getter"))
@@ -425,7 +455,7 @@ var _ = Describe("GetDigest", func() {
 			u := mustParseURL(server.URL)
 
 			image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
-			_, err = reg.GetDigest(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, err = reg.GetDigest(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 		})
@@ -438,7 +468,7 @@ var _ = Describe("GetDigest", func() {
 			u := mustParseURL(server.URL)
 
 			image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
-			_, err = reg.GetDigest(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			_, err = reg.GetDigest(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 
 			Expect(err).To(HaveOccurred())
 		})
@@ -470,9 +500,9 @@ var _ = Describe("GetDigest", func() {
 		var digest string
 		image := fmt.Sprintf("%s/%s/%s:%s", u.Host, validImageOrg, validImageName, validImageTag)
 		if withRegistryAuthGetter {
-			digest, err = reg.GetDigest(ctx, image, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter)
+			digest, err = reg.GetDigest(ctx, image, &kmmv1beta1.TLSOptions{}, mockRegistryAuthGetter, nil)
 		} else {
-			digest, err = reg.GetDigest(ctx, image, &kmmv1beta1.TLSOptions{}, nil)
+			digest, err = reg.GetDigest(ctx, image, &kmmv1beta1.TLSOptions{}, nil, nil)
 		}
 		Expect(err).ToNot(HaveOccurred())
 		Expect(digest).To(Equal(manifestDigest))
@@ -482,8 +512,201 @@ var _ = Describe("GetDigest", func() {
 	)
 })
 
+var _ = Describe("multi-architecture images", func() {
+	var (
+		amd64Image v1.Image
+		arm64Image v1.Image
+		image      string
+		index      v1.ImageIndex
+		reg        Registry
+		server     *httptest.Server
+	)
+
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		var err error
+
+		amd64Image, err = random.Image(100, 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		arm64Image, err = random.Image(100, 2)
+		Expect(err).NotTo(HaveOccurred())
+
+		index = mutate.AppendManifests(
+			empty.Index,
+			mutate.IndexAddendum{
+				Add:        amd64Image,
+				Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}},
+			},
+			mutate.IndexAddendum{
+				Add:        arm64Image,
+				Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+			},
+		)
+
+		server = httptest.NewServer(ggcrregistry.New())
+		DeferCleanup(server.Close)
+
+		image = mustParseURL(server.URL).Host + "/org/image:tag"
+
+		ref, err := name.ParseReference(image)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remote.WriteIndex(ref, index)).To(Succeed())
+
+		reg = NewRegistry(nil)
+	})
+
+	It("should return the layers of the image matching the platform", func() {
+		digests, _, err := reg.GetLayersDigests(ctx, image, nil, nil, &v1.Platform{OS: "linux", Architecture: "arm64"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digests).To(HaveLen(2))
+	})
+
+	It("should check the index if no platform is set", func() {
+		Expect(reg.ImageExists(ctx, image, nil, nil, nil)).To(BeTrue())
+	})
+
+	It("should return an error for the layers of the index if no platform is set", func() {
+		_, _, err := reg.GetLayersDigests(ctx, image, nil, nil, nil)
+		Expect(err).To(MatchError(ContainSubstring("platform is required")))
+	})
+
+	It("should return an error if no image matches the platform", func() {
+		_, err := reg.ImageExists(ctx, image, nil, nil, &v1.Platform{OS: "linux", Architecture: "s390x"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the digest of the image matching the platform", func() {
+		expected, err := arm64Image.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			reg.GetDigest(ctx, image, nil, nil, &v1.Platform{OS: "linux", Architecture: "arm64"}),
+		).To(
+			Equal(expected.String()),
+		)
+	})
+
+	It("should return the digest of the index if no platform is set", func() {
+		expected, err := index.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(reg.GetDigest(ctx, image, nil, nil, nil)).To(Equal(expected.String()))
+	})
+})
+
 func mustParseURL(rawURL string) *url.URL {
 	u, err := url.Parse(rawURL)
 	Expect(err).ToNot(HaveOccurred())
 	return u
 }
+
+var _ = Describe("mirrors", func() {
+	const repo = "/org/image:tag"
+
+	var (
+		emptyServer *httptest.Server
+		image       v1.Image
+		server      *httptest.Server
+	)
+
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		var err error
+
+		image, err = random.Image(100, 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		server = httptest.NewServer(ggcrregistry.New())
+		DeferCleanup(server.Close)
+
+		emptyServer = httptest.NewServer(ggcrregistry.New())
+		DeferCleanup(emptyServer.Close)
+
+		ref, err := name.ParseReference(mustParseURL(server.URL).Host + repo)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remote.Write(ref, image)).To(Succeed())
+	})
+
+	It("should use the first mirror that has the image", func() {
+		reg := NewRegistry([]mirror.Registry{
+			{
+				// The original location does not exist.
+				Prefix: "registry.invalid",
+				Mirrors: []mirror.Mirror{
+					{Location: mustParseURL(emptyServer.URL).Host},
+					{Location: mustParseURL(server.URL).Host},
+				},
+			},
+		})
+
+		const img = "registry.invalid" + repo
+
+		Expect(reg.ImageExists(ctx, img, nil, nil, nil)).To(BeTrue())
+
+		expected, err := image.Digest()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reg.GetDigest(ctx, img, nil, nil, nil)).To(Equal(expected.String()))
+
+		digests, pullConfig, err := reg.GetLayersDigests(ctx, img, nil, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digests).To(HaveLen(1))
+
+		_, err = reg.GetLayerByDigest(digests[0], pullConfig)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should fall back to the original location", func() {
+		host := mustParseURL(server.URL).Host
+
+		reg := NewRegistry([]mirror.Registry{
+			{
+				Prefix:  host,
+				Mirrors: []mirror.Mirror{{Location: mustParseURL(emptyServer.URL).Host}},
+			},
+		})
+
+		Expect(reg.ImageExists(ctx, host+repo, nil, nil, nil)).To(BeTrue())
+	})
+
+	It("should rewrite the location of the image", func() {
+		reg := NewRegistry([]mirror.Registry{
+			{Prefix: "registry.invalid", Location: mustParseURL(emptyServer.URL).Host},
+		})
+
+		Expect(reg.ImageExists(ctx, "registry.invalid"+repo, nil, nil, nil)).To(BeFalse())
+	})
+
+	It("should trust the CA bundle of a mirror", func() {
+		tlsServer := httptest.NewTLSServer(ggcrregistry.New())
+		DeferCleanup(tlsServer.Close)
+
+		host := mustParseURL(tlsServer.URL).Host
+
+		ref, err := name.ParseReference(host + repo)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remote.Write(ref, image, remote.WithTransport(tlsServer.Client().Transport))).To(Succeed())
+
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+		Expect(
+			os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw}), 0644),
+		).To(
+			Succeed(),
+		)
+
+		m := mirror.Mirror{Location: host}
+
+		reg := NewRegistry([]mirror.Registry{{Prefix: "registry.invalid", Mirrors: []mirror.Mirror{m}}})
+
+		By("not trusting the mirror without its CA bundle")
+		Expect(reg.ImageExists(ctx, "registry.invalid"+repo, nil, nil, nil)).Error().To(HaveOccurred())
+
+		m.CAFile = caFile
+
+		reg = NewRegistry([]mirror.Registry{{Prefix: "registry.invalid", Mirrors: []mirror.Mirror{m}}})
+
+		Expect(reg.ImageExists(ctx, "registry.invalid"+repo, nil, nil, nil)).To(BeTrue())
+	})
+})
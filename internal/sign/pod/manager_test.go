@@ -61,7 +61,7 @@ var _ = Describe("ShouldSync", func() {
 		}
 
 		gomock.InOrder(
-			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any()).Return(true, nil),
+			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any(), nil).Return(true, nil),
 		)
 
 		shouldSync, err := mgr.ShouldSync(ctx, mld)
@@ -82,7 +82,7 @@ var _ = Describe("ShouldSync", func() {
 		}
 
 		gomock.InOrder(
-			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any()).Return(false, errors.New("generic-registry-error")),
+			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any(), nil).Return(false, errors.New("generic-registry-error")),
 		)
 
 		shouldSync, err := mgr.ShouldSync(ctx, mld)
@@ -104,7 +104,7 @@ var _ = Describe("ShouldSync", func() {
 		}
 
 		gomock.InOrder(
-			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any()).Return(false, nil),
+			reg.EXPECT().ImageExists(ctx, imageName, nil, gomock.Any(), nil).Return(false, nil),
 		)
 
 		shouldSync, err := mgr.ShouldSync(ctx, mld)
@@ -184,9 +184,9 @@ var _ = Describe("Sync", func() {
 			}
 
 			gomock.InOrder(
-				podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, "sign").Return(labels),
+				podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, mld.ContainerImage, "sign").Return(labels),
 				maker.EXPECT().MakePodTemplate(ctx, mld, labels, previousImageName, true, mld.Owner).Return(&j, nil),
-				podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, utils.PodTypeSign, mld.Owner).Return(&newPod, nil),
+				podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, mld.ContainerImage, utils.PodTypeSign, mld.Owner).Return(&newPod, nil),
 				podhelper.EXPECT().IsPodChanged(&j, &newPod).Return(false, nil),
 				podhelper.EXPECT().GetPodStatus(&newPod).Return(podStatus, poderr),
 			)
@@ -210,7 +210,7 @@ var _ = Describe("Sync", func() {
 		ctx := context.Background()
 
 		gomock.InOrder(
-			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, "sign").Return(labels),
+			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, mld.ContainerImage, "sign").Return(labels),
 			maker.EXPECT().MakePodTemplate(ctx, mld, labels, previousImageName, true, mld.Owner).
 				Return(nil, errors.New("random error")),
 		)
@@ -236,9 +236,9 @@ var _ = Describe("Sync", func() {
 		}
 
 		gomock.InOrder(
-			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, "sign").Return(labels),
+			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, mld.ContainerImage, "sign").Return(labels),
 			maker.EXPECT().MakePodTemplate(ctx, mld, labels, previousImageName, true, mld.Owner).Return(&j, nil),
-			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, utils.PodTypeSign, mld.Owner).Return(nil, errors.New("random error")),
+			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, mld.ContainerImage, utils.PodTypeSign, mld.Owner).Return(nil, errors.New("random error")),
 		)
 
 		Expect(
@@ -262,9 +262,9 @@ var _ = Describe("Sync", func() {
 		}
 
 		gomock.InOrder(
-			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, "sign").Return(labels),
+			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, mld.ContainerImage, "sign").Return(labels),
 			maker.EXPECT().MakePodTemplate(ctx, mld, labels, previousImageName, true, mld.Owner).Return(&j, nil),
-			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, utils.PodTypeSign, mld.Owner).Return(nil, utils.ErrNoMatchingPod),
+			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, mld.ContainerImage, utils.PodTypeSign, mld.Owner).Return(nil, utils.ErrNoMatchingPod),
 			podhelper.EXPECT().CreatePod(ctx, &j).Return(errors.New("unable to create pod")),
 		)
 
@@ -290,9 +290,9 @@ var _ = Describe("Sync", func() {
 		}
 
 		gomock.InOrder(
-			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, "sign").Return(labels),
+			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, mld.ContainerImage, "sign").Return(labels),
 			maker.EXPECT().MakePodTemplate(ctx, mld, labels, previousImageName, true, mld.Owner).Return(&j, nil),
-			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, utils.PodTypeSign, mld.Owner).Return(nil, utils.ErrNoMatchingPod),
+			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, mld.ContainerImage, utils.PodTypeSign, mld.Owner).Return(nil, utils.ErrNoMatchingPod),
 			podhelper.EXPECT().CreatePod(ctx, &j).Return(nil),
 		)
 
@@ -319,9 +319,9 @@ var _ = Describe("Sync", func() {
 		}
 
 		gomock.InOrder(
-			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, "sign").Return(labels),
+			podhelper.EXPECT().PodLabels(mld.Name, kernelVersion, mld.ContainerImage, "sign").Return(labels),
 			maker.EXPECT().MakePodTemplate(ctx, mld, labels, previousImageName, true, mld.Owner).Return(&newPod, nil),
-			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, utils.PodTypeSign, mld.Owner).Return(&newPod, nil),
+			podhelper.EXPECT().GetModulePodByKernel(ctx, mld.Name, mld.Namespace, kernelVersion, mld.ContainerImage, utils.PodTypeSign, mld.Owner).Return(&newPod, nil),
 			podhelper.EXPECT().IsPodChanged(&newPod, &newPod).Return(true, nil),
 			podhelper.EXPECT().DeletePod(ctx, &newPod).Return(nil),
 		)
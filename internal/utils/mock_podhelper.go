@@ -5,6 +5,7 @@
 //
 //	mockgen -source=podhelper.go -package=utils -destination=mock_podhelper.go
 //
+
 // Package utils is a generated GoMock package.
 package utils
 
@@ -69,18 +70,18 @@ func (mr *MockPodHelperMockRecorder) DeletePod(ctx, pod any) *gomock.Call {
 }
 
 // GetModulePodByKernel mocks base method.
-func (m *MockPodHelper) GetModulePodByKernel(ctx context.Context, modName, namespace, targetKernel, podType string, owner v10.Object) (*v1.Pod, error) {
+func (m *MockPodHelper) GetModulePodByKernel(ctx context.Context, modName, namespace, targetKernel, targetImage, podType string, owner v10.Object) (*v1.Pod, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModulePodByKernel", ctx, modName, namespace, targetKernel, podType, owner)
+	ret := m.ctrl.Call(m, "GetModulePodByKernel", ctx, modName, namespace, targetKernel, targetImage, podType, owner)
 	ret0, _ := ret[0].(*v1.Pod)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetModulePodByKernel indicates an expected call of GetModulePodByKernel.
-func (mr *MockPodHelperMockRecorder) GetModulePodByKernel(ctx, modName, namespace, targetKernel, podType, owner any) *gomock.Call {
+func (mr *MockPodHelperMockRecorder) GetModulePodByKernel(ctx, modName, namespace, targetKernel, targetImage, podType, owner any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModulePodByKernel", reflect.TypeOf((*MockPodHelper)(nil).GetModulePodByKernel), ctx, modName, namespace, targetKernel, podType, owner)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModulePodByKernel", reflect.TypeOf((*MockPodHelper)(nil).GetModulePodByKernel), ctx, modName, namespace, targetKernel, targetImage, podType, owner)
 }
 
 // GetModulePods mocks base method.
@@ -129,15 +130,15 @@ func (mr *MockPodHelperMockRecorder) IsPodChanged(existingPod, newPod any) *gomo
 }
 
 // PodLabels mocks base method.
-func (m *MockPodHelper) PodLabels(modName, targetKernel, podType string) map[string]string {
+func (m *MockPodHelper) PodLabels(modName, targetKernel, targetImage, podType string) map[string]string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PodLabels", modName, targetKernel, podType)
+	ret := m.ctrl.Call(m, "PodLabels", modName, targetKernel, targetImage, podType)
 	ret0, _ := ret[0].(map[string]string)
 	return ret0
 }
 
 // PodLabels indicates an expected call of PodLabels.
-func (mr *MockPodHelperMockRecorder) PodLabels(modName, targetKernel, podType any) *gomock.Call {
+func (mr *MockPodHelperMockRecorder) PodLabels(modName, targetKernel, targetImage, podType any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PodLabels", reflect.TypeOf((*MockPodHelper)(nil).PodLabels), modName, targetKernel, podType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PodLabels", reflect.TypeOf((*MockPodHelper)(nil).PodLabels), modName, targetKernel, targetImage, podType)
 }
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 
@@ -30,8 +32,9 @@ var ErrNoMatchingPod = errors.New("no matching pod")
 
 type PodHelper interface {
 	IsPodChanged(existingPod *v1.Pod, newPod *v1.Pod) (bool, error)
-	PodLabels(modName string, targetKernel string, podType string) map[string]string
-	GetModulePodByKernel(ctx context.Context, modName, namespace, targetKernel, podType string, owner metav1.Object) (*v1.Pod, error)
+	PodLabels(modName string, targetKernel string, targetImage string, podType string) map[string]string
+	// GetModulePodByKernel returns the Pod of the given type that builds or signs targetImage for targetKernel.
+	GetModulePodByKernel(ctx context.Context, modName, namespace, targetKernel, targetImage, podType string, owner metav1.Object) (*v1.Pod, error)
 	GetModulePods(ctx context.Context, modName, namespace, podType string, owner metav1.Object) ([]v1.Pod, error)
 	DeletePod(ctx context.Context, pod *v1.Pod) error
 	CreatePod(ctx context.Context, podSpec *v1.Pod) error
@@ -60,8 +63,8 @@ func (ph *podHelper) IsPodChanged(existingPod *v1.Pod, newPod *v1.Pod) (bool, er
 	return true, nil
 }
 
-func (ph *podHelper) PodLabels(modName string, targetKernel string, podType string) map[string]string {
-	labels := moduleKernelLabels(modName, targetKernel, podType)
+func (ph *podHelper) PodLabels(modName string, targetKernel string, targetImage string, podType string) map[string]string {
+	labels := moduleKernelLabels(modName, targetKernel, targetImage, podType)
 
 	labels["app.kubernetes.io/name"] = "kmm"
 	labels["app.kubernetes.io/component"] = podType
@@ -70,8 +73,8 @@ func (ph *podHelper) PodLabels(modName string, targetKernel string, podType stri
 	return labels
 }
 
-func (ph *podHelper) GetModulePodByKernel(ctx context.Context, modName, namespace, targetKernel, podType string, owner metav1.Object) (*v1.Pod, error) {
-	matchLabels := moduleKernelLabels(modName, targetKernel, podType)
+func (ph *podHelper) GetModulePodByKernel(ctx context.Context, modName, namespace, targetKernel, targetImage, podType string, owner metav1.Object) (*v1.Pod, error) {
+	matchLabels := moduleKernelLabels(modName, targetKernel, targetImage, podType)
 	pods, err := ph.getPods(ctx, namespace, matchLabels)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get module %s, pods by kernel %s: %v", modName, targetKernel, err)
@@ -150,12 +153,22 @@ func (ph *podHelper) getPods(ctx context.Context, namespace string, labels map[s
 	return podList.Items, nil
 }
 
-func moduleKernelLabels(moduleName, targetKernel, podType string) map[string]string {
+// moduleKernelLabels also identify the target image, so that several images can be built or signed for the same
+// kernel when kernel mappings select them on different nodes.
+func moduleKernelLabels(moduleName, targetKernel, targetImage, podType string) map[string]string {
 	labels := moduleLabels(moduleName, podType)
 	labels[constants.TargetKernelTarget] = targetKernel
+	labels[constants.TargetImageHashLabel] = imageHash(targetImage)
 	return labels
 }
 
+// imageHash returns a label value identifying image, which may be longer than a label value and contain forbidden
+// characters.
+func imageHash(image string) string {
+	sum := sha256.Sum256([]byte(image))
+	return hex.EncodeToString(sum[:16])
+}
+
 func moduleLabels(moduleName, podType string) map[string]string {
 	return map[string]string{
 		constants.ModuleNameLabel: moduleName,
@@ -34,15 +34,16 @@ var _ = Describe("PodLabels", func() {
 			ObjectMeta: metav1.ObjectMeta{Name: "moduleName"},
 		}
 		mgr := NewPodHelper(clnt)
-		labels := mgr.PodLabels(mod.Name, "targetKernel", "podType")
+		labels := mgr.PodLabels(mod.Name, "targetKernel", "targetImage", "podType")
 
 		expected := map[string]string{
-			"app.kubernetes.io/name":      "kmm",
-			"app.kubernetes.io/component": "podType",
-			"app.kubernetes.io/part-of":   "kmm",
-			constants.ModuleNameLabel:     "moduleName",
-			constants.TargetKernelTarget:  "targetKernel",
-			constants.PodType:             "podType",
+			"app.kubernetes.io/name":       "kmm",
+			"app.kubernetes.io/component":  "podType",
+			"app.kubernetes.io/part-of":    "kmm",
+			constants.ModuleNameLabel:      "moduleName",
+			constants.TargetKernelTarget:   "targetKernel",
+			constants.TargetImageHashLabel: imageHash("targetImage"),
+			constants.PodType:              "podType",
 		}
 
 		Expect(labels).To(Equal(expected))
@@ -76,9 +77,10 @@ var _ = Describe("GetModulePodByKernel", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		labels := map[string]string{
-			constants.ModuleNameLabel:    "moduleName",
-			constants.TargetKernelTarget: "targetKernel",
-			constants.PodType:            "podType",
+			constants.ModuleNameLabel:      "moduleName",
+			constants.TargetKernelTarget:   "targetKernel",
+			constants.TargetImageHashLabel: imageHash("targetImage"),
+			constants.PodType:              "podType",
 		}
 
 		opts := []sigclient.ListOption{
@@ -93,7 +95,7 @@ var _ = Describe("GetModulePodByKernel", func() {
 			},
 		)
 
-		pod, err := ph.GetModulePodByKernel(ctx, mod.Name, mod.Namespace, "targetKernel", "podType", &mod)
+		pod, err := ph.GetModulePodByKernel(ctx, mod.Name, mod.Namespace, "targetKernel", "targetImage", "podType", &mod)
 
 		Expect(pod).To(Equal(&j))
 		Expect(err).NotTo(HaveOccurred())
@@ -106,9 +108,10 @@ var _ = Describe("GetModulePodByKernel", func() {
 		}
 
 		labels := map[string]string{
-			constants.ModuleNameLabel:    "moduleName",
-			constants.TargetKernelTarget: "targetKernel",
-			constants.PodType:            "podType",
+			constants.ModuleNameLabel:      "moduleName",
+			constants.TargetKernelTarget:   "targetKernel",
+			constants.TargetImageHashLabel: imageHash("targetImage"),
+			constants.PodType:              "podType",
 		}
 
 		opts := []sigclient.ListOption{
@@ -119,7 +122,7 @@ var _ = Describe("GetModulePodByKernel", func() {
 
 		clnt.EXPECT().List(ctx, &podList, opts).Return(errors.New("random error"))
 
-		_, err := ph.GetModulePodByKernel(ctx, mod.Name, mod.Namespace, "targetKernel", "podType", &mod)
+		_, err := ph.GetModulePodByKernel(ctx, mod.Name, mod.Namespace, "targetKernel", "targetImage", "podType", &mod)
 
 		Expect(err).To(HaveOccurred())
 	})
@@ -144,9 +147,10 @@ var _ = Describe("GetModulePodByKernel", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		labels := map[string]string{
-			constants.ModuleNameLabel:    "moduleName",
-			constants.TargetKernelTarget: "targetKernel",
-			constants.PodType:            "podType",
+			constants.ModuleNameLabel:      "moduleName",
+			constants.TargetKernelTarget:   "targetKernel",
+			constants.TargetImageHashLabel: imageHash("targetImage"),
+			constants.PodType:              "podType",
 		}
 
 		opts := []sigclient.ListOption{
@@ -161,7 +165,7 @@ var _ = Describe("GetModulePodByKernel", func() {
 			},
 		)
 
-		_, err = ph.GetModulePodByKernel(ctx, mod.Name, mod.Namespace, "targetKernel", "podType", &mod)
+		_, err = ph.GetModulePodByKernel(ctx, mod.Name, mod.Namespace, "targetKernel", "targetImage", "podType", &mod)
 
 		Expect(err).To(HaveOccurred())
 	})
@@ -191,9 +195,10 @@ var _ = Describe("GetModulePodByKernel", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		labels := map[string]string{
-			constants.ModuleNameLabel:    "moduleName",
-			constants.TargetKernelTarget: "targetKernel",
-			constants.PodType:            "podType",
+			constants.ModuleNameLabel:      "moduleName",
+			constants.TargetKernelTarget:   "targetKernel",
+			constants.TargetImageHashLabel: imageHash("targetImage"),
+			constants.PodType:              "podType",
 		}
 
 		opts := []sigclient.ListOption{
@@ -208,7 +213,7 @@ var _ = Describe("GetModulePodByKernel", func() {
 			},
 		)
 
-		pod, err := ph.GetModulePodByKernel(ctx, mod.Name, mod.Namespace, "targetKernel", "podType", &mod)
+		pod, err := ph.GetModulePodByKernel(ctx, mod.Name, mod.Namespace, "targetKernel", "targetImage", "podType", &mod)
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(pod).To(Equal(&j1))
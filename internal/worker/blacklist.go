@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+//go:generate mockgen -source=blacklist.go -package=worker -destination=mock_blacklist.go
+
+type BlacklistManager interface {
+	// Path returns the path of the Module's blacklist file.
+	Path() string
+	// Remove deletes the Module's blacklist file, if it exists.
+	Remove() error
+	// Write replaces the Module's blacklist file with one blacklisting modules.
+	// The file is removed if modules is empty.
+	Write(modules []string) error
+}
+
+type blacklistManager struct {
+	dir       string
+	logger    logr.Logger
+	name      string
+	namespace string
+}
+
+// NewBlacklistManager returns a BlacklistManager that manages the blacklist file of the Module namespace/name in dir,
+// a modprobe configuration directory.
+func NewBlacklistManager(dir, namespace, name string, logger logr.Logger) BlacklistManager {
+	return &blacklistManager{
+		dir:       dir,
+		logger:    logger,
+		name:      name,
+		namespace: namespace,
+	}
+}
+
+func (bm *blacklistManager) Path() string {
+	return filepath.Join(bm.dir, fmt.Sprintf("kmm-%s-%s.conf", bm.namespace, bm.name))
+}
+
+func (bm *blacklistManager) Remove() error {
+	if bm.namespace == "" || bm.name == "" {
+		return nil
+	}
+
+	path := bm.Path()
+
+	bm.logger.Info("Removing the blacklist", "path", path)
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not remove %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func (bm *blacklistManager) Write(modules []string) error {
+	if len(modules) == 0 {
+		return bm.Remove()
+	}
+
+	if bm.namespace == "" || bm.name == "" {
+		return errors.New("the Module owning the blacklist is unknown")
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Managed by KMM for Module %s/%s; do not edit.\n", bm.namespace, bm.name)
+
+	for _, m := range modules {
+		fmt.Fprintf(&sb, "blacklist %s\n", m)
+	}
+
+	path := bm.Path()
+
+	bm.logger.Info("Writing the blacklist", "path", path, "modules", modules)
+
+	// Write to a temporary file first so that modprobe never reads a partial file.
+	tmp, err := os.CreateTemp(bm.dir, ".kmm-blacklist-")
+	if err != nil {
+		return fmt.Errorf("could not create a temporary file in %s: %v", bm.dir, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.WriteString(sb.String()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write %s: %v", tmp.Name(), err)
+	}
+
+	if err = tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not set the mode of %s: %v", tmp.Name(), err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("could not close %s: %v", tmp.Name(), err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not rename %s to %s: %v", tmp.Name(), path, err)
+	}
+
+	return nil
+}
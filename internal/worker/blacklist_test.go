@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("blacklistManager", func() {
+	var (
+		bm  BlacklistManager
+		dir string
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		bm = NewBlacklistManager(dir, "some-namespace", "some-name", GinkgoLogr)
+	})
+
+	It("should name the file after the Module", func() {
+		Expect(bm.Path()).To(Equal(filepath.Join(dir, "kmm-some-namespace-some-name.conf")))
+	})
+
+	It("should write and replace the blacklist", func() {
+		Expect(bm.Write([]string{"a", "b"})).To(Succeed())
+		Expect(bm.Write([]string{"c"})).To(Succeed())
+
+		Expect(
+			os.ReadFile(bm.Path()),
+		).To(
+			Equal([]byte("# Managed by KMM for Module some-namespace/some-name; do not edit.\nblacklist c\n")),
+		)
+
+		// No temporary file is left behind.
+		Expect(os.ReadDir(dir)).To(HaveLen(1))
+	})
+
+	It("should remove the blacklist if there is no module to blacklist", func() {
+		Expect(bm.Write([]string{"a"})).To(Succeed())
+		Expect(bm.Write(nil)).To(Succeed())
+		Expect(bm.Path()).NotTo(BeAnExistingFile())
+	})
+
+	It("should not fail when removing a missing blacklist", func() {
+		Expect(bm.Remove()).To(Succeed())
+	})
+
+	It("should not write a blacklist for an unknown Module", func() {
+		bm = NewBlacklistManager(dir, "", "", GinkgoLogr)
+
+		Expect(bm.Write([]string{"a"})).NotTo(Succeed())
+		Expect(bm.Write(nil)).To(Succeed())
+		Expect(os.ReadDir(dir)).To(BeEmpty())
+	})
+})
@@ -11,9 +11,13 @@ import (
 	"github.com/go-logr/logr"
 )
 
+// maxStderrTailLines is the number of stderr lines kept by CommandLogger.
+const maxStderrTailLines = 5
+
 type CommandLogger struct {
 	logger         logr.Logger
 	stdErr, stdOut io.Reader
+	stdErrTail     []string
 	wg             *sync.WaitGroup
 }
 
@@ -67,10 +71,26 @@ func (cl *CommandLogger) write(r io.Reader, name string, errs chan<- error) {
 	s := bufio.NewScanner(r)
 
 	for s.Scan() {
-		logger.Info(s.Text())
+		line := s.Text()
+
+		logger.Info(line)
+
+		if name == "stderr" {
+			if len(cl.stdErrTail) == maxStderrTailLines {
+				cl.stdErrTail = cl.stdErrTail[1:]
+			}
+
+			cl.stdErrTail = append(cl.stdErrTail, line)
+		}
 	}
 
 	if err := s.Err(); err != nil {
 		errs <- err
 	}
 }
+
+// StderrTail returns the last lines written by the command to stderr.
+// It must only be called after Wait has returned.
+func (cl *CommandLogger) StderrTail() []string {
+	return cl.stdErrTail
+}
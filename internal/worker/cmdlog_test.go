@@ -90,6 +90,7 @@ var _ = Describe("CommandLogger_Wait", func() {
 		expected := map[string]string{"stderr": stderrMsg, "stdout": stdoutMsg}
 
 		Expect(msgs).To(Equal(expected))
+		Expect(cl.StderrTail()).To(Equal([]string{stderrMsg}))
 	})
 })
 
@@ -1,11 +1,48 @@
 package worker
 
 const (
+	FlagAction            = "action"
 	FlagFirmwareClassPath = "set-firmware-class-path"
 	FlagFirmwareMountPath = "set-firmware-mount-path"
+	FlagGarbageCollect    = "gc"
+	FlagInUsePollInterval = "in-use-poll-interval"
+	FlagInUseTimeout      = "in-use-timeout"
+	FlagKeepImage         = "keep-image"
+	FlagListHolders       = "list-holder-processes"
+	FlagLoadTimeout       = "load-timeout"
+	FlagMaxSize           = "max-size"
+	FlagModprobeRunner    = "modprobe-runner"
+	FlagOutput            = "output"
+	FlagParameter         = "parameter"
+	FlagPullTimeout       = "pull-timeout"
+	FlagRegistryMirrors   = "registry-mirrors"
+	FlagUnloadTimeout     = "unload-timeout"
 
+	// EnvModuleName and EnvModuleNamespace identify the Module a worker Pod was created for.
+	EnvModuleName      = "KMM_MODULE_NAME"
+	EnvModuleNamespace = "KMM_MODULE_NAMESPACE"
+
+	OutputJSON = "json"
+	OutputText = "text"
+
+	ModprobeRunnerBinary = "binary"
+	ModprobeRunnerNative = "native"
+
+	BlacklistDir              = "/var/run/kmm/modprobe.d"
+	BlacklistHostDir          = "/etc/modprobe.d"
 	FirmwareClassPathLocation = "/sys/module/firmware_class/parameters/path"
 	ImagesDir                 = "/var/run/kmm/images"
+	ImagesHostDir             = "/var/lib/kmm/images"
+	KernelLogPath             = "/dev/kmsg"
+	KernelReleaseLocation     = "/proc/sys/kernel/osrelease"
+	ModprobeConfDir           = "/etc/modprobe.d"
+	ProcDir                   = "/proc"
 	PullSecretsDir            = "/var/run/kmm/pull-secrets"
+	SysModuleDir              = "/sys/module"
+	TerminationMessagePath    = "/dev/termination-log"
+	VerificationKeysDir       = "/var/run/kmm/verification-keys"
 	FirmwareMountPath         = "/var/lib/firmware"
+
+	// KernelLogLines is the number of kernel log lines included in the termination message.
+	KernelLogLines = 10
 )
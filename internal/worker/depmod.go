@@ -0,0 +1,318 @@
+package worker
+
+import (
+	"bytes"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	modulesDepBinFileName = "modules.dep.bin"
+
+	// ksymtabPrefix is the prefix of the symbols that the kernel's EXPORT_SYMBOL macros create in modules.
+	ksymtabPrefix = "__ksymtab_"
+)
+
+//go:generate mockgen -source=depmod.go -package=worker -destination=mock_depmod.go
+
+type Depmod interface {
+	// EnsureModulesDep generates modules.dep and modules.dep.bin in modDir if they are missing or do not describe the
+	// module files found in modDir.
+	EnsureModulesDep(modDir string) error
+}
+
+type depmod struct {
+	logger logr.Logger
+}
+
+// NewDepmod returns a Depmod that computes dependencies from the symbol tables of the module files, like depmod(8).
+func NewDepmod(logger logr.Logger) Depmod {
+	return &depmod{logger: logger.WithName("depmod")}
+}
+
+func (d *depmod) EnsureModulesDep(modDir string) error {
+	paths, err := listModuleFiles(modDir)
+	if err != nil {
+		return err
+	}
+
+	reason, err := modulesDepStaleReason(modDir, paths)
+	if err != nil {
+		return err
+	}
+
+	if reason == "" {
+		d.logger.V(1).Info("modules.dep is up to date", "directory", modDir)
+		return nil
+	}
+
+	d.logger.Info("Generating modules.dep", "directory", modDir, "reason", reason)
+
+	deps, err := d.computeDependencies(modDir, paths)
+	if err != nil {
+		return err
+	}
+
+	text := &bytes.Buffer{}
+	lines := make(map[string]string, len(paths))
+
+	for _, name := range sortedKeys(paths) {
+		depPaths := make([]string, 0, len(deps[name]))
+
+		for _, dep := range deps[name] {
+			depPaths = append(depPaths, paths[dep])
+		}
+
+		line := paths[name] + ":"
+
+		if len(depPaths) > 0 {
+			line += " " + strings.Join(depPaths, " ")
+		}
+
+		lines[name] = line
+		fmt.Fprintln(text, line)
+	}
+
+	if err = atomicWrite(filepath.Join(modDir, modulesDepFileName), text.Bytes(), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %v", modulesDepFileName, err)
+	}
+
+	if err = atomicWrite(filepath.Join(modDir, modulesDepBinFileName), encodeKmodIndex(lines), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %v", modulesDepBinFileName, err)
+	}
+
+	return nil
+}
+
+// computeDependencies returns, for each module, all modules it depends on, directly or not, in the modules.dep
+// order: each module only depends on the ones that follow it.
+func (d *depmod) computeDependencies(modDir string, paths map[string]string) (map[string][]string, error) {
+	var (
+		exporters = make(map[string]string)
+		needed    = make(map[string][]string, len(paths))
+	)
+
+	for _, name := range sortedKeys(paths) {
+		exports, undefined, err := readModuleSymbols(filepath.Join(modDir, paths[name]))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sym := range exports {
+			if other, ok := exporters[sym]; ok {
+				d.logger.Info(utils.WarnString("Symbol exported by several modules; using the first one"), "symbol", sym, "modules", []string{other, name})
+				continue
+			}
+
+			exporters[sym] = name
+		}
+
+		needed[name] = undefined
+	}
+
+	direct := make(map[string][]string, len(paths))
+
+	for _, name := range sortedKeys(paths) {
+		bySymbol := make(map[string][]string)
+
+		for _, sym := range needed[name] {
+			// Symbols that no module exports are expected to be provided by the kernel.
+			if exporter, ok := exporters[sym]; ok && exporter != name {
+				bySymbol[exporter] = append(bySymbol[exporter], sym)
+			}
+		}
+
+		direct[name] = sortedKeys(bySymbol)
+
+		for _, dep := range direct[name] {
+			d.logger.V(1).Info("Dependency found", "module", name, "dependency", dep, "symbols", bySymbol[dep])
+		}
+	}
+
+	all := make(map[string][]string, len(paths))
+
+	for _, name := range sortedKeys(paths) {
+		order := make([]string, 0)
+		visited := sets.New[string]()
+		visiting := sets.New[string](name)
+
+		var visit func(mod string) error
+
+		// Dependencies are appended after their own dependencies, so the result is reversed below.
+		visit = func(mod string) error {
+			for _, dep := range direct[mod] {
+				if visiting.Has(dep) {
+					return fmt.Errorf("dependency cycle detected between modules %s and %s", mod, dep)
+				}
+
+				if visited.Has(dep) {
+					continue
+				}
+
+				visiting.Insert(dep)
+
+				if err := visit(dep); err != nil {
+					return err
+				}
+
+				visiting.Delete(dep)
+				visited.Insert(dep)
+				order = append(order, dep)
+			}
+
+			return nil
+		}
+
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+
+		all[name] = order
+
+		d.logger.Info("Module dependencies", "module", name, "path", paths[name], "dependencies", order)
+	}
+
+	return all, nil
+}
+
+// listModuleFiles returns the path relative to modDir of all module files found in modDir, by module name.
+func listModuleFiles(modDir string) (map[string]string, error) {
+	paths := make(map[string]string)
+
+	err := filepath.WalkDir(modDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		name, ok := moduleNameFromPath(path)
+		if !ok {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(modDir, path)
+		if err != nil {
+			return err
+		}
+
+		// WalkDir is lexical; like depmod without a search order, keep the first file found for each module.
+		if _, ok = paths[name]; !ok {
+			paths[name] = relPath
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("could not list the module files in %s: %v", modDir, err)
+	}
+
+	return paths, nil
+}
+
+// modulesDepStaleReason returns why modules.dep in modDir must be generated, or an empty string if it is up to date.
+func modulesDepStaleReason(modDir string, paths map[string]string) (string, error) {
+	depPath := filepath.Join(modDir, modulesDepFileName)
+
+	depInfo, err := os.Stat(depPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return modulesDepFileName + " does not exist", nil
+	} else if err != nil {
+		return "", fmt.Errorf("could not stat %s: %v", depPath, err)
+	}
+
+	// libkmod, used by the modprobe binary, only reads the binary index.
+	if _, err = os.Stat(filepath.Join(modDir, modulesDepBinFileName)); errors.Is(err, fs.ErrNotExist) {
+		return modulesDepBinFileName + " does not exist", nil
+	} else if err != nil {
+		return "", fmt.Errorf("could not stat %s: %v", modulesDepBinFileName, err)
+	}
+
+	md, err := ReadModuleDeps(modDir)
+	if err != nil {
+		return "", err
+	}
+
+	if len(md.paths) != len(paths) {
+		return fmt.Sprintf("%s lists %d modules but %d were found", modulesDepFileName, len(md.paths), len(paths)), nil
+	}
+
+	for name, p := range paths {
+		if listed, ok := md.paths[name]; !ok || filepath.Clean(listed) != p {
+			return fmt.Sprintf("%s is not listed in %s", p, modulesDepFileName), nil
+		}
+
+		fi, err := os.Stat(filepath.Join(modDir, p))
+		if err != nil {
+			return "", fmt.Errorf("could not stat %s: %v", p, err)
+		}
+
+		if fi.ModTime().After(depInfo.ModTime()) {
+			return fmt.Sprintf("%s is newer than %s", p, modulesDepFileName), nil
+		}
+	}
+
+	return "", nil
+}
+
+// readModuleSymbols returns the symbols that the module at path exports, and those it needs.
+func readModuleSymbols(path string) ([]string, []string, error) {
+	b, err := ReadModuleFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := elf.NewFile(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open %s as an ELF file: %v", path, err)
+	}
+
+	syms, err := f.Symbols()
+	if err != nil {
+		if errors.Is(err, elf.ErrNoSymbols) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, fmt.Errorf("could not read the symbols of %s: %v", path, err)
+	}
+
+	var exports, undefined []string
+
+	for _, s := range syms {
+		switch {
+		case s.Name == "":
+			continue
+		case s.Section == elf.SHN_UNDEF:
+			undefined = append(undefined, s.Name)
+		case strings.HasPrefix(s.Name, ksymtabPrefix):
+			exports = append(exports, strings.TrimPrefix(s.Name, ksymtabPrefix))
+		}
+	}
+
+	return exports, undefined, nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
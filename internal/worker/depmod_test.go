@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("depmod_EnsureModulesDep", func() {
+	const expectedModulesDep = `extra/alone.ko:
+kernel/base.ko:
+extra/mid.ko.xz: kernel/base.ko
+extra/top.ko: extra/mid.ko.xz kernel/base.ko
+`
+
+	var (
+		d      Depmod
+		modDir string
+	)
+
+	copyTree := func(src string) string {
+		GinkgoHelper()
+
+		dst := GinkgoT().TempDir()
+
+		err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			relPath, err := filepath.Rel(src, path)
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				return os.MkdirAll(filepath.Join(dst, relPath), 0755)
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			return os.WriteFile(filepath.Join(dst, relPath), b, 0644)
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		return dst
+	}
+
+	readModulesDep := func() string {
+		GinkgoHelper()
+
+		b, err := os.ReadFile(filepath.Join(modDir, modulesDepFileName))
+		Expect(err).NotTo(HaveOccurred())
+
+		return string(b)
+	}
+
+	BeforeEach(func() {
+		d = NewDepmod(GinkgoLogr)
+		modDir = copyTree("testdata/depmod/lib/modules/" + testdataKernelVersion)
+	})
+
+	It("should generate modules.dep and modules.dep.bin if they do not exist", func() {
+		Expect(d.EnsureModulesDep(modDir)).To(Succeed())
+		Expect(readModulesDep()).To(Equal(expectedModulesDep))
+
+		b, err := os.ReadFile(filepath.Join(modDir, modulesDepBinFileName))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lookupKmodIndex(b, "top")).To(Equal("extra/top.ko: extra/mid.ko.xz kernel/base.ko"))
+		Expect(lookupKmodIndex(b, "alone")).To(Equal("extra/alone.ko:"))
+
+		md, err := ReadModuleDeps(modDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md.Dependencies("top")).To(Equal([]string{"mid", "base"}))
+	})
+
+	It("should not regenerate an up-to-date modules.dep", func() {
+		const content = "kernel/base.ko:\nextra/mid.ko.xz:\nextra/top.ko:\nextra/alone.ko:\n"
+
+		Expect(os.WriteFile(filepath.Join(modDir, modulesDepFileName), []byte(content), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(modDir, modulesDepBinFileName), nil, 0644)).To(Succeed())
+
+		Expect(d.EnsureModulesDep(modDir)).To(Succeed())
+		Expect(readModulesDep()).To(Equal(content))
+	})
+
+	It("should regenerate modules.dep if a module is not listed", func() {
+		Expect(os.WriteFile(filepath.Join(modDir, modulesDepFileName), []byte("extra/alone.ko:\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(modDir, modulesDepBinFileName), nil, 0644)).To(Succeed())
+
+		Expect(d.EnsureModulesDep(modDir)).To(Succeed())
+		Expect(readModulesDep()).To(Equal(expectedModulesDep))
+	})
+
+	It("should regenerate modules.dep if a module is newer", func() {
+		Expect(d.EnsureModulesDep(modDir)).To(Succeed())
+
+		depPath := filepath.Join(modDir, modulesDepFileName)
+		Expect(os.WriteFile(depPath, []byte("kernel/base.ko:\nextra/mid.ko.xz:\nextra/top.ko:\nextra/alone.ko:\n"), 0644)).To(Succeed())
+
+		future := time.Now().Add(time.Hour)
+		Expect(os.Chtimes(filepath.Join(modDir, "extra", "top.ko"), future, future)).To(Succeed())
+
+		Expect(d.EnsureModulesDep(modDir)).To(Succeed())
+		Expect(readModulesDep()).To(Equal(expectedModulesDep))
+	})
+
+	It("should return an error if modules depend on each other", func() {
+		modDir = copyTree("testdata/depmod-cycle/lib/modules/" + testdataKernelVersion)
+
+		Expect(
+			d.EnsureModulesDep(modDir),
+		).To(
+			MatchError(ContainSubstring("dependency cycle")),
+		)
+
+		Expect(filepath.Join(modDir, modulesDepFileName)).NotTo(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,166 @@
+package worker
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// Reason classifies a worker failure, so that it can be surfaced by the controller.
+type Reason string
+
+const (
+	ReasonFirmware             Reason = "FirmwareError"
+	ReasonImageAuth            Reason = "ImageAuthFailed"
+	ReasonImagePull            Reason = "ImagePullFailed"
+	ReasonImageVerification    Reason = "ImageVerificationFailed"
+	ReasonKernelMismatch       Reason = "KernelMismatch"
+	ReasonModuleInUse          Reason = "ModuleInUse"
+	ReasonModuleNotLoaded      Reason = "ModuleNotLoaded"
+	ReasonParameterNotWritable Reason = "ParameterNotWritable"
+	ReasonPostLoadHookFailed   Reason = "PostLoadHookFailed"
+	ReasonPostUnloadHookFailed Reason = "PostUnloadHookFailed"
+	ReasonPreLoadHookFailed    Reason = "PreLoadHookFailed"
+	ReasonPreUnloadHookFailed  Reason = "PreUnloadHookFailed"
+	ReasonTimeout              Reason = "Timeout"
+	ReasonUnknownSymbol        Reason = "UnknownSymbol"
+)
+
+// Phase is the step of the worker's action that failed.
+type Phase string
+
+const (
+	PhaseBlacklistInTreeModules Phase = "BlacklistInTreeModules"
+	PhaseCheckModuleUsage       Phase = "CheckModuleUsage"
+	PhaseDepmod                 Phase = "Depmod"
+	PhaseInstallFirmware        Phase = "InstallFirmware"
+	PhaseModprobe               Phase = "Modprobe"
+	PhasePostLoadHook           Phase = "PostLoadHook"
+	PhasePostUnloadHook         Phase = "PostUnloadHook"
+	PhasePreLoadHook            Phase = "PreLoadHook"
+	PhasePreUnloadHook          Phase = "PreUnloadHook"
+	PhasePullImage              Phase = "PullImage"
+	PhaseRemoveBlacklist        Phase = "RemoveBlacklist"
+	PhaseRemoveInTreeModule     Phase = "RemoveInTreeModule"
+	PhaseSetParameters          Phase = "SetParameters"
+	PhaseVerifyCompatible       Phase = "VerifyCompatible"
+	PhaseVerifyLoaded           Phase = "VerifyLoaded"
+)
+
+// Error is an error carrying a Reason.
+type Error struct {
+	Reason Reason
+	Err    error
+}
+
+func NewError(reason Reason, err error) *Error {
+	return &Error{Reason: reason, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ReasonFromError returns the Reason of the first Error found in err's chain, or an empty string.
+func ReasonFromError(err error) Reason {
+	var wErr *Error
+
+	if errors.As(err, &wErr) {
+		return wErr.Reason
+	}
+
+	return ""
+}
+
+type phaseError struct {
+	phase Phase
+	err   error
+}
+
+// WithPhase records in err the phase during which it occurred.
+func WithPhase(phase Phase, err error) error {
+	return &phaseError{phase: phase, err: err}
+}
+
+func (pe *phaseError) Error() string {
+	return pe.err.Error()
+}
+
+func (pe *phaseError) Unwrap() error {
+	return pe.err
+}
+
+// PhaseFromError returns the Phase of the first error created by WithPhase found in err's chain, or an empty string.
+func PhaseFromError(err error) Phase {
+	var pe *phaseError
+
+	if errors.As(err, &pe) {
+		return pe.phase
+	}
+
+	return ""
+}
+
+// reasonPatterns maps reasons to substrings found in the output of modprobe, in errors returned by system calls or in
+// the kernel log.
+// They are matched case-insensitively and in order.
+var reasonPatterns = []struct {
+	reason     Reason
+	substrings []string
+}{
+	{
+		reason:     ReasonModuleInUse,
+		substrings: []string{"device or resource busy", "is in use"},
+	},
+	{
+		reason:     ReasonUnknownSymbol,
+		substrings: []string{"unknown symbol", "disagrees about version of symbol"},
+	},
+	{
+		reason:     ReasonKernelMismatch,
+		substrings: []string{"invalid module format", "exec format error", "version magic"},
+	},
+	{
+		reason:     ReasonFirmware,
+		substrings: []string{"direct firmware load", "failed to load firmware"},
+	},
+}
+
+// classifyMessages returns the Reason matching the first message that contains a known pattern, or an empty string.
+func classifyMessages(messages ...string) Reason {
+	for _, m := range messages {
+		m = strings.ToLower(m)
+
+		for _, rp := range reasonPatterns {
+			for _, s := range rp.substrings {
+				if strings.Contains(m, s) {
+					return rp.reason
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// classifyPullError returns the Reason carried by err if any, ReasonImageAuth if err was caused by the registry
+// rejecting our credentials, and ReasonImagePull otherwise.
+func classifyPullError(err error) Reason {
+	if r := ReasonFromError(err); r != "" {
+		return r
+	}
+
+	var tErr *transport.Error
+
+	if errors.As(err, &tErr) && (tErr.StatusCode == http.StatusUnauthorized || tErr.StatusCode == http.StatusForbidden) {
+		return ReasonImageAuth
+	}
+
+	return ReasonImagePull
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReasonFromError", func() {
+	It("should return an empty reason for a plain error", func() {
+		Expect(
+			ReasonFromError(errors.New("random error")),
+		).To(
+			BeEmpty(),
+		)
+	})
+
+	It("should return the reason of a wrapped Error", func() {
+		err := fmt.Errorf("wrapped: %w", NewError(ReasonKernelMismatch, errors.New("random error")))
+
+		Expect(err.Error()).To(Equal("wrapped: random error"))
+		Expect(ReasonFromError(err)).To(Equal(ReasonKernelMismatch))
+	})
+})
+
+var _ = Describe("PhaseFromError", func() {
+	It("should return an empty phase for a plain error", func() {
+		Expect(
+			PhaseFromError(errors.New("random error")),
+		).To(
+			BeEmpty(),
+		)
+	})
+
+	It("should return the phase of a wrapped error", func() {
+		err := fmt.Errorf("wrapped: %w", WithPhase(PhaseModprobe, errors.New("random error")))
+
+		Expect(err.Error()).To(Equal("wrapped: random error"))
+		Expect(PhaseFromError(err)).To(Equal(PhaseModprobe))
+	})
+})
+
+var _ = Describe("classifyMessages", func() {
+	DescribeTable(
+		"should classify known messages",
+		func(messages []string, expected Reason) {
+			Expect(
+				classifyMessages(messages...),
+			).To(
+				Equal(expected),
+			)
+		},
+		Entry(nil, []string{"modprobe: FATAL: Module kmm_ci_a is in use."}, ReasonModuleInUse),
+		Entry(nil, []string{"could not unload kmm_ci_a: device or resource busy"}, ReasonModuleInUse),
+		Entry(
+			nil,
+			[]string{"modprobe: ERROR: could not insert 'kmm_ci_a': Unknown symbol in module, or unknown parameter (see dmesg)"},
+			ReasonUnknownSymbol,
+		),
+		Entry(nil, []string{"no match", "kmm_ci_a: disagrees about version of symbol module_layout"}, ReasonUnknownSymbol),
+		Entry(nil, []string{"modprobe: ERROR: could not insert 'kmm_ci_a': Exec format error"}, ReasonKernelMismatch),
+		Entry(nil, []string{"[    1.000000] kmm_ci_a: version magic '5.0.0 SMP' should be '6.0.0 SMP'"}, ReasonKernelMismatch),
+		Entry(nil, []string{"[    1.000000] kmm_ci_a 0000:00:01.0: Direct firmware load for fw.bin failed with error -2"}, ReasonFirmware),
+		Entry(nil, []string{"random error"}, Reason("")),
+		Entry(nil, nil, Reason("")),
+	)
+})
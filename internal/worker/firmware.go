@@ -0,0 +1,551 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/go-logr/logr"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// FirmwareManifestsDir is the directory, relative to the host firmware directory, holding the firmware manifests.
+const FirmwareManifestsDir = ".kmm-manifests"
+
+// FirmwareFile is a file installed into the host firmware directory.
+type FirmwareFile struct {
+	// Path is relative to the host firmware directory.
+	Path string `json:"path"`
+	// SHA256 is the hex-encoded digest of regular files.
+	SHA256 string `json:"sha256,omitempty"`
+	// Link is the target of symbolic links.
+	Link string `json:"link,omitempty"`
+}
+
+// FirmwareManifest lists the firmware files installed by a module.
+type FirmwareManifest struct {
+	// Owner is the module that installed the files, as namespace/name.
+	Owner string         `json:"owner"`
+	Files []FirmwareFile `json:"files"`
+}
+
+//go:generate mockgen -source=firmware.go -package=worker -destination=mock_firmware.go
+
+type FirmwareManager interface {
+	// Install copies the firmware files from srcDir into dstDir and records them in the owner's manifest.
+	Install(srcDir, dstDir string) error
+	// Remove deletes the files listed in the owner's manifest that no other manifest references.
+	// srcDir is used to find the files to remove if the owner has no manifest.
+	Remove(srcDir, dstDir string) error
+	// Verify returns an Error with ReasonFirmware if a file listed in the owner's manifest is missing or was modified.
+	Verify(dstDir string) error
+}
+
+type firmwareManager struct {
+	logger logr.Logger
+	owner  string
+}
+
+// NewFirmwareManager returns a FirmwareManager that manages the firmware files of owner, a module in the
+// namespace/name format.
+func NewFirmwareManager(owner string, logger logr.Logger) FirmwareManager {
+	return &firmwareManager{
+		logger: logger,
+		owner:  owner,
+	}
+}
+
+func (fm *firmwareManager) Install(srcDir, dstDir string) error {
+	if fm.owner == "" {
+		return NewError(ReasonFirmware, errors.New("the firmware owner is unknown"))
+	}
+
+	lock, err := fm.lock(dstDir)
+	if err != nil {
+		return NewError(ReasonFirmware, err)
+	}
+	defer lock.Close()
+
+	manifest := FirmwareManifest{Owner: fm.owner}
+
+	others, err := fm.otherManifests(dstDir)
+	if err != nil {
+		return err
+	}
+
+	err = filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return fmt.Errorf("could not get the path of %s relative to %s: %v", path, srcDir, err)
+		}
+
+		dstPath := filepath.Join(dstDir, relPath)
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(dstPath, 0755)
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("could not read symbolic link %s: %v", path, err)
+			}
+
+			if err = installSymlink(target, dstPath); err != nil {
+				return err
+			}
+
+			manifest.Files = append(manifest.Files, FirmwareFile{Path: relPath, Link: target})
+		case d.Type().IsRegular():
+			sum, err := fm.installFile(path, dstPath, relPath, others)
+			if err != nil {
+				return err
+			}
+
+			manifest.Files = append(manifest.Files, FirmwareFile{Path: relPath, SHA256: sum})
+		default:
+			fm.logger.Info(utils.WarnString("Ignoring firmware file with unsupported type"), "path", path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return NewError(ReasonFirmware, fmt.Errorf("could not install firmware from %s to %s: %v", srcDir, dstDir, err))
+	}
+
+	if err = fm.removeStaleFiles(dstDir, &manifest, others); err != nil {
+		return NewError(ReasonFirmware, err)
+	}
+
+	if err = fm.writeManifest(dstDir, &manifest); err != nil {
+		return NewError(ReasonFirmware, err)
+	}
+
+	return nil
+}
+
+// installFile atomically installs src at dst, unless dst already has the same content.
+// It returns the hex-encoded SHA256 digest of src.
+func (fm *firmwareManager) installFile(src, dst, relPath string, others []FirmwareManifest) (string, error) {
+	sum, err := fileSHA256(src)
+	if err != nil {
+		return "", err
+	}
+
+	logger := fm.logger.WithValues("path", dst)
+
+	existing, err := fileSHA256(dst)
+	if err == nil && existing == sum {
+		logger.V(1).Info("Firmware file already installed")
+		return sum, nil
+	}
+
+	if err == nil {
+		for _, m := range others {
+			for _, f := range m.Files {
+				if f.Path == relPath {
+					logger.Info(utils.WarnString("Overwriting firmware file installed by another module"), "module", m.Owner)
+				}
+			}
+		}
+	}
+
+	logger.Info("Installing firmware file", "sha256", sum)
+
+	if err = atomicCopy(src, dst, sum); err != nil {
+		return "", err
+	}
+
+	return sum, nil
+}
+
+func (fm *firmwareManager) Remove(srcDir, dstDir string) error {
+	if fm.owner == "" {
+		return errors.New("the firmware owner is unknown")
+	}
+
+	lock, err := fm.lock(dstDir)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	manifestPath := fm.manifestPath(dstDir)
+
+	manifest, err := readFirmwareManifest(manifestPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		fm.logger.Info("No firmware manifest; removing the files found in the image", "path", manifestPath)
+
+		if manifest, err = manifestFromDir(srcDir); err != nil {
+			return fmt.Errorf("could not list the firmware files in the image: %v", err)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	others, err := fm.otherManifests(dstDir)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{fm.removeFiles(dstDir, manifest.Files, others)}
+
+	if err = os.Remove(manifestPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// removeStaleFiles removes the files listed in the owner's previous manifest that are not part of manifest anymore.
+func (fm *firmwareManager) removeStaleFiles(dstDir string, manifest *FirmwareManifest, others []FirmwareManifest) error {
+	previous, err := readFirmwareManifest(fm.manifestPath(dstDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	current := sets.New[string]()
+
+	for _, f := range manifest.Files {
+		current.Insert(f.Path)
+	}
+
+	stale := make([]FirmwareFile, 0)
+
+	for _, f := range previous.Files {
+		if !current.Has(f.Path) {
+			stale = append(stale, f)
+		}
+	}
+
+	if err = fm.removeFiles(dstDir, stale, others); err != nil {
+		return fmt.Errorf("could not remove the firmware files that are not in the image anymore: %v", err)
+	}
+
+	return nil
+}
+
+// removeFiles removes files from dstDir, unless they are listed in others or were modified since they were installed.
+func (fm *firmwareManager) removeFiles(dstDir string, files []FirmwareFile, others []FirmwareManifest) error {
+	inUse := sets.New[string]()
+
+	for _, m := range others {
+		for _, f := range m.Files {
+			inUse.Insert(f.Path)
+		}
+	}
+
+	errs := make([]error, 0)
+
+	for _, f := range files {
+		logger := fm.logger.WithValues("path", f.Path)
+
+		if inUse.Has(f.Path) {
+			logger.Info("Firmware file is used by another module; not removing it")
+			continue
+		}
+
+		path := filepath.Join(dstDir, f.Path)
+
+		if f.SHA256 != "" {
+			sum, err := fileSHA256(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			if err == nil && sum != f.SHA256 {
+				logger.Info(utils.WarnString("Firmware file was modified since it was installed; not removing it"))
+				continue
+			}
+		}
+
+		logger.Info("Removing firmware file")
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (fm *firmwareManager) Verify(dstDir string) error {
+	manifest, err := readFirmwareManifest(fm.manifestPath(dstDir))
+	if err != nil {
+		return NewError(ReasonFirmware, err)
+	}
+
+	problems := make([]string, 0)
+
+	for _, f := range manifest.Files {
+		path := filepath.Join(dstDir, f.Path)
+
+		if f.Link != "" {
+			if target, err := os.Readlink(path); err != nil || target != f.Link {
+				problems = append(problems, fmt.Sprintf("%s: not a link to %s", f.Path, f.Link))
+			}
+
+			continue
+		}
+
+		sum, err := fileSHA256(path)
+
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			problems = append(problems, f.Path+": missing")
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s: %v", f.Path, err))
+		case sum != f.SHA256:
+			problems = append(problems, fmt.Sprintf("%s: sha256 is %s instead of %s", f.Path, sum, f.SHA256))
+		}
+	}
+
+	if len(problems) > 0 {
+		return NewError(ReasonFirmware, fmt.Errorf("firmware files do not match the manifest: %s", strings.Join(problems, "; ")))
+	}
+
+	fm.logger.Info("Firmware files verified", "count", len(manifest.Files))
+
+	return nil
+}
+
+// lock takes an exclusive lock on the firmware manifests directory of dstDir, so that workers of different modules
+// on the same node do not remove files that another one is installing.
+// The lock is released when the returned file is closed.
+func (fm *firmwareManager) lock(dstDir string) (*os.File, error) {
+	dir := filepath.Join(dstDir, FirmwareManifestsDir)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create the firmware manifests directory: %v", err)
+	}
+
+	fd, err := os.Open(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", dir, err)
+	}
+
+	if err = syscall.Flock(int(fd.Fd()), syscall.LOCK_EX); err != nil {
+		fd.Close()
+		return nil, fmt.Errorf("could not lock %s: %w", dir, err)
+	}
+
+	return fd, nil
+}
+
+func (fm *firmwareManager) manifestPath(dstDir string) string {
+	return filepath.Join(dstDir, FirmwareManifestsDir, strings.ReplaceAll(fm.owner, "/", "_")+".json")
+}
+
+// otherManifests returns the manifests of all modules other than the owner.
+func (fm *firmwareManager) otherManifests(dstDir string) ([]FirmwareManifest, error) {
+	dir := filepath.Join(dstDir, FirmwareManifestsDir)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read the firmware manifests: %v", err)
+	}
+
+	own := filepath.Base(fm.manifestPath(dstDir))
+	manifests := make([]FirmwareManifest, 0, len(entries))
+
+	for _, e := range entries {
+		if e.Name() == own || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+
+		m, err := readFirmwareManifest(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, *m)
+	}
+
+	return manifests, nil
+}
+
+func (fm *firmwareManager) writeManifest(dstDir string, manifest *FirmwareManifest) error {
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal the firmware manifest: %v", err)
+	}
+
+	path := fm.manifestPath(dstDir)
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("could not create the firmware manifests directory: %v", err)
+	}
+
+	if err = atomicWrite(path, b, 0644); err != nil {
+		return fmt.Errorf("could not write the firmware manifest: %v", err)
+	}
+
+	return nil
+}
+
+func readFirmwareManifest(path string) (*FirmwareManifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the firmware manifest: %w", err)
+	}
+
+	m := FirmwareManifest{}
+
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the firmware manifest %s: %v", path, err)
+	}
+
+	return &m, nil
+}
+
+// manifestFromDir returns a manifest listing the files in dir, without their digests.
+func manifestFromDir(dir string) (*FirmwareManifest, error) {
+	m := FirmwareManifest{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		m.Files = append(m.Files, FirmwareFile{Path: relPath})
+
+		return nil
+	})
+
+	return &m, err
+}
+
+func fileSHA256(path string) (string, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+
+	h := sha256.New()
+
+	if _, err = io.Copy(h, fd); err != nil {
+		return "", fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// atomicCopy copies src to a temporary file next to dst, checks that its digest is sum, and renames it to dst.
+func atomicCopy(src, dst, sum string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("could not create a temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+
+	_, err = io.Copy(io.MultiWriter(tmp, h), in)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not copy %s to %s: %v", src, tmp.Name(), err)
+	}
+
+	// The source may have changed since its digest was computed.
+	if copied := hex.EncodeToString(h.Sum(nil)); copied != sum {
+		return fmt.Errorf("%s changed while being copied: sha256 %s instead of %s", src, copied, sum)
+	}
+
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("could not set the permissions of %s: %v", tmp.Name(), err)
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
+
+func atomicWrite(path string, b []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(b)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// installSymlink atomically creates or replaces the symbolic link dst pointing to target.
+func installSymlink(target, dst string) error {
+	if existing, err := os.Readlink(dst); err == nil && existing == target {
+		return nil
+	}
+
+	tmp := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp-link")
+
+	_ = os.Remove(tmp)
+
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("could not create symbolic link %s: %v", tmp, err)
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("could not rename %s to %s: %v", tmp, dst, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("firmwareManager", func() {
+	const owner = "some-namespace/some-module"
+
+	var (
+		fm      FirmwareManager
+		hostDir string
+		srcDir  string
+	)
+
+	writeFile := func(path, content string) {
+		GinkgoHelper()
+
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+	}
+
+	readManifest := func(o string) FirmwareManifest {
+		GinkgoHelper()
+
+		b, err := os.ReadFile(
+			filepath.Join(hostDir, FirmwareManifestsDir, filepath.Base(filepath.Dir(o))+"_"+filepath.Base(o)+".json"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		m := FirmwareManifest{}
+		Expect(json.Unmarshal(b, &m)).To(Succeed())
+
+		return m
+	}
+
+	digest := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+
+	BeforeEach(func() {
+		hostDir = GinkgoT().TempDir()
+		srcDir = GinkgoT().TempDir()
+		fm = NewFirmwareManager(owner, GinkgoLogr)
+
+		writeFile(filepath.Join(srcDir, "file1"), "some data 1")
+		writeFile(filepath.Join(srcDir, "binDir", "file2"), "some data 2")
+		Expect(os.Symlink("binDir/file2", filepath.Join(srcDir, "link"))).To(Succeed())
+	})
+
+	Describe("Install", func() {
+		It("should copy the files and write the manifest", func() {
+			Expect(fm.Install(srcDir, hostDir)).To(Succeed())
+
+			Expect(os.ReadFile(filepath.Join(hostDir, "file1"))).To(BeEquivalentTo("some data 1"))
+			Expect(os.ReadFile(filepath.Join(hostDir, "binDir", "file2"))).To(BeEquivalentTo("some data 2"))
+			Expect(os.Readlink(filepath.Join(hostDir, "link"))).To(Equal("binDir/file2"))
+
+			fi, err := os.Stat(filepath.Join(hostDir, "file1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fi.Mode().Perm()).To(BeEquivalentTo(0644))
+
+			Expect(readManifest(owner)).To(Equal(FirmwareManifest{
+				Owner: owner,
+				Files: []FirmwareFile{
+					{Path: "binDir/file2", SHA256: digest("some data 2")},
+					{Path: "file1", SHA256: digest("some data 1")},
+					{Path: "link", Link: "binDir/file2"},
+				},
+			}))
+
+			// No temporary file should be left behind.
+			entries, err := os.ReadDir(hostDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(4))
+		})
+
+		It("should be idempotent and replace modified files", func() {
+			Expect(fm.Install(srcDir, hostDir)).To(Succeed())
+			writeFile(filepath.Join(hostDir, "file1"), "tampered")
+
+			Expect(fm.Install(srcDir, hostDir)).To(Succeed())
+			Expect(os.ReadFile(filepath.Join(hostDir, "file1"))).To(BeEquivalentTo("some data 1"))
+		})
+
+		It("should remove the files that are not in the image anymore", func() {
+			otherSrcDir := GinkgoT().TempDir()
+			writeFile(filepath.Join(otherSrcDir, "link"), "some other data")
+
+			Expect(fm.Install(srcDir, hostDir)).To(Succeed())
+
+			Expect(os.Remove(filepath.Join(srcDir, "file1"))).To(Succeed())
+			Expect(os.Remove(filepath.Join(srcDir, "link"))).To(Succeed())
+
+			// The other module now owns link.
+			Expect(NewFirmwareManager("other-namespace/other-module", GinkgoLogr).Install(otherSrcDir, hostDir)).To(Succeed())
+
+			Expect(fm.Install(srcDir, hostDir)).To(Succeed())
+
+			Expect(filepath.Join(hostDir, "file1")).NotTo(BeAnExistingFile())
+			Expect(os.ReadFile(filepath.Join(hostDir, "link"))).To(BeEquivalentTo("some other data"))
+			Expect(os.ReadFile(filepath.Join(hostDir, "binDir", "file2"))).To(BeEquivalentTo("some data 2"))
+			Expect(readManifest(owner).Files).To(Equal([]FirmwareFile{
+				{Path: "binDir/file2", SHA256: digest("some data 2")},
+			}))
+		})
+
+		It("should fail if the owner is unknown", func() {
+			err := NewFirmwareManager("", GinkgoLogr).Install(srcDir, hostDir)
+			Expect(ReasonFromError(err)).To(Equal(ReasonFirmware))
+		})
+
+		It("should fail if the source directory does not exist", func() {
+			err := fm.Install(filepath.Join(srcDir, "missing"), hostDir)
+			Expect(ReasonFromError(err)).To(Equal(ReasonFirmware))
+		})
+	})
+
+	Describe("Remove", func() {
+		It("should only remove the files that no other module references", func() {
+			otherSrcDir := GinkgoT().TempDir()
+			writeFile(filepath.Join(otherSrcDir, "file1"), "some data 1")
+
+			Expect(fm.Install(srcDir, hostDir)).To(Succeed())
+			Expect(NewFirmwareManager("other-namespace/other-module", GinkgoLogr).Install(otherSrcDir, hostDir)).To(Succeed())
+
+			Expect(fm.Remove(srcDir, hostDir)).To(Succeed())
+
+			Expect(os.ReadFile(filepath.Join(hostDir, "file1"))).To(BeEquivalentTo("some data 1"))
+			Expect(filepath.Join(hostDir, "binDir", "file2")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostDir, "link")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostDir, "binDir")).To(BeADirectory())
+			Expect(filepath.Join(hostDir, FirmwareManifestsDir, "some-namespace_some-module.json")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostDir, FirmwareManifestsDir, "other-namespace_other-module.json")).To(BeAnExistingFile())
+		})
+
+		It("should not remove files that were modified since they were installed", func() {
+			Expect(fm.Install(srcDir, hostDir)).To(Succeed())
+			writeFile(filepath.Join(hostDir, "file1"), "replaced")
+
+			Expect(fm.Remove(srcDir, hostDir)).To(Succeed())
+
+			Expect(os.ReadFile(filepath.Join(hostDir, "file1"))).To(BeEquivalentTo("replaced"))
+			Expect(filepath.Join(hostDir, "binDir", "file2")).NotTo(BeAnExistingFile())
+		})
+
+		It("should remove the files found in the image if there is no manifest", func() {
+			writeFile(filepath.Join(hostDir, "file1"), "some data 1")
+			writeFile(filepath.Join(hostDir, "binDir", "file2"), "some data 2")
+			writeFile(filepath.Join(hostDir, "unrelated"), "unrelated")
+
+			Expect(fm.Remove(srcDir, hostDir)).To(Succeed())
+
+			Expect(filepath.Join(hostDir, "file1")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostDir, "binDir", "file2")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostDir, "unrelated")).To(BeAnExistingFile())
+		})
+	})
+
+	It("should not remove a shared file while another module installs it", func() {
+		otherSrcDir := GinkgoT().TempDir()
+		writeFile(filepath.Join(otherSrcDir, "file1"), "some data 1")
+
+		other := NewFirmwareManager("other-namespace/other-module", GinkgoLogr)
+
+		for i := 0; i < 20; i++ {
+			Expect(fm.Install(srcDir, hostDir)).To(Succeed())
+
+			var wg sync.WaitGroup
+
+			wg.Add(2)
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				Expect(other.Install(otherSrcDir, hostDir)).To(Succeed())
+			}()
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				Expect(fm.Remove(srcDir, hostDir)).To(Succeed())
+			}()
+
+			wg.Wait()
+
+			Expect(os.ReadFile(filepath.Join(hostDir, "file1"))).To(BeEquivalentTo("some data 1"))
+			Expect(other.Verify(hostDir)).To(Succeed())
+			Expect(other.Remove(otherSrcDir, hostDir)).To(Succeed())
+		}
+	})
+
+	It("should wait for the lock on the manifests directory", func() {
+		lock, err := fm.(*firmwareManager).lock(hostDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		done := make(chan error)
+
+		go func() {
+			done <- fm.Install(srcDir, hostDir)
+		}()
+
+		Consistently(done, 100*time.Millisecond).ShouldNot(Receive())
+		Expect(lock.Close()).To(Succeed())
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	Describe("Verify", func() {
+		BeforeEach(func() {
+			Expect(fm.Install(srcDir, hostDir)).To(Succeed())
+		})
+
+		It("should succeed if the files were not modified", func() {
+			Expect(fm.Verify(hostDir)).To(Succeed())
+		})
+
+		It("should detect missing and modified files", func() {
+			Expect(os.Remove(filepath.Join(hostDir, "file1"))).To(Succeed())
+			writeFile(filepath.Join(hostDir, "binDir", "file2"), "tampered")
+			Expect(os.Remove(filepath.Join(hostDir, "link"))).To(Succeed())
+
+			err := fm.Verify(hostDir)
+			Expect(ReasonFromError(err)).To(Equal(ReasonFirmware))
+			Expect(err).To(MatchError(ContainSubstring("file1: missing")))
+			Expect(err).To(MatchError(ContainSubstring("binDir/file2: sha256 is " + digest("tampered"))))
+			Expect(err).To(MatchError(ContainSubstring("link: not a link to binDir/file2")))
+		})
+
+		It("should fail if there is no manifest", func() {
+			err := NewFirmwareManager("other-namespace/other-module", GinkgoLogr).Verify(hostDir)
+			Expect(ReasonFromError(err)).To(Equal(ReasonFirmware))
+		})
+	})
+})
@@ -0,0 +1,159 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+)
+
+// HookType identifies when a hook runs.
+type HookType string
+
+const (
+	HookPreLoad    HookType = "PreLoad"
+	HookPostLoad   HookType = "PostLoad"
+	HookPreUnload  HookType = "PreUnload"
+	HookPostUnload HookType = "PostUnload"
+
+	// DefaultHookTimeout is used for hooks that do not set a timeout.
+	DefaultHookTimeout = 30 * time.Second
+
+	// hookWaitDelay bounds the time spent waiting for the output of a hook after it was killed.
+	hookWaitDelay = 5 * time.Second
+
+	// hookPath is the PATH set in the environment of hooks.
+	hookPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+)
+
+var hookPathDirs = filepath.SplitList(hookPath)
+
+var hookReasons = map[HookType]Reason{
+	HookPreLoad:    ReasonPreLoadHookFailed,
+	HookPostLoad:   ReasonPostLoadHookFailed,
+	HookPreUnload:  ReasonPreUnloadHookFailed,
+	HookPostUnload: ReasonPostUnloadHookFailed,
+}
+
+//go:generate mockgen -source=hooks.go -package=worker -destination=mock_hooks.go
+
+type HookRunner interface {
+	// RunHook runs the hook of type ht configured in cfg, if any, from the image mounted at fsDir.
+	// The hook runs in fsDir and finds the image's executables first in its PATH; it is not chrooted, so that it can
+	// access the node's /sys, /dev and /proc.
+	// It returns an Error with the reason matching ht if the hook fails or times out.
+	RunHook(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, fsDir string, ht HookType) error
+}
+
+type hookRunner struct {
+	logger logr.Logger
+}
+
+func NewHookRunner(logger logr.Logger) HookRunner {
+	return &hookRunner{
+		logger: logger.WithName("hook"),
+	}
+}
+
+func (hr *hookRunner) RunHook(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, fsDir string, ht HookType) error {
+	hook := hookFromConfig(cfg, ht)
+	if hook == nil {
+		return nil
+	}
+
+	timeout := DefaultHookTimeout
+	if hook.Timeout != nil {
+		timeout = hook.Timeout.Duration
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Joining with / first prevents the path from escaping the image.
+	path := filepath.Join(fsDir, filepath.Join("/", hook.Path))
+
+	cmd := exec.CommandContext(ctx, path, hook.Args...)
+	cmd.Dir = fsDir
+	cmd.Env = []string{
+		"KERNEL_VERSION=" + cfg.KernelVersion,
+		"MOD_NAME=" + cfg.Modprobe.ModuleName,
+		"PATH=" + imageHookPath(fsDir),
+	}
+	// The process group is killed on timeout, so that children of the hook do not keep its output pipes open.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = hookWaitDelay
+
+	logger := hr.logger.WithValues("type", ht)
+
+	cl, err := NewCommandLogger(cmd, logger)
+	if err != nil {
+		return fmt.Errorf("could not create a command logger: %v", err)
+	}
+
+	logger.Info("Running hook", "command", cmd.String(), "timeout", timeout)
+
+	if err = cmd.Start(); err != nil {
+		return NewError(hookReasons[ht], fmt.Errorf("could not start the %s hook %s: %v", ht, hook.Path, err))
+	}
+
+	if err = cl.Wait(); err != nil {
+		return fmt.Errorf("error while waiting on the command logger: %v", err)
+	}
+
+	// The exit error is not wrapped, so that it is not reported as the exit code of modprobe.
+	if err = cmd.Wait(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return NewError(hookReasons[ht], fmt.Errorf("the %s hook %s timed out after %v", ht, hook.Path, timeout))
+		}
+
+		if tail := cl.StderrTail(); len(tail) > 0 {
+			err = fmt.Errorf("%s: %v", strings.Join(tail, "; "), err)
+		}
+
+		return NewError(hookReasons[ht], fmt.Errorf("the %s hook %s failed: %v", ht, hook.Path, err))
+	}
+
+	return nil
+}
+
+// imageHookPath returns the PATH of hooks: the bin directories of the image mounted at fsDir, followed by those of the
+// worker.
+func imageHookPath(fsDir string) string {
+	dirs := make([]string, 0, 2*len(hookPathDirs))
+
+	for _, d := range hookPathDirs {
+		dirs = append(dirs, filepath.Join(fsDir, d))
+	}
+
+	return strings.Join(append(dirs, hookPathDirs...), string(filepath.ListSeparator))
+}
+
+func hookFromConfig(cfg *kmmv1beta1.ModuleConfig, ht HookType) *kmmv1beta1.ModprobeHook {
+	hooks := cfg.Modprobe.Hooks
+	if hooks == nil {
+		return nil
+	}
+
+	switch ht {
+	case HookPreLoad:
+		return hooks.PreLoad
+	case HookPostLoad:
+		return hooks.PostLoad
+	case HookPreUnload:
+		return hooks.PreUnload
+	case HookPostUnload:
+		return hooks.PostUnload
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("hookRunner_RunHook", func() {
+	var (
+		fsDir string
+		hr    HookRunner
+	)
+
+	ctx := context.Background()
+
+	writeScript := func(name, content string) {
+		GinkgoHelper()
+
+		Expect(
+			os.WriteFile(filepath.Join(fsDir, name), []byte("#!/bin/sh\n"+content), 0755),
+		).To(
+			Succeed(),
+		)
+	}
+
+	configWithHook := func(ht HookType, hook *v1beta1.ModprobeHook) *v1beta1.ModuleConfig {
+		cfg := &v1beta1.ModuleConfig{
+			KernelVersion: "1.2.3",
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: "some-module",
+				Hooks:      &v1beta1.ModprobeHooks{},
+			},
+		}
+
+		switch ht {
+		case HookPreLoad:
+			cfg.Modprobe.Hooks.PreLoad = hook
+		case HookPostLoad:
+			cfg.Modprobe.Hooks.PostLoad = hook
+		case HookPreUnload:
+			cfg.Modprobe.Hooks.PreUnload = hook
+		case HookPostUnload:
+			cfg.Modprobe.Hooks.PostUnload = hook
+		}
+
+		return cfg
+	}
+
+	BeforeEach(func() {
+		fsDir = GinkgoT().TempDir()
+		hr = NewHookRunner(GinkgoLogr)
+	})
+
+	It("should do nothing if no hook is configured", func() {
+		Expect(hr.RunHook(ctx, &v1beta1.ModuleConfig{}, fsDir, HookPreLoad)).To(Succeed())
+		Expect(hr.RunHook(ctx, configWithHook(HookPreLoad, nil), fsDir, HookPreLoad)).To(Succeed())
+	})
+
+	It("should run the hook from the image with its arguments and a controlled environment", func() {
+		writeScript("hook.sh", `echo "$KERNEL_VERSION $MOD_NAME $HOME $1" > out`)
+
+		cfg := configWithHook(HookPostLoad, &v1beta1.ModprobeHook{Path: "/hook.sh", Args: []string{"arg"}})
+
+		Expect(hr.RunHook(ctx, cfg, fsDir, HookPostLoad)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(fsDir, "out"))).To(Equal([]byte("1.2.3 some-module  arg\n")))
+	})
+
+	It("should not run executables outside of the image", func() {
+		cfg := configWithHook(HookPreLoad, &v1beta1.ModprobeHook{Path: "../../bin/true"})
+
+		Expect(
+			ReasonFromError(hr.RunHook(ctx, cfg, fsDir, HookPreLoad)),
+		).To(
+			Equal(ReasonPreLoadHookFailed),
+		)
+	})
+
+	DescribeTable(
+		"should report failures with the reason of the hook",
+		func(ht HookType, reason Reason) {
+			writeScript("hook.sh", "echo some error >&2\nexit 3\n")
+
+			err := hr.RunHook(ctx, configWithHook(ht, &v1beta1.ModprobeHook{Path: "/hook.sh"}), fsDir, ht)
+			Expect(err).To(MatchError(ContainSubstring("some error")))
+			Expect(ReasonFromError(err)).To(Equal(reason))
+
+			// The exit code must not be reported as the one of modprobe.
+			Expect(NewTerminationMessage(err, nil).ExitCode).To(BeNil())
+		},
+		Entry(nil, HookPreLoad, ReasonPreLoadHookFailed),
+		Entry(nil, HookPostLoad, ReasonPostLoadHookFailed),
+		Entry(nil, HookPreUnload, ReasonPreUnloadHookFailed),
+		Entry(nil, HookPostUnload, ReasonPostUnloadHookFailed),
+	)
+
+	It("should kill the hook after its timeout", func() {
+		writeScript("hook.sh", "exec sleep 10\n")
+
+		cfg := configWithHook(HookPreUnload, &v1beta1.ModprobeHook{
+			Path:    "/hook.sh",
+			Timeout: &metav1.Duration{Duration: 100 * time.Millisecond},
+		})
+
+		err := hr.RunHook(ctx, cfg, fsDir, HookPreUnload)
+		Expect(err).To(MatchError(ContainSubstring("timed out")))
+		Expect(ReasonFromError(err)).To(Equal(ReasonPreUnloadHookFailed))
+	})
+
+	It("should kill the children of the hook after its timeout", func() {
+		writeScript("hook.sh", "sleep 10 &\nsleep 10\n")
+
+		cfg := configWithHook(HookPreUnload, &v1beta1.ModprobeHook{
+			Path:    "/hook.sh",
+			Timeout: &metav1.Duration{Duration: 100 * time.Millisecond},
+		})
+
+		start := time.Now()
+
+		err := hr.RunHook(ctx, cfg, fsDir, HookPreUnload)
+		Expect(err).To(MatchError(ContainSubstring("timed out")))
+		Expect(time.Since(start)).To(BeNumerically("<", hookWaitDelay))
+	})
+
+	It("should find the image's executables first in its PATH", func() {
+		Expect(os.MkdirAll(filepath.Join(fsDir, "usr", "bin"), 0755)).To(Succeed())
+		writeScript("usr/bin/some-tool", "echo from-image > out\n")
+		writeScript("hook.sh", "some-tool\n")
+
+		cfg := configWithHook(HookPostLoad, &v1beta1.ModprobeHook{Path: "/hook.sh"})
+
+		Expect(hr.RunHook(ctx, cfg, fsDir, HookPostLoad)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(fsDir, "out"))).To(Equal([]byte("from-image\n")))
+	})
+
+	It("should let the hook write to the node's filesystem outside of the image", func() {
+		// A sysfs-like attribute outside of the image.
+		attr := filepath.Join(GinkgoT().TempDir(), "sys", "module", "some_module", "parameters", "some_param")
+		Expect(os.MkdirAll(filepath.Dir(attr), 0755)).To(Succeed())
+		Expect(os.WriteFile(attr, []byte("0\n"), 0644)).To(Succeed())
+
+		writeScript("hook.sh", `echo 1 > "$1"`)
+
+		cfg := configWithHook(HookPostLoad, &v1beta1.ModprobeHook{Path: "/hook.sh", Args: []string{attr}})
+
+		Expect(hr.RunHook(ctx, cfg, fsDir, HookPostLoad)).To(Succeed())
+		Expect(os.ReadFile(attr)).To(Equal([]byte("1\n")))
+	})
+})
@@ -0,0 +1,589 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/go-logr/logr"
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/moby/moby/pkg/archive"
+)
+
+const (
+	cacheLocksDir = "locks"
+	cacheRefsDir  = "refs"
+	cacheTmpDir   = "tmp"
+
+	refTmpPrefix     = ".tmp-"
+	unpackLockSuffix = ".unpack"
+
+	// cacheTmpMaxAge is the age after which temporary directories left by interrupted workers are removed.
+	cacheTmpMaxAge = time.Hour
+)
+
+//go:generate mockgen -source=imagecache.go -package=worker -destination=mock_imagecache.go
+
+// ImageCache stores unpacked images on the node, keyed by digest.
+// It is shared by all worker Pods on the node: concurrent workers unpacking the same image are serialized with a file
+// lock, and unpacked trees are renamed into place atomically.
+type ImageCache interface {
+	// GarbageCollect removes the trees of images that are not in keepImages, least recently used first, until the
+	// cache uses at most maxSize bytes, and the references to removed trees.
+	// Trees in use by a worker are never removed.
+	GarbageCollect(keepImages []string, maxSize int64) error
+	// Mount returns the filesystem of the image with the given digest, calling getImage to unpack it if needed.
+	// The tree is protected from garbage collection until Unmount is called or the process exits.
+	Mount(ctx context.Context, imageName, digest string, getImage func() (v1.Image, error)) (string, error)
+	// Unmount releases the trees returned by Mount, so that they can be garbage collected.
+	// They must not be used afterwards.
+	Unmount()
+	// Lookup returns the digest last recorded for name by Mount or Tag, or an empty string if there is none.
+	Lookup(name string) (string, error)
+	// Tag records that name designates the image with the given digest.
+	// Like the references written by Mount, it is removed by GarbageCollect once the image's tree is removed.
+	Tag(name, digest string) error
+}
+
+type imageCache struct {
+	baseDir string
+	logger  logr.Logger
+
+	// heldLocks are shared locks on the trees returned by Mount; they are released by Unmount or when the process
+	// exits.
+	heldLocks []*os.File
+	mut       sync.Mutex
+}
+
+func NewImageCache(baseDir string, logger logr.Logger) ImageCache {
+	return &imageCache{
+		baseDir: baseDir,
+		logger:  logger,
+	}
+}
+
+func (ic *imageCache) Mount(ctx context.Context, imageName, digest string, getImage func() (v1.Image, error)) (string, error) {
+	h, err := v1.NewHash(digest)
+	if err != nil {
+		return "", fmt.Errorf("invalid digest %q for %s: %v", digest, imageName, err)
+	}
+
+	logger := ic.logger.WithValues("image name", imageName, "digest", digest)
+
+	for _, d := range []string{cacheLocksDir, cacheRefsDir, cacheTmpDir, h.Algorithm} {
+		if err = os.MkdirAll(filepath.Join(ic.baseDir, d), os.ModeDir|0755); err != nil {
+			return "", fmt.Errorf("could not create the cache directory %s: %v", d, err)
+		}
+	}
+
+	// The shared lock protects the tree from garbage collection while it is in use.
+	lock, err := ic.lock(h, "", syscall.LOCK_SH)
+	if err != nil {
+		return "", err
+	}
+
+	entryDir := ic.entryDir(h)
+
+	if err = ic.ensureUnpacked(ctx, logger, h, getImage); err != nil {
+		lock.Close()
+		return "", err
+	}
+
+	now := time.Now()
+
+	if err = os.Chtimes(entryDir, now, now); err != nil {
+		lock.Close()
+		return "", fmt.Errorf("could not update the last use time of %s: %v", entryDir, err)
+	}
+
+	if err = ic.writeRef(imageName, h); err != nil {
+		lock.Close()
+		return "", err
+	}
+
+	ic.mut.Lock()
+	ic.heldLocks = append(ic.heldLocks, lock)
+	ic.mut.Unlock()
+
+	return filepath.Join(entryDir, "fs"), nil
+}
+
+func (ic *imageCache) Unmount() {
+	ic.mut.Lock()
+	defer ic.mut.Unlock()
+
+	for _, lock := range ic.heldLocks {
+		lock.Close()
+	}
+
+	ic.heldLocks = nil
+}
+
+func (ic *imageCache) Lookup(name string) (string, error) {
+	path := filepath.Join(ic.baseDir, cacheRefsDir, refFileName(name))
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("could not read the reference for %s: %v", name, err)
+	}
+
+	return string(b), nil
+}
+
+func (ic *imageCache) Tag(name, digest string) error {
+	h, err := v1.NewHash(digest)
+	if err != nil {
+		return fmt.Errorf("invalid digest %q for %s: %v", digest, name, err)
+	}
+
+	if err = os.MkdirAll(filepath.Join(ic.baseDir, cacheRefsDir), os.ModeDir|0755); err != nil {
+		return fmt.Errorf("could not create the cache directory %s: %v", cacheRefsDir, err)
+	}
+
+	return ic.writeRef(name, h)
+}
+
+func (ic *imageCache) entryDir(h v1.Hash) string {
+	return filepath.Join(ic.baseDir, h.Algorithm, h.Hex)
+}
+
+// lock locks the lock file of h with the given suffix.
+func (ic *imageCache) lock(h v1.Hash, suffix string, how int) (*os.File, error) {
+	path := filepath.Join(ic.baseDir, cacheLocksDir, h.Algorithm+"-"+h.Hex+suffix)
+
+	fd, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the lock file %s: %v", path, err)
+	}
+
+	if err = syscall.Flock(int(fd.Fd()), how); err != nil {
+		fd.Close()
+		return nil, fmt.Errorf("could not lock %s: %w", path, err)
+	}
+
+	return fd, nil
+}
+
+// ensureUnpacked unpacks the image if it is not in the cache yet.
+// Workers unpacking the same image are serialized, so that it is only pulled once.
+func (ic *imageCache) ensureUnpacked(ctx context.Context, logger logr.Logger, h v1.Hash, getImage func() (v1.Image, error)) error {
+	lock, err := ic.lock(h, unpackLockSuffix, syscall.LOCK_EX)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	entryDir := ic.entryDir(h)
+
+	if _, err = os.Stat(entryDir); err == nil {
+		logger.Info("Image found in the cache; skipping pull")
+		return nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not stat %s: %v", entryDir, err)
+	}
+
+	return ic.unpack(ctx, logger, h, getImage)
+}
+
+// unpack writes the image in a temporary directory, then renames it into place.
+func (ic *imageCache) unpack(ctx context.Context, logger logr.Logger, h v1.Hash, getImage func() (v1.Image, error)) error {
+	tmpDir, err := os.MkdirTemp(filepath.Join(ic.baseDir, cacheTmpDir), h.Hex+"-")
+	if err != nil {
+		return fmt.Errorf("could not create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dstDirFS := filepath.Join(tmpDir, "fs")
+
+	if err = os.Mkdir(dstDirFS, os.ModeDir|0755); err != nil {
+		return fmt.Errorf("could not create the filesystem directory %s: %v", dstDirFS, err)
+	}
+
+	logger.V(1).Info("Pulling image")
+
+	img, err := getImage()
+	if err != nil {
+		return err
+	}
+
+	errs := make(chan error, 2)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	rd, wr := io.Pipe()
+
+	go func() {
+		defer wg.Done()
+		defer wr.Close()
+
+		logger.V(1).Info("Starting to export image")
+
+		if err := crane.Export(img, wr); err != nil {
+			errs <- err
+			return
+		}
+
+		logger.V(1).Info("Done exporting image")
+	}()
+
+	go func() {
+		defer wg.Done()
+		defer rd.Close()
+
+		id := idtools.CurrentIdentity()
+
+		tarOpts := &archive.TarOptions{ChownOpts: &id}
+
+		if err := archive.Untar(rd, dstDirFS, tarOpts); err != nil {
+			errs <- err
+			return
+		}
+
+		logger.V(1).Info("Done writing tar archive")
+	}()
+
+	wg.Wait()
+	close(errs)
+
+	chErrs := make([]error, 0)
+
+	for chErr := range errs {
+		chErrs = append(chErrs, chErr)
+	}
+
+	if err = errors.Join(chErrs...); err != nil {
+		return fmt.Errorf("got one or more errors while writing the image: %v", err)
+	}
+
+	if err = ctx.Err(); err != nil {
+		return fmt.Errorf("not adding the image to the cache: %v", err)
+	}
+
+	entryDir := ic.entryDir(h)
+
+	logger.V(1).Info("Image written to the filesystem; moving it into place", "path", entryDir)
+
+	if err = os.Rename(tmpDir, entryDir); err != nil {
+		return fmt.Errorf("could not rename %s to %s: %v", tmpDir, entryDir, err)
+	}
+
+	return nil
+}
+
+func refFileName(imageName string) string {
+	sum := sha256.Sum256([]byte(imageName))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeRef records the digest that imageName resolved to, so that GarbageCollect can find the trees of the images it
+// must keep.
+func (ic *imageCache) writeRef(imageName string, h v1.Hash) error {
+	refsDir := filepath.Join(ic.baseDir, cacheRefsDir)
+
+	fd, err := os.CreateTemp(refsDir, refTmpPrefix)
+	if err != nil {
+		return fmt.Errorf("could not create a temporary file in %s: %v", refsDir, err)
+	}
+	defer os.Remove(fd.Name())
+
+	_, err = fd.WriteString(h.String())
+
+	if cErr := fd.Close(); err == nil {
+		err = cErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not write %s: %v", fd.Name(), err)
+	}
+
+	path := filepath.Join(refsDir, refFileName(imageName))
+
+	if err = os.Rename(fd.Name(), path); err != nil {
+		return fmt.Errorf("could not rename %s to %s: %v", fd.Name(), path, err)
+	}
+
+	return nil
+}
+
+type cacheEntry struct {
+	hash     v1.Hash
+	lastUsed time.Time
+	size     int64
+}
+
+func (ic *imageCache) GarbageCollect(keepImages []string, maxSize int64) error {
+	keep := make(map[v1.Hash]bool, len(keepImages))
+
+	for _, image := range keepImages {
+		b, err := os.ReadFile(filepath.Join(ic.baseDir, cacheRefsDir, refFileName(image)))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return fmt.Errorf("could not read the reference for %s: %v", image, err)
+		}
+
+		h, err := v1.NewHash(string(b))
+		if err != nil {
+			return fmt.Errorf("invalid reference for %s: %v", image, err)
+		}
+
+		keep[h] = true
+	}
+
+	entries, err := ic.listEntries()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+
+	for _, e := range entries {
+		total += e.size
+	}
+
+	// Least recently used first
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUsed.Before(entries[j].lastUsed)
+	})
+
+	errs := make([]error, 0)
+
+	for _, e := range entries {
+		if total <= maxSize {
+			break
+		}
+
+		logger := ic.logger.WithValues("digest", e.hash.String())
+
+		if keep[e.hash] {
+			logger.V(1).Info("Image is referenced; keeping it")
+			continue
+		}
+
+		removed, err := ic.removeEntry(e.hash)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if !removed {
+			logger.Info("Image is in use; keeping it")
+			continue
+		}
+
+		logger.Info("Removed image from the cache", "size", e.size)
+
+		total -= e.size
+	}
+
+	errs = append(errs, ic.removeDanglingRefs(), ic.removeStaleTmpDirs())
+
+	return errors.Join(errs...)
+}
+
+// removeDanglingRefs removes the references to trees that are not in the cache anymore.
+func (ic *imageCache) removeDanglingRefs() error {
+	refsDir := filepath.Join(ic.baseDir, cacheRefsDir)
+
+	entries, err := os.ReadDir(refsDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("could not read %s: %v", refsDir, err)
+	}
+
+	errs := make([]error, 0)
+
+	for _, e := range entries {
+		// Temporary files are removed by writeRef.
+		if strings.HasPrefix(e.Name(), refTmpPrefix) {
+			continue
+		}
+
+		path := filepath.Join(refsDir, e.Name())
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not read %s: %v", path, err))
+			continue
+		}
+
+		// Invalid references cannot point at a tree.
+		if h, err := v1.NewHash(string(b)); err == nil {
+			if _, err = os.Stat(ic.entryDir(h)); err == nil || !errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+		}
+
+		ic.logger.V(1).Info("Removing dangling reference", "path", path)
+
+		if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("could not remove %s: %v", path, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ic *imageCache) listEntries() ([]cacheEntry, error) {
+	entries := make([]cacheEntry, 0)
+
+	algoDirs, err := os.ReadDir(ic.baseDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return entries, nil
+		}
+
+		return nil, fmt.Errorf("could not read %s: %v", ic.baseDir, err)
+	}
+
+	for _, ad := range algoDirs {
+		switch ad.Name() {
+		case cacheLocksDir, cacheRefsDir, cacheTmpDir:
+			continue
+		}
+
+		if !ad.IsDir() {
+			continue
+		}
+
+		dirs, err := os.ReadDir(filepath.Join(ic.baseDir, ad.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %v", ad.Name(), err)
+		}
+
+		for _, d := range dirs {
+			h, err := v1.NewHash(ad.Name() + ":" + d.Name())
+			if err != nil {
+				ic.logger.Info("Ignoring unexpected entry in the image cache", "name", filepath.Join(ad.Name(), d.Name()))
+				continue
+			}
+
+			e, err := ic.readEntry(h)
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, *e)
+		}
+	}
+
+	return entries, nil
+}
+
+func (ic *imageCache) readEntry(h v1.Hash) (*cacheEntry, error) {
+	dir := ic.entryDir(h)
+
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not stat %s: %v", dir, err)
+	}
+
+	e := cacheEntry{
+		hash:     h,
+		lastUsed: fi.ModTime(),
+	}
+
+	err = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			e.size += info.Size()
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("could not compute the size of %s: %v", dir, err)
+	}
+
+	return &e, nil
+}
+
+// removeEntry removes the tree of h, unless a worker is using it.
+func (ic *imageCache) removeEntry(h v1.Hash) (bool, error) {
+	lock, err := ic.lock(h, "", syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return false, nil
+		}
+
+		return false, err
+	}
+	defer lock.Close()
+
+	dir := ic.entryDir(h)
+
+	if err = os.RemoveAll(dir); err != nil {
+		return false, fmt.Errorf("could not remove %s: %v", dir, err)
+	}
+
+	return true, nil
+}
+
+func (ic *imageCache) removeStaleTmpDirs() error {
+	tmpDir := filepath.Join(ic.baseDir, cacheTmpDir)
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("could not read %s: %v", tmpDir, err)
+	}
+
+	errs := make([]error, 0)
+
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if time.Since(info.ModTime()) < cacheTmpMaxAge {
+			continue
+		}
+
+		path := filepath.Join(tmpDir, e.Name())
+
+		ic.logger.Info("Removing stale temporary directory", "path", path)
+
+		if err = os.RemoveAll(path); err != nil {
+			errs = append(errs, fmt.Errorf("could not remove %s: %v", path, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
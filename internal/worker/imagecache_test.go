@@ -0,0 +1,288 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("imageCache_Mount", func() {
+	const imageName = "example.com/org/image:tag"
+
+	var (
+		baseDir   string
+		ic        ImageCache
+		srcImg    v1.Image
+		srcDigest v1.Hash
+	)
+
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		var err error
+
+		srcImg, err = crane.Append(empty.Image, "testdata/archive.tar")
+		Expect(err).NotTo(HaveOccurred())
+
+		srcDigest, err = srcImg.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		baseDir = GinkgoT().TempDir()
+		ic = NewImageCache(baseDir, GinkgoLogr)
+	})
+
+	It("should unpack the image and record its digest", func() {
+		res, err := ic.Mount(ctx, imageName, srcDigest.String(), func() (v1.Image, error) { return srcImg, nil })
+		Expect(err).NotTo(HaveOccurred())
+
+		imgRoot := filepath.Join(baseDir, srcDigest.Algorithm, srcDigest.Hex, "fs")
+		Expect(res).To(Equal(imgRoot))
+		Expect(filepath.Join(imgRoot, "subdir", "subsubdir", "c")).To(BeARegularFile())
+
+		Expect(
+			os.ReadFile(filepath.Join(baseDir, cacheRefsDir, refFileName(imageName))),
+		).To(
+			Equal([]byte(srcDigest.String())),
+		)
+
+		Expect(os.ReadDir(filepath.Join(baseDir, cacheTmpDir))).To(BeEmpty())
+	})
+
+	It("should not unpack the image again if it is in the cache", func() {
+		dstDir := filepath.Join(baseDir, srcDigest.Algorithm, srcDigest.Hex)
+
+		Expect(
+			os.MkdirAll(dstDir, os.ModeDir|0755),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		getImage := func() (v1.Image, error) {
+			return nil, errors.New("should not be called")
+		}
+
+		res, err := ic.Mount(ctx, imageName, srcDigest.String(), getImage)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(filepath.Join(dstDir, "fs")))
+	})
+
+	It("should unpack the image only once when several workers mount it concurrently", func() {
+		const workers = 4
+
+		var (
+			calls int32
+			wg    sync.WaitGroup
+		)
+
+		getImage := func() (v1.Image, error) {
+			atomic.AddInt32(&calls, 1)
+			return srcImg, nil
+		}
+
+		results := make([]string, workers)
+		errs := make([]error, workers)
+
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				// One cache per goroutine, so that each one has its own lock file descriptor like separate workers.
+				results[i], errs[i] = NewImageCache(baseDir, GinkgoLogr).Mount(ctx, imageName, srcDigest.String(), getImage)
+			}(i)
+		}
+
+		wg.Wait()
+
+		Expect(errors.Join(errs...)).NotTo(HaveOccurred())
+		Expect(calls).To(BeEquivalentTo(1))
+
+		for _, r := range results {
+			Expect(r).To(Equal(filepath.Join(baseDir, srcDigest.Algorithm, srcDigest.Hex, "fs")))
+		}
+	})
+
+	It("should not add the image to the cache if unpacking fails", func() {
+		getImage := func() (v1.Image, error) {
+			return nil, errors.New("random error")
+		}
+
+		_, err := ic.Mount(ctx, imageName, srcDigest.String(), getImage)
+		Expect(err).To(HaveOccurred())
+
+		Expect(filepath.Join(baseDir, srcDigest.Algorithm, srcDigest.Hex)).NotTo(BeADirectory())
+		Expect(os.ReadDir(filepath.Join(baseDir, cacheTmpDir))).To(BeEmpty())
+	})
+
+	It("should record tags", func() {
+		Expect(ic.Lookup("some-name")).To(BeEmpty())
+		Expect(ic.Tag("some-name", srcDigest.String())).To(Succeed())
+		Expect(ic.Lookup("some-name")).To(Equal(srcDigest.String()))
+	})
+
+	It("should return an error if the digest is invalid", func() {
+		_, err := ic.Mount(ctx, imageName, "not-a-digest", nil)
+		Expect(err).To(MatchError(ContainSubstring("invalid digest")))
+	})
+})
+
+var _ = Describe("imageCache_GarbageCollect", func() {
+	var (
+		baseDir string
+		ic      *imageCache
+	)
+
+	hashOf := func(s string) v1.Hash {
+		h, _, err := v1.SHA256(strings.NewReader(s))
+		Expect(err).NotTo(HaveOccurred())
+
+		return h
+	}
+
+	// addEntry adds a tree holding size bytes, last used at lastUsed, and references it from imageName.
+	addEntry := func(imageName string, size int, lastUsed time.Time) v1.Hash {
+		GinkgoHelper()
+
+		h := hashOf(imageName)
+		dir := ic.entryDir(h)
+
+		Expect(os.MkdirAll(filepath.Join(dir, "fs"), os.ModeDir|0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "fs", "file"), make([]byte, size), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(baseDir, cacheRefsDir), os.ModeDir|0755)).To(Succeed())
+		Expect(ic.writeRef(imageName, h)).To(Succeed())
+		Expect(os.Chtimes(dir, lastUsed, lastUsed)).To(Succeed())
+
+		return h
+	}
+
+	now := time.Now()
+
+	BeforeEach(func() {
+		baseDir = GinkgoT().TempDir()
+		ic = NewImageCache(baseDir, GinkgoLogr).(*imageCache)
+
+		Expect(os.MkdirAll(filepath.Join(baseDir, cacheLocksDir), os.ModeDir|0755)).To(Succeed())
+	})
+
+	It("should do nothing if the cache does not exist", func() {
+		Expect(
+			NewImageCache(filepath.Join(baseDir, "missing"), GinkgoLogr).GarbageCollect(nil, 0),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should remove all unreferenced images if the maximum size is 0", func() {
+		old := addEntry("old", 10, now.Add(-time.Hour))
+		recent := addEntry("recent", 10, now)
+		kept := addEntry("kept", 10, now.Add(-2*time.Hour))
+
+		Expect(ic.GarbageCollect([]string{"kept", "not-in-cache"}, 0)).To(Succeed())
+
+		Expect(ic.entryDir(old)).NotTo(BeADirectory())
+		Expect(ic.entryDir(recent)).NotTo(BeADirectory())
+		Expect(ic.entryDir(kept)).To(BeADirectory())
+
+		refsDir := filepath.Join(baseDir, cacheRefsDir)
+
+		Expect(filepath.Join(refsDir, refFileName("old"))).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(refsDir, refFileName("recent"))).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(refsDir, refFileName("kept"))).To(BeARegularFile())
+	})
+
+	It("should remove the least recently used images first", func() {
+		oldest := addEntry("oldest", 10, now.Add(-2*time.Hour))
+		old := addEntry("old", 10, now.Add(-time.Hour))
+		recent := addEntry("recent", 10, now)
+
+		Expect(ic.GarbageCollect(nil, 15)).To(Succeed())
+
+		Expect(ic.entryDir(oldest)).NotTo(BeADirectory())
+		Expect(ic.entryDir(old)).NotTo(BeADirectory())
+		Expect(ic.entryDir(recent)).To(BeADirectory())
+	})
+
+	It("should not remove images used by a worker", func() {
+		h := addEntry("in-use", 10, now)
+
+		lock, err := ic.lock(h, "", syscall.LOCK_SH)
+		Expect(err).NotTo(HaveOccurred())
+		defer lock.Close()
+
+		Expect(ic.GarbageCollect(nil, 0)).To(Succeed())
+		Expect(ic.entryDir(h)).To(BeADirectory())
+	})
+
+	It("should remove images released by Unmount", func() {
+		const imageName = "example.com/org/image:tag"
+
+		img, err := crane.Append(empty.Image, "testdata/archive.tar")
+		Expect(err).NotTo(HaveOccurred())
+
+		h, err := img.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		fsDir, err := ic.Mount(context.Background(), imageName, h.String(), func() (v1.Image, error) { return img, nil })
+		Expect(err).NotTo(HaveOccurred())
+
+		// The files in the archive are empty
+		Expect(os.WriteFile(filepath.Join(fsDir, "file"), make([]byte, 10), 0644)).To(Succeed())
+
+		Expect(ic.GarbageCollect(nil, 0)).To(Succeed())
+		Expect(ic.entryDir(h)).To(BeADirectory())
+
+		ic.Unmount()
+
+		Expect(ic.GarbageCollect(nil, 0)).To(Succeed())
+		Expect(ic.entryDir(h)).NotTo(BeADirectory())
+		Expect(filepath.Join(baseDir, cacheRefsDir, refFileName(imageName))).NotTo(BeAnExistingFile())
+	})
+
+	It("should remove stale temporary directories", func() {
+		stale := filepath.Join(baseDir, cacheTmpDir, "stale")
+		fresh := filepath.Join(baseDir, cacheTmpDir, "fresh")
+
+		Expect(os.MkdirAll(stale, os.ModeDir|0755)).To(Succeed())
+		Expect(os.MkdirAll(fresh, os.ModeDir|0755)).To(Succeed())
+
+		staleTime := now.Add(-2 * cacheTmpMaxAge)
+		Expect(os.Chtimes(stale, staleTime, staleTime)).To(Succeed())
+
+		Expect(ic.GarbageCollect(nil, 0)).To(Succeed())
+
+		Expect(stale).NotTo(BeADirectory())
+		Expect(fresh).To(BeADirectory())
+	})
+
+	It("should ignore unexpected entries", func() {
+		Expect(os.MkdirAll(filepath.Join(baseDir, "sha256", "not-a-hash"), os.ModeDir|0755)).To(Succeed())
+
+		Expect(ic.GarbageCollect(nil, 0)).To(Succeed())
+	})
+
+	It("should return an error if a reference is invalid", func() {
+		Expect(os.MkdirAll(filepath.Join(baseDir, cacheRefsDir), os.ModeDir|0755)).To(Succeed())
+
+		Expect(
+			os.WriteFile(filepath.Join(baseDir, cacheRefsDir, refFileName("image")), []byte("invalid"), 0644),
+		).To(
+			Succeed(),
+		)
+
+		Expect(ic.GarbageCollect([]string{"image"}, 0)).To(MatchError(ContainSubstring("invalid reference")))
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/localimage"
 )
 
 //go:generate mockgen -source=imagemounter.go -package=worker -destination=mock_imagemounter.go
@@ -11,3 +12,25 @@ import (
 type ImageMounter interface {
 	MountImage(ctx context.Context, imageName string, cfg *kmmv1beta1.ModuleConfig) (string, error)
 }
+
+type imageMounterSelector struct {
+	local  ImageMounter
+	remote ImageMounter
+}
+
+// NewImageMounterSelector returns an ImageMounter that mounts local images (oci: and oci-archive: references) with
+// local, and all other images with remote.
+func NewImageMounterSelector(local, remote ImageMounter) ImageMounter {
+	return &imageMounterSelector{
+		local:  local,
+		remote: remote,
+	}
+}
+
+func (ims *imageMounterSelector) MountImage(ctx context.Context, imageName string, cfg *kmmv1beta1.ModuleConfig) (string, error) {
+	if localimage.IsLocal(imageName) {
+		return ims.local.MountImage(ctx, imageName, cfg)
+	}
+
+	return ims.remote.MountImage(ctx, imageName, cfg)
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("imageMounterSelector_MountImage", func() {
+	var (
+		local  *MockImageMounter
+		remote *MockImageMounter
+		ims    ImageMounter
+	)
+
+	ctx := context.Background()
+	cfg := &kmmv1beta1.ModuleConfig{}
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		local = NewMockImageMounter(ctrl)
+		remote = NewMockImageMounter(ctrl)
+		ims = NewImageMounterSelector(local, remote)
+	})
+
+	It("should use the local mounter for local images", func() {
+		const imageName = "oci:/some/path"
+
+		local.EXPECT().MountImage(ctx, imageName, cfg).Return("/local", nil)
+
+		Expect(ims.MountImage(ctx, imageName, cfg)).To(Equal("/local"))
+	})
+
+	It("should use the remote mounter for other images", func() {
+		const imageName = "quay.io/org/image:tag"
+
+		remote.EXPECT().MountImage(ctx, imageName, cfg).Return("/remote", nil)
+
+		Expect(ims.MountImage(ctx, imageName, cfg)).To(Equal("/remote"))
+	})
+})
@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+)
+
+// Constants of the kmod index format, used by libkmod for modules.dep.bin.
+const (
+	kmodIndexMagic   uint32 = 0xB007F457
+	kmodIndexVersion uint32 = 0x00020001
+
+	kmodIndexNodeValues uint32 = 0x40000000
+	kmodIndexNodeChilds uint32 = 0x20000000
+)
+
+// kmodIndexNode is a node of the trie stored in kmod index files.
+// Nodes are written without prefixes: each arc holds a single character.
+type kmodIndexNode struct {
+	children map[byte]*kmodIndexNode
+	values   []string
+}
+
+// encodeKmodIndex returns a kmod index file in which each key has a single value with priority 0.
+// Keys must not contain NUL characters.
+func encodeKmodIndex(entries map[string]string) []byte {
+	root := &kmodIndexNode{}
+
+	for key, value := range entries {
+		node := root
+
+		for i := 0; i < len(key); i++ {
+			if node.children == nil {
+				node.children = make(map[byte]*kmodIndexNode)
+			}
+
+			child := node.children[key[i]]
+			if child == nil {
+				child = &kmodIndexNode{}
+				node.children[key[i]] = child
+			}
+
+			node = child
+		}
+
+		node.values = append(node.values, value)
+	}
+
+	buf := &bytes.Buffer{}
+
+	// The root offset is written once the whole trie is.
+	writeUint32(buf, kmodIndexMagic)
+	writeUint32(buf, kmodIndexVersion)
+	writeUint32(buf, 0)
+
+	rootOffset := writeKmodIndexNode(buf, root)
+
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b[8:], rootOffset)
+
+	return b
+}
+
+// writeKmodIndexNode writes the children of node before node itself, so that their offsets are known.
+// It returns the offset of node, along with the flags describing its fields.
+func writeKmodIndexNode(buf *bytes.Buffer, node *kmodIndexNode) uint32 {
+	chars := make([]byte, 0, len(node.children))
+
+	for c := range node.children {
+		chars = append(chars, c)
+	}
+
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+
+	childOffsets := make(map[byte]uint32, len(chars))
+
+	for _, c := range chars {
+		childOffsets[c] = writeKmodIndexNode(buf, node.children[c])
+	}
+
+	offset := uint32(buf.Len())
+
+	if len(chars) > 0 {
+		offset |= kmodIndexNodeChilds
+
+		first, last := chars[0], chars[len(chars)-1]
+
+		buf.WriteByte(first)
+		buf.WriteByte(last)
+
+		for c := int(first); c <= int(last); c++ {
+			// Missing children have offset 0.
+			writeUint32(buf, childOffsets[byte(c)])
+		}
+	}
+
+	if len(node.values) > 0 {
+		offset |= kmodIndexNodeValues
+
+		writeUint32(buf, uint32(len(node.values)))
+
+		for _, v := range node.values {
+			writeUint32(buf, 0)
+			buf.WriteString(v)
+			buf.WriteByte(0)
+		}
+	}
+
+	return offset
+}
+
+func writeUint32(buf *bytes.Buffer, v uint32) {
+	_ = binary.Write(buf, binary.BigEndian, v)
+}
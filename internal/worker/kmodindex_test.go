@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// lookupKmodIndex returns the first value of key in a kmod index, like libkmod's index_search.
+// Prefixes are not supported.
+func lookupKmodIndex(b []byte, key string) string {
+	GinkgoHelper()
+
+	Expect(len(b)).To(BeNumerically(">=", 12))
+	Expect(binary.BigEndian.Uint32(b)).To(Equal(kmodIndexMagic))
+	Expect(binary.BigEndian.Uint32(b[4:])).To(Equal(kmodIndexVersion))
+
+	offset := binary.BigEndian.Uint32(b[8:])
+
+	for i := 0; ; i++ {
+		pos := offset &^ (kmodIndexNodeValues | kmodIndexNodeChilds)
+
+		if i == len(key) {
+			if offset&kmodIndexNodeChilds != 0 {
+				first, last := b[pos], b[pos+1]
+				pos += 2 + 4*uint32(last-first+1)
+			}
+
+			if offset&kmodIndexNodeValues == 0 {
+				return ""
+			}
+
+			// Skip the value count and the priority of the first value.
+			value := b[pos+8:]
+
+			return string(value[:bytes.IndexByte(value, 0)])
+		}
+
+		if offset&kmodIndexNodeChilds == 0 {
+			return ""
+		}
+
+		first, last := b[pos], b[pos+1]
+		if key[i] < first || key[i] > last {
+			return ""
+		}
+
+		if offset = binary.BigEndian.Uint32(b[pos+2+4*uint32(key[i]-first):]); offset == 0 {
+			return ""
+		}
+	}
+}
+
+var _ = Describe("encodeKmodIndex", func() {
+	It("should encode an empty index", func() {
+		b := encodeKmodIndex(nil)
+
+		Expect(lookupKmodIndex(b, "a")).To(BeEmpty())
+	})
+
+	It("should make all keys searchable", func() {
+		entries := map[string]string{
+			"a":     "a.ko:",
+			"ab":    "ab.ko: a.ko",
+			"ac":    "ac.ko:",
+			"b_c":   "b_c.ko: ab.ko a.ko",
+			"zz":    "zz.ko:",
+			"kmm_a": "extra/kmm_a.ko.xz:",
+		}
+
+		b := encodeKmodIndex(entries)
+
+		for k, v := range entries {
+			Expect(lookupKmodIndex(b, k)).To(Equal(v), "key %s", k)
+		}
+
+		Expect(lookupKmodIndex(b, "")).To(BeEmpty())
+		Expect(lookupKmodIndex(b, "b")).To(BeEmpty())
+		Expect(lookupKmodIndex(b, "abc")).To(BeEmpty())
+		Expect(lookupKmodIndex(b, "unknown")).To(BeEmpty())
+	})
+})
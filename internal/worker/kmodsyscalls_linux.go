@@ -0,0 +1,23 @@
+package worker
+
+import "golang.org/x/sys/unix"
+
+type kmodSyscallsImpl struct{}
+
+func (kmodSyscallsImpl) DeleteModule(name string, force bool) error {
+	flags := unix.O_NONBLOCK
+
+	if force {
+		flags |= unix.O_TRUNC
+	}
+
+	return unix.DeleteModule(name, flags)
+}
+
+func (kmodSyscallsImpl) FinitModule(fd int, params []string) error {
+	return unix.FinitModule(fd, kernelModuleParams(params), 0)
+}
+
+func (kmodSyscallsImpl) InitModule(image []byte, params []string) error {
+	return unix.InitModule(image, kernelModuleParams(params))
+}
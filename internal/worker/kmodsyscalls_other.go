@@ -0,0 +1,21 @@
+//go:build !linux
+
+package worker
+
+import "errors"
+
+var errKmodSyscallsUnsupported = errors.New("kernel module syscalls are only supported on Linux")
+
+type kmodSyscallsImpl struct{}
+
+func (kmodSyscallsImpl) DeleteModule(string, bool) error {
+	return errKmodSyscallsUnsupported
+}
+
+func (kmodSyscallsImpl) FinitModule(int, []string) error {
+	return errKmodSyscallsUnsupported
+}
+
+func (kmodSyscallsImpl) InitModule([]byte, []string) error {
+	return errKmodSyscallsUnsupported
+}
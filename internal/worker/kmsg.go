@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// kmsgRecordMaxSize is larger than the biggest record the kernel returns in a single read(2) on /dev/kmsg.
+const kmsgRecordMaxSize = 8192
+
+// ReadKernelLog returns the last n messages of the kernel log, formatted like dmesg(1) does.
+// path is read without blocking, one record per read(2), as documented in the kernel's dev-kmsg ABI.
+func ReadKernelLog(path string, n int) ([]string, error) {
+	// Use raw system calls; os.File would register the character device with the runtime poller and block once all
+	// records are read, instead of returning EAGAIN.
+	fd, err := syscall.Open(path, syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer syscall.Close(fd)
+
+	var (
+		buf   = make([]byte, kmsgRecordMaxSize)
+		lines = make([]string, 0, n)
+	)
+
+	for {
+		c, err := syscall.Read(fd, buf)
+		if err != nil {
+			if errors.Is(err, syscall.EAGAIN) {
+				break
+			}
+
+			// The record we were about to read was overwritten; the next read returns the oldest available record.
+			if errors.Is(err, syscall.EPIPE) {
+				continue
+			}
+
+			return nil, fmt.Errorf("could not read %s: %v", path, err)
+		}
+
+		if c == 0 {
+			break
+		}
+
+		for _, record := range bytes.Split(buf[:c], []byte{'\n'}) {
+			line, ok := parseKmsgRecord(string(record))
+			if !ok {
+				continue
+			}
+
+			if len(lines) == n {
+				lines = lines[1:]
+			}
+
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, nil
+}
+
+// parseKmsgRecord formats a "priority,sequence,timestamp,flags;message" record.
+// It returns false for empty lines, continuation lines and malformed records.
+func parseKmsgRecord(record string) (string, bool) {
+	prefix, msg, ok := strings.Cut(record, ";")
+	if !ok {
+		return "", false
+	}
+
+	fields := strings.Split(prefix, ",")
+	if len(fields) < 4 {
+		return "", false
+	}
+
+	usec, err := strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("[%5d.%06d] %s", usec/1e6, usec%1e6, msg), true
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReadKernelLog", func() {
+	It("should return an error if the file does not exist", func() {
+		_, err := ReadKernelLog("/non/existent/path", 10)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the last formatted messages", func() {
+		const contents = `6,1,1000000,-;first message
+6,2,2000001,-;second message
+ SUBSYSTEM=pci
+ DEVICE=+pci:0000:00:01.0
+invalid
+3,3,123456789,-;third message
+`
+
+		path := filepath.Join(GinkgoT().TempDir(), "kmsg")
+
+		Expect(
+			os.WriteFile(path, []byte(contents), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			ReadKernelLog(path, 2),
+		).To(
+			Equal([]string{
+				"[    2.000001] second message",
+				"[  123.456789] third message",
+			}),
+		)
+	})
+})
+
+var _ = Describe("parseKmsgRecord", func() {
+	DescribeTable(
+		"should reject invalid records",
+		func(record string) {
+			_, ok := parseKmsgRecord(record)
+			Expect(ok).To(BeFalse())
+		},
+		Entry("empty", ""),
+		Entry("continuation line", " SUBSYSTEM=pci"),
+		Entry("missing fields", "6,1;message"),
+		Entry("invalid timestamp", "6,1,abc,-;message"),
+	)
+})
@@ -0,0 +1,280 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/go-logr/logr"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/validate"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/localimage"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/platform"
+	"github.com/moby/moby/pkg/archive"
+)
+
+// ociRefNameAnnotation holds the tag of a manifest in an OCI layout's index.
+const ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+
+type localImageMounter struct {
+	cache  ImageCache
+	logger logr.Logger
+}
+
+// NewLocalImageMounter returns an ImageMounter that reads images from OCI layout directories and OCI archives present
+// on the node, for use when no registry is reachable.
+// Image digests are verified before the image is unpacked.
+func NewLocalImageMounter(cache ImageCache, logger logr.Logger) ImageMounter {
+	return &localImageMounter{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+func (lim *localImageMounter) MountImage(ctx context.Context, imageName string, cfg *kmmv1beta1.ModuleConfig) (string, error) {
+	ref, err := localimage.Parse(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	if cfg.ImageVerification != nil {
+		return "", NewError(ReasonImageVerification, errors.New("signature verification is not supported for local images"))
+	}
+
+	p, err := imagePlatform(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.Transport == localimage.TransportOCIArchive {
+		return lim.mountArchive(ctx, imageName, ref, *p)
+	}
+
+	lp, err := layout.FromPath(ref.Path)
+	if err != nil {
+		return "", fmt.Errorf("could not read the OCI layout at %s: %v", ref.Path, err)
+	}
+
+	desc, err := findLayoutManifest(lp, ref, *p)
+	if err != nil {
+		return "", err
+	}
+
+	return lim.cache.Mount(ctx, imageName, desc.Digest.String(), lim.imageGetter(lp, ref, desc.Digest))
+}
+
+// mountArchive mounts the image of an OCI archive.
+// The image digest found in an archive is recorded under the archive's digest, so that an unchanged archive is not
+// extracted again if its image is still in the cache.
+func (lim *localImageMounter) mountArchive(ctx context.Context, imageName string, ref *localimage.Reference, p v1.Platform) (string, error) {
+	logger := lim.logger.WithValues("image name", imageName)
+
+	archiveDigest, err := fileDigest(ref.Path)
+	if err != nil {
+		return "", err
+	}
+
+	archiveRef := archiveRefName(imageName, archiveDigest, p)
+
+	digest, err := lim.cache.Lookup(archiveRef)
+	if err != nil {
+		return "", err
+	}
+
+	var tmpDir string
+
+	defer func() {
+		if tmpDir != "" {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+
+	extract := func() (layout.Path, error) {
+		var err error
+
+		if tmpDir, err = os.MkdirTemp("", "oci-archive-"); err != nil {
+			return "", fmt.Errorf("could not create a temporary directory: %v", err)
+		}
+
+		logger.V(1).Info("Extracting archive", "path", ref.Path, "destination", tmpDir)
+
+		if err = extractArchive(ref.Path, tmpDir); err != nil {
+			return "", err
+		}
+
+		lp, err := layout.FromPath(tmpDir)
+		if err != nil {
+			return "", fmt.Errorf("could not read the OCI layout in %s: %v", ref.Path, err)
+		}
+
+		return lp, nil
+	}
+
+	var getImage func() (v1.Image, error)
+
+	if digest == "" {
+		lp, err := extract()
+		if err != nil {
+			return "", err
+		}
+
+		desc, err := findLayoutManifest(lp, ref, p)
+		if err != nil {
+			return "", err
+		}
+
+		digest = desc.Digest.String()
+		getImage = lim.imageGetter(lp, ref, desc.Digest)
+	} else {
+		logger.Info("Archive unchanged since it was last extracted", "archive digest", archiveDigest, "digest", digest)
+
+		h, err := v1.NewHash(digest)
+		if err != nil {
+			return "", fmt.Errorf("invalid digest %q recorded for %s: %v", digest, ref.Path, err)
+		}
+
+		// Only called if the image was removed from the cache since.
+		getImage = func() (v1.Image, error) {
+			lp, err := extract()
+			if err != nil {
+				return nil, err
+			}
+
+			return lim.imageGetter(lp, ref, h)()
+		}
+	}
+
+	fsDir, err := lim.cache.Mount(ctx, imageName, digest, getImage)
+	if err != nil {
+		return "", err
+	}
+
+	if err = lim.cache.Tag(archiveRef, digest); err != nil {
+		return "", err
+	}
+
+	return fsDir, nil
+}
+
+// imageGetter returns a function that reads the image with digest h from lp and verifies its digests.
+func (lim *localImageMounter) imageGetter(lp layout.Path, ref *localimage.Reference, h v1.Hash) func() (v1.Image, error) {
+	return func() (v1.Image, error) {
+		img, err := lp.Image(h)
+		if err != nil {
+			return nil, fmt.Errorf("could not read image %s from %s: %v", h, ref.Path, err)
+		}
+
+		lim.logger.V(1).Info("Verifying image digests", "path", ref.Path, "digest", h)
+
+		if err = validate.Image(img); err != nil {
+			return nil, fmt.Errorf("image %s in %s is corrupted: %v", h, ref.Path, err)
+		}
+
+		return img, nil
+	}
+}
+
+// archiveRefName returns the name under which the digest of the image selected for p in an archive is recorded.
+func archiveRefName(imageName string, archiveDigest v1.Hash, p v1.Platform) string {
+	return fmt.Sprintf("%s#%s#%s", imageName, archiveDigest, p.String())
+}
+
+func fileDigest(path string) (v1.Hash, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return v1.Hash{}, fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	h, _, err := v1.SHA256(fd)
+	if err != nil {
+		return v1.Hash{}, fmt.Errorf("could not compute the digest of %s: %v", path, err)
+	}
+
+	return h, nil
+}
+
+func extractArchive(path, dstDir string) error {
+	fd, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	id := idtools.CurrentIdentity()
+
+	if err = archive.Untar(fd, dstDir, &archive.TarOptions{ChownOpts: &id}); err != nil {
+		return fmt.Errorf("could not extract %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// findLayoutManifest returns the descriptor of the image designated by ref in the layout's index.
+// If ref has neither a tag nor a digest, the index must contain exactly one image.
+// If ref designates a multi-architecture index, the image matching p is selected from it.
+func findLayoutManifest(lp layout.Path, ref *localimage.Reference, p v1.Platform) (*v1.Descriptor, error) {
+	idx, err := lp.ImageIndex()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the index of %s: %v", ref.Path, err)
+	}
+
+	im, err := idx.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the index manifest of %s: %v", ref.Path, err)
+	}
+
+	matches := make([]v1.Descriptor, 0, 1)
+
+	for _, d := range im.Manifests {
+		switch {
+		case ref.Digest != "":
+			if d.Digest.String() != ref.Digest {
+				continue
+			}
+		case ref.Tag != "":
+			if d.Annotations[ociRefNameAnnotation] != ref.Tag {
+				continue
+			}
+		}
+
+		matches = append(matches, d)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no image matching %q in %s", ref.Tag+ref.Digest, ref.Path)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%d images match %q in %s; set a tag or a digest", len(matches), ref.Tag+ref.Digest, ref.Path)
+	}
+
+	if matches[0].MediaType.IsIndex() {
+		child, err := idx.ImageIndex(matches[0].Digest)
+		if err != nil {
+			return nil, fmt.Errorf("could not read index %s from %s: %v", matches[0].Digest, ref.Path, err)
+		}
+
+		childManifest, err := child.IndexManifest()
+		if err != nil {
+			return nil, fmt.Errorf("could not read the manifest of index %s from %s: %v", matches[0].Digest, ref.Path, err)
+		}
+
+		desc, err := platform.SelectManifest(childManifest, p)
+		if err != nil {
+			return nil, fmt.Errorf("could not select an image from index %s in %s: %v", matches[0].Digest, ref.Path, err)
+		}
+
+		matches[0] = *desc
+	}
+
+	if !matches[0].MediaType.IsImage() {
+		return nil, fmt.Errorf("%s in %s has unsupported media type %s", matches[0].Digest, ref.Path, matches[0].MediaType)
+	}
+
+	return &matches[0], nil
+}
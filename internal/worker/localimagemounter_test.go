@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/moby/moby/pkg/archive"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("localImageMounter_MountImage", func() {
+	var (
+		imagesDir  string
+		layoutDir  string
+		lim        ImageMounter
+		srcImg     v1.Image
+		srcDigest  v1.Hash
+		otherImage v1.Image
+	)
+
+	ctx := context.Background()
+	cfg := &kmmv1beta1.ModuleConfig{}
+
+	BeforeEach(func() {
+		var err error
+
+		srcImg, err = crane.Append(empty.Image, "testdata/archive.tar")
+		Expect(err).NotTo(HaveOccurred())
+
+		srcDigest, err = srcImg.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		otherImage, err = crane.Append(empty.Image, "testdata/config.yaml")
+		Expect(err).NotTo(HaveOccurred())
+
+		layoutDir = GinkgoT().TempDir()
+
+		lp, err := layout.Write(layoutDir, empty.Index)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			lp.AppendImage(srcImg, layout.WithAnnotations(map[string]string{ociRefNameAnnotation: "v1"})),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			lp.AppendImage(otherImage, layout.WithAnnotations(map[string]string{ociRefNameAnnotation: "v2"})),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		imagesDir = GinkgoT().TempDir()
+		lim = NewLocalImageMounter(NewImageCache(imagesDir, GinkgoLogr), GinkgoLogr)
+	})
+
+	expectImage := func(res string) {
+		GinkgoHelper()
+
+		imgRoot := filepath.Join(imagesDir, srcDigest.Algorithm, srcDigest.Hex, "fs")
+		Expect(res).To(Equal(imgRoot))
+		Expect(filepath.Join(imgRoot, "subdir", "subsubdir", "c")).To(BeARegularFile())
+	}
+
+	It("should mount an image selected by tag from an OCI layout", func() {
+		imageName := "oci:" + layoutDir + ":v1"
+
+		res, err := lim.MountImage(ctx, imageName, cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		expectImage(res)
+	})
+
+	writeArchive := func() string {
+		GinkgoHelper()
+
+		rd, err := archive.Tar(layoutDir, archive.Uncompressed)
+		Expect(err).NotTo(HaveOccurred())
+		defer rd.Close()
+
+		archivePath := filepath.Join(GinkgoT().TempDir(), "image.tar")
+
+		fd, err := os.Create(archivePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = io.Copy(fd, rd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fd.Close()).To(Succeed())
+
+		return archivePath
+	}
+
+	It("should mount an image selected by digest from an OCI archive", func() {
+		imageName := "oci-archive:" + writeArchive() + "@" + srcDigest.String()
+
+		res, err := lim.MountImage(ctx, imageName, cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		expectImage(res)
+
+		// The tree is unpacked again if it was removed, even if the archive did not change.
+		Expect(os.RemoveAll(filepath.Join(imagesDir, srcDigest.Algorithm))).To(Succeed())
+
+		res, err = lim.MountImage(ctx, imageName, cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		expectImage(res)
+	})
+
+	It("should not extract an unchanged archive again", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		mockCache := NewMockImageCache(ctrl)
+		lim = NewLocalImageMounter(mockCache, GinkgoLogr)
+
+		// Not an archive: extracting it would fail.
+		archivePath := filepath.Join(GinkgoT().TempDir(), "image.tar")
+		Expect(os.WriteFile(archivePath, []byte("some content"), 0644)).To(Succeed())
+
+		archiveDigest, _, err := v1.SHA256(strings.NewReader("some content"))
+		Expect(err).NotTo(HaveOccurred())
+
+		const fsDir = "/some/dir"
+
+		imageName := "oci-archive:" + archivePath
+		archiveRef := archiveRefName(imageName, archiveDigest, v1.Platform{OS: "linux", Architecture: "some-arch"})
+
+		gomock.InOrder(
+			mockCache.EXPECT().Lookup(archiveRef).Return(srcDigest.String(), nil),
+			mockCache.EXPECT().Mount(ctx, imageName, srcDigest.String(), gomock.Any()).Return(fsDir, nil),
+			mockCache.EXPECT().Tag(archiveRef, srcDigest.String()),
+		)
+
+		Expect(
+			lim.MountImage(ctx, imageName, &kmmv1beta1.ModuleConfig{Platform: "linux/some-arch"}),
+		).To(
+			Equal(fsDir),
+		)
+	})
+
+	It("should mount the image for the platform from a multi-architecture index", func() {
+		idx := mutate.AppendManifests(
+			empty.Index,
+			mutate.IndexAddendum{
+				Add:        otherImage,
+				Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "some-other-arch"}},
+			},
+			mutate.IndexAddendum{
+				Add:        srcImg,
+				Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "some-arch"}},
+			},
+		)
+
+		lp, err := layout.FromPath(layoutDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			lp.AppendIndex(idx, layout.WithAnnotations(map[string]string{ociRefNameAnnotation: "multiarch"})),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		res, err := lim.MountImage(ctx, "oci:"+layoutDir+":multiarch", &kmmv1beta1.ModuleConfig{Platform: "linux/some-arch"})
+		Expect(err).NotTo(HaveOccurred())
+
+		expectImage(res)
+	})
+
+	It("should refuse to mount local images that must be verified", func() {
+		cfg := &kmmv1beta1.ModuleConfig{
+			ImageVerification: &kmmv1beta1.ImageVerification{},
+		}
+
+		_, err := lim.MountImage(ctx, "oci:"+layoutDir+":v1", cfg)
+		Expect(ReasonFromError(err)).To(Equal(ReasonImageVerification))
+	})
+
+	It("should return an error if several images match", func() {
+		_, err := lim.MountImage(ctx, "oci:"+layoutDir, cfg)
+		Expect(err).To(MatchError(ContainSubstring("set a tag or a digest")))
+	})
+
+	It("should return an error if no image matches", func() {
+		_, err := lim.MountImage(ctx, "oci:"+layoutDir+":v3", cfg)
+		Expect(err).To(MatchError(ContainSubstring("no image matching")))
+	})
+
+	It("should return an error if a layer is corrupted", func() {
+		layers, err := srcImg.Layers()
+		Expect(err).NotTo(HaveOccurred())
+
+		layerDigest, err := layers[0].Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		blobPath := filepath.Join(layoutDir, "blobs", layerDigest.Algorithm, layerDigest.Hex)
+
+		Expect(
+			os.WriteFile(blobPath, []byte("corrupted"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		_, err = lim.MountImage(ctx, "oci:"+layoutDir+":v1", cfg)
+		Expect(err).To(MatchError(ContainSubstring("corrupted")))
+	})
+})
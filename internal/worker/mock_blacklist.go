@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: blacklist.go
+//
+// Generated by this command:
+//
+//	mockgen -source=blacklist.go -package=worker -destination=mock_blacklist.go
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlacklistManager is a mock of BlacklistManager interface.
+type MockBlacklistManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlacklistManagerMockRecorder
+}
+
+// MockBlacklistManagerMockRecorder is the mock recorder for MockBlacklistManager.
+type MockBlacklistManagerMockRecorder struct {
+	mock *MockBlacklistManager
+}
+
+// NewMockBlacklistManager creates a new mock instance.
+func NewMockBlacklistManager(ctrl *gomock.Controller) *MockBlacklistManager {
+	mock := &MockBlacklistManager{ctrl: ctrl}
+	mock.recorder = &MockBlacklistManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlacklistManager) EXPECT() *MockBlacklistManagerMockRecorder {
+	return m.recorder
+}
+
+// Path mocks base method.
+func (m *MockBlacklistManager) Path() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Path")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Path indicates an expected call of Path.
+func (mr *MockBlacklistManagerMockRecorder) Path() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Path", reflect.TypeOf((*MockBlacklistManager)(nil).Path))
+}
+
+// Remove mocks base method.
+func (m *MockBlacklistManager) Remove() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockBlacklistManagerMockRecorder) Remove() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockBlacklistManager)(nil).Remove))
+}
+
+// Write mocks base method.
+func (m *MockBlacklistManager) Write(modules []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", modules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockBlacklistManagerMockRecorder) Write(modules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockBlacklistManager)(nil).Write), modules)
+}
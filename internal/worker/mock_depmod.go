@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: depmod.go
+//
+// Generated by this command:
+//
+//	mockgen -source=depmod.go -package=worker -destination=mock_depmod.go
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDepmod is a mock of Depmod interface.
+type MockDepmod struct {
+	ctrl     *gomock.Controller
+	recorder *MockDepmodMockRecorder
+}
+
+// MockDepmodMockRecorder is the mock recorder for MockDepmod.
+type MockDepmodMockRecorder struct {
+	mock *MockDepmod
+}
+
+// NewMockDepmod creates a new mock instance.
+func NewMockDepmod(ctrl *gomock.Controller) *MockDepmod {
+	mock := &MockDepmod{ctrl: ctrl}
+	mock.recorder = &MockDepmodMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDepmod) EXPECT() *MockDepmodMockRecorder {
+	return m.recorder
+}
+
+// EnsureModulesDep mocks base method.
+func (m *MockDepmod) EnsureModulesDep(modDir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureModulesDep", modDir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureModulesDep indicates an expected call of EnsureModulesDep.
+func (mr *MockDepmodMockRecorder) EnsureModulesDep(modDir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureModulesDep", reflect.TypeOf((*MockDepmod)(nil).EnsureModulesDep), modDir)
+}
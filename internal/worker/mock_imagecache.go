@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: imagecache.go
+//
+// Generated by this command:
+//
+//	mockgen -source=imagecache.go -package=worker -destination=mock_imagecache.go
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImageCache is a mock of ImageCache interface.
+type MockImageCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageCacheMockRecorder
+}
+
+// MockImageCacheMockRecorder is the mock recorder for MockImageCache.
+type MockImageCacheMockRecorder struct {
+	mock *MockImageCache
+}
+
+// NewMockImageCache creates a new mock instance.
+func NewMockImageCache(ctrl *gomock.Controller) *MockImageCache {
+	mock := &MockImageCache{ctrl: ctrl}
+	mock.recorder = &MockImageCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageCache) EXPECT() *MockImageCacheMockRecorder {
+	return m.recorder
+}
+
+// GarbageCollect mocks base method.
+func (m *MockImageCache) GarbageCollect(keepImages []string, maxSize int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GarbageCollect", keepImages, maxSize)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GarbageCollect indicates an expected call of GarbageCollect.
+func (mr *MockImageCacheMockRecorder) GarbageCollect(keepImages, maxSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GarbageCollect", reflect.TypeOf((*MockImageCache)(nil).GarbageCollect), keepImages, maxSize)
+}
+
+// Lookup mocks base method.
+func (m *MockImageCache) Lookup(name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockImageCacheMockRecorder) Lookup(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockImageCache)(nil).Lookup), name)
+}
+
+// Mount mocks base method.
+func (m *MockImageCache) Mount(ctx context.Context, imageName, digest string, getImage func() (v1.Image, error)) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mount", ctx, imageName, digest, getImage)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Mount indicates an expected call of Mount.
+func (mr *MockImageCacheMockRecorder) Mount(ctx, imageName, digest, getImage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mount", reflect.TypeOf((*MockImageCache)(nil).Mount), ctx, imageName, digest, getImage)
+}
+
+// Tag mocks base method.
+func (m *MockImageCache) Tag(name, digest string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tag", name, digest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tag indicates an expected call of Tag.
+func (mr *MockImageCacheMockRecorder) Tag(name, digest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tag", reflect.TypeOf((*MockImageCache)(nil).Tag), name, digest)
+}
+
+// Unmount mocks base method.
+func (m *MockImageCache) Unmount() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Unmount")
+}
+
+// Unmount indicates an expected call of Unmount.
+func (mr *MockImageCacheMockRecorder) Unmount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmount", reflect.TypeOf((*MockImageCache)(nil).Unmount))
+}
@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: moduleusage.go
+//
+// Generated by this command:
+//
+//	mockgen -source=moduleusage.go -package=worker -destination=mock_moduleusage.go
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsageChecker is a mock of UsageChecker interface.
+type MockUsageChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsageCheckerMockRecorder
+}
+
+// MockUsageCheckerMockRecorder is the mock recorder for MockUsageChecker.
+type MockUsageCheckerMockRecorder struct {
+	mock *MockUsageChecker
+}
+
+// NewMockUsageChecker creates a new mock instance.
+func NewMockUsageChecker(ctrl *gomock.Controller) *MockUsageChecker {
+	mock := &MockUsageChecker{ctrl: ctrl}
+	mock.recorder = &MockUsageCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsageChecker) EXPECT() *MockUsageCheckerMockRecorder {
+	return m.recorder
+}
+
+// ModuleUsage mocks base method.
+func (m *MockUsageChecker) ModuleUsage(name string) (*ModuleUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModuleUsage", name)
+	ret0, _ := ret[0].(*ModuleUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModuleUsage indicates an expected call of ModuleUsage.
+func (mr *MockUsageCheckerMockRecorder) ModuleUsage(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModuleUsage", reflect.TypeOf((*MockUsageChecker)(nil).ModuleUsage), name)
+}
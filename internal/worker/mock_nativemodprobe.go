@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: nativemodprobe.go
+//
+// Generated by this command:
+//
+//	mockgen -source=nativemodprobe.go -package=worker -destination=mock_nativemodprobe.go
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockkmodSyscalls is a mock of kmodSyscalls interface.
+type MockkmodSyscalls struct {
+	ctrl     *gomock.Controller
+	recorder *MockkmodSyscallsMockRecorder
+}
+
+// MockkmodSyscallsMockRecorder is the mock recorder for MockkmodSyscalls.
+type MockkmodSyscallsMockRecorder struct {
+	mock *MockkmodSyscalls
+}
+
+// NewMockkmodSyscalls creates a new mock instance.
+func NewMockkmodSyscalls(ctrl *gomock.Controller) *MockkmodSyscalls {
+	mock := &MockkmodSyscalls{ctrl: ctrl}
+	mock.recorder = &MockkmodSyscallsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockkmodSyscalls) EXPECT() *MockkmodSyscallsMockRecorder {
+	return m.recorder
+}
+
+// DeleteModule mocks base method.
+func (m *MockkmodSyscalls) DeleteModule(name string, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteModule", name, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteModule indicates an expected call of DeleteModule.
+func (mr *MockkmodSyscallsMockRecorder) DeleteModule(name, force any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModule", reflect.TypeOf((*MockkmodSyscalls)(nil).DeleteModule), name, force)
+}
+
+// FinitModule mocks base method.
+func (m *MockkmodSyscalls) FinitModule(fd int, params []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinitModule", fd, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinitModule indicates an expected call of FinitModule.
+func (mr *MockkmodSyscallsMockRecorder) FinitModule(fd, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinitModule", reflect.TypeOf((*MockkmodSyscalls)(nil).FinitModule), fd, params)
+}
+
+// InitModule mocks base method.
+func (m *MockkmodSyscalls) InitModule(image []byte, params []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitModule", image, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InitModule indicates an expected call of InitModule.
+func (mr *MockkmodSyscallsMockRecorder) InitModule(image, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitModule", reflect.TypeOf((*MockkmodSyscalls)(nil).InitModule), image, params)
+}
@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: parameters.go
+//
+// Generated by this command:
+//
+//	mockgen -source=parameters.go -package=worker -destination=mock_parameters.go
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	v1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockParameterSetter is a mock of ParameterSetter interface.
+type MockParameterSetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockParameterSetterMockRecorder
+}
+
+// MockParameterSetterMockRecorder is the mock recorder for MockParameterSetter.
+type MockParameterSetterMockRecorder struct {
+	mock *MockParameterSetter
+}
+
+// NewMockParameterSetter creates a new mock instance.
+func NewMockParameterSetter(ctrl *gomock.Controller) *MockParameterSetter {
+	mock := &MockParameterSetter{ctrl: ctrl}
+	mock.recorder = &MockParameterSetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockParameterSetter) EXPECT() *MockParameterSetterMockRecorder {
+	return m.recorder
+}
+
+// SetParameters mocks base method.
+func (m *MockParameterSetter) SetParameters(cfg *v1beta1.ModuleConfig, names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParameters", cfg, names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetParameters indicates an expected call of SetParameters.
+func (mr *MockParameterSetterMockRecorder) SetParameters(cfg, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParameters", reflect.TypeOf((*MockParameterSetter)(nil).SetParameters), cfg, names)
+}
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: verifier.go
+//
+// Generated by this command:
+//
+//	mockgen -source=verifier.go -package=worker -destination=mock_verifier.go
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	v1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModuleVerifier is a mock of ModuleVerifier interface.
+type MockModuleVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockModuleVerifierMockRecorder
+}
+
+// MockModuleVerifierMockRecorder is the mock recorder for MockModuleVerifier.
+type MockModuleVerifierMockRecorder struct {
+	mock *MockModuleVerifier
+}
+
+// NewMockModuleVerifier creates a new mock instance.
+func NewMockModuleVerifier(ctrl *gomock.Controller) *MockModuleVerifier {
+	mock := &MockModuleVerifier{ctrl: ctrl}
+	mock.recorder = &MockModuleVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModuleVerifier) EXPECT() *MockModuleVerifierMockRecorder {
+	return m.recorder
+}
+
+// ModuleStatuses mocks base method.
+func (m *MockModuleVerifier) ModuleStatuses(cfg *v1beta1.ModuleConfig, fsDir string) ([]ModuleStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModuleStatuses", cfg, fsDir)
+	ret0, _ := ret[0].([]ModuleStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModuleStatuses indicates an expected call of ModuleStatuses.
+func (mr *MockModuleVerifierMockRecorder) ModuleStatuses(cfg, fsDir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModuleStatuses", reflect.TypeOf((*MockModuleVerifier)(nil).ModuleStatuses), cfg, fsDir)
+}
+
+// VerifyCompatible mocks base method.
+func (m *MockModuleVerifier) VerifyCompatible(cfg *v1beta1.ModuleConfig, fsDir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCompatible", cfg, fsDir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyCompatible indicates an expected call of VerifyCompatible.
+func (mr *MockModuleVerifierMockRecorder) VerifyCompatible(cfg, fsDir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCompatible", reflect.TypeOf((*MockModuleVerifier)(nil).VerifyCompatible), cfg, fsDir)
+}
+
+// VerifyLive mocks base method.
+func (m *MockModuleVerifier) VerifyLive(cfg *v1beta1.ModuleConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyLive", cfg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyLive indicates an expected call of VerifyLive.
+func (mr *MockModuleVerifierMockRecorder) VerifyLive(cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyLive", reflect.TypeOf((*MockModuleVerifier)(nil).VerifyLive), cfg)
+}
+
+// VerifyLoaded mocks base method.
+func (m *MockModuleVerifier) VerifyLoaded(cfg *v1beta1.ModuleConfig, fsDir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyLoaded", cfg, fsDir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyLoaded indicates an expected call of VerifyLoaded.
+func (mr *MockModuleVerifierMockRecorder) VerifyLoaded(cfg, fsDir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyLoaded", reflect.TypeOf((*MockModuleVerifier)(nil).VerifyLoaded), cfg, fsDir)
+}
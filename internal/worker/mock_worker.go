@@ -5,6 +5,7 @@
 //
 //	mockgen -source=worker.go -package=worker -destination=mock_worker.go
 //
+
 // Package worker is a generated GoMock package.
 package worker
 
@@ -53,6 +54,21 @@ func (mr *MockWorkerMockRecorder) LoadKmod(ctx, cfg, firmwareMountPath any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadKmod", reflect.TypeOf((*MockWorker)(nil).LoadKmod), ctx, cfg, firmwareMountPath)
 }
 
+// Render mocks base method.
+func (m *MockWorker) Render(ctx context.Context, cfg *v1beta1.ModuleConfig, action Action, firmwareMountPath string) (*Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Render", ctx, cfg, action, firmwareMountPath)
+	ret0, _ := ret[0].(*Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Render indicates an expected call of Render.
+func (mr *MockWorkerMockRecorder) Render(ctx, cfg, action, firmwareMountPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockWorker)(nil).Render), ctx, cfg, action, firmwareMountPath)
+}
+
 // SetFirmwareClassPath mocks base method.
 func (m *MockWorker) SetFirmwareClassPath(value string) error {
 	m.ctrl.T.Helper()
@@ -67,6 +83,35 @@ func (mr *MockWorkerMockRecorder) SetFirmwareClassPath(value any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFirmwareClassPath", reflect.TypeOf((*MockWorker)(nil).SetFirmwareClassPath), value)
 }
 
+// SetParameters mocks base method.
+func (m *MockWorker) SetParameters(cfg *v1beta1.ModuleConfig, names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParameters", cfg, names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetParameters indicates an expected call of SetParameters.
+func (mr *MockWorkerMockRecorder) SetParameters(cfg, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParameters", reflect.TypeOf((*MockWorker)(nil).SetParameters), cfg, names)
+}
+
+// Status mocks base method.
+func (m *MockWorker) Status(ctx context.Context, cfg *v1beta1.ModuleConfig, firmwareMountPath string) (*Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx, cfg, firmwareMountPath)
+	ret0, _ := ret[0].(*Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockWorkerMockRecorder) Status(ctx, cfg, firmwareMountPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockWorker)(nil).Status), ctx, cfg, firmwareMountPath)
+}
+
 // UnloadKmod mocks base method.
 func (m *MockWorker) UnloadKmod(ctx context.Context, cfg *v1beta1.ModuleConfig, firmwareMountPath string) error {
 	m.ctrl.T.Helper()
@@ -80,3 +125,31 @@ func (mr *MockWorkerMockRecorder) UnloadKmod(ctx, cfg, firmwareMountPath any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadKmod", reflect.TypeOf((*MockWorker)(nil).UnloadKmod), ctx, cfg, firmwareMountPath)
 }
+
+// VerifyFirmware mocks base method.
+func (m *MockWorker) VerifyFirmware(firmwareMountPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyFirmware", firmwareMountPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyFirmware indicates an expected call of VerifyFirmware.
+func (mr *MockWorkerMockRecorder) VerifyFirmware(firmwareMountPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyFirmware", reflect.TypeOf((*MockWorker)(nil).VerifyFirmware), firmwareMountPath)
+}
+
+// VerifyKmod mocks base method.
+func (m *MockWorker) VerifyKmod(cfg *v1beta1.ModuleConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyKmod", cfg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyKmod indicates an expected call of VerifyKmod.
+func (mr *MockWorkerMockRecorder) VerifyKmod(cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyKmod", reflect.TypeOf((*MockWorker)(nil).VerifyKmod), cfg)
+}
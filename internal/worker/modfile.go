@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+const moduleFileSuffix = ".ko"
+
+var moduleFileDecompressors = map[string]func(r io.Reader) (io.ReadCloser, error){
+	".gz": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	".xz": func(r io.Reader) (io.ReadCloser, error) {
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(xr), nil
+	},
+	".zst": func(r io.Reader) (io.ReadCloser, error) {
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.IOReadCloser(), nil
+	},
+}
+
+// moduleNameFromPath returns the normalized name of the kernel module stored at path, and false if path is not a
+// kernel module file.
+func moduleNameFromPath(path string) (string, bool) {
+	base := filepath.Base(path)
+
+	if ext := filepath.Ext(base); moduleFileDecompressors[ext] != nil {
+		base = strings.TrimSuffix(base, ext)
+	}
+
+	name, ok := strings.CutSuffix(base, moduleFileSuffix)
+	if !ok {
+		return "", false
+	}
+
+	return normalizeModuleName(name), true
+}
+
+// isCompressedModuleFile returns true if path has the extension of a compressed kernel module.
+func isCompressedModuleFile(path string) bool {
+	return moduleFileDecompressors[filepath.Ext(path)] != nil
+}
+
+// ReadModuleFile returns the contents of the kernel module file at path, decompressed if needed.
+func ReadModuleFile(path string) ([]byte, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	var r io.Reader = fd
+
+	if decompress := moduleFileDecompressors[filepath.Ext(path)]; decompress != nil {
+		rc, err := decompress(fd)
+		if err != nil {
+			return nil, fmt.Errorf("could not create a decompressor for %s: %v", path, err)
+		}
+		defer rc.Close()
+
+		r = rc
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return b, nil
+}
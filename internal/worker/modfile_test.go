@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testdataNativeModulesDir = "testdata/native/lib/modules/" + testdataKernelVersion
+
+var _ = Describe("moduleNameFromPath", func() {
+	DescribeTable(
+		"should work as expected",
+		func(path, expectedName string, expectedOK bool) {
+			name, ok := moduleNameFromPath(path)
+			Expect(ok).To(Equal(expectedOK))
+			Expect(name).To(Equal(expectedName))
+		},
+		Entry(nil, "kernel/drivers/some-module.ko", "some_module", true),
+		Entry(nil, "/some_module.ko.xz", "some_module", true),
+		Entry(nil, "some_module.ko.zst", "some_module", true),
+		Entry(nil, "some_module.ko.gz", "some_module", true),
+		Entry(nil, "some_module.ko.bz2", "", false),
+		Entry(nil, "modules.dep", "", false),
+	)
+})
+
+var _ = Describe("ReadModuleFile", func() {
+	It("should return an error if the file does not exist", func() {
+		_, err := ReadModuleFile("/non/existent/path.ko")
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable(
+		"should return an error if the file is not properly compressed",
+		func(ext string) {
+			path := filepath.Join(GinkgoT().TempDir(), "module.ko"+ext)
+
+			Expect(
+				os.WriteFile(path, []byte("not compressed"), 0644),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			_, err := ReadModuleFile(path)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry(nil, ".gz"),
+		Entry(nil, ".xz"),
+		Entry(nil, ".zst"),
+	)
+
+	DescribeTable(
+		"should decompress modules",
+		func(path, expected string) {
+			Expect(
+				ReadModuleFile(testdataNativeModulesDir + "/" + path),
+			).To(
+				Equal([]byte(expected)),
+			)
+		},
+		Entry(nil, "kernel/a.ko", "module a"),
+		Entry(nil, "kernel/b.ko.xz", "module b"),
+		Entry(nil, "kernel/c.ko.zst", "module c"),
+		Entry(nil, "kernel/d.ko.gz", "module d"),
+	)
+})
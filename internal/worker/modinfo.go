@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"strings"
+)
+
+const modInfoSection = ".modinfo"
+
+// ModInfo holds the key/value pairs found in the .modinfo ELF section of a kernel module.
+// Keys such as depends, alias or parm may appear more than once.
+type ModInfo map[string][]string
+
+// Get returns the first value for key, or an empty string if key is not present.
+func (mi ModInfo) Get(key string) string {
+	if v := mi[key]; len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+// ReadModInfo parses the .modinfo section of the kernel module at path, which may be compressed.
+func ReadModInfo(path string) (ModInfo, error) {
+	b, err := ReadModuleFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := elf.NewFile(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s as an ELF file: %v", path, err)
+	}
+
+	section := f.Section(modInfoSection)
+	if section == nil {
+		return nil, fmt.Errorf("%s: no %s section", path, modInfoSection)
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not read the %s section: %v", path, modInfoSection, err)
+	}
+
+	return parseModInfo(data), nil
+}
+
+func parseModInfo(b []byte) ModInfo {
+	mi := make(ModInfo)
+
+	for _, field := range bytes.Split(b, []byte{0}) {
+		key, value, ok := strings.Cut(string(field), "=")
+		if !ok {
+			continue
+		}
+
+		mi[key] = append(mi[key], value)
+	}
+
+	return mi
+}
+
+// normalizeModuleName returns the name under which the kernel knows a module, as dashes and underscores are
+// interchangeable in module names.
+func normalizeModuleName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
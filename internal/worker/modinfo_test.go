@@ -0,0 +1,52 @@
+package worker
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testdataKernelVersion = "6.0.0-test"
+	testdataModulesDir    = "testdata/modules/lib/modules/" + testdataKernelVersion
+)
+
+var _ = Describe("ReadModInfo", func() {
+	It("should return an error if the file does not exist", func() {
+		_, err := ReadModInfo("/non/existent/path")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the file is not an ELF file", func() {
+		_, err := ReadModInfo("testdata/config.yaml")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should parse the .modinfo section", func() {
+		mi, err := ReadModInfo(testdataModulesDir + "/extra/kmod-a.ko")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mi.Get("name")).To(Equal("kmod_a"))
+		Expect(mi.Get("version")).To(Equal("1.2.3"))
+		Expect(mi.Get("srcversion")).To(Equal("ABCDEF0123456789ABCDEF0"))
+		Expect(mi.Get("depends")).To(Equal("kmod_b"))
+		Expect(mi.Get("vermagic")).To(Equal("6.0.0-test SMP mod_unload "))
+		Expect(mi.Get("non-existent")).To(BeEmpty())
+	})
+
+	It("should parse the .modinfo section of a compressed module", func() {
+		mi, err := ReadModInfo(testdataModulesDir + "/extra/kmod_e.ko.xz")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mi.Get("name")).To(Equal("kmod_e"))
+	})
+})
+
+var _ = Describe("parseModInfo", func() {
+	It("should keep all values for repeated keys", func() {
+		mi := parseModInfo([]byte("alias=a\x00alias=b\x00\x00\x00garbage\x00name=test\x00"))
+
+		Expect(mi).To(Equal(ModInfo{
+			"alias": {"a", "b"},
+			"name":  {"test"},
+		}))
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strings"
 
 	"github.com/go-logr/logr"
 )
@@ -41,7 +42,11 @@ func (mr *modprobeRunnerImpl) Run(ctx context.Context, args ...string) error {
 	}
 
 	if err = cmd.Wait(); err != nil {
-		return fmt.Errorf("error while waiting on the command: %v", err)
+		if tail := cl.StderrTail(); len(tail) > 0 {
+			return fmt.Errorf("modprobe failed: %s: %w", strings.Join(tail, "; "), err)
+		}
+
+		return fmt.Errorf("error while waiting on the command: %w", err)
 	}
 
 	return nil
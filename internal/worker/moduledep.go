@@ -0,0 +1,266 @@
+package worker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	modulesDepFileName     = "modules.dep"
+	modulesSoftdepFileName = "modules.softdep"
+)
+
+type softdep struct {
+	pre  []string
+	post []string
+}
+
+// ModuleDeps holds the dependencies between kernel modules, as read from a modules.dep file and softdep directives.
+// All module names are normalized.
+type ModuleDeps struct {
+	// paths maps module names to the path of their file, relative to the modules directory.
+	paths map[string]string
+	// deps maps module names to their dependencies, in the modules.dep order.
+	// That order is such that each module only depends on the ones that follow it.
+	deps     map[string][]string
+	softdeps map[string]*softdep
+}
+
+func newModuleDeps() *ModuleDeps {
+	return &ModuleDeps{
+		paths:    make(map[string]string),
+		deps:     make(map[string][]string),
+		softdeps: make(map[string]*softdep),
+	}
+}
+
+// ReadModuleDeps reads modules.dep and, if it exists, modules.softdep in modDir.
+func ReadModuleDeps(modDir string) (*ModuleDeps, error) {
+	md := newModuleDeps()
+
+	depPath := filepath.Join(modDir, modulesDepFileName)
+
+	fd, err := os.Open(depPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", depPath, err)
+	}
+	defer fd.Close()
+
+	if err = md.parseModulesDep(fd); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", depPath, err)
+	}
+
+	if err = md.AddSoftdepsFromFile(filepath.Join(modDir, modulesSoftdepFileName)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return md, nil
+}
+
+// AddSoftdepsFromDir reads softdep directives from all *.conf files in dir, if it exists.
+func (md *ModuleDeps) AddSoftdepsFromDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.conf"))
+	if err != nil {
+		return fmt.Errorf("could not list configuration files in %s: %v", dir, err)
+	}
+
+	for _, p := range paths {
+		if err = md.AddSoftdepsFromFile(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddSoftdepsFromFile reads softdep directives from a modprobe.d(5) style file.
+func (md *ModuleDeps) AddSoftdepsFromFile(path string) error {
+	fd, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", path, err)
+	}
+	defer fd.Close()
+
+	if err = md.parseSoftdeps(fd); err != nil {
+		return fmt.Errorf("could not parse %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// Path returns the path of the module file relative to the modules directory, and false if the module is unknown.
+func (md *ModuleDeps) Path(name string) (string, bool) {
+	p, ok := md.paths[normalizeModuleName(name)]
+	return p, ok
+}
+
+// Dependencies returns the dependencies of name, as found in modules.dep.
+func (md *ModuleDeps) Dependencies(name string) []string {
+	return md.deps[normalizeModuleName(name)]
+}
+
+// LoadOrder returns all modules that must be loaded for name to be loaded, in the order in which they must be
+// loaded: softdep pre modules and dependencies first, then name, then softdep post modules.
+// Softdep modules that are not in modules.dep are ignored, as modprobe does.
+func (md *ModuleDeps) LoadOrder(name string) ([]string, error) {
+	name = normalizeModuleName(name)
+
+	if _, ok := md.paths[name]; !ok {
+		return nil, fmt.Errorf("module %s not found in %s", name, modulesDepFileName)
+	}
+
+	var (
+		order    = make([]string, 0)
+		done     = sets.New[string]()
+		visiting = sets.New[string]()
+	)
+
+	var visit func(mod string, optional bool) error
+
+	visit = func(mod string, optional bool) error {
+		mod = normalizeModuleName(mod)
+
+		if done.Has(mod) {
+			return nil
+		}
+
+		if visiting.Has(mod) {
+			return fmt.Errorf("dependency cycle detected involving module %s", mod)
+		}
+
+		if _, ok := md.paths[mod]; !ok {
+			if optional {
+				return nil
+			}
+
+			return fmt.Errorf("dependency %s not found in %s", mod, modulesDepFileName)
+		}
+
+		visiting.Insert(mod)
+
+		sd := md.softdeps[mod]
+
+		if sd != nil {
+			for _, pre := range sd.pre {
+				if err := visit(pre, true); err != nil {
+					return err
+				}
+			}
+		}
+
+		deps := md.deps[mod]
+
+		for i := len(deps) - 1; i >= 0; i-- {
+			if err := visit(deps[i], false); err != nil {
+				return err
+			}
+		}
+
+		visiting.Delete(mod)
+		done.Insert(mod)
+		order = append(order, mod)
+
+		if sd != nil {
+			for _, post := range sd.post {
+				if err := visit(post, true); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if err := visit(name, false); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (md *ModuleDeps) parseModulesDep(r io.Reader) error {
+	s := bufio.NewScanner(r)
+
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		modPath, depsStr, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("invalid line %q", line)
+		}
+
+		name, ok := moduleNameFromPath(modPath)
+		if !ok {
+			return fmt.Errorf("%q is not a kernel module path", modPath)
+		}
+
+		md.paths[name] = modPath
+
+		depPaths := strings.Fields(depsStr)
+		deps := make([]string, 0, len(depPaths))
+
+		for _, dp := range depPaths {
+			depName, ok := moduleNameFromPath(dp)
+			if !ok {
+				return fmt.Errorf("%q is not a kernel module path", dp)
+			}
+
+			deps = append(deps, depName)
+		}
+
+		md.deps[name] = deps
+	}
+
+	return s.Err()
+}
+
+func (md *ModuleDeps) parseSoftdeps(r io.Reader) error {
+	s := bufio.NewScanner(r)
+
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+
+		if len(fields) < 2 || fields[0] != "softdep" {
+			continue
+		}
+
+		name := normalizeModuleName(fields[1])
+
+		sd := md.softdeps[name]
+		if sd == nil {
+			sd = &softdep{}
+			md.softdeps[name] = sd
+		}
+
+		var list *[]string
+
+		for _, f := range fields[2:] {
+			switch f {
+			case "pre:":
+				list = &sd.pre
+			case "post:":
+				list = &sd.post
+			default:
+				if list == nil {
+					return fmt.Errorf("softdep for %s: %q is not preceded by pre: or post:", name, f)
+				}
+
+				*list = append(*list, normalizeModuleName(f))
+			}
+		}
+	}
+
+	return s.Err()
+}
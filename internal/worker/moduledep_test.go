@@ -0,0 +1,167 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReadModuleDeps", func() {
+	It("should return an error if modules.dep does not exist", func() {
+		_, err := ReadModuleDeps(GinkgoT().TempDir())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if modules.dep is invalid", func() {
+		dir := GinkgoT().TempDir()
+
+		Expect(
+			os.WriteFile(filepath.Join(dir, modulesDepFileName), []byte("kernel/a.ko kernel/b.ko\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		_, err := ReadModuleDeps(dir)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read modules.dep and modules.softdep", func() {
+		md, err := ReadModuleDeps(testdataNativeModulesDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		path, ok := md.Path("e")
+		Expect(ok).To(BeTrue())
+		Expect(path).To(Equal("extra/e.ko"))
+
+		_, ok = md.Path("unknown")
+		Expect(ok).To(BeFalse())
+
+		Expect(md.Dependencies("a")).To(Equal([]string{"b", "c"}))
+		Expect(md.Dependencies("c")).To(BeEmpty())
+		Expect(md.softdeps).To(HaveKeyWithValue("a", &softdep{pre: []string{"d"}}))
+	})
+})
+
+var _ = Describe("ModuleDeps_LoadOrder", func() {
+	var md *ModuleDeps
+
+	BeforeEach(func() {
+		var err error
+
+		md, err = ReadModuleDeps(testdataNativeModulesDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if the module is unknown", func() {
+		_, err := md.LoadOrder("unknown")
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable(
+		"should load dependencies and softdep pre modules first",
+		func(name string, expected []string) {
+			Expect(
+				md.LoadOrder(name),
+			).To(
+				Equal(expected),
+			)
+		},
+		Entry(nil, "c", []string{"c"}),
+		Entry(nil, "b", []string{"c", "b"}),
+		Entry(nil, "a", []string{"d", "c", "b", "a"}),
+		Entry(nil, "e", []string{"c", "b", "d", "a", "e"}),
+	)
+
+	It("should load softdep post modules after the module and ignore unknown softdeps", func() {
+		Expect(
+			md.parseSoftdeps(strings.NewReader("softdep c pre: unknown post: e\n")),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			md.LoadOrder("c"),
+		).To(
+			Equal([]string{"c", "b", "d", "a", "e"}),
+		)
+	})
+
+	It("should return an error on dependency cycles", func() {
+		Expect(
+			md.parseSoftdeps(strings.NewReader("softdep d pre: e\n")),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		_, err := md.LoadOrder("e")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if a dependency is missing", func() {
+		Expect(
+			md.parseModulesDep(strings.NewReader("extra/f.ko: extra/missing.ko\n")),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		_, err := md.LoadOrder("f")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ModuleDeps_AddSoftdepsFromDir", func() {
+	It("should only read .conf files", func() {
+		dir := GinkgoT().TempDir()
+
+		Expect(
+			os.WriteFile(filepath.Join(dir, "softdep.conf"), []byte("# comment\noptions a b=c\nsoftdep a-b pre: c-d e post: f\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(dir, "softdep.conf.bak"), []byte("softdep x pre: y\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		md := newModuleDeps()
+
+		Expect(
+			md.AddSoftdepsFromDir(dir),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(md.softdeps).To(Equal(map[string]*softdep{
+			"a_b": {pre: []string{"c_d", "e"}, post: []string{"f"}},
+		}))
+	})
+
+	It("should return an error for invalid softdep directives", func() {
+		dir := GinkgoT().TempDir()
+
+		Expect(
+			os.WriteFile(filepath.Join(dir, "softdep.conf"), []byte("softdep a b\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			newModuleDeps().AddSoftdepsFromDir(dir),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should do nothing if the directory does not exist", func() {
+		Expect(
+			newModuleDeps().AddSoftdepsFromDir("/non/existent/path"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
@@ -0,0 +1,208 @@
+package worker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ModuleUsage describes what holds a loaded kernel module.
+type ModuleUsage struct {
+	Name string
+	// RefCount is the module's reference count, as reported by the kernel.
+	RefCount int
+	// Holders are the modules that depend on this module.
+	Holders []string
+	// Processes holding device nodes created by the module, as "pid (command) path".
+	// They are only listed if the UsageChecker was created with listProcesses.
+	Processes []string
+}
+
+func (mu *ModuleUsage) InUse() bool {
+	return mu.RefCount > 0 || len(mu.Holders) > 0
+}
+
+func (mu *ModuleUsage) String() string {
+	sb := strings.Builder{}
+
+	fmt.Fprintf(&sb, "module %s has %d references", mu.Name, mu.RefCount)
+
+	if len(mu.Holders) > 0 {
+		fmt.Fprintf(&sb, "; used by modules %s", strings.Join(mu.Holders, ", "))
+	}
+
+	if len(mu.Processes) > 0 {
+		fmt.Fprintf(&sb, "; device nodes held by %s", strings.Join(mu.Processes, ", "))
+	}
+
+	return sb.String()
+}
+
+//go:generate mockgen -source=moduleusage.go -package=worker -destination=mock_moduleusage.go
+
+type UsageChecker interface {
+	// ModuleUsage returns what holds the module, or nil if the module is not loaded.
+	ModuleUsage(name string) (*ModuleUsage, error)
+}
+
+type usageChecker struct {
+	listProcesses bool
+	logger        logr.Logger
+	procDir       string
+	sysModuleDir  string
+}
+
+// NewUsageChecker returns a UsageChecker that reads the reference count and the holders of modules from sysModuleDir.
+// If listProcesses is true, it also walks the file descriptors of all processes in procDir to find those holding a
+// device node whose major number was registered under the module's name.
+func NewUsageChecker(sysModuleDir, procDir string, listProcesses bool, logger logr.Logger) UsageChecker {
+	return &usageChecker{
+		listProcesses: listProcesses,
+		logger:        logger,
+		procDir:       procDir,
+		sysModuleDir:  sysModuleDir,
+	}
+}
+
+func (uc *usageChecker) ModuleUsage(name string) (*ModuleUsage, error) {
+	name = normalizeModuleName(name)
+	modDir := filepath.Join(uc.sysModuleDir, name)
+
+	refCnt, ok, err := readSysfsValue(filepath.Join(modDir, "refcnt"))
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		// Built-in modules have no refcnt and cannot be unloaded anyway.
+		return nil, nil
+	}
+
+	mu := ModuleUsage{Name: name}
+
+	if mu.RefCount, err = strconv.Atoi(refCnt); err != nil {
+		return nil, fmt.Errorf("invalid reference count %q for module %s: %v", refCnt, name, err)
+	}
+
+	holders, err := os.ReadDir(filepath.Join(modDir, "holders"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("could not read the holders of module %s: %v", name, err)
+	}
+
+	for _, h := range holders {
+		mu.Holders = append(mu.Holders, h.Name())
+	}
+
+	if uc.listProcesses && mu.InUse() {
+		if mu.Processes, err = uc.processes(name); err != nil {
+			// The processes are only informational.
+			uc.logger.Info("Could not list the processes holding the module's devices", "module", name, "error", err)
+		}
+	}
+
+	return &mu, nil
+}
+
+// processes returns the processes that have a device node open whose major number is registered under name.
+func (uc *usageChecker) processes(name string) ([]string, error) {
+	majors, err := uc.deviceMajors(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if majors.Len() == 0 {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(uc.procDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", uc.procDir, err)
+	}
+
+	procs := make([]string, 0)
+
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+
+		pidDir := filepath.Join(uc.procDir, e.Name())
+
+		// Processes may exit or deny access while we walk them; skip them.
+		fds, err := os.ReadDir(filepath.Join(pidDir, "fd"))
+		if err != nil {
+			continue
+		}
+
+		for _, fd := range fds {
+			fdPath := filepath.Join(pidDir, "fd", fd.Name())
+
+			fi, err := os.Stat(fdPath)
+			if err != nil || fi.Mode()&fs.ModeDevice == 0 {
+				continue
+			}
+
+			st, ok := fi.Sys().(*syscall.Stat_t)
+			if !ok || !majors.Has(unix.Major(uint64(st.Rdev))) {
+				continue
+			}
+
+			comm, _ := os.ReadFile(filepath.Join(pidDir, "comm"))
+			target, _ := os.Readlink(fdPath)
+
+			procs = append(procs, fmt.Sprintf("%d (%s) %s", pid, strings.TrimSpace(string(comm)), target))
+		}
+	}
+
+	sort.Strings(procs)
+
+	return procs, nil
+}
+
+// deviceMajors returns the character and block device major numbers that are registered under name in /proc/devices.
+func (uc *usageChecker) deviceMajors(name string) (sets.Set[uint32], error) {
+	path := filepath.Join(uc.procDir, "devices")
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	majors := sets.New[uint32]()
+	scanner := bufio.NewScanner(fd)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		// Section headers ("Character devices:") and blank lines do not start with a number.
+		if len(fields) != 2 || normalizeModuleName(fields[1]) != name {
+			continue
+		}
+
+		major, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			continue
+		}
+
+		majors.Insert(uint32(major))
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return majors, nil
+}
@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("usageChecker_ModuleUsage", func() {
+	var (
+		procDir      string
+		sysModuleDir string
+	)
+
+	BeforeEach(func() {
+		procDir = GinkgoT().TempDir()
+		sysModuleDir = GinkgoT().TempDir()
+	})
+
+	addModule := func(name, refCnt string, holders ...string) {
+		GinkgoHelper()
+
+		modDir := filepath.Join(sysModuleDir, name)
+
+		Expect(os.MkdirAll(filepath.Join(modDir, "holders"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(modDir, "refcnt"), []byte(refCnt+"\n"), 0644)).To(Succeed())
+
+		for _, h := range holders {
+			Expect(os.Symlink("../../"+h, filepath.Join(modDir, "holders", h))).To(Succeed())
+		}
+	}
+
+	It("should return nil if the module is not loaded", func() {
+		Expect(
+			NewUsageChecker(sysModuleDir, procDir, false, GinkgoLogr).ModuleUsage("mod"),
+		).To(
+			BeNil(),
+		)
+	})
+
+	It("should return the reference count and the holders", func() {
+		addModule("mod_a", "2", "mod_b", "mod_c")
+
+		mu, err := NewUsageChecker(sysModuleDir, procDir, false, GinkgoLogr).ModuleUsage("mod-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mu.InUse()).To(BeTrue())
+		Expect(*mu).To(Equal(ModuleUsage{Name: "mod_a", RefCount: 2, Holders: []string{"mod_b", "mod_c"}}))
+		Expect(mu.String()).To(Equal("module mod_a has 2 references; used by modules mod_b, mod_c"))
+	})
+
+	It("should report unused modules", func() {
+		addModule("mod", "0")
+
+		mu, err := NewUsageChecker(sysModuleDir, procDir, false, GinkgoLogr).ModuleUsage("mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mu.InUse()).To(BeFalse())
+	})
+
+	It("should return an error if the reference count is invalid", func() {
+		addModule("mod", "invalid")
+
+		_, err := NewUsageChecker(sysModuleDir, procDir, false, GinkgoLogr).ModuleUsage("mod")
+		Expect(err).To(MatchError(ContainSubstring("invalid reference count")))
+	})
+
+	It("should list the processes holding the module's device nodes", func() {
+		addModule("mod", "1")
+
+		// /dev/null has major number 1.
+		devices := "Character devices:\n  1 mod\n  4 tty\n\nBlock devices:\n  8 sd\n"
+		Expect(os.WriteFile(filepath.Join(procDir, "devices"), []byte(devices), 0644)).To(Succeed())
+
+		addProcess := func(pid, comm, target string) {
+			GinkgoHelper()
+
+			fdDir := filepath.Join(procDir, pid, "fd")
+
+			Expect(os.MkdirAll(fdDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(procDir, pid, "comm"), []byte(comm+"\n"), 0644)).To(Succeed())
+			Expect(os.Symlink(target, filepath.Join(fdDir, "3"))).To(Succeed())
+		}
+
+		addProcess("123", "holder", os.DevNull)
+		addProcess("456", "other", procDir)
+		Expect(os.MkdirAll(filepath.Join(procDir, "self"), 0755)).To(Succeed())
+
+		mu, err := NewUsageChecker(sysModuleDir, procDir, true, GinkgoLogr).ModuleUsage("mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mu.Processes).To(Equal([]string{"123 (holder) " + os.DevNull}))
+		Expect(mu.String()).To(HaveSuffix("; device nodes held by 123 (holder) /dev/null"))
+	})
+
+	It("should not fail if the processes cannot be listed", func() {
+		addModule("mod", "1")
+
+		mu, err := NewUsageChecker(sysModuleDir, procDir, true, GinkgoLogr).ModuleUsage("mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mu.Processes).To(BeEmpty())
+	})
+})
@@ -0,0 +1,304 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/go-logr/logr"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+)
+
+//go:generate mockgen -source=nativemodprobe.go -package=worker -destination=mock_nativemodprobe.go
+
+type kmodSyscalls interface {
+	DeleteModule(name string, force bool) error
+	FinitModule(fd int, params []string) error
+	InitModule(image []byte, params []string) error
+}
+
+// kernelModuleParams returns params as the single string expected by init_module(2) and finit_module(2).
+// Values containing whitespace are quoted, so that the kernel does not split them.
+func kernelModuleParams(params []string) string {
+	quoted := make([]string, 0, len(params))
+
+	for _, p := range params {
+		name, value, ok := strings.Cut(p, "=")
+
+		if ok && strings.ContainsAny(value, " \t\n") && !strings.HasPrefix(value, `"`) {
+			p = name + `="` + value + `"`
+		}
+
+		quoted = append(quoted, p)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// modprobeArgs is the subset of the modprobe command-line supported by the native runner.
+type modprobeArgs struct {
+	dirName    string
+	force      bool
+	moduleName string
+	params     []string
+	remove     bool
+}
+
+func parseModprobeArgs(args []string) (*modprobeArgs, error) {
+	ma := modprobeArgs{dirName: "/"}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case ma.moduleName != "":
+			ma.params = append(ma.params, arg)
+		case arg == "--verbose":
+		case arg == "--force":
+			ma.force = true
+		case arg == "--remove":
+			ma.remove = true
+		case strings.HasPrefix(arg, "--dirname="):
+			ma.dirName = strings.TrimPrefix(arg, "--dirname=")
+		case arg == "--dirname":
+			if i++; i == len(args) {
+				return nil, errors.New("--dirname requires a value")
+			}
+
+			ma.dirName = args[i]
+		case strings.HasPrefix(arg, "--"):
+			return nil, fmt.Errorf("unsupported option %q", arg)
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			shortFlags := arg[1:]
+
+			for shortFlags != "" {
+				f := shortFlags[0]
+				shortFlags = shortFlags[1:]
+
+				switch f {
+				case 'v':
+				case 'f':
+					ma.force = true
+				case 'r':
+					ma.remove = true
+				case 'd':
+					// -d takes the rest of the argument, or the next argument, as its value
+					if shortFlags == "" {
+						if i++; i == len(args) {
+							return nil, errors.New("-d requires a value")
+						}
+
+						shortFlags = args[i]
+					}
+
+					ma.dirName = shortFlags
+					shortFlags = ""
+				default:
+					return nil, fmt.Errorf("unsupported option -%c", f)
+				}
+			}
+		default:
+			ma.moduleName = arg
+		}
+	}
+
+	if ma.moduleName == "" {
+		return nil, errors.New("no module name")
+	}
+
+	if ma.remove && len(ma.params) > 0 {
+		return nil, errors.New("module parameters cannot be passed when removing a module")
+	}
+
+	return &ma, nil
+}
+
+type nativeModprobeRunner struct {
+	confDir      string
+	logger       logr.Logger
+	sys          kmodSyscalls
+	sysModuleDir string
+}
+
+// NewNativeModprobeRunner returns a ModprobeRunner that does not need the modprobe binary.
+// It resolves dependencies using modules.dep and softdep directives, and loads and unloads modules using the
+// finit_module(2), init_module(2) and delete_module(2) system calls.
+// It supports a subset of the modprobe command-line: -v, -r, -f, -d and module parameters.
+func NewNativeModprobeRunner(logger logr.Logger) ModprobeRunner {
+	return &nativeModprobeRunner{
+		confDir:      ModprobeConfDir,
+		logger:       logger.WithName("native-modprobe"),
+		sys:          kmodSyscallsImpl{},
+		sysModuleDir: SysModuleDir,
+	}
+}
+
+func (nmr *nativeModprobeRunner) Run(ctx context.Context, args ...string) error {
+	nmr.logger.Info("Running native modprobe", "args", args)
+
+	ma, err := parseModprobeArgs(args)
+	if err != nil {
+		return fmt.Errorf("could not parse modprobe arguments %v: %v", args, err)
+	}
+
+	release, ok, err := readSysfsValue(kernelReleaseLocation)
+	if err != nil {
+		return fmt.Errorf("could not determine the running kernel release: %v", err)
+	}
+
+	if !ok {
+		return fmt.Errorf("could not determine the running kernel release: %s does not exist", kernelReleaseLocation)
+	}
+
+	modDir := filepath.Join(ma.dirName, "lib", "modules", release)
+
+	md, err := ReadModuleDeps(modDir)
+	if err != nil {
+		return fmt.Errorf("could not read module dependencies: %v", err)
+	}
+
+	if err = md.AddSoftdepsFromDir(nmr.confDir); err != nil {
+		return fmt.Errorf("could not read softdep configuration: %v", err)
+	}
+
+	if ma.remove {
+		return nmr.unload(ctx, md, ma.moduleName, ma.force)
+	}
+
+	return nmr.load(ctx, md, modDir, ma)
+}
+
+func (nmr *nativeModprobeRunner) load(ctx context.Context, md *ModuleDeps, modDir string, ma *modprobeArgs) error {
+	order, err := md.LoadOrder(ma.moduleName)
+	if err != nil {
+		return fmt.Errorf("could not determine the loading order for %s: %v", ma.moduleName, err)
+	}
+
+	nmr.logger.Info("Resolved loading order", "modules", order)
+
+	target := normalizeModuleName(ma.moduleName)
+
+	for _, name := range order {
+		if err = ctx.Err(); err != nil {
+			return fmt.Errorf("not loading %s: %v", name, err)
+		}
+
+		if nmr.isLoaded(name) {
+			nmr.logger.Info("Module already loaded", "name", name)
+			continue
+		}
+
+		var params []string
+
+		if name == target {
+			params = ma.params
+		}
+
+		// modules.dep paths are relative to the modules directory, or absolute within the base directory
+		path, _ := md.Path(name)
+
+		if filepath.IsAbs(path) {
+			path = filepath.Join(ma.dirName, path)
+		} else {
+			path = filepath.Join(modDir, path)
+		}
+
+		nmr.logger.Info("Loading module", "name", name, "path", path, "params", params)
+
+		if err = nmr.loadFile(path, params); err != nil {
+			return fmt.Errorf("could not load %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (nmr *nativeModprobeRunner) loadFile(path string, params []string) error {
+	var err error
+
+	if isCompressedModuleFile(path) {
+		var b []byte
+
+		if b, err = ReadModuleFile(path); err != nil {
+			return err
+		}
+
+		err = nmr.sys.InitModule(b, params)
+	} else {
+		var fd *os.File
+
+		if fd, err = os.Open(path); err != nil {
+			return fmt.Errorf("could not open %s: %v", path, err)
+		}
+		defer fd.Close()
+
+		err = nmr.sys.FinitModule(int(fd.Fd()), params)
+	}
+
+	if errors.Is(err, syscall.EEXIST) {
+		nmr.logger.Info("Module was loaded concurrently", "path", path)
+		return nil
+	}
+
+	return err
+}
+
+func (nmr *nativeModprobeRunner) unload(ctx context.Context, md *ModuleDeps, moduleName string, force bool) error {
+	target := normalizeModuleName(moduleName)
+
+	if !nmr.isLoaded(target) {
+		nmr.logger.Info("Module is not loaded; nothing to do", "name", target)
+		return nil
+	}
+
+	order := []string{target}
+
+	if _, ok := md.Path(target); ok {
+		loadOrder, err := md.LoadOrder(target)
+		if err != nil {
+			return fmt.Errorf("could not determine the dependencies of %s: %v", target, err)
+		}
+
+		order = make([]string, 0, len(loadOrder))
+
+		for i := len(loadOrder) - 1; i >= 0; i-- {
+			order = append(order, loadOrder[i])
+		}
+	} else {
+		nmr.logger.Info("Module not found in "+modulesDepFileName+"; removing it alone", "name", target)
+	}
+
+	nmr.logger.Info("Resolved unloading order", "modules", order)
+
+	for _, name := range order {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("not unloading %s: %v", name, err)
+		}
+
+		if !nmr.isLoaded(name) {
+			continue
+		}
+
+		nmr.logger.Info("Unloading module", "name", name)
+
+		if err := nmr.sys.DeleteModule(name, force); err != nil {
+			if name == target {
+				return fmt.Errorf("could not unload %s: %w", name, err)
+			}
+
+			// Like modprobe, only try to remove dependencies; they may be in use by other modules.
+			nmr.logger.Info(utils.WarnString("Could not unload dependency"), "name", name, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (nmr *nativeModprobeRunner) isLoaded(name string) bool {
+	_, err := os.Stat(filepath.Join(nmr.sysModuleDir, name))
+	return err == nil
+}
@@ -0,0 +1,301 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("parseModprobeArgs", func() {
+	DescribeTable(
+		"should parse valid arguments",
+		func(args []string, expected modprobeArgs) {
+			Expect(
+				parseModprobeArgs(args),
+			).To(
+				Equal(&expected),
+			)
+		},
+		Entry(nil, []string{"mod"}, modprobeArgs{dirName: "/", moduleName: "mod"}),
+		Entry(
+			nil,
+			[]string{"-vd", "/opt", "mod", "a=b", "-c"},
+			modprobeArgs{dirName: "/opt", moduleName: "mod", params: []string{"a=b", "-c"}},
+		),
+		Entry(nil, []string{"-d/opt", "-r", "mod"}, modprobeArgs{dirName: "/opt", moduleName: "mod", remove: true}),
+		Entry(nil, []string{"-rv", "mod"}, modprobeArgs{dirName: "/", moduleName: "mod", remove: true}),
+		Entry(nil, []string{"-rf", "mod"}, modprobeArgs{dirName: "/", force: true, moduleName: "mod", remove: true}),
+		Entry(nil, []string{"-r", "--force", "mod"}, modprobeArgs{dirName: "/", force: true, moduleName: "mod", remove: true}),
+		Entry(
+			nil,
+			[]string{"--verbose", "--remove", "--dirname=/opt", "mod"},
+			modprobeArgs{dirName: "/opt", moduleName: "mod", remove: true},
+		),
+		Entry(nil, []string{"--dirname", "/opt", "mod"}, modprobeArgs{dirName: "/opt", moduleName: "mod"}),
+	)
+
+	DescribeTable(
+		"should return an error for invalid arguments",
+		func(args []string) {
+			_, err := parseModprobeArgs(args)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no arguments", nil),
+		Entry("no module name", []string{"-v"}),
+		Entry("unsupported short flag", []string{"-a", "mod"}),
+		Entry("unsupported long flag", []string{"--all", "mod"}),
+		Entry("-d without value", []string{"-d"}),
+		Entry("--dirname without value", []string{"--dirname"}),
+		Entry("parameters when removing", []string{"-r", "mod", "a=b"}),
+	)
+})
+
+var _ = Describe("kernelModuleParams", func() {
+	DescribeTable(
+		"should join parameters for the kernel",
+		func(params []string, expected string) {
+			Expect(
+				kernelModuleParams(params),
+			).To(
+				Equal(expected),
+			)
+		},
+		Entry("no parameters", nil, ""),
+		Entry(nil, []string{"p1=v1", "p2"}, "p1=v1 p2"),
+		Entry(nil, []string{"p1=a b", "p2=c"}, `p1="a b" p2=c`),
+		Entry(nil, []string{`p1="a b"`}, `p1="a b"`),
+	)
+})
+
+var _ = Describe("nativeModprobeRunner_Run", func() {
+	const dirName = "testdata/native"
+
+	var (
+		ctx          = context.TODO()
+		mockSys      *MockkmodSyscalls
+		nmr          *nativeModprobeRunner
+		sysModuleDir string
+	)
+
+	BeforeEach(func() {
+		mockSys = NewMockkmodSyscalls(gomock.NewController(GinkgoT()))
+
+		kernelReleaseLocation = filepath.Join(GinkgoT().TempDir(), "osrelease")
+
+		Expect(
+			os.WriteFile(kernelReleaseLocation, []byte(testdataKernelVersion+"\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		DeferCleanup(func() {
+			kernelReleaseLocation = KernelReleaseLocation
+		})
+
+		sysModuleDir = GinkgoT().TempDir()
+
+		nmr = &nativeModprobeRunner{
+			confDir:      GinkgoT().TempDir(),
+			logger:       GinkgoLogr,
+			sys:          mockSys,
+			sysModuleDir: sysModuleDir,
+		}
+	})
+
+	markLoaded := func(names ...string) {
+		for _, n := range names {
+			Expect(
+				os.Mkdir(filepath.Join(sysModuleDir, n), 0755),
+			).NotTo(
+				HaveOccurred(),
+			)
+		}
+	}
+
+	// finitModuleWithContents checks the contents of the file passed to finit_module.
+	finitModuleWithContents := func(expected string) func(int, []string) error {
+		return func(fd int, _ []string) error {
+			defer GinkgoRecover()
+
+			b, err := os.ReadFile(fmt.Sprintf("/proc/self/fd/%d", fd))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal(expected))
+
+			return nil
+		}
+	}
+
+	It("should return an error for invalid arguments", func() {
+		Expect(
+			nmr.Run(ctx, "--all", "e"),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if modules.dep does not exist", func() {
+		Expect(
+			nmr.Run(ctx, "-d", "/non/existent/path", "e"),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the module is unknown", func() {
+		Expect(
+			nmr.Run(ctx, "-d", dirName, "unknown"),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should load all dependencies in order, decompressing modules when needed", func() {
+		gomock.InOrder(
+			mockSys.EXPECT().InitModule([]byte("module c"), nil),
+			mockSys.EXPECT().InitModule([]byte("module b"), nil),
+			mockSys.EXPECT().InitModule([]byte("module d"), nil),
+			mockSys.EXPECT().FinitModule(gomock.Any(), nil).DoAndReturn(finitModuleWithContents("module a")),
+			mockSys.EXPECT().FinitModule(gomock.Any(), []string{"p1=v1", "p2"}).DoAndReturn(finitModuleWithContents("module e")),
+		)
+
+		Expect(
+			nmr.Run(ctx, "-vd", dirName, "e", "p1=v1", "p2"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should skip loaded modules and read softdeps from the configuration directory", func() {
+		markLoaded("c", "d")
+
+		Expect(
+			os.WriteFile(filepath.Join(nmr.confDir, "e.conf"), []byte("softdep e post: b\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		gomock.InOrder(
+			mockSys.EXPECT().InitModule([]byte("module b"), nil),
+			mockSys.EXPECT().FinitModule(gomock.Any(), nil).DoAndReturn(finitModuleWithContents("module a")),
+			mockSys.EXPECT().FinitModule(gomock.Any(), nil).DoAndReturn(finitModuleWithContents("module e")),
+		)
+
+		Expect(
+			nmr.Run(ctx, "-d", dirName, "e"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should ignore EEXIST", func() {
+		mockSys.EXPECT().InitModule([]byte("module c"), nil).Return(syscall.EEXIST)
+
+		Expect(
+			nmr.Run(ctx, "-d", dirName, "c"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should stop at the first module that cannot be loaded", func() {
+		mockSys.EXPECT().InitModule([]byte("module c"), nil).Return(syscall.ENOEXEC)
+
+		Expect(
+			nmr.Run(ctx, "-d", dirName, "b"),
+		).To(
+			MatchError(syscall.ENOEXEC),
+		)
+	})
+
+	It("should do nothing when unloading a module that is not loaded", func() {
+		Expect(
+			nmr.Run(ctx, "-rd", dirName, "e"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should unload loaded modules in the reverse loading order", func() {
+		markLoaded("a", "c", "e")
+
+		gomock.InOrder(
+			mockSys.EXPECT().DeleteModule("e", false),
+			mockSys.EXPECT().DeleteModule("a", false),
+			mockSys.EXPECT().DeleteModule("c", false),
+		)
+
+		Expect(
+			nmr.Run(ctx, "-rd", dirName, "e"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should ignore errors when unloading dependencies", func() {
+		markLoaded("b", "c")
+
+		gomock.InOrder(
+			mockSys.EXPECT().DeleteModule("b", false),
+			mockSys.EXPECT().DeleteModule("c", false).Return(syscall.EBUSY),
+		)
+
+		Expect(
+			nmr.Run(ctx, "-rd", dirName, "b"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the module cannot be unloaded", func() {
+		markLoaded("b", "c")
+
+		mockSys.EXPECT().DeleteModule("b", false).Return(syscall.EBUSY)
+
+		Expect(
+			nmr.Run(ctx, "-rd", dirName, "b"),
+		).To(
+			MatchError(syscall.EBUSY),
+		)
+	})
+
+	It("should force the removal if requested", func() {
+		markLoaded("b")
+
+		mockSys.EXPECT().DeleteModule("b", true)
+
+		Expect(
+			nmr.Run(ctx, "-rfd", dirName, "b"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should unload modules that are not in modules.dep", func() {
+		markLoaded("unknown")
+
+		mockSys.EXPECT().DeleteModule("unknown", false)
+
+		Expect(
+			nmr.Run(ctx, "-rd", dirName, "unknown"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the context is done", func() {
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		Expect(
+			nmr.Run(cancelledCtx, "-d", dirName, "c"),
+		).To(
+			HaveOccurred(),
+		)
+	})
+})
@@ -0,0 +1,147 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+)
+
+// flagValue is the value of a parameter set without a value, which the kernel only accepts for boolean parameters.
+const flagValue = "1"
+
+// ParseParameters returns the values of modprobe parameters by name.
+// A parameter without a value, such as a boolean flag, has the value 1; if a parameter is set several times, the last
+// value wins, like with modprobe.
+func ParseParameters(params []string) map[string]string {
+	values := make(map[string]string, len(params))
+
+	for _, p := range params {
+		name, value, found := strings.Cut(p, "=")
+		if !found {
+			value = flagValue
+		}
+
+		values[name] = value
+	}
+
+	return values
+}
+
+// ChangedParameters returns the sorted names of the parameters in newParams that are not in oldParams or that have a
+// different value.
+// It returns false if a parameter in oldParams is not in newParams: its default value can only be restored by
+// reloading the module.
+func ChangedParameters(oldParams, newParams []string) ([]string, bool) {
+	oldValues := ParseParameters(oldParams)
+	newValues := ParseParameters(newParams)
+
+	names := make([]string, 0)
+
+	for name := range oldValues {
+		if _, ok := newValues[name]; !ok {
+			return nil, false
+		}
+	}
+
+	for name, value := range newValues {
+		if oldValue, ok := oldValues[name]; !ok || oldValue != value {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, true
+}
+
+//go:generate mockgen -source=parameters.go -package=worker -destination=mock_parameters.go
+
+type ParameterSetter interface {
+	// SetParameters writes the value that cfg sets for each parameter in names into the module's sysfs parameters
+	// directory.
+	// It returns an Error with ReasonParameterNotWritable, without changing any parameter, if one of them cannot be
+	// changed at runtime.
+	SetParameters(cfg *kmmv1beta1.ModuleConfig, names []string) error
+}
+
+type parameterSetter struct {
+	logger       logr.Logger
+	sysModuleDir string
+}
+
+// NewParameterSetter returns a ParameterSetter that writes the parameters of the modules loaded in sysModuleDir.
+func NewParameterSetter(sysModuleDir string, logger logr.Logger) ParameterSetter {
+	return &parameterSetter{
+		logger:       logger,
+		sysModuleDir: sysModuleDir,
+	}
+}
+
+func (w *worker) SetParameters(cfg *kmmv1beta1.ModuleConfig, names []string) error {
+	if err := w.ps.SetParameters(cfg, names); err != nil {
+		return WithPhase(PhaseSetParameters, err)
+	}
+
+	return nil
+}
+
+func (ps *parameterSetter) SetParameters(cfg *kmmv1beta1.ModuleConfig, names []string) error {
+	if cfg.Modprobe.RawArgs != nil {
+		return errors.New("parameters cannot be updated when RawArgs are used")
+	}
+
+	moduleName := normalizeModuleName(cfg.Modprobe.ModuleName)
+	moduleDir := filepath.Join(ps.sysModuleDir, moduleName)
+
+	if _, err := os.Stat(moduleDir); err != nil {
+		return fmt.Errorf("could not find module %s in sysfs: %v", moduleName, err)
+	}
+
+	values := ParseParameters(cfg.Modprobe.Parameters)
+	paramDir := filepath.Join(moduleDir, "parameters")
+
+	// Check all parameters first, so that they are either all updated or the module is reloaded.
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return fmt.Errorf("parameter %s is not set in the module's config", name)
+		}
+
+		fi, err := os.Stat(filepath.Join(paramDir, name))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return NewError(
+					ReasonParameterNotWritable,
+					fmt.Errorf("parameter %s of module %s is not exposed in %s", name, moduleName, paramDir),
+				)
+			}
+
+			return fmt.Errorf("could not stat parameter %s: %v", name, err)
+		}
+
+		if fi.Mode().Perm()&0222 == 0 {
+			return NewError(
+				ReasonParameterNotWritable,
+				fmt.Errorf("parameter %s of module %s is read-only", name, moduleName),
+			)
+		}
+	}
+
+	for _, name := range names {
+		path := filepath.Join(paramDir, name)
+
+		ps.logger.Info("Setting parameter", "module", moduleName, "name", name, "value", values[name])
+
+		if err := os.WriteFile(path, []byte(values[name]), 0644); err != nil {
+			return fmt.Errorf("could not write %q into %s: %v", values[name], path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ChangedParameters", func() {
+	DescribeTable(
+		"should work as expected",
+		func(oldParams, newParams, expectedNames []string, expectedOK bool) {
+			names, ok := ChangedParameters(oldParams, newParams)
+			Expect(ok).To(Equal(expectedOK))
+			Expect(names).To(Equal(expectedNames))
+		},
+		Entry("no parameters", nil, nil, []string{}, true),
+		Entry("same parameters", []string{"a=1", "b"}, []string{"b", "a=1"}, []string{}, true),
+		Entry("flag set to its value", []string{"b"}, []string{"b=1"}, []string{}, true),
+		Entry("changed and added parameters", []string{"a=1", "b=2"}, []string{"c=3", "b=3", "a=1"}, []string{"b", "c"}, true),
+		Entry("last value wins", []string{"a=1"}, []string{"a=2", "a=1"}, []string{}, true),
+		Entry("removed parameter", []string{"a=1", "b=2"}, []string{"a=2"}, nil, false),
+	)
+})
+
+var _ = Describe("worker_SetParameters", func() {
+	const moduleName = "some-module"
+
+	var (
+		paramDir     string
+		sysModuleDir string
+		w            Worker
+	)
+
+	cfg := &v1beta1.ModuleConfig{
+		Modprobe: v1beta1.ModprobeSpec{
+			ModuleName: moduleName,
+			Parameters: []string{"a=1", "b=new", "c=3", "e"},
+		},
+	}
+
+	BeforeEach(func() {
+		sysModuleDir = GinkgoT().TempDir()
+		paramDir = filepath.Join(sysModuleDir, "some_module", "parameters")
+		w = NewWorker(nil, nil, nil, nil, nil, nil, nil, nil, NewParameterSetter(sysModuleDir, GinkgoLogr), Timeouts{}, GinkgoLogr)
+
+		Expect(os.MkdirAll(paramDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(paramDir, "a"), []byte("0"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(paramDir, "b"), []byte("old"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(paramDir, "c"), []byte("0"), 0444)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(paramDir, "e"), []byte("N"), 0644)).To(Succeed())
+	})
+
+	readParam := func(name string) string {
+		GinkgoHelper()
+
+		b, err := os.ReadFile(filepath.Join(paramDir, name))
+		Expect(err).NotTo(HaveOccurred())
+
+		return string(b)
+	}
+
+	It("should write the parameters", func() {
+		Expect(w.SetParameters(cfg, []string{"a", "b"})).To(Succeed())
+		Expect(readParam("a")).To(Equal("1"))
+		Expect(readParam("b")).To(Equal("new"))
+	})
+
+	It("should enable parameters set without a value", func() {
+		Expect(w.SetParameters(cfg, []string{"e"})).To(Succeed())
+		Expect(readParam("e")).To(Equal("1"))
+	})
+
+	It("should not change any parameter if one is read-only", func() {
+		err := w.SetParameters(cfg, []string{"a", "c"})
+		Expect(ReasonFromError(err)).To(Equal(ReasonParameterNotWritable))
+		Expect(PhaseFromError(err)).To(Equal(PhaseSetParameters))
+		Expect(readParam("a")).To(Equal("0"))
+	})
+
+	It("should return an error if the parameter is not exposed in sysfs", func() {
+		cfg := &v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				Parameters: []string{"d=4"},
+			},
+		}
+
+		err := w.SetParameters(cfg, []string{"d"})
+		Expect(ReasonFromError(err)).To(Equal(ReasonParameterNotWritable))
+	})
+
+	It("should return an error if the module is not loaded", func() {
+		Expect(os.RemoveAll(filepath.Join(sysModuleDir, "some_module"))).To(Succeed())
+
+		Expect(w.SetParameters(cfg, []string{"a"})).NotTo(Succeed())
+	})
+
+	It("should return an error if RawArgs are used", func() {
+		rawCfg := &v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				RawArgs: &v1beta1.ModprobeArgs{Load: []string{"a"}},
+			},
+		}
+
+		Expect(w.SetParameters(rawCfg, []string{"a"})).NotTo(Succeed())
+	})
+})
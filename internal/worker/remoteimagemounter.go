@@ -4,174 +4,213 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
-	"os"
-	"path/filepath"
-	"sync"
 
-	"github.com/docker/docker/pkg/idtools"
 	"github.com/go-logr/logr"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mirror"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
-	"github.com/moby/moby/pkg/archive"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/platform"
 )
 
 type remoteImageMounter struct {
-	baseDir  string
+	cache    ImageCache
 	keyChain authn.Keychain
 	logger   logr.Logger
+	mirrors  []mirror.Registry
+	verifier SignatureVerifier
 }
 
-func NewRemoteImageMounter(baseDir string, keyChain authn.Keychain, logger logr.Logger) ImageMounter {
+func NewRemoteImageMounter(
+	cache ImageCache,
+	keyChain authn.Keychain,
+	verifier SignatureVerifier,
+	mirrors []mirror.Registry,
+	logger logr.Logger,
+) ImageMounter {
 	return &remoteImageMounter{
-		baseDir:  baseDir,
+		cache:    cache,
 		keyChain: keyChain,
 		logger:   logger,
+		mirrors:  mirrors,
+		verifier: verifier,
 	}
 }
 
 func (rim *remoteImageMounter) MountImage(ctx context.Context, imageName string, cfg *kmmv1beta1.ModuleConfig) (string, error) {
 	logger := rim.logger.V(1).WithValues("image name", imageName)
 
-	opts := []crane.Option{
-		crane.WithContext(ctx),
-		crane.WithAuthFromKeychain(rim.keyChain),
+	p, err := imagePlatform(cfg)
+	if err != nil {
+		return "", err
 	}
 
-	if cfg.InsecurePull {
-		logger.Info(utils.WarnString("Pulling without TLS"))
-		opts = append(opts, crane.Insecure)
-	}
+	logger.V(1).Info("Selecting platform", "platform", p.String())
 
-	logger.V(1).Info("Getting digest")
+	var (
+		errs         []error
+		opts         []crane.Option
+		remoteDigest string
+		source       string
+	)
 
-	remoteDigest, err := crane.Digest(imageName, opts...)
-	if err != nil {
-		return "", fmt.Errorf("could not get the digest for %s: %v", imageName, err)
-	}
+	// Use the first location of the image that can be resolved; the image is pulled from there.
+	for _, s := range mirror.Sources(rim.mirrors, imageName) {
+		if opts, err = rim.options(ctx, s, cfg, p); err != nil {
+			errs = append(errs, fmt.Errorf("could not get the options to pull from %s: %w", s.Image, err))
+			continue
+		}
 
-	dstDir := filepath.Join(rim.baseDir, imageName)
-	digestPath := filepath.Join(dstDir, "digest")
+		logger.V(1).Info("Getting digest", "source", s.Image)
 
-	dstDirFS := filepath.Join(dstDir, "fs")
-	cleanup := false
+		// The digest of the image for the platform, which is also the digest of the pulled image
+		if remoteDigest, err = crane.Digest(s.Image, opts...); err != nil {
+			errs = append(errs, fmt.Errorf("could not get the digest for %s: %w", s.Image, err))
+			continue
+		}
 
-	logger.Info("Reading digest file", "path", digestPath)
+		source = s.Image
+		break
+	}
+
+	if source == "" {
+		return "", errors.Join(errs...)
+	}
 
-	b, err := os.ReadFile(digestPath)
+	o := crane.GetOptions(opts...)
+
+	ref, err := name.ParseReference(source, o.Name...)
 	if err != nil {
-		if os.IsNotExist(err) {
-			cleanup = true
-		} else {
-			return "", fmt.Errorf("could not open the digest file %s: %v", digestPath, err)
-		}
-	} else {
-		logger.V(1).Info(
-			"Comparing digests",
-			"local file",
-			string(b),
-			"remote image",
-			remoteDigest,
-		)
-
-		if string(b) == remoteDigest {
-			logger.Info("Local file and remote digest are identical; skipping pull")
-			return dstDirFS, nil
-		} else {
-			logger.Info("Local file and remote digest differ; pulling image")
-			cleanup = true
-		}
+		return "", fmt.Errorf("could not parse %s: %v", source, err)
 	}
 
-	if cleanup {
-		logger.Info("Cleaning up image directory", "path", dstDir)
+	// Pull by digest, so that the image we unpack is the one that was verified.
+	digestRef := ref.Context().Digest(remoteDigest)
+
+	if iv := cfg.ImageVerification; iv != nil {
+		logger.Info("Verifying the image signature")
 
-		if err = os.RemoveAll(dstDir); err != nil {
-			return "", fmt.Errorf("could not cleanup %s: %v", dstDir, err)
+		if err = rim.verify(ctx, source, digestRef, iv, opts); err != nil {
+			return "", fmt.Errorf("could not verify %s: %w", source, err)
 		}
 	}
 
-	if err = os.MkdirAll(dstDirFS, os.ModeDir|0755); err != nil {
-		return "", fmt.Errorf("could not create the filesystem directory %s: %v", dstDirFS, err)
+	getImage := func() (v1.Image, error) {
+		img, err := crane.Pull(digestRef.String(), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("could not pull %s: %w", source, err)
+		}
+
+		return img, nil
 	}
 
-	logger.V(1).Info("Pulling image")
+	// The cache is keyed on the name used in the Module, so that it does not depend on the mirrors.
+	return rim.cache.Mount(ctx, imageName, remoteDigest, getImage)
+}
 
-	img, err := crane.Pull(imageName, opts...)
+// verify verifies the signature of the image index that source points to, if it lists digestRef, and then the one of
+// digestRef itself.
+// cosign signs the index of multi-architecture images by default, and their platform manifests only on request.
+func (rim *remoteImageMounter) verify(
+	ctx context.Context,
+	source string,
+	digestRef name.Digest,
+	iv *kmmv1beta1.ImageVerification,
+	opts []crane.Option,
+) error {
+	o := crane.GetOptions(opts...)
+
+	refs := make([]name.Digest, 0, 2)
+
+	desc, err := crane.Head(source, opts...)
 	if err != nil {
-		return "", fmt.Errorf("could not pull %s: %v", imageName, err)
+		return fmt.Errorf("could not get the descriptor of %s: %w", source, err)
 	}
 
-	errs := make(chan error, 2)
-
-	wg := sync.WaitGroup{}
-	wg.Add(2)
-
-	rd, wr := io.Pipe()
+	if desc.MediaType.IsIndex() && desc.Digest.String() != digestRef.DigestStr() {
+		indexRef := digestRef.Context().Digest(desc.Digest.String())
 
-	go func() {
-		defer wg.Done()
-		defer wr.Close()
-
-		logger.V(1).Info("Starting to export image")
+		idx, err := remote.Index(indexRef, o.Remote...)
+		if err != nil {
+			return fmt.Errorf("could not get the index %s: %w", indexRef, err)
+		}
 
-		if err := crane.Export(img, wr); err != nil {
-			errs <- err
-			return
+		im, err := idx.IndexManifest()
+		if err != nil {
+			return fmt.Errorf("could not read the index %s: %w", indexRef, err)
 		}
 
-		logger.V(1).Info("Done exporting image")
-	}()
+		// The tag may have moved since the image was resolved; only trust an index that lists the image.
+		for _, m := range im.Manifests {
+			if m.Digest.String() == digestRef.DigestStr() {
+				refs = append(refs, indexRef)
+				break
+			}
+		}
+	}
 
-	go func() {
-		defer wg.Done()
-		defer rd.Close()
+	refs = append(refs, digestRef)
 
-		id := idtools.CurrentIdentity()
+	errs := make([]error, 0, len(refs))
 
-		tarOpts := &archive.TarOptions{ChownOpts: &id}
+	for _, r := range refs {
+		rim.logger.V(1).Info("Verifying signature", "reference", r.String())
 
-		if err := archive.Untar(rd, dstDirFS, tarOpts); err != nil {
-			errs <- err
-			return
+		if err = rim.verifier.Verify(ctx, r, iv, o.Remote...); err == nil {
+			return nil
 		}
 
-		logger.V(1).Info("Done writing tar archive")
-	}()
-
-	wg.Wait()
-	close(errs)
+		errs = append(errs, err)
+	}
 
-	chErrs := make([]error, 0)
+	return errors.Join(errs...)
+}
 
-	for chErr := range errs {
-		chErrs = append(chErrs, chErr)
+// options returns the options to pull from s: the TLS settings of the mirror, or the ones of cfg for the original
+// location.
+func (rim *remoteImageMounter) options(ctx context.Context, s mirror.Source, cfg *kmmv1beta1.ModuleConfig, p *v1.Platform) ([]crane.Option, error) {
+	opts := []crane.Option{
+		crane.WithContext(ctx),
+		crane.WithAuthFromKeychain(rim.keyChain),
+		crane.WithPlatform(p),
 	}
 
-	if err = errors.Join(chErrs...); err != nil {
-		return "", fmt.Errorf("got one or more errors while writing the image: %v", err)
+	m := s.Mirror
+
+	if m == nil {
+		m = &mirror.Mirror{Insecure: cfg.InsecurePull}
 	}
 
-	if err = ctx.Err(); err != nil {
-		return "", fmt.Errorf("not writing digest file: %v", err)
+	if m.Insecure {
+		rim.logger.Info(utils.WarnString("Pulling without TLS"), "source", s.Image)
+		opts = append(opts, crane.Insecure)
 	}
 
-	logger.V(1).Info("Image written to the filesystem")
+	if m.InsecureSkipTLSVerify {
+		rim.logger.Info(utils.WarnString("Pulling without verifying the TLS certificate"), "source", s.Image)
+	}
 
-	digest, err := img.Digest()
+	rt, err := m.Transport()
 	if err != nil {
-		return "", fmt.Errorf("could not get the digest of the pulled image: %v", err)
+		return nil, err
 	}
 
-	digestStr := digest.String()
+	if rt != nil {
+		opts = append(opts, crane.WithTransport(rt))
+	}
 
-	logger.V(1).Info("Writing digest", "digest", digestStr)
+	return opts, nil
+}
 
-	if err = os.WriteFile(digestPath, []byte(digestStr), 0644); err != nil {
-		return "", fmt.Errorf("could not write the digest file at %s: %v", digestPath, err)
+// imagePlatform returns the platform pinned in cfg, or the platform of the node.
+func imagePlatform(cfg *kmmv1beta1.ModuleConfig) (*v1.Platform, error) {
+	if cfg.Platform == "" {
+		return platform.Runtime(), nil
 	}
 
-	return dstDirFS, nil
+	return platform.Parse(cfg.Platform)
 }
@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,12 +14,20 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/registry"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mirror"
+	"github.com/kubernetes-sigs/kernel-module-management/pkg/platform"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 )
 
@@ -138,12 +147,12 @@ var _ = Describe("imageMounter_MountImage", func() {
 				keyChain = &fakeKeyChainAndAuthenticator{token: *expectedToken}
 			}
 
-			rim := NewRemoteImageMounter(tmpDir, keyChain, GinkgoLogr)
+			rim := NewRemoteImageMounter(NewImageCache(tmpDir, GinkgoLogr), keyChain, nil, nil, GinkgoLogr)
 
 			res, err := rim.MountImage(context.Background(), remoteImageName, modConfig)
 			Expect(err).NotTo(HaveOccurred())
 
-			imgRoot := filepath.Join(tmpDir, serverURL.Host, "test", "archive:tag", "fs")
+			imgRoot := filepath.Join(tmpDir, srcDigest.Algorithm, srcDigest.Hex, "fs")
 			Expect(res).To(Equal(imgRoot))
 
 			Expect(imgRoot).To(BeADirectory())
@@ -171,36 +180,301 @@ var _ = Describe("imageMounter_MountImage", func() {
 			).To(
 				BeTrue(),
 			)
-
-			digestFilePath := filepath.Join(tmpDir, serverURL.Host, "test", "archive:tag", "digest")
-
-			Expect(os.ReadFile(digestFilePath)).To(Equal([]byte(srcDigest.String())))
 		},
 		Entry("without authentication", ""),
 		Entry("with authentication", ""),
 	)
 
-	It("should not pull if the digest file exist an has the expected value", func() {
-		tmpDir := GinkgoT().TempDir()
+	It("should mount the image with its remote digest", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		mockCache := NewMockImageCache(ctrl)
 
-		dstDir := filepath.Join(tmpDir, serverURL.Host, "test", "archive:tag")
+		mockCache.
+			EXPECT().
+			Mount(context.Background(), remoteImageName, srcDigest.String(), gomock.Any()).
+			Return("/some/path", nil)
 
-		Expect(
-			os.MkdirAll(dstDir, os.ModeDir|0755),
-		).NotTo(
-			HaveOccurred(),
+		rim := NewRemoteImageMounter(mockCache, authn.NewMultiKeychain(), nil, nil, GinkgoLogr)
+
+		res, err := rim.MountImage(context.Background(), remoteImageName, modConfig)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal("/some/path"))
+	})
+	Context("signature verification", func() {
+		var (
+			ctrl         *gomock.Controller
+			mockCache    *MockImageCache
+			mockVerifier *MockSignatureVerifier
+			rim          ImageMounter
 		)
 
-		Expect(
-			os.WriteFile(filepath.Join(dstDir, "digest"), []byte(srcDigest.String()), 0700),
-		).NotTo(
-			HaveOccurred(),
+		cfg := &kmmv1beta1.ModuleConfig{
+			InsecurePull: true,
+			ImageVerification: &kmmv1beta1.ImageVerification{
+				KeysSecret: corev1.LocalObjectReference{Name: "keys"},
+			},
+		}
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockCache = NewMockImageCache(ctrl)
+			mockVerifier = NewMockSignatureVerifier(ctrl)
+			rim = NewRemoteImageMounter(mockCache, authn.NewMultiKeychain(), mockVerifier, nil, GinkgoLogr)
+		})
+
+		It("should mount the image if its signature is valid", func() {
+			expectedRef := serverURL.Host + "/test/archive@" + srcDigest.String()
+
+			gomock.InOrder(
+				mockVerifier.
+					EXPECT().
+					Verify(context.Background(), gomock.Any(), cfg.ImageVerification, gomock.Any()).
+					DoAndReturn(func(_ context.Context, ref name.Digest, _ *kmmv1beta1.ImageVerification, _ ...remote.Option) error {
+						Expect(ref.String()).To(Equal(expectedRef))
+						return nil
+					}),
+				mockCache.
+					EXPECT().
+					Mount(context.Background(), remoteImageName, srcDigest.String(), gomock.Any()).
+					Return("/some/path", nil),
+			)
+
+			res, err := rim.MountImage(context.Background(), remoteImageName, cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("/some/path"))
+		})
+
+		It("should not mount the image if its signature is invalid", func() {
+			mockVerifier.
+				EXPECT().
+				Verify(context.Background(), gomock.Any(), cfg.ImageVerification, gomock.Any()).
+				Return(NewError(ReasonImageVerification, errors.New("random error")))
+
+			_, err := rim.MountImage(context.Background(), remoteImageName, cfg)
+			Expect(err).To(HaveOccurred())
+			Expect(classifyPullError(err)).To(Equal(ReasonImageVerification))
+		})
+	})
+
+	Context("multi-architecture images", func() {
+		const indexPathAndTag = "/test/multiarch:tag"
+
+		var (
+			indexDigest    v1.Hash
+			indexImageName string
+			otherImg       v1.Image
+			otherDigest    v1.Hash
 		)
 
-		rim := NewRemoteImageMounter(tmpDir, authn.NewMultiKeychain(), GinkgoLogr)
+		BeforeEach(func() {
+			var err error
 
-		res, err := rim.MountImage(context.Background(), remoteImageName, modConfig)
-		Expect(err).NotTo(HaveOccurred())
-		Expect(res).To(Equal(filepath.Join(dstDir, "fs")))
+			otherImg, err = random.Image(64, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			otherDigest, err = otherImg.Digest()
+			Expect(err).NotTo(HaveOccurred())
+
+			idx := mutate.AppendManifests(
+				empty.Index,
+				mutate.IndexAddendum{
+					Add:        otherImg,
+					Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "some-other-arch"}},
+				},
+				mutate.IndexAddendum{
+					Add:        srcImg,
+					Descriptor: v1.Descriptor{Platform: platform.Runtime()},
+				},
+			)
+
+			indexImageName = serverURL.Host + indexPathAndTag
+
+			ref, err := name.ParseReference(indexImageName, name.Insecure)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(remote.WriteIndex(ref, idx)).To(Succeed())
+
+			indexDigest, err = idx.Digest()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should mount the image for the platform of the node", func() {
+			tmpDir := GinkgoT().TempDir()
+
+			rim := NewRemoteImageMounter(NewImageCache(tmpDir, GinkgoLogr), authn.NewMultiKeychain(), nil, nil, GinkgoLogr)
+
+			res, err := rim.MountImage(context.Background(), indexImageName, modConfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			imgRoot := filepath.Join(tmpDir, srcDigest.Algorithm, srcDigest.Hex, "fs")
+			Expect(res).To(Equal(imgRoot))
+			Expect(filepath.Join(imgRoot, "subdir", "subsubdir", "c")).To(BeARegularFile())
+		})
+
+		It("should mount the image for the pinned platform", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockCache := NewMockImageCache(ctrl)
+
+			mockCache.
+				EXPECT().
+				Mount(context.Background(), indexImageName, otherDigest.String(), gomock.Any()).
+				Return("/some/path", nil)
+
+			rim := NewRemoteImageMounter(mockCache, authn.NewMultiKeychain(), nil, nil, GinkgoLogr)
+
+			cfg := &kmmv1beta1.ModuleConfig{
+				InsecurePull: true,
+				Platform:     "linux/some-other-arch",
+			}
+
+			res, err := rim.MountImage(context.Background(), indexImageName, cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("/some/path"))
+		})
+
+		It("should pull the image from a mirror and cache it under its original name", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockCache := NewMockImageCache(ctrl)
+
+			const originalImageName = "registry.invalid" + indexPathAndTag
+
+			mockCache.
+				EXPECT().
+				Mount(context.Background(), originalImageName, srcDigest.String(), gomock.Any()).
+				Return("/some/path", nil)
+
+			mirrors := []mirror.Registry{
+				{
+					Prefix:  "registry.invalid",
+					Mirrors: []mirror.Mirror{{Location: serverURL.Host, Insecure: true}},
+				},
+			}
+
+			rim := NewRemoteImageMounter(mockCache, authn.NewMultiKeychain(), nil, mirrors, GinkgoLogr)
+
+			res, err := rim.MountImage(context.Background(), originalImageName, modConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("/some/path"))
+		})
+
+		It("should skip a mirror whose CA bundle cannot be read", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockCache := NewMockImageCache(ctrl)
+
+			const originalImageName = "registry.invalid" + indexPathAndTag
+
+			mirrors := []mirror.Registry{
+				{
+					Prefix: "registry.invalid",
+					Mirrors: []mirror.Mirror{
+						{Location: serverURL.Host, CAFile: filepath.Join(GinkgoT().TempDir(), "ca.pem")},
+					},
+				},
+			}
+
+			rim := NewRemoteImageMounter(mockCache, authn.NewMultiKeychain(), nil, mirrors, GinkgoLogr)
+
+			_, err := rim.MountImage(context.Background(), originalImageName, modConfig)
+			Expect(err).To(MatchError(ContainSubstring("could not read the CA bundle of mirror " + serverURL.Host)))
+		})
+
+		DescribeTable(
+			"should verify the signature of the index, then of the image",
+			func(indexSigned, imageSigned, mounted bool) {
+				ctrl := gomock.NewController(GinkgoT())
+				mockCache := NewMockImageCache(ctrl)
+				mockVerifier := NewMockSignatureVerifier(ctrl)
+
+				cfg := &kmmv1beta1.ModuleConfig{
+					InsecurePull: true,
+					ImageVerification: &kmmv1beta1.ImageVerification{
+						KeysSecret: corev1.LocalObjectReference{Name: "keys"},
+					},
+				}
+
+				verifyError := func(signed bool) error {
+					if signed {
+						return nil
+					}
+
+					return NewError(ReasonImageVerification, errors.New("no valid signature"))
+				}
+
+				indexRef := serverURL.Host + "/test/multiarch@" + indexDigest.String()
+				imageRef := serverURL.Host + "/test/multiarch@" + srcDigest.String()
+
+				calls := []any{
+					mockVerifier.
+						EXPECT().
+						Verify(context.Background(), gomock.Any(), cfg.ImageVerification, gomock.Any()).
+						DoAndReturn(func(_ context.Context, ref name.Digest, _ *kmmv1beta1.ImageVerification, _ ...remote.Option) error {
+							Expect(ref.String()).To(Equal(indexRef))
+							return verifyError(indexSigned)
+						}),
+				}
+
+				if !indexSigned {
+					calls = append(
+						calls,
+						mockVerifier.
+							EXPECT().
+							Verify(context.Background(), gomock.Any(), cfg.ImageVerification, gomock.Any()).
+							DoAndReturn(func(_ context.Context, ref name.Digest, _ *kmmv1beta1.ImageVerification, _ ...remote.Option) error {
+								Expect(ref.String()).To(Equal(imageRef))
+								return verifyError(imageSigned)
+							}),
+					)
+				}
+
+				if mounted {
+					calls = append(
+						calls,
+						mockCache.
+							EXPECT().
+							Mount(context.Background(), indexImageName, srcDigest.String(), gomock.Any()).
+							Return("/some/path", nil),
+					)
+				}
+
+				gomock.InOrder(calls...)
+
+				rim := NewRemoteImageMounter(mockCache, authn.NewMultiKeychain(), mockVerifier, nil, GinkgoLogr)
+
+				_, err := rim.MountImage(context.Background(), indexImageName, cfg)
+
+				if mounted {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(classifyPullError(err)).To(Equal(ReasonImageVerification))
+				}
+			},
+			Entry("index signed", true, false, true),
+			Entry("image signed", false, true, true),
+			Entry("nothing signed", false, false, false),
+		)
+
+		It("should return an error if no image matches the platform", func() {
+			rim := NewRemoteImageMounter(NewImageCache(GinkgoT().TempDir(), GinkgoLogr), authn.NewMultiKeychain(), nil, nil, GinkgoLogr)
+
+			cfg := &kmmv1beta1.ModuleConfig{
+				InsecurePull: true,
+				Platform:     "linux/missing-arch",
+			}
+
+			_, err := rim.MountImage(context.Background(), indexImageName, cfg)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error if the pinned platform is invalid", func() {
+			rim := NewRemoteImageMounter(NewImageCache(GinkgoT().TempDir(), GinkgoLogr), authn.NewMultiKeychain(), nil, nil, GinkgoLogr)
+
+			cfg := &kmmv1beta1.ModuleConfig{
+				InsecurePull: true,
+				Platform:     "invalid",
+			}
+
+			_, err := rim.MountImage(context.Background(), indexImageName, cfg)
+			Expect(err).To(HaveOccurred())
+		})
 	})
 })
@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+)
+
+type Action string
+
+const (
+	ActionLoad   Action = "load"
+	ActionUnload Action = "unload"
+)
+
+// Plan lists what LoadKmod or UnloadKmod do for a ModuleConfig, in order.
+type Plan struct {
+	Action   Action `json:"action"`
+	Image    string `json:"image"`
+	ImageDir string `json:"imageDir"`
+	// InTreeModulesToRemove are blacklisted in Blacklist and unloaded before firmware is installed and the module is
+	// loaded.
+	InTreeModulesToRemove []string `json:"inTreeModulesToRemove,omitempty"`
+	// Blacklist is the Module's blacklist file, written when loading and removed when unloading.
+	Blacklist string `json:"blacklist,omitempty"`
+	// ModprobeArgs assume that the module is not in use when unloading; --force is added if it is and the
+	// InUsePolicy is Force.
+	ModprobeArgs []string      `json:"modprobeArgs"`
+	Softdep      string        `json:"softdep,omitempty"`
+	Firmware     *FirmwarePlan `json:"firmware,omitempty"`
+	// PreHook and PostHook run before and after modprobe.
+	PreHook  *kmmv1beta1.ModprobeHook `json:"preHook,omitempty"`
+	PostHook *kmmv1beta1.ModprobeHook `json:"postHook,omitempty"`
+}
+
+// FirmwarePlan lists the firmware files installed before loading or removed after unloading.
+type FirmwarePlan struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	// Files are relative to Source and Destination.
+	Files []string `json:"files"`
+}
+
+// Status describes the modules and firmware of a ModuleConfig on the node.
+type Status struct {
+	Image    string          `json:"image"`
+	ImageDir string          `json:"imageDir"`
+	Modules  []ModuleStatus  `json:"modules"`
+	Firmware *FirmwareStatus `json:"firmware,omitempty"`
+}
+
+type FirmwareStatus struct {
+	Directory string `json:"directory"`
+	// Error describes missing or modified files; it is empty if the files match the manifest.
+	Error string `json:"error,omitempty"`
+}
+
+// LoadArgs returns the modprobe arguments that load the module configured in cfg from the image mounted at fsDir.
+func LoadArgs(cfg *kmmv1beta1.ModuleConfig, fsDir string) []string {
+	if cfg.Modprobe.RawArgs != nil {
+		return cfg.Modprobe.RawArgs.Load
+	}
+
+	args := []string{"-vd", filepath.Join(fsDir, cfg.Modprobe.DirName)}
+
+	if cfg.Modprobe.Args != nil {
+		args = append(args, cfg.Modprobe.Args.Load...)
+	}
+
+	args = append(args, cfg.Modprobe.ModuleName)
+
+	return append(args, cfg.Modprobe.Parameters...)
+}
+
+// UnloadArgs returns the modprobe arguments that unload the module configured in cfg from the image mounted at fsDir.
+// force is ignored if RawArgs are used.
+func UnloadArgs(cfg *kmmv1beta1.ModuleConfig, fsDir string, force bool) []string {
+	if cfg.Modprobe.RawArgs != nil {
+		return cfg.Modprobe.RawArgs.Unload
+	}
+
+	args := []string{"-rvd", filepath.Join(fsDir, cfg.Modprobe.DirName)}
+
+	if force {
+		args = append(args, "--force")
+	}
+
+	if cfg.Modprobe.Args != nil {
+		args = append(args, cfg.Modprobe.Args.Unload...)
+	}
+
+	return append(args, cfg.Modprobe.ModuleName)
+}
+
+// SoftdepConfig returns the modprobe configuration that makes each module in modulesLoadingOrder depend on the next.
+func SoftdepConfig(modulesLoadingOrder []string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(modulesLoadingOrder)-1; i++ {
+		fmt.Fprintf(&sb, "softdep %s pre: %s\n", modulesLoadingOrder[i], modulesLoadingOrder[i+1])
+	}
+
+	return sb.String()
+}
+
+func (w *worker) Render(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, action Action, firmwareMountPath string) (*Plan, error) {
+	if action != ActionLoad && action != ActionUnload {
+		return nil, fmt.Errorf("invalid action %q", action)
+	}
+
+	fsDir, err := w.im.MountImage(ctx, cfg.ContainerImage, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mount image %s: %w", cfg.ContainerImage, err)
+	}
+
+	plan := Plan{
+		Action:    action,
+		Image:     cfg.ContainerImage,
+		ImageDir:  fsDir,
+		Blacklist: w.bm.Path(),
+		Softdep:   SoftdepConfig(cfg.Modprobe.ModulesLoadingOrder),
+	}
+
+	if action == ActionLoad {
+		plan.InTreeModulesToRemove = inTreeModulesToRemove(cfg)
+		plan.ModprobeArgs = LoadArgs(cfg, fsDir)
+		plan.PreHook = hookFromConfig(cfg, HookPreLoad)
+		plan.PostHook = hookFromConfig(cfg, HookPostLoad)
+	} else {
+		plan.ModprobeArgs = UnloadArgs(cfg, fsDir, false)
+		plan.PreHook = hookFromConfig(cfg, HookPreUnload)
+		plan.PostHook = hookFromConfig(cfg, HookPostUnload)
+	}
+
+	if cfg.Modprobe.FirmwarePath != "" {
+		fp := FirmwarePlan{
+			Source:      filepath.Join(fsDir, cfg.Modprobe.FirmwarePath),
+			Destination: firmwareMountPath,
+		}
+
+		m, err := manifestFromDir(fp.Source)
+		if err != nil {
+			return nil, fmt.Errorf("could not list the firmware files in the image: %v", err)
+		}
+
+		for _, f := range m.Files {
+			fp.Files = append(fp.Files, f.Path)
+		}
+
+		plan.Firmware = &fp
+	}
+
+	return &plan, nil
+}
+
+func (w *worker) Status(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) (*Status, error) {
+	fsDir, err := w.im.MountImage(ctx, cfg.ContainerImage, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mount image %s: %w", cfg.ContainerImage, err)
+	}
+
+	modules, err := w.mv.ModuleStatuses(cfg, fsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	status := Status{
+		Image:    cfg.ContainerImage,
+		ImageDir: fsDir,
+		Modules:  modules,
+	}
+
+	if cfg.Modprobe.FirmwarePath != "" {
+		status.Firmware = &FirmwareStatus{Directory: firmwareMountPath}
+
+		if err = w.fm.Verify(firmwareMountPath); err != nil {
+			status.Firmware.Error = err.Error()
+		}
+	}
+
+	return &status, nil
+}
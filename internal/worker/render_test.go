@@ -0,0 +1,246 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("LoadArgs", func() {
+	It("should return RawArgs if they are set", func() {
+		cfg := &v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				RawArgs: &v1beta1.ModprobeArgs{Load: []string{"a", "b"}},
+			},
+		}
+
+		Expect(LoadArgs(cfg, "/image")).To(Equal([]string{"a", "b"}))
+	})
+
+	It("should add the arguments and parameters", func() {
+		cfg := &v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: "mod",
+				DirName:    "/opt",
+				Args:       &v1beta1.ModprobeArgs{Load: []string{"--first-time"}},
+				Parameters: []string{"a=1", "b=2"},
+			},
+		}
+
+		Expect(
+			LoadArgs(cfg, "/image"),
+		).To(
+			Equal([]string{"-vd", "/image/opt", "--first-time", "mod", "a=1", "b=2"}),
+		)
+	})
+})
+
+var _ = Describe("UnloadArgs", func() {
+	cfg := &v1beta1.ModuleConfig{
+		Modprobe: v1beta1.ModprobeSpec{
+			ModuleName: "mod",
+			DirName:    "/opt",
+			Args:       &v1beta1.ModprobeArgs{Unload: []string{"--syslog"}},
+		},
+	}
+
+	It("should not add --force by default", func() {
+		Expect(UnloadArgs(cfg, "/image", false)).To(Equal([]string{"-rvd", "/image/opt", "--syslog", "mod"}))
+	})
+
+	It("should add --force", func() {
+		Expect(UnloadArgs(cfg, "/image", true)).To(Equal([]string{"-rvd", "/image/opt", "--force", "--syslog", "mod"}))
+	})
+
+	It("should ignore force if RawArgs are set", func() {
+		rawCfg := &v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				RawArgs: &v1beta1.ModprobeArgs{Unload: []string{"a"}},
+			},
+		}
+
+		Expect(UnloadArgs(rawCfg, "/image", true)).To(Equal([]string{"a"}))
+	})
+})
+
+var _ = Describe("SoftdepConfig", func() {
+	It("should return an empty string if there is no loading order", func() {
+		Expect(SoftdepConfig(nil)).To(BeEmpty())
+	})
+
+	It("should make each module depend on the next one", func() {
+		Expect(
+			SoftdepConfig([]string{"a", "b", "c"}),
+		).To(
+			Equal("softdep a pre: b\nsoftdep b pre: c\n"),
+		)
+	})
+})
+
+var _ = Describe("worker_Render", func() {
+	const (
+		imageName = "some-image"
+		mountPath = "/var/lib/firmware"
+	)
+
+	var (
+		im       *MockImageMounter
+		w        Worker
+		imageDir string
+	)
+
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		im = NewMockImageMounter(ctrl)
+		w = NewWorker(im, nil, nil, nil, nil, nil, nil, NewBlacklistManager("/etc/modprobe.d", "ns", "name", GinkgoLogr), nil, Timeouts{}, GinkgoLogr)
+		imageDir = GinkgoT().TempDir()
+
+		Expect(os.MkdirAll(filepath.Join(imageDir, "firmware", "sub"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(imageDir, "firmware", "a.bin"), nil, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(imageDir, "firmware", "sub", "b.bin"), nil, 0644)).To(Succeed())
+	})
+
+	cfg := &v1beta1.ModuleConfig{
+		ContainerImage:       imageName,
+		InTreeModuleToRemove: "in-tree",
+		Modprobe: v1beta1.ModprobeSpec{
+			ModuleName:          "mod",
+			DirName:             "/opt",
+			FirmwarePath:        "/firmware",
+			ModulesLoadingOrder: []string{"mod", "dep"},
+			Hooks: &v1beta1.ModprobeHooks{
+				PreLoad:   &v1beta1.ModprobeHook{Path: "/pre-load"},
+				PostLoad:  &v1beta1.ModprobeHook{Path: "/post-load"},
+				PreUnload: &v1beta1.ModprobeHook{Path: "/pre-unload"},
+			},
+		},
+	}
+
+	It("should return an error for unknown actions", func() {
+		_, err := w.Render(ctx, cfg, "invalid", mountPath)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the image cannot be mounted", func() {
+		im.EXPECT().MountImage(ctx, imageName, cfg).Return("", errors.New("random error"))
+
+		_, err := w.Render(ctx, cfg, ActionLoad, mountPath)
+		Expect(err).To(MatchError(ContainSubstring("random error")))
+	})
+
+	It("should render the load actions", func() {
+		im.EXPECT().MountImage(ctx, imageName, cfg).Return(imageDir, nil)
+
+		Expect(
+			w.Render(ctx, cfg, ActionLoad, mountPath),
+		).To(
+			Equal(&Plan{
+				Action:                ActionLoad,
+				Image:                 imageName,
+				ImageDir:              imageDir,
+				InTreeModulesToRemove: []string{"in-tree"},
+				Blacklist:             "/etc/modprobe.d/kmm-ns-name.conf",
+				ModprobeArgs:          []string{"-vd", imageDir + "/opt", "mod"},
+				Softdep:               "softdep mod pre: dep\n",
+				Firmware: &FirmwarePlan{
+					Source:      imageDir + "/firmware",
+					Destination: mountPath,
+					Files:       []string{"a.bin", "sub/b.bin"},
+				},
+				PreHook:  &v1beta1.ModprobeHook{Path: "/pre-load"},
+				PostHook: &v1beta1.ModprobeHook{Path: "/post-load"},
+			}),
+		)
+	})
+
+	It("should render the unload actions", func() {
+		im.EXPECT().MountImage(ctx, imageName, cfg).Return(imageDir, nil)
+
+		plan, err := w.Render(ctx, cfg, ActionUnload, mountPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.InTreeModulesToRemove).To(BeEmpty())
+		Expect(plan.Blacklist).To(Equal("/etc/modprobe.d/kmm-ns-name.conf"))
+		Expect(plan.ModprobeArgs).To(Equal([]string{"-rvd", imageDir + "/opt", "mod"}))
+		Expect(plan.Firmware.Files).To(Equal([]string{"a.bin", "sub/b.bin"}))
+		Expect(plan.PreHook).To(Equal(&v1beta1.ModprobeHook{Path: "/pre-unload"}))
+		Expect(plan.PostHook).To(BeNil())
+	})
+})
+
+var _ = Describe("worker_Status", func() {
+	const (
+		imageDir  = "/image"
+		imageName = "some-image"
+		mountPath = "/var/lib/firmware"
+	)
+
+	var (
+		fm *MockFirmwareManager
+		im *MockImageMounter
+		mv *MockModuleVerifier
+		w  Worker
+	)
+
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		fm = NewMockFirmwareManager(ctrl)
+		im = NewMockImageMounter(ctrl)
+		mv = NewMockModuleVerifier(ctrl)
+		w = NewWorker(im, nil, mv, nil, fm, nil, nil, nil, nil, Timeouts{}, GinkgoLogr)
+	})
+
+	It("should report the modules and the firmware", func() {
+		cfg := &v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:   "mod",
+				FirmwarePath: "/firmware",
+			},
+		}
+
+		modules := []ModuleStatus{{Name: "mod", Loaded: true}}
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, cfg).Return(imageDir, nil),
+			mv.EXPECT().ModuleStatuses(cfg, imageDir).Return(modules, nil),
+			fm.EXPECT().Verify(mountPath).Return(NewError(ReasonFirmware, errors.New("a.bin: missing"))),
+		)
+
+		Expect(
+			w.Status(ctx, cfg, mountPath),
+		).To(
+			Equal(&Status{
+				Image:    imageName,
+				ImageDir: imageDir,
+				Modules:  modules,
+				Firmware: &FirmwareStatus{Directory: mountPath, Error: "a.bin: missing"},
+			}),
+		)
+	})
+
+	It("should not verify the firmware if there is none", func() {
+		cfg := &v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe:       v1beta1.ModprobeSpec{ModuleName: "mod"},
+		}
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, cfg).Return(imageDir, nil),
+			mv.EXPECT().ModuleStatuses(cfg, imageDir),
+		)
+
+		status, err := w.Status(ctx, cfg, mountPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Firmware).To(BeNil())
+	})
+})
@@ -0,0 +1,356 @@
+package worker
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+)
+
+const (
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	cosignSignatureType       = "cosign container image signature"
+	dsseMediaType             = "application/vnd.dsse.envelope.v1+json"
+	inTotoPayloadType         = "application/vnd.in-toto+json"
+	simpleSigningMediaType    = "application/vnd.dev.cosign.simplesigning.v1+json"
+)
+
+//go:generate mockgen -source=signatureverifier.go -package=worker -destination=mock_signatureverifier.go
+
+// SignatureVerifier verifies the cosign signatures and attestations of images.
+type SignatureVerifier interface {
+	// Verify returns an Error with ReasonImageVerification if the image designated by ref is not signed with one of
+	// the public keys found in the verifier's keys directory.
+	Verify(ctx context.Context, ref name.Digest, iv *kmmv1beta1.ImageVerification, opts ...remote.Option) error
+}
+
+type signatureVerifier struct {
+	keysDir string
+	logger  logr.Logger
+}
+
+// NewSignatureVerifier returns a SignatureVerifier that reads PEM-encoded public keys from the files in keysDir.
+func NewSignatureVerifier(keysDir string, logger logr.Logger) SignatureVerifier {
+	return &signatureVerifier{
+		keysDir: keysDir,
+		logger:  logger,
+	}
+}
+
+func (sv *signatureVerifier) Verify(ctx context.Context, ref name.Digest, iv *kmmv1beta1.ImageVerification, opts ...remote.Option) error {
+	logger := sv.logger.V(1).WithValues("image", ref.String())
+
+	keys, err := sv.readKeys()
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return NewError(ReasonImageVerification, fmt.Errorf("no public key found in %s", sv.keysDir))
+	}
+
+	var (
+		kind   string
+		suffix string
+		verify func(v1.Layer, v1.Descriptor, []crypto.PublicKey, v1.Hash) error
+	)
+
+	switch iv.Type {
+	case kmmv1beta1.ImageVerificationSignature, "":
+		kind = "signature"
+		suffix = "sig"
+		verify = verifySignatureLayer
+	case kmmv1beta1.ImageVerificationAttestation:
+		kind = "attestation"
+		suffix = "att"
+		verify = verifyAttestationLayer
+	default:
+		return fmt.Errorf("unhandled verification type %q", iv.Type)
+	}
+
+	digest, err := v1.NewHash(ref.DigestStr())
+	if err != nil {
+		return fmt.Errorf("invalid digest in %s: %v", ref, err)
+	}
+
+	tag := ref.Context().Tag(fmt.Sprintf("%s-%s.%s", digest.Algorithm, digest.Hex, suffix))
+
+	logger.Info("Getting cosign artifact", "tag", tag.String())
+
+	img, err := remote.Image(tag, append(opts, remote.WithContext(ctx))...)
+	if err != nil {
+		return NewError(ReasonImageVerification, fmt.Errorf("could not get %s for %s: %w", tag, ref, err))
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return fmt.Errorf("could not get the manifest of %s: %v", tag, err)
+	}
+
+	errs := make([]error, 0, len(manifest.Layers))
+
+	for _, desc := range manifest.Layers {
+		layer, err := img.LayerByDigest(desc.Digest)
+		if err != nil {
+			return fmt.Errorf("could not get layer %s of %s: %v", desc.Digest, tag, err)
+		}
+
+		if err = verify(layer, desc, keys, digest); err != nil {
+			logger.Info("Signature rejected", "layer", desc.Digest.String(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %v", desc.Digest, err))
+			continue
+		}
+
+		logger.Info("Signature verified", "layer", desc.Digest.String())
+
+		return nil
+	}
+
+	return NewError(
+		ReasonImageVerification,
+		fmt.Errorf("no valid %s found for %s: %w", kind, ref, errors.Join(errs...)),
+	)
+}
+
+// readKeys reads all public keys from the files in the keys directory.
+// Hidden files are skipped, so that the internal entries of Secret volumes are only read once.
+func (sv *signatureVerifier) readKeys() ([]crypto.PublicKey, error) {
+	entries, err := os.ReadDir(sv.keysDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the keys directory: %v", err)
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(entries))
+
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(sv.keysDir, e.Name())
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %v", path, err)
+		}
+
+		fileKeys, err := parsePublicKeys(b)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse public keys from %s: %v", path, err)
+		}
+
+		sv.logger.V(1).Info("Read public keys", "path", path, "count", len(fileKeys))
+
+		keys = append(keys, fileKeys...)
+	}
+
+	return keys, nil
+}
+
+func parsePublicKeys(b []byte) ([]crypto.PublicKey, error) {
+	keys := make([]crypto.PublicKey, 0, 1)
+
+	for {
+		var block *pem.Block
+
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM-encoded public key found")
+	}
+
+	return keys, nil
+}
+
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// verifySignatureLayer verifies a cosign simple signing layer, whose annotation holds the signature of its payload.
+func verifySignatureLayer(layer v1.Layer, desc v1.Descriptor, keys []crypto.PublicKey, digest v1.Hash) error {
+	if desc.MediaType != simpleSigningMediaType {
+		return fmt.Errorf("unexpected media type %s", desc.MediaType)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(desc.Annotations[cosignSignatureAnnotation])
+	if err != nil || len(sig) == 0 {
+		return errors.New("missing or invalid signature annotation")
+	}
+
+	payload, err := readLayer(layer)
+	if err != nil {
+		return err
+	}
+
+	if err = verifyWithAnyKey(keys, payload, sig); err != nil {
+		return err
+	}
+
+	p := simpleSigningPayload{}
+
+	if err = json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("could not unmarshal the payload: %v", err)
+	}
+
+	if p.Critical.Type != cosignSignatureType {
+		return fmt.Errorf("unexpected payload type %q", p.Critical.Type)
+	}
+
+	if p.Critical.Image.DockerManifestDigest != digest.String() {
+		return fmt.Errorf("signature is for %s", p.Critical.Image.DockerManifestDigest)
+	}
+
+	return nil
+}
+
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+	Signatures  []struct {
+		Sig string `json:"sig"`
+	} `json:"signatures"`
+}
+
+type inTotoStatement struct {
+	Subject []struct {
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+}
+
+// verifyAttestationLayer verifies a DSSE envelope holding an in-toto statement whose subject is the image.
+func verifyAttestationLayer(layer v1.Layer, desc v1.Descriptor, keys []crypto.PublicKey, digest v1.Hash) error {
+	if desc.MediaType != dsseMediaType {
+		return fmt.Errorf("unexpected media type %s", desc.MediaType)
+	}
+
+	b, err := readLayer(layer)
+	if err != nil {
+		return err
+	}
+
+	env := dsseEnvelope{}
+
+	if err = json.Unmarshal(b, &env); err != nil {
+		return fmt.Errorf("could not unmarshal the envelope: %v", err)
+	}
+
+	if env.PayloadType != inTotoPayloadType {
+		return fmt.Errorf("unexpected payload type %q", env.PayloadType)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return fmt.Errorf("could not decode the payload: %v", err)
+	}
+
+	pae := fmt.Sprintf("DSSEv1 %d %s %d %s", len(env.PayloadType), env.PayloadType, len(payload), payload)
+
+	verified := false
+
+	for _, s := range env.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+
+		if verifyWithAnyKey(keys, []byte(pae), sig) == nil {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return errors.New("no signature matches the public keys")
+	}
+
+	st := inTotoStatement{}
+
+	if err = json.Unmarshal(payload, &st); err != nil {
+		return fmt.Errorf("could not unmarshal the statement: %v", err)
+	}
+
+	for _, s := range st.Subject {
+		if s.Digest[digest.Algorithm] == digest.Hex {
+			return nil
+		}
+	}
+
+	return errors.New("the image is not a subject of the attestation")
+}
+
+func readLayer(layer v1.Layer) ([]byte, error) {
+	// Cosign layers are not compressed; reading the blob also verifies its digest.
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the layer: %v", err)
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the layer: %v", err)
+	}
+
+	return b, nil
+}
+
+func verifyWithAnyKey(keys []crypto.PublicKey, msg, sig []byte) error {
+	hash := sha256.Sum256(msg)
+
+	for _, k := range keys {
+		var ok bool
+
+		switch key := k.(type) {
+		case *ecdsa.PublicKey:
+			ok = ecdsa.VerifyASN1(key, hash[:], sig)
+		case *rsa.PublicKey:
+			ok = rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig) == nil
+		case ed25519.PublicKey:
+			ok = ed25519.Verify(key, msg, sig)
+		}
+
+		if ok {
+			return nil
+		}
+	}
+
+	return errors.New("no public key matches the signature")
+}
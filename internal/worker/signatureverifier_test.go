@@ -0,0 +1,269 @@
+package worker
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("signatureVerifier_Verify", func() {
+	var (
+		digestRef name.Digest
+		imgDigest v1.Hash
+		keysDir   string
+		key       *ecdsa.PrivateKey
+		server    *httptest.Server
+		sv        SignatureVerifier
+	)
+
+	ctx := context.Background()
+
+	newKey := func() *ecdsa.PrivateKey {
+		GinkgoHelper()
+
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		return k
+	}
+
+	writePublicKey := func(fileName string, k *ecdsa.PrivateKey) {
+		GinkgoHelper()
+
+		der, err := x509.MarshalPKIXPublicKey(k.Public())
+		Expect(err).NotTo(HaveOccurred())
+
+		b := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+		Expect(os.WriteFile(filepath.Join(keysDir, fileName), b, 0644)).To(Succeed())
+	}
+
+	sign := func(k *ecdsa.PrivateKey, msg []byte) []byte {
+		GinkgoHelper()
+
+		hash := sha256.Sum256(msg)
+
+		sig, err := k.Sign(rand.Reader, hash[:], crypto.SHA256)
+		Expect(err).NotTo(HaveOccurred())
+
+		return sig
+	}
+
+	// pushArtifact pushes a cosign artifact made of layer for the image, with the given tag suffix.
+	pushArtifact := func(suffix string, layer mutate.Addendum) {
+		GinkgoHelper()
+
+		img, err := mutate.Append(empty.Image, layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		tag := digestRef.Context().Tag(fmt.Sprintf("sha256-%s.%s", imgDigest.Hex, suffix))
+
+		Expect(remote.Write(tag, img)).To(Succeed())
+	}
+
+	pushSignature := func(k *ecdsa.PrivateKey, digest string) {
+		GinkgoHelper()
+
+		payload := []byte(
+			fmt.Sprintf(
+				`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`,
+				digestRef.Context().String(),
+				digest,
+			),
+		)
+
+		pushArtifact("sig", mutate.Addendum{
+			Layer: static.NewLayer(payload, simpleSigningMediaType),
+			Annotations: map[string]string{
+				cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sign(k, payload)),
+			},
+		})
+	}
+
+	pushAttestation := func(k *ecdsa.PrivateKey, digestHex string) {
+		GinkgoHelper()
+
+		statement := []byte(
+			fmt.Sprintf(
+				`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v0.2","subject":[{"name":"%s","digest":{"sha256":"%s"}}],"predicate":{}}`,
+				digestRef.Context().String(),
+				digestHex,
+			),
+		)
+
+		pae := fmt.Sprintf("DSSEv1 %d %s %d %s", len(inTotoPayloadType), inTotoPayloadType, len(statement), statement)
+
+		env, err := json.Marshal(map[string]any{
+			"payloadType": inTotoPayloadType,
+			"payload":     base64.StdEncoding.EncodeToString(statement),
+			"signatures": []map[string]string{
+				{"sig": base64.StdEncoding.EncodeToString(sign(k, []byte(pae)))},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		pushArtifact("att", mutate.Addendum{Layer: static.NewLayer(env, types.MediaType(dsseMediaType))})
+	}
+
+	BeforeEach(func() {
+		server = httptest.NewServer(
+			registry.New(registry.Logger(log.New(GinkgoWriter, "registry | ", log.LstdFlags))),
+		)
+
+		serverURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		img, err := random.Image(64, 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		imgDigest, err = img.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		tag, err := name.NewTag(serverURL.Host+"/test/image:tag", name.Insecure)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(remote.Write(tag, img)).To(Succeed())
+
+		digestRef = tag.Context().Digest(imgDigest.String())
+
+		key = newKey()
+		keysDir = GinkgoT().TempDir()
+		sv = NewSignatureVerifier(keysDir, GinkgoLogr)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	signature := &kmmv1beta1.ImageVerification{Type: kmmv1beta1.ImageVerificationSignature}
+
+	It("should accept an image signed with one of the keys", func() {
+		writePublicKey("other.pub", newKey())
+		writePublicKey("cosign.pub", key)
+		pushSignature(key, imgDigest.String())
+
+		Expect(sv.Verify(ctx, digestRef, signature)).To(Succeed())
+	})
+
+	It("should use signatures by default", func() {
+		writePublicKey("cosign.pub", key)
+		pushSignature(key, imgDigest.String())
+
+		Expect(sv.Verify(ctx, digestRef, &kmmv1beta1.ImageVerification{})).To(Succeed())
+	})
+
+	It("should reject an unsigned image", func() {
+		writePublicKey("cosign.pub", key)
+
+		err := sv.Verify(ctx, digestRef, signature)
+		Expect(err).To(HaveOccurred())
+		Expect(ReasonFromError(err)).To(Equal(ReasonImageVerification))
+	})
+
+	It("should reject an image signed with another key", func() {
+		writePublicKey("cosign.pub", key)
+		pushSignature(newKey(), imgDigest.String())
+
+		err := sv.Verify(ctx, digestRef, signature)
+		Expect(err).To(MatchError(ContainSubstring("no public key matches the signature")))
+		Expect(ReasonFromError(err)).To(Equal(ReasonImageVerification))
+	})
+
+	It("should reject a signature for another image", func() {
+		writePublicKey("cosign.pub", key)
+		pushSignature(key, "sha256:"+fmt.Sprintf("%064d", 0))
+
+		err := sv.Verify(ctx, digestRef, signature)
+		Expect(err).To(MatchError(ContainSubstring("signature is for")))
+		Expect(ReasonFromError(err)).To(Equal(ReasonImageVerification))
+	})
+
+	It("should return an error if no key is configured", func() {
+		pushSignature(key, imgDigest.String())
+
+		err := sv.Verify(ctx, digestRef, signature)
+		Expect(err).To(MatchError(ContainSubstring("no public key found")))
+	})
+
+	It("should ignore the hidden entries of Secret volumes", func() {
+		writePublicKey("cosign.pub", key)
+		Expect(os.Mkdir(filepath.Join(keysDir, "..data"), 0755)).To(Succeed())
+		pushSignature(key, imgDigest.String())
+
+		Expect(sv.Verify(ctx, digestRef, signature)).To(Succeed())
+	})
+
+	It("should return an error if a key file is invalid", func() {
+		Expect(os.WriteFile(filepath.Join(keysDir, "invalid.pub"), []byte("not a key"), 0644)).To(Succeed())
+
+		Expect(
+			sv.Verify(ctx, digestRef, signature),
+		).To(
+			MatchError(ContainSubstring("no PEM-encoded public key found")),
+		)
+	})
+
+	Context("attestations", func() {
+		attestation := &kmmv1beta1.ImageVerification{Type: kmmv1beta1.ImageVerificationAttestation}
+
+		It("should accept an attestation signed with one of the keys", func() {
+			writePublicKey("cosign.pub", key)
+			pushAttestation(key, imgDigest.Hex)
+
+			Expect(sv.Verify(ctx, digestRef, attestation)).To(Succeed())
+		})
+
+		It("should reject an attestation signed with another key", func() {
+			writePublicKey("cosign.pub", key)
+			pushAttestation(newKey(), imgDigest.Hex)
+
+			err := sv.Verify(ctx, digestRef, attestation)
+			Expect(err).To(MatchError(ContainSubstring("no signature matches the public keys")))
+			Expect(ReasonFromError(err)).To(Equal(ReasonImageVerification))
+		})
+
+		It("should reject an attestation for another image", func() {
+			writePublicKey("cosign.pub", key)
+			pushAttestation(key, fmt.Sprintf("%064d", 0))
+
+			Expect(
+				sv.Verify(ctx, digestRef, attestation),
+			).To(
+				MatchError(ContainSubstring("not a subject of the attestation")),
+			)
+		})
+
+		It("should not accept a signature instead of an attestation", func() {
+			writePublicKey("cosign.pub", key)
+			pushSignature(key, imgDigest.String())
+
+			Expect(sv.Verify(ctx, digestRef, attestation)).NotTo(Succeed())
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+)
+
+// maxTerminationMessageLength is the maximum size of a container's termination message accepted by the kubelet.
+const maxTerminationMessageLength = 4096
+
+// TerminationMessage is written by the worker to its termination message path when it fails.
+type TerminationMessage struct {
+	Phase  Phase  `json:"phase,omitempty"`
+	Reason Reason `json:"reason,omitempty"`
+	// ExitCode is the exit code of the modprobe command, if it ran.
+	ExitCode  *int     `json:"exitCode,omitempty"`
+	Message   string   `json:"message"`
+	KernelLog []string `json:"kernelLog,omitempty"`
+}
+
+// NewTerminationMessage builds a TerminationMessage from err.
+// If err does not carry a Reason, one is inferred from the error message and, for modprobe failures, from the last
+// kernel log lines.
+func NewTerminationMessage(err error, kernelLog []string) *TerminationMessage {
+	tm := TerminationMessage{
+		Phase:     PhaseFromError(err),
+		Reason:    ReasonFromError(err),
+		Message:   err.Error(),
+		KernelLog: kernelLog,
+	}
+
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		tm.ExitCode = &code
+	}
+
+	if tm.Reason == "" {
+		messages := []string{tm.Message}
+
+		if tm.Phase == PhaseModprobe {
+			// Most recent lines first
+			for i := len(kernelLog) - 1; i >= 0; i-- {
+				messages = append(messages, kernelLog[i])
+			}
+		}
+
+		tm.Reason = classifyMessages(messages...)
+	}
+
+	return &tm
+}
+
+// WriteTerminationMessage writes tm to path as JSON.
+// If the result is too long, the oldest kernel log lines are dropped first, then the message is truncated.
+func WriteTerminationMessage(path string, tm *TerminationMessage) error {
+	b, err := json.Marshal(tm)
+	if err != nil {
+		return fmt.Errorf("could not marshal the termination message: %v", err)
+	}
+
+	if len(b) > maxTerminationMessageLength {
+		truncated := *tm
+
+		// Marshalling a TerminationMessage cannot fail.
+		for len(b) > maxTerminationMessageLength && len(truncated.KernelLog) > 0 {
+			truncated.KernelLog = truncated.KernelLog[1:]
+			b, _ = json.Marshal(truncated)
+		}
+
+		if len(b) > maxTerminationMessageLength {
+			// Find the first message length that makes the encoded message too long.
+			n := sort.Search(len(tm.Message)+1, func(i int) bool {
+				truncated.Message = tm.Message[:i]
+				b, _ = json.Marshal(truncated)
+				return len(b) > maxTerminationMessageLength
+			})
+
+			truncated.Message = tm.Message[:n-1]
+			b, _ = json.Marshal(truncated)
+		}
+	}
+
+	if err = os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("could not write the termination message to %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func ParseTerminationMessage(s string) (*TerminationMessage, error) {
+	tm := TerminationMessage{}
+
+	if err := json.Unmarshal([]byte(s), &tm); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the termination message: %v", err)
+	}
+
+	return &tm, nil
+}
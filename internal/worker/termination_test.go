@@ -0,0 +1,159 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("WriteTerminationMessage", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "termination-log")
+	})
+
+	It("should write a message that can be parsed", func() {
+		err := fmt.Errorf("wrapped: %w", NewError(ReasonKernelMismatch, errors.New("random error")))
+
+		Expect(
+			WriteTerminationMessage(path, NewTerminationMessage(err, []string{"line 1", "line 2"})),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		b, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			ParseTerminationMessage(string(b)),
+		).To(
+			Equal(&TerminationMessage{
+				Reason:    ReasonKernelMismatch,
+				Message:   "wrapped: random error",
+				KernelLog: []string{"line 1", "line 2"},
+			}),
+		)
+	})
+
+	It("should truncate long messages", func() {
+		tm := &TerminationMessage{
+			Reason:  ReasonKernelMismatch,
+			Message: strings.Repeat(`"`, 2*maxTerminationMessageLength),
+		}
+
+		Expect(
+			WriteTerminationMessage(path, tm),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		b, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(b)).To(BeNumerically("<=", maxTerminationMessageLength))
+
+		parsed, err := ParseTerminationMessage(string(b))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.Reason).To(Equal(ReasonKernelMismatch))
+		Expect(parsed.Message).NotTo(BeEmpty())
+	})
+
+	It("should drop the oldest kernel log lines before truncating the message", func() {
+		tm := &TerminationMessage{
+			Reason:  ReasonKernelMismatch,
+			Message: "some message",
+			KernelLog: []string{
+				strings.Repeat("a", maxTerminationMessageLength/2),
+				strings.Repeat("b", maxTerminationMessageLength/2),
+				"most recent line",
+			},
+		}
+
+		Expect(
+			WriteTerminationMessage(path, tm),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		b, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			ParseTerminationMessage(string(b)),
+		).To(
+			Equal(&TerminationMessage{
+				Reason:    ReasonKernelMismatch,
+				Message:   "some message",
+				KernelLog: []string{tm.KernelLog[1], tm.KernelLog[2]},
+			}),
+		)
+	})
+})
+
+var _ = Describe("NewTerminationMessage", func() {
+	It("should include the phase and the exit code", func() {
+		exitErr := exec.Command("sh", "-c", "exit 3").Run()
+		Expect(exitErr).To(HaveOccurred())
+
+		err := WithPhase(PhaseModprobe, fmt.Errorf("modprobe failed: %w", exitErr))
+
+		Expect(
+			NewTerminationMessage(err, nil),
+		).To(
+			Equal(&TerminationMessage{
+				Phase:    PhaseModprobe,
+				ExitCode: ptr.To(3),
+				Message:  "modprobe failed: exit status 3",
+			}),
+		)
+	})
+
+	It("should classify modprobe failures using the kernel log", func() {
+		err := WithPhase(PhaseModprobe, errors.New("modprobe failed"))
+
+		kernelLog := []string{
+			"[    1.000000] kmm_ci_a: version magic '5.0.0' should be '6.0.0'",
+			"[    2.000000] kmm_ci_a: Unknown symbol some_function (err -2)",
+		}
+
+		Expect(
+			NewTerminationMessage(err, kernelLog).Reason,
+		).To(
+			Equal(ReasonUnknownSymbol),
+		)
+	})
+
+	It("should not use the kernel log for other phases", func() {
+		err := WithPhase(PhaseVerifyLoaded, errors.New("random error"))
+
+		Expect(
+			NewTerminationMessage(err, []string{"[    2.000000] kmm_ci_a: Unknown symbol some_function (err -2)"}).Reason,
+		).To(
+			BeEmpty(),
+		)
+	})
+
+	It("should prefer the reason carried by the error", func() {
+		err := NewError(ReasonImagePull, errors.New("Exec format error"))
+
+		Expect(
+			NewTerminationMessage(err, nil).Reason,
+		).To(
+			Equal(ReasonImagePull),
+		)
+	})
+})
+
+var _ = Describe("ParseTerminationMessage", func() {
+	It("should return an error for invalid JSON", func() {
+		_, err := ParseTerminationMessage("not JSON")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Timeouts bound the steps of LoadKmod and UnloadKmod.
+// 0 means no timeout.
+type Timeouts struct {
+	// Pull bounds pulling and unpacking the image.
+	Pull time.Duration
+	// Load and Unload bound the steps that follow, in LoadKmod and UnloadKmod respectively.
+	Load   time.Duration
+	Unload time.Duration
+	// InUse bounds waiting for a module with the Wait in-use policy to be released, and InUsePollInterval is how often
+	// its usage is checked meanwhile.
+	// 0 means DefaultModuleInUseTimeout and DefaultModuleInUsePollInterval respectively.
+	InUse             time.Duration
+	InUsePollInterval time.Duration
+}
+
+// timeoutGracePeriod is how long withTimeout waits for fn to return after the deadline, so that interruptible steps
+// can report where they stopped.
+var timeoutGracePeriod = time.Second
+
+// withTimeout runs fn with a context that expires after timeout, unless timeout is 0.
+// If fn does not return in time, withTimeout returns an Error with ReasonTimeout without waiting for it: some steps,
+// such as loading a module, cannot be interrupted, and the worker must still exit.
+func withTimeout(ctx context.Context, timeout time.Duration, step string, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- fn(tctx)
+	}()
+
+	var (
+		err      error
+		returned bool
+	)
+
+	select {
+	case err = <-errCh:
+		returned = true
+	case <-tctx.Done():
+		select {
+		case err = <-errCh:
+			returned = true
+		case <-time.After(timeoutGracePeriod):
+		}
+	}
+
+	if returned && err == nil {
+		return nil
+	}
+
+	// The parent context was cancelled, for instance because the worker received SIGTERM.
+	if ctx.Err() != nil {
+		if err == nil {
+			err = ctx.Err()
+		}
+
+		return fmt.Errorf("%s was interrupted: %w", step, err)
+	}
+
+	if !errors.Is(tctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	timeoutErr := fmt.Errorf("%s did not complete within %v", step, timeout)
+
+	if err != nil {
+		timeoutErr = fmt.Errorf("%v: %v", timeoutErr, err)
+	}
+
+	if phase := PhaseFromError(err); phase != "" {
+		return WithPhase(phase, NewError(ReasonTimeout, timeoutErr))
+	}
+
+	return NewError(ReasonTimeout, timeoutErr)
+}
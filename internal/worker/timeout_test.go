@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("withTimeout", func() {
+	ctx := context.TODO()
+
+	It("should run fn with the parent context if the timeout is 0", func() {
+		err := withTimeout(ctx, 0, "step", func(fnCtx context.Context) error {
+			Expect(fnCtx).To(Equal(ctx))
+			return nil
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return the error of fn if it returns in time", func() {
+		fnErr := WithPhase(PhaseModprobe, errors.New("random error"))
+
+		err := withTimeout(ctx, time.Minute, "step", func(context.Context) error { return fnErr })
+
+		Expect(err).To(Equal(fnErr))
+		Expect(ReasonFromError(err)).To(BeEmpty())
+	})
+
+	It("should return a timeout if fn fails because of the deadline", func() {
+		err := withTimeout(ctx, 10*time.Millisecond, "step", func(fnCtx context.Context) error {
+			<-fnCtx.Done()
+			return WithPhase(PhaseModprobe, fnCtx.Err())
+		})
+
+		Expect(err).To(MatchError(ContainSubstring("step did not complete within 10ms")))
+		Expect(ReasonFromError(err)).To(Equal(ReasonTimeout))
+		Expect(PhaseFromError(err)).To(Equal(PhaseModprobe))
+	})
+
+	It("should not wait for fn if it ignores the deadline", func() {
+		DeferCleanup(func(d time.Duration) { timeoutGracePeriod = d }, timeoutGracePeriod)
+		timeoutGracePeriod = 0
+
+		release := make(chan struct{})
+		defer close(release)
+
+		err := withTimeout(ctx, 10*time.Millisecond, "step", func(context.Context) error {
+			<-release
+			return nil
+		})
+
+		Expect(err).To(MatchError("step did not complete within 10ms"))
+		Expect(ReasonFromError(err)).To(Equal(ReasonTimeout))
+	})
+
+	It("should not return a timeout if the parent context is cancelled", func() {
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := withTimeout(cancelledCtx, time.Minute, "step", func(fnCtx context.Context) error {
+			<-fnCtx.Done()
+			return fnCtx.Err()
+		})
+
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(ReasonFromError(err)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,407 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+//go:generate mockgen -source=verifier.go -package=worker -destination=mock_verifier.go
+
+// ModuleStatus compares the state of a module in the kernel with its file in a kmod image.
+type ModuleStatus struct {
+	Name   string `json:"name"`
+	Loaded bool   `json:"loaded"`
+	// InitState is empty for built-in modules.
+	InitState  string `json:"initState,omitempty"`
+	SrcVersion string `json:"srcVersion,omitempty"`
+	Version    string `json:"version,omitempty"`
+	// ImagePath is the module file in the image; ImageError says why it is empty.
+	ImagePath       string `json:"imagePath,omitempty"`
+	ImageError      string `json:"imageError,omitempty"`
+	ImageSrcVersion string `json:"imageSrcVersion,omitempty"`
+	ImageVersion    string `json:"imageVersion,omitempty"`
+}
+
+// MatchesImage returns true if the module is live and its srcversion and version match those found in the image.
+func (ms *ModuleStatus) MatchesImage() bool {
+	return ms.Loaded &&
+		(ms.InitState == "" || ms.InitState == "live") &&
+		ms.ImagePath != "" &&
+		(ms.ImageSrcVersion == "" || ms.ImageSrcVersion == ms.SrcVersion) &&
+		(ms.ImageVersion == "" || ms.ImageVersion == ms.Version)
+}
+
+type ModuleVerifier interface {
+	// ModuleStatuses returns the status of the module configured in cfg and of all modules in its loading order.
+	ModuleStatuses(cfg *kmmv1beta1.ModuleConfig, fsDir string) ([]ModuleStatus, error)
+	VerifyCompatible(cfg *kmmv1beta1.ModuleConfig, fsDir string) error
+	VerifyLoaded(cfg *kmmv1beta1.ModuleConfig, fsDir string) error
+	// VerifyLive makes sure that the module configured in cfg, and all modules in its loading order, are live in the
+	// kernel, without reading the image.
+	VerifyLive(cfg *kmmv1beta1.ModuleConfig) error
+}
+
+type moduleVerifierImpl struct {
+	logger       logr.Logger
+	sysModuleDir string
+}
+
+func NewModuleVerifier(sysModuleDir string, logger logr.Logger) ModuleVerifier {
+	return &moduleVerifierImpl{
+		logger:       logger,
+		sysModuleDir: sysModuleDir,
+	}
+}
+
+var kernelReleaseLocation = KernelReleaseLocation
+
+// VerifyCompatible makes sure that the modules configured in cfg, and the dependencies found in the image mounted at
+// fsDir, were built for the running kernel.
+// It returns an Error with ReasonKernelMismatch if that is not the case.
+func (mv *moduleVerifierImpl) VerifyCompatible(cfg *kmmv1beta1.ModuleConfig, fsDir string) error {
+	if cfg.Modprobe.RawArgs != nil {
+		mv.logger.Info("RawArgs are used; cannot determine which modules will be loaded, skipping compatibility check")
+		return nil
+	}
+
+	release, ok, err := readSysfsValue(kernelReleaseLocation)
+	if err != nil {
+		return fmt.Errorf("could not determine the running kernel release: %v", err)
+	}
+
+	if !ok {
+		return fmt.Errorf("could not determine the running kernel release: %s does not exist", kernelReleaseLocation)
+	}
+
+	if cfg.KernelVersion != release {
+		return NewError(
+			ReasonKernelMismatch,
+			fmt.Errorf("the configuration targets kernel %s but the node is running %s", cfg.KernelVersion, release),
+		)
+	}
+
+	kmodDir := moduleTreeDir(fsDir, cfg)
+
+	if _, err = os.Stat(kmodDir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return NewError(
+				ReasonKernelMismatch,
+				fmt.Errorf("the image does not contain modules for kernel %s: %s does not exist", release, kmodDir),
+			)
+		}
+
+		return fmt.Errorf("could not stat %s: %v", kmodDir, err)
+	}
+
+	checked := sets.New[string]()
+
+	for _, name := range append([]string{cfg.Modprobe.ModuleName}, cfg.Modprobe.ModulesLoadingOrder...) {
+		if err = mv.verifyVermagic(kmodDir, name, release, false, checked); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mv *moduleVerifierImpl) verifyVermagic(kmodDir, name, release string, isDependency bool, checked sets.Set[string]) error {
+	normalized := normalizeModuleName(name)
+
+	if checked.Has(normalized) {
+		return nil
+	}
+
+	checked.Insert(normalized)
+
+	logger := mv.logger.WithValues("module", name)
+
+	koPath, err := FindModuleFile(kmodDir, name)
+	if err != nil {
+		if isDependency {
+			logger.Info(utils.WarnString("Dependency not found in the image; not checking it"), "error", err)
+			return nil
+		}
+
+		return fmt.Errorf("could not find the module file in the image: %v", err)
+	}
+
+	mi, err := ReadModInfo(koPath)
+	if err != nil {
+		return fmt.Errorf("could not read modinfo: %v", err)
+	}
+
+	if modName := mi.Get("name"); modName != "" && modName != normalized {
+		return fmt.Errorf("%s declares module name %q instead of %q", koPath, modName, normalized)
+	}
+
+	vermagic := mi.Get("vermagic")
+
+	logger.V(1).Info("Checking vermagic", "path", koPath, "vermagic", vermagic)
+
+	if fields := strings.Fields(vermagic); len(fields) == 0 || fields[0] != release {
+		return NewError(
+			ReasonKernelMismatch,
+			fmt.Errorf("%s has vermagic %q, which does not match the running kernel %s", koPath, vermagic, release),
+		)
+	}
+
+	for _, dep := range strings.Split(mi.Get("depends"), ",") {
+		if dep == "" {
+			continue
+		}
+
+		if err = mv.verifyVermagic(kmodDir, dep, release, true, checked); err != nil {
+			return fmt.Errorf("dependency of %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// VerifyLoaded makes sure that the module configured in cfg, and all modules in its loading order, are live in the
+// kernel and that their srcversion and version match the .ko files found in the image mounted at fsDir.
+// When RawArgs are used, the files loaded by modprobe are not known; only the liveness of the module is checked.
+func (mv *moduleVerifierImpl) VerifyLoaded(cfg *kmmv1beta1.ModuleConfig, fsDir string) error {
+	if cfg.Modprobe.RawArgs != nil {
+		mv.logger.Info("RawArgs are used; only checking that the module is live", "module", cfg.Modprobe.ModuleName)
+		return mv.VerifyLive(cfg)
+	}
+
+	names := cfg.Modprobe.ModulesLoadingOrder
+	if len(names) == 0 {
+		names = []string{cfg.Modprobe.ModuleName}
+	}
+
+	kmodDir := moduleTreeDir(fsDir, cfg)
+
+	for _, name := range names {
+		if err := mv.verifyModule(name, kmodDir); err != nil {
+			return fmt.Errorf("could not verify module %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (mv *moduleVerifierImpl) verifyModule(name, kmodDir string) error {
+	ms, err := mv.moduleStatus(name, kmodDir)
+	if err != nil {
+		return err
+	}
+
+	logger := mv.logger.WithValues("module", name)
+
+	if err = mv.checkLive(ms); err != nil {
+		return err
+	}
+
+	if ms.ImagePath == "" {
+		return fmt.Errorf("could not find the module file in the image: %s", ms.ImageError)
+	}
+
+	keys := []struct {
+		name             string
+		actual, expected string
+	}{
+		{name: "srcversion", actual: ms.SrcVersion, expected: ms.ImageSrcVersion},
+		{name: "version", actual: ms.Version, expected: ms.ImageVersion},
+	}
+
+	for _, key := range keys {
+		if key.expected == "" {
+			logger.V(1).Info("Key not present in the image's module; not comparing it", "key", key.name)
+			continue
+		}
+
+		if key.actual != key.expected {
+			return fmt.Errorf("loaded %s %q does not match %q from %s", key.name, key.actual, key.expected, ms.ImagePath)
+		}
+
+		logger.V(1).Info("Loaded module matches the image", "key", key.name, "value", key.actual)
+	}
+
+	logger.Info("Module is loaded")
+
+	return nil
+}
+
+// VerifyLive returns an Error with ReasonModuleNotLoaded if the module configured in cfg, or one of the modules in its
+// loading order, is not live in the kernel.
+// When RawArgs are used, only the module is checked.
+func (mv *moduleVerifierImpl) VerifyLive(cfg *kmmv1beta1.ModuleConfig) error {
+	names := cfg.Modprobe.ModulesLoadingOrder
+	if len(names) == 0 || cfg.Modprobe.RawArgs != nil {
+		names = []string{cfg.Modprobe.ModuleName}
+	}
+
+	for _, name := range names {
+		ms, err := mv.sysModuleStatus(name)
+		if err != nil {
+			return fmt.Errorf("could not get the status of module %s: %v", name, err)
+		}
+
+		if err = mv.checkLive(ms); err != nil {
+			return NewError(ReasonModuleNotLoaded, fmt.Errorf("module %s: %v", name, err))
+		}
+
+		mv.logger.V(1).Info("Module is live", "module", name)
+	}
+
+	return nil
+}
+
+func (mv *moduleVerifierImpl) checkLive(ms *ModuleStatus) error {
+	if !ms.Loaded {
+		return fmt.Errorf("module is not loaded: %s does not exist", filepath.Join(mv.sysModuleDir, ms.Name))
+	}
+
+	if ms.InitState != "" && ms.InitState != "live" {
+		return fmt.Errorf("module is in state %q instead of live", ms.InitState)
+	}
+
+	return nil
+}
+
+func (mv *moduleVerifierImpl) ModuleStatuses(cfg *kmmv1beta1.ModuleConfig, fsDir string) ([]ModuleStatus, error) {
+	names := make([]string, 0, 1+len(cfg.Modprobe.ModulesLoadingOrder))
+	seen := sets.New[string]()
+
+	for _, name := range append([]string{cfg.Modprobe.ModuleName}, cfg.Modprobe.ModulesLoadingOrder...) {
+		if name == "" || seen.Has(normalizeModuleName(name)) {
+			continue
+		}
+
+		seen.Insert(normalizeModuleName(name))
+		names = append(names, name)
+	}
+
+	kmodDir := moduleTreeDir(fsDir, cfg)
+	statuses := make([]ModuleStatus, 0, len(names))
+
+	for _, name := range names {
+		ms, err := mv.moduleStatus(name, kmodDir)
+		if err != nil {
+			return nil, fmt.Errorf("could not get the status of module %s: %v", name, err)
+		}
+
+		statuses = append(statuses, *ms)
+	}
+
+	return statuses, nil
+}
+
+// moduleStatus reads the state of a module from sysfs and its versions from its file under kmodDir.
+func (mv *moduleVerifierImpl) moduleStatus(name, kmodDir string) (*ModuleStatus, error) {
+	ms, err := mv.sysModuleStatus(name)
+	if err != nil {
+		return nil, err
+	}
+
+	koPath, err := FindModuleFile(kmodDir, name)
+	if err != nil {
+		ms.ImageError = err.Error()
+		return ms, nil
+	}
+
+	mv.logger.V(1).Info("Reading modinfo", "module", name, "path", koPath)
+
+	mi, err := ReadModInfo(koPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read modinfo: %v", err)
+	}
+
+	ms.ImagePath = koPath
+	ms.ImageSrcVersion = mi.Get("srcversion")
+	ms.ImageVersion = mi.Get("version")
+
+	return ms, nil
+}
+
+// sysModuleStatus reads the state of a module from sysfs.
+func (mv *moduleVerifierImpl) sysModuleStatus(name string) (*ModuleStatus, error) {
+	ms := ModuleStatus{Name: normalizeModuleName(name)}
+	sysDir := filepath.Join(mv.sysModuleDir, ms.Name)
+
+	if _, err := os.Stat(sysDir); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("could not stat %s: %v", sysDir, err)
+		}
+	} else {
+		ms.Loaded = true
+
+		// initstate is absent for built-in modules.
+		attrs := map[string]*string{
+			"initstate":  &ms.InitState,
+			"srcversion": &ms.SrcVersion,
+			"version":    &ms.Version,
+		}
+
+		for attr, value := range attrs {
+			if *value, _, err = readSysfsValue(filepath.Join(sysDir, attr)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &ms, nil
+}
+
+// FindModuleFile looks for the kernel module file for name under dir; it may be compressed.
+// Dashes and underscores are considered equivalent.
+func FindModuleFile(dir, name string) (string, error) {
+	var (
+		normalized = normalizeModuleName(name)
+		path       string
+	)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		if modName, ok := moduleNameFromPath(p); ok && modName == normalized {
+			path = p
+			return filepath.SkipAll
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("error while walking %s: %v", dir, err)
+	}
+
+	if path == "" {
+		return "", fmt.Errorf("no kernel module file for %s in %s", name, dir)
+	}
+
+	return path, nil
+}
+
+// moduleTreeDir returns the lib/modules/<kernel version> directory of the image mounted at fsDir.
+func moduleTreeDir(fsDir string, cfg *kmmv1beta1.ModuleConfig) string {
+	return filepath.Join(fsDir, cfg.Modprobe.DirName, "lib", "modules", cfg.KernelVersion)
+}
+
+// readSysfsValue returns the trimmed contents of a sysfs or procfs attribute, and false if it does not exist.
+func readSysfsValue(path string) (string, bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", false, nil
+		}
+
+		return "", false, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return strings.TrimSpace(string(b)), true, nil
+}
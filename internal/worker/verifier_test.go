@@ -0,0 +1,418 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("moduleVerifierImpl_VerifyCompatible", func() {
+	const fsDir = "testdata/modules"
+
+	var mv ModuleVerifier
+
+	BeforeEach(func() {
+		kernelReleaseLocation = filepath.Join(GinkgoT().TempDir(), "osrelease")
+		mv = NewModuleVerifier("", GinkgoLogr)
+
+		Expect(
+			os.WriteFile(kernelReleaseLocation, []byte(testdataKernelVersion+"\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	AfterEach(func() {
+		kernelReleaseLocation = KernelReleaseLocation
+	})
+
+	makeConfig := func(kernelVersion string, modules ...string) *kmmv1beta1.ModuleConfig {
+		cfg := &kmmv1beta1.ModuleConfig{
+			KernelVersion: kernelVersion,
+			Modprobe: kmmv1beta1.ModprobeSpec{
+				ModuleName: modules[0],
+				DirName:    "/",
+			},
+		}
+
+		if len(modules) > 1 {
+			cfg.Modprobe.ModulesLoadingOrder = modules
+		}
+
+		return cfg
+	}
+
+	It("should skip the check when RawArgs are used", func() {
+		cfg := &kmmv1beta1.ModuleConfig{
+			Modprobe: kmmv1beta1.ModprobeSpec{
+				RawArgs: &kmmv1beta1.ModprobeArgs{Load: []string{"a"}},
+			},
+		}
+
+		Expect(
+			mv.VerifyCompatible(cfg, fsDir),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the kernel release cannot be read", func() {
+		kernelReleaseLocation = "/non/existent/path"
+
+		err := mv.VerifyCompatible(makeConfig(testdataKernelVersion, "kmod_a"), fsDir)
+		Expect(err).To(HaveOccurred())
+		Expect(ReasonFromError(err)).To(BeEmpty())
+	})
+
+	DescribeTable(
+		"should return a KernelMismatch error",
+		func(cfg *kmmv1beta1.ModuleConfig) {
+			err := mv.VerifyCompatible(cfg, fsDir)
+			Expect(err).To(HaveOccurred())
+			Expect(ReasonFromError(err)).To(Equal(ReasonKernelMismatch))
+		},
+		Entry("config for another kernel", makeConfig("5.0.0-other", "kmod_a")),
+		Entry("module with another vermagic", makeConfig(testdataKernelVersion, "kmod_c")),
+		Entry("dependency with another vermagic", makeConfig(testdataKernelVersion, "kmod_d")),
+		Entry("module in the loading order with another vermagic", makeConfig(testdataKernelVersion, "kmod_a", "kmod_c")),
+	)
+
+	It("should return a KernelMismatch error if the image has no modules for the kernel", func() {
+		kernelReleaseLocation = filepath.Join(GinkgoT().TempDir(), "osrelease")
+
+		Expect(
+			os.WriteFile(kernelReleaseLocation, []byte("1.2.3"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		err := mv.VerifyCompatible(makeConfig("1.2.3", "kmod_a"), fsDir)
+		Expect(err).To(HaveOccurred())
+		Expect(ReasonFromError(err)).To(Equal(ReasonKernelMismatch))
+	})
+
+	It("should return an error if the module is not in the image", func() {
+		Expect(
+			mv.VerifyCompatible(makeConfig(testdataKernelVersion, "kmod_missing"), fsDir),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should work as expected", func() {
+		Expect(
+			mv.VerifyCompatible(makeConfig(testdataKernelVersion, "kmod-a", "kmod-a", "kmod_b"), fsDir),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
+
+var _ = Describe("moduleVerifierImpl_VerifyLoaded", func() {
+	var (
+		mv     ModuleVerifier
+		sysDir string
+	)
+
+	writeSysModule := func(name string, attrs map[string]string) {
+		GinkgoHelper()
+
+		dir := filepath.Join(sysDir, name)
+
+		Expect(
+			os.MkdirAll(dir, 0755),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		for k, v := range attrs {
+			Expect(
+				os.WriteFile(filepath.Join(dir, k), []byte(v+"\n"), 0644),
+			).NotTo(
+				HaveOccurred(),
+			)
+		}
+	}
+
+	cfg := &kmmv1beta1.ModuleConfig{
+		KernelVersion: testdataKernelVersion,
+		Modprobe: kmmv1beta1.ModprobeSpec{
+			ModuleName:          "kmod-a",
+			DirName:             "/",
+			ModulesLoadingOrder: []string{"kmod-a", "kmod_b"},
+		},
+	}
+
+	const fsDir = "testdata/modules"
+
+	BeforeEach(func() {
+		sysDir = GinkgoT().TempDir()
+		mv = NewModuleVerifier(sysDir, GinkgoLogr)
+	})
+
+	It("should only check that the module is live when RawArgs are used", func() {
+		rawCfg := &kmmv1beta1.ModuleConfig{
+			Modprobe: kmmv1beta1.ModprobeSpec{
+				ModuleName:          "kmod_a",
+				ModulesLoadingOrder: []string{"kmod_a", "kmod_b"},
+				RawArgs:             &kmmv1beta1.ModprobeArgs{Load: []string{"a"}},
+			},
+		}
+
+		err := mv.VerifyLoaded(rawCfg, fsDir)
+		Expect(err).To(MatchError(ContainSubstring("kmod_a")))
+		Expect(ReasonFromError(err)).To(Equal(ReasonModuleNotLoaded))
+
+		writeSysModule("kmod_a", map[string]string{"initstate": "live"})
+
+		Expect(
+			mv.VerifyLoaded(rawCfg, fsDir),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if a module is not loaded", func() {
+		writeSysModule("kmod_a", map[string]string{"initstate": "live", "version": "1.2.3", "srcversion": "ABCDEF0123456789ABCDEF0"})
+
+		Expect(
+			mv.VerifyLoaded(cfg, fsDir),
+		).To(
+			MatchError(ContainSubstring("kmod_b")),
+		)
+	})
+
+	It("should return an error if a module is not live", func() {
+		writeSysModule("kmod_a", map[string]string{"initstate": "coming"})
+
+		Expect(
+			mv.VerifyLoaded(cfg, fsDir),
+		).To(
+			MatchError(ContainSubstring("coming")),
+		)
+	})
+
+	It("should return an error if the version does not match", func() {
+		writeSysModule("kmod_a", map[string]string{"initstate": "live", "version": "1.0.0", "srcversion": "ABCDEF0123456789ABCDEF0"})
+		writeSysModule("kmod_b", map[string]string{"initstate": "live", "srcversion": "0123456789ABCDEF0123456"})
+
+		Expect(
+			mv.VerifyLoaded(cfg, fsDir),
+		).To(
+			MatchError(ContainSubstring("1.0.0")),
+		)
+	})
+
+	It("should return an error if the srcversion is missing", func() {
+		writeSysModule("kmod_a", map[string]string{"initstate": "live", "version": "1.2.3"})
+
+		Expect(
+			mv.VerifyLoaded(cfg, fsDir),
+		).To(
+			MatchError(ContainSubstring("srcversion")),
+		)
+	})
+
+	It("should return an error if the module file is not in the image", func() {
+		writeSysModule("other", map[string]string{"initstate": "live"})
+
+		otherCfg := cfg.DeepCopy()
+		otherCfg.Modprobe.ModuleName = "other"
+		otherCfg.Modprobe.ModulesLoadingOrder = nil
+
+		Expect(
+			mv.VerifyLoaded(otherCfg, fsDir),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should work as expected", func() {
+		writeSysModule("kmod_a", map[string]string{"initstate": "live", "version": "1.2.3", "srcversion": "ABCDEF0123456789ABCDEF0"})
+		writeSysModule("kmod_b", map[string]string{"initstate": "live", "srcversion": "0123456789ABCDEF0123456"})
+
+		Expect(
+			mv.VerifyLoaded(cfg, fsDir),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
+
+var _ = Describe("moduleVerifierImpl_VerifyLive", func() {
+	var (
+		mv     ModuleVerifier
+		sysDir string
+	)
+
+	cfg := &kmmv1beta1.ModuleConfig{
+		Modprobe: kmmv1beta1.ModprobeSpec{
+			ModuleName:          "kmod-a",
+			ModulesLoadingOrder: []string{"kmod-a", "kmod_b"},
+		},
+	}
+
+	writeInitState := func(name, state string) {
+		GinkgoHelper()
+
+		dir := filepath.Join(sysDir, name)
+
+		Expect(
+			os.MkdirAll(dir, 0755),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(dir, "initstate"), []byte(state+"\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+	}
+
+	BeforeEach(func() {
+		sysDir = GinkgoT().TempDir()
+		mv = NewModuleVerifier(sysDir, GinkgoLogr)
+	})
+
+	It("should only check the module when RawArgs are used", func() {
+		rawCfg := &kmmv1beta1.ModuleConfig{
+			Modprobe: kmmv1beta1.ModprobeSpec{
+				ModuleName:          "kmod-a",
+				ModulesLoadingOrder: []string{"kmod-a", "kmod_b"},
+				RawArgs:             &kmmv1beta1.ModprobeArgs{Load: []string{"a"}},
+			},
+		}
+
+		err := mv.VerifyLive(rawCfg)
+		Expect(err).To(MatchError(ContainSubstring("kmod-a")))
+		Expect(ReasonFromError(err)).To(Equal(ReasonModuleNotLoaded))
+
+		writeInitState("kmod_a", "live")
+
+		Expect(
+			mv.VerifyLive(rawCfg),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return a ModuleNotLoaded error if a module is not loaded", func() {
+		writeInitState("kmod_a", "live")
+
+		err := mv.VerifyLive(cfg)
+		Expect(err).To(MatchError(ContainSubstring("kmod_b")))
+		Expect(ReasonFromError(err)).To(Equal(ReasonModuleNotLoaded))
+	})
+
+	It("should return a ModuleNotLoaded error if a module is not live", func() {
+		writeInitState("kmod_a", "live")
+		writeInitState("kmod_b", "going")
+
+		err := mv.VerifyLive(cfg)
+		Expect(err).To(MatchError(ContainSubstring("going")))
+		Expect(ReasonFromError(err)).To(Equal(ReasonModuleNotLoaded))
+	})
+
+	It("should only check the module if there is no loading order", func() {
+		writeInitState("kmod_a", "live")
+
+		singleCfg := cfg.DeepCopy()
+		singleCfg.Modprobe.ModulesLoadingOrder = nil
+
+		Expect(
+			mv.VerifyLive(singleCfg),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should work as expected", func() {
+		writeInitState("kmod_a", "live")
+		writeInitState("kmod_b", "live")
+
+		Expect(
+			mv.VerifyLive(cfg),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
+
+var _ = Describe("moduleVerifierImpl_ModuleStatuses", func() {
+	const fsDir = "testdata/modules"
+
+	It("should report loaded and missing modules", func() {
+		sysDir := GinkgoT().TempDir()
+
+		modDir := filepath.Join(sysDir, "kmod_a")
+		Expect(os.MkdirAll(modDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(modDir, "initstate"), []byte("live\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(modDir, "version"), []byte("1.0.0\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(modDir, "srcversion"), []byte("ABCDEF0123456789ABCDEF0\n"), 0644)).To(Succeed())
+
+		cfg := &kmmv1beta1.ModuleConfig{
+			KernelVersion: testdataKernelVersion,
+			Modprobe: kmmv1beta1.ModprobeSpec{
+				ModuleName:          "kmod-a",
+				DirName:             "/",
+				ModulesLoadingOrder: []string{"kmod-a", "kmod_b", "other"},
+			},
+		}
+
+		statuses, err := NewModuleVerifier(sysDir, GinkgoLogr).ModuleStatuses(cfg, fsDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(3))
+
+		Expect(statuses[0]).To(Equal(ModuleStatus{
+			Name:            "kmod_a",
+			Loaded:          true,
+			InitState:       "live",
+			SrcVersion:      "ABCDEF0123456789ABCDEF0",
+			Version:         "1.0.0",
+			ImagePath:       filepath.Join(fsDir, "lib/modules", testdataKernelVersion, "extra/kmod-a.ko"),
+			ImageSrcVersion: "ABCDEF0123456789ABCDEF0",
+			ImageVersion:    "1.2.3",
+		}))
+		Expect(statuses[0].MatchesImage()).To(BeFalse())
+
+		Expect(statuses[1].Loaded).To(BeFalse())
+		Expect(statuses[1].ImagePath).NotTo(BeEmpty())
+		Expect(statuses[1].MatchesImage()).To(BeFalse())
+
+		Expect(statuses[2].ImagePath).To(BeEmpty())
+		Expect(statuses[2].ImageError).To(ContainSubstring("no kernel module file for other"))
+	})
+})
+
+var _ = Describe("FindModuleFile", func() {
+	It("should treat dashes and underscores as equivalent", func() {
+		Expect(
+			FindModuleFile(testdataModulesDir, "kmod_a"),
+		).To(
+			Equal(testdataModulesDir + "/extra/kmod-a.ko"),
+		)
+
+		Expect(
+			FindModuleFile(testdataModulesDir, "kmod-b"),
+		).To(
+			Equal(testdataModulesDir + "/extra/kmod_b.ko"),
+		)
+	})
+
+	It("should find compressed modules", func() {
+		Expect(
+			FindModuleFile(testdataModulesDir, "kmod_e"),
+		).To(
+			Equal(testdataModulesDir + "/extra/kmod_e.ko.xz"),
+		)
+	})
+
+	It("should return an error if the module cannot be found", func() {
+		_, err := FindModuleFile(testdataModulesDir, "kmod_missing")
+		Expect(err).To(HaveOccurred())
+	})
+})
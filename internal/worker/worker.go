@@ -2,14 +2,16 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/go-logr/logr"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
-	cp "github.com/otiai10/copy"
 )
 
 //go:generate mockgen -source=worker.go -package=worker -destination=mock_worker.go
@@ -17,36 +19,100 @@ import (
 type Worker interface {
 	LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error
 	SetFirmwareClassPath(value string) error
+	// SetParameters updates the parameters in names of the loaded module through sysfs, with the values set in cfg.
+	SetParameters(cfg *kmmv1beta1.ModuleConfig, names []string) error
 	UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error
+	VerifyFirmware(firmwareMountPath string) error
+	// VerifyKmod makes sure that the modules of cfg are still live in the kernel, without pulling the image.
+	VerifyKmod(cfg *kmmv1beta1.ModuleConfig) error
+	// Render returns what LoadKmod or UnloadKmod would do for cfg, without loading or unloading anything.
+	Render(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, action Action, firmwareMountPath string) (*Plan, error)
+	// Status reports whether the modules of cfg are loaded and match the image, and whether their firmware was modified.
+	Status(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) (*Status, error)
 }
 
 type worker struct {
-	im     ImageMounter
-	logger logr.Logger
-	mr     ModprobeRunner
+	bm       BlacklistManager
+	dm       Depmod
+	fm       FirmwareManager
+	hr       HookRunner
+	im       ImageMounter
+	logger   logr.Logger
+	mr       ModprobeRunner
+	mv       ModuleVerifier
+	ps       ParameterSetter
+	timeouts Timeouts
+	uc       UsageChecker
 }
 
-func NewWorker(im ImageMounter, mr ModprobeRunner, logger logr.Logger) Worker {
+func NewWorker(
+	im ImageMounter,
+	mr ModprobeRunner,
+	mv ModuleVerifier,
+	uc UsageChecker,
+	fm FirmwareManager,
+	dm Depmod,
+	hr HookRunner,
+	bm BlacklistManager,
+	ps ParameterSetter,
+	timeouts Timeouts,
+	logger logr.Logger,
+) Worker {
 	return &worker{
-		im:     im,
-		logger: logger,
-		mr:     mr,
+		bm:       bm,
+		dm:       dm,
+		fm:       fm,
+		hr:       hr,
+		im:       im,
+		logger:   logger,
+		mr:       mr,
+		mv:       mv,
+		ps:       ps,
+		timeouts: timeouts,
+		uc:       uc,
 	}
 }
 
 func (w *worker) LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error {
-	imageName := cfg.ContainerImage
-
-	fsDir, err := w.im.MountImage(ctx, imageName, cfg)
+	fsDir, err := w.mountImage(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to mount image %s: %v", imageName, err)
+		return err
+	}
+
+	return withTimeout(ctx, w.timeouts.Load, "loading the module", func(ctx context.Context) error {
+		return w.loadKmod(ctx, cfg, fsDir, firmwareMountPath)
+	})
+}
+
+// inTreeModulesToRemove returns the in-tree modules of cfg, including the one in its deprecated field.
+func inTreeModulesToRemove(cfg *kmmv1beta1.ModuleConfig) []string {
+	if len(cfg.InTreeModulesToRemove) == 0 && cfg.InTreeModuleToRemove != "" {
+		return []string{cfg.InTreeModuleToRemove}
+	}
+
+	return cfg.InTreeModulesToRemove
+}
+
+func (w *worker) loadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, fsDir, firmwareMountPath string) error {
+	var err error
+
+	if err = w.mv.VerifyCompatible(cfg, fsDir); err != nil {
+		return WithPhase(PhaseVerifyCompatible, fmt.Errorf("module is not compatible with the running kernel: %w", err))
+	}
+
+	// Blacklisting the in-tree modules first prevents them from being loaded again before they are removed, and after a
+	// reboot.
+	inTreeModules := inTreeModulesToRemove(cfg)
+
+	if err = w.bm.Write(inTreeModules); err != nil {
+		return WithPhase(PhaseBlacklistInTreeModules, fmt.Errorf("could not blacklist the in-tree modules: %v", err))
 	}
 
-	if inTree := cfg.InTreeModuleToRemove; inTree != "" {
+	for _, inTree := range inTreeModules {
 		w.logger.Info("Unloading in-tree module", "name", inTree)
 
 		if err = w.mr.Run(ctx, "-rv", inTree); err != nil {
-			return fmt.Errorf("could not remove in-tree module %s: %v", inTree, err)
+			return WithPhase(PhaseRemoveInTreeModule, fmt.Errorf("could not remove in-tree module %s: %w", inTree, err))
 		}
 	}
 
@@ -54,37 +120,36 @@ func (w *worker) LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, fir
 	if cfg.Modprobe.FirmwarePath != "" {
 		imageFirmwarePath := filepath.Join(fsDir, cfg.Modprobe.FirmwarePath)
 		w.logger.Info("preparing firmware for loading", "image directory", imageFirmwarePath, "host mount directory", firmwareMountPath)
-		options := cp.Options{
-			OnError: func(src, dest string, err error) error {
-				if err != nil {
-					return fmt.Errorf("internal copy error: failed to copy from %s to %s: %v", src, dest, err)
-				}
-				return nil
-			},
-		}
-		if err = cp.Copy(imageFirmwarePath, firmwareMountPath, options); err != nil {
-			return fmt.Errorf("failed to copy firmware from path %s to path %s: %v", imageFirmwarePath, firmwareMountPath, err)
+
+		if err = w.fm.Install(imageFirmwarePath, firmwareMountPath); err != nil {
+			return WithPhase(
+				PhaseInstallFirmware,
+				fmt.Errorf("failed to install firmware from path %s to path %s: %w", imageFirmwarePath, firmwareMountPath, err),
+			)
 		}
 	}
 
-	moduleName := cfg.Modprobe.ModuleName
+	if err = w.ensureModulesDep(cfg, fsDir); err != nil {
+		return WithPhase(PhaseDepmod, err)
+	}
 
-	var args []string
+	if err = w.hr.RunHook(ctx, cfg, fsDir, HookPreLoad); err != nil {
+		return WithPhase(PhasePreLoadHook, err)
+	}
 
-	if cfg.Modprobe.RawArgs != nil {
-		args = cfg.Modprobe.RawArgs.Load
-	} else {
-		args = []string{"-vd", filepath.Join(fsDir, cfg.Modprobe.DirName)}
+	if err = w.mr.Run(ctx, LoadArgs(cfg, fsDir)...); err != nil {
+		return WithPhase(PhaseModprobe, err)
+	}
 
-		if cfg.Modprobe.Args != nil {
-			args = append(args, cfg.Modprobe.Args.Load...)
-		}
+	if err = w.hr.RunHook(ctx, cfg, fsDir, HookPostLoad); err != nil {
+		return WithPhase(PhasePostLoadHook, err)
+	}
 
-		args = append(args, moduleName)
-		args = append(args, cfg.Modprobe.Parameters...)
+	if err = w.mv.VerifyLoaded(cfg, fsDir); err != nil {
+		return WithPhase(PhaseVerifyLoaded, fmt.Errorf("module was not loaded as expected: %v", err))
 	}
 
-	return w.mr.Run(ctx, args...)
+	return nil
 }
 
 var firmwareClassPathLocation = FirmwareClassPathLocation
@@ -112,61 +177,199 @@ func (w *worker) SetFirmwareClassPath(value string) error {
 }
 
 func (w *worker) UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error {
-	imageName := cfg.ContainerImage
-
-	fsDir, err := w.im.MountImage(ctx, imageName, cfg)
+	fsDir, err := w.mountImage(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to mount image %s: %v", imageName, err)
+		return err
 	}
 
+	return withTimeout(ctx, w.timeouts.Unload, "unloading the module", func(ctx context.Context) error {
+		return w.unloadKmod(ctx, cfg, fsDir, firmwareMountPath)
+	})
+}
+
+func (w *worker) unloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, fsDir, firmwareMountPath string) error {
 	moduleName := cfg.Modprobe.ModuleName
 
-	var args []string
+	// The hook may release the module, so it runs before the usage check.
+	if err := w.hr.RunHook(ctx, cfg, fsDir, HookPreUnload); err != nil {
+		return WithPhase(PhasePreUnloadHook, err)
+	}
 
-	if cfg.Modprobe.RawArgs != nil {
-		args = cfg.Modprobe.RawArgs.Unload
-	} else {
-		args = []string{"-rvd", filepath.Join(fsDir, cfg.Modprobe.DirName)}
+	mode, err := w.checkModuleUsage(ctx, cfg)
+	if err != nil {
+		return WithPhase(PhaseCheckModuleUsage, err)
+	}
 
-		if cfg.Modprobe.Args != nil {
-			args = append(args, cfg.Modprobe.Args.Unload...)
-		}
+	// The Module is not configured on the node anymore, even if its module is left loaded.
+	if err = w.bm.Remove(); err != nil {
+		return WithPhase(PhaseRemoveBlacklist, fmt.Errorf("could not remove the blacklist: %v", err))
+	}
+
+	if mode == unloadSkip {
+		return nil
+	}
 
-		args = append(args, moduleName)
+	if cfg.Modprobe.RawArgs != nil && mode == unloadForce {
+		w.logger.Info(utils.WarnString("RawArgs are used; not adding --force"))
+	}
+
+	if err = w.ensureModulesDep(cfg, fsDir); err != nil {
+		return WithPhase(PhaseDepmod, err)
 	}
 
 	w.logger.Info("Unloading module", "name", moduleName)
 
-	if err = w.mr.Run(ctx, args...); err != nil {
-		return fmt.Errorf("could not unload module %s: %v", moduleName, err)
+	if err = w.mr.Run(ctx, UnloadArgs(cfg, fsDir, mode == unloadForce)...); err != nil {
+		err = fmt.Errorf("could not unload module %s: %w", moduleName, err)
+
+		if classifyMessages(err.Error()) == ReasonModuleInUse {
+			// The module was taken between the check and the removal.
+			err = NewError(ReasonModuleInUse, err)
+		}
+
+		return WithPhase(PhaseModprobe, err)
 	}
 
-	//remove firmware files only (no directories)
+	// remove the firmware files that no other module uses
 	if cfg.Modprobe.FirmwarePath != "" {
 		imageFirmwarePath := filepath.Join(fsDir, cfg.Modprobe.FirmwarePath)
-		err = filepath.Walk(imageFirmwarePath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			if !info.IsDir() {
-				relPath, err := filepath.Rel(imageFirmwarePath, path)
-				if err != nil {
-					w.logger.Info(utils.WarnString("failed to get relative path"), "imageFirmwarePath", imageFirmwarePath, "path", path, "error", err)
-					return nil
-				}
-				fileToRemove := filepath.Join(firmwareMountPath, relPath)
-				w.logger.Info("Removing firmware file", "file", fileToRemove)
-				err = os.Remove(fileToRemove)
-				if err != nil {
-					w.logger.Info(utils.WarnString("failed to delete file"), "file", fileToRemove, "error", err)
-				}
-			}
-			return nil
-		})
-		if err != nil {
+
+		if err = w.fm.Remove(imageFirmwarePath, firmwareMountPath); err != nil {
 			w.logger.Info(utils.WarnString("failed to remove all firmware blobs"), "error", err)
 		}
 	}
 
+	if err = w.hr.RunHook(ctx, cfg, fsDir, HookPostUnload); err != nil {
+		return WithPhase(PhasePostUnloadHook, err)
+	}
+
 	return nil
 }
+
+// mountImage pulls and mounts the image of cfg within the pull timeout, and returns the directory it is mounted in.
+func (w *worker) mountImage(ctx context.Context, cfg *kmmv1beta1.ModuleConfig) (string, error) {
+	imageName := cfg.ContainerImage
+
+	var fsDir string
+
+	err := withTimeout(ctx, w.timeouts.Pull, "pulling image "+imageName, func(ctx context.Context) error {
+		dir, err := w.im.MountImage(ctx, imageName, cfg)
+		if err != nil {
+			return NewError(classifyPullError(err), fmt.Errorf("failed to mount image %s: %w", imageName, err))
+		}
+
+		fsDir = dir
+
+		return nil
+	})
+	if err != nil {
+		return "", WithPhase(PhasePullImage, err)
+	}
+
+	return fsDir, nil
+}
+
+// ensureModulesDep makes sure that modprobe can resolve the dependencies of the modules in the image mounted at fsDir.
+func (w *worker) ensureModulesDep(cfg *kmmv1beta1.ModuleConfig, fsDir string) error {
+	if cfg.Modprobe.RawArgs != nil {
+		w.logger.V(1).Info("RawArgs are used; not checking modules.dep")
+		return nil
+	}
+
+	modDir := moduleTreeDir(fsDir, cfg)
+
+	if _, err := os.Stat(modDir); errors.Is(err, fs.ErrNotExist) {
+		// Let modprobe report the missing directory.
+		w.logger.Info(utils.WarnString("Modules directory not found; not checking modules.dep"), "directory", modDir)
+		return nil
+	}
+
+	if err := w.dm.EnsureModulesDep(modDir); err != nil {
+		return fmt.Errorf("could not generate the module dependencies in %s: %w", modDir, err)
+	}
+
+	return nil
+}
+
+func (w *worker) VerifyFirmware(firmwareMountPath string) error {
+	return w.fm.Verify(firmwareMountPath)
+}
+
+func (w *worker) VerifyKmod(cfg *kmmv1beta1.ModuleConfig) error {
+	if err := w.mv.VerifyLive(cfg); err != nil {
+		return WithPhase(PhaseVerifyLoaded, fmt.Errorf("module is no longer loaded as expected: %w", err))
+	}
+
+	return nil
+}
+
+type unloadMode int
+
+const (
+	unloadNormal unloadMode = iota
+	unloadForce
+	unloadSkip
+)
+
+const (
+	DefaultModuleInUsePollInterval = 2 * time.Second
+	DefaultModuleInUseTimeout      = time.Minute
+)
+
+// checkModuleUsage applies the module's InUsePolicy if the module is in use.
+// With the Wait policy, it waits for the module to be released and returns an Error with ReasonModuleInUse listing
+// what holds the module if it is not released in time.
+func (w *worker) checkModuleUsage(ctx context.Context, cfg *kmmv1beta1.ModuleConfig) (unloadMode, error) {
+	name := cfg.Modprobe.ModuleName
+	policy := cfg.Modprobe.InUsePolicy
+
+	if name == "" {
+		return unloadNormal, nil
+	}
+
+	logger := w.logger.WithValues("module", name, "policy", policy)
+
+	timeout := w.timeouts.InUse
+	if timeout == 0 {
+		timeout = DefaultModuleInUseTimeout
+	}
+
+	pollInterval := w.timeouts.InUsePollInterval
+	if pollInterval == 0 {
+		pollInterval = DefaultModuleInUsePollInterval
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		mu, err := w.uc.ModuleUsage(name)
+		if err != nil {
+			return unloadNormal, fmt.Errorf("could not check if module %s is in use: %v", name, err)
+		}
+
+		if mu == nil || !mu.InUse() {
+			return unloadNormal, nil
+		}
+
+		switch policy {
+		case kmmv1beta1.ModuleInUsePolicyForce:
+			logger.Info(utils.WarnString("Module is in use; forcing its removal"), "usage", mu.String())
+			return unloadForce, nil
+		case kmmv1beta1.ModuleInUsePolicySkip:
+			logger.Info(utils.WarnString("Module is in use; not unloading it"), "usage", mu.String())
+			return unloadSkip, nil
+		}
+
+		if time.Now().After(deadline) {
+			return unloadNormal, NewError(ReasonModuleInUse, fmt.Errorf("module is still in use after %v: %s", timeout, mu))
+		}
+
+		logger.Info("Module is in use; waiting for it to be released", "usage", mu.String())
+
+		select {
+		case <-ctx.Done():
+			return unloadNormal, NewError(ReasonModuleInUse, fmt.Errorf("stopped waiting for the module to be released: %s: %v", mu, ctx.Err()))
+		case <-time.After(pollInterval):
+		}
+	}
+}
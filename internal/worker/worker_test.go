@@ -3,9 +3,13 @@ package worker
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -14,8 +18,12 @@ import (
 
 var _ = Describe("worker_LoadKmod", func() {
 	var (
+		bm       BlacklistManager
+		dm       *MockDepmod
+		fm       *MockFirmwareManager
 		im       *MockImageMounter
 		mr       *MockModprobeRunner
+		mv       *MockModuleVerifier
 		w        Worker
 		imageDir string
 		hostDir  string
@@ -25,7 +33,11 @@ var _ = Describe("worker_LoadKmod", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		im = NewMockImageMounter(ctrl)
 		mr = NewMockModprobeRunner(ctrl)
-		w = NewWorker(im, mr, GinkgoLogr)
+		mv = NewMockModuleVerifier(ctrl)
+		fm = NewMockFirmwareManager(ctrl)
+		dm = NewMockDepmod(ctrl)
+		bm = NewBlacklistManager(GinkgoT().TempDir(), "some-namespace", "some-name", GinkgoLogr)
+		w = NewWorker(im, mr, mv, nil, fm, dm, NewHookRunner(GinkgoLogr), bm, nil, Timeouts{}, GinkgoLogr)
 
 		var err error
 		imageDir, err = os.MkdirTemp("", "imageDir")
@@ -58,13 +70,110 @@ var _ = Describe("worker_LoadKmod", func() {
 			MountImage(ctx, imageName, &cfg).
 			Return("", errors.New("random error"))
 
+		err := w.LoadKmod(ctx, &cfg, "")
+		Expect(err).To(HaveOccurred())
+		Expect(PhaseFromError(err)).To(Equal(PhasePullImage))
+		Expect(ReasonFromError(err)).To(Equal(ReasonImagePull))
+	})
+
+	It("should report authentication failures when pulling the image", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+		}
+		im.
+			EXPECT().
+			MountImage(ctx, imageName, &cfg).
+			Return("", fmt.Errorf("could not pull: %w", &transport.Error{StatusCode: http.StatusUnauthorized}))
+
 		Expect(
-			w.LoadKmod(ctx, &cfg, ""),
+			ReasonFromError(w.LoadKmod(ctx, &cfg, "")),
 		).To(
-			HaveOccurred(),
+			Equal(ReasonImageAuth),
 		)
 	})
 
+	It("should time out if the image is not pulled in time", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+		}
+
+		w = NewWorker(im, mr, mv, nil, fm, dm, NewHookRunner(GinkgoLogr), bm, nil, Timeouts{Pull: 10 * time.Millisecond}, GinkgoLogr)
+
+		im.
+			EXPECT().
+			MountImage(gomock.Any(), imageName, &cfg).
+			DoAndReturn(func(ctx context.Context, _ string, _ *v1beta1.ModuleConfig) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			})
+
+		err := w.LoadKmod(ctx, &cfg, "")
+		Expect(err).To(HaveOccurred())
+		Expect(PhaseFromError(err)).To(Equal(PhasePullImage))
+		Expect(ReasonFromError(err)).To(Equal(ReasonTimeout))
+	})
+
+	It("should time out if the module is not loaded in time", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		w = NewWorker(im, mr, mv, nil, fm, dm, NewHookRunner(GinkgoLogr), bm, nil, Timeouts{Load: 10 * time.Millisecond}, GinkgoLogr)
+
+		DeferCleanup(func(d time.Duration) { timeoutGracePeriod = d }, timeoutGracePeriod)
+		timeoutGracePeriod = 0
+
+		release := make(chan struct{})
+		defer close(release)
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			mv.EXPECT().VerifyCompatible(&cfg, ""),
+			mr.
+				EXPECT().
+				Run(gomock.Any(), "-vd", dirName, moduleName).
+				DoAndReturn(func(context.Context, ...string) error {
+					// finit_module cannot be interrupted
+					<-release
+					return errors.New("random error")
+				}),
+		)
+
+		err := w.LoadKmod(ctx, &cfg, "")
+		Expect(err).To(MatchError("loading the module did not complete within 10ms"))
+		Expect(ReasonFromError(err)).To(Equal(ReasonTimeout))
+	})
+
+	It("should return an error if the module is not compatible with the running kernel", func() {
+		const inTreeModuleToRemove = "intree"
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage:       imageName,
+			InTreeModuleToRemove: inTreeModuleToRemove,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			mv.
+				EXPECT().
+				VerifyCompatible(&cfg, "").
+				Return(NewError(ReasonKernelMismatch, errors.New("random error"))),
+		)
+
+		err := w.LoadKmod(ctx, &cfg, "")
+		Expect(err).To(HaveOccurred())
+		Expect(PhaseFromError(err)).To(Equal(PhaseVerifyCompatible))
+		Expect(ReasonFromError(err)).To(Equal(ReasonKernelMismatch))
+	})
+
 	It("should return an error if modprobe failed", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
@@ -76,22 +185,75 @@ var _ = Describe("worker_LoadKmod", func() {
 
 		gomock.InOrder(
 			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			mv.EXPECT().VerifyCompatible(&cfg, ""),
 			mr.EXPECT().Run(ctx, "-vd", dirName, moduleName).Return(errors.New("random error")),
 		)
 
+		err := w.LoadKmod(ctx, &cfg, "")
+		Expect(err).To(HaveOccurred())
+		Expect(PhaseFromError(err)).To(Equal(PhaseModprobe))
+	})
+
+	It("should return an error if the module could not be verified after loading", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			mv.EXPECT().VerifyCompatible(&cfg, ""),
+			mr.EXPECT().Run(ctx, "-vd", dirName, moduleName),
+			mv.EXPECT().VerifyLoaded(&cfg, "").Return(errors.New("random error")),
+		)
+
+		err := w.LoadKmod(ctx, &cfg, "")
+		Expect(err).To(HaveOccurred())
+		Expect(PhaseFromError(err)).To(Equal(PhaseVerifyLoaded))
+	})
+
+	It("should blacklist and remove the in-tree modules if configured", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage:        imageName,
+			InTreeModulesToRemove: []string{"intree1", "intree2"},
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			mv.EXPECT().VerifyCompatible(&cfg, ""),
+			mr.EXPECT().Run(ctx, "-rv", "intree1").Do(func(_ context.Context, _ ...string) {
+				Expect(bm.Path()).To(BeARegularFile())
+			}),
+			mr.EXPECT().Run(ctx, "-rv", "intree2"),
+			mr.EXPECT().Run(ctx, "-vd", dirName, moduleName),
+			mv.EXPECT().VerifyLoaded(&cfg, ""),
+		)
+
 		Expect(
 			w.LoadKmod(ctx, &cfg, ""),
-		).To(
+		).NotTo(
 			HaveOccurred(),
 		)
+
+		Expect(
+			os.ReadFile(bm.Path()),
+		).To(
+			ContainSubstring("blacklist intree1\nblacklist intree2\n"),
+		)
 	})
 
-	It("should remove the in-tree module if configured", func() {
-		const inTreeModuleToRemove = "intree"
+	It("should remove a stale blacklist if no in-tree module is configured", func() {
+		Expect(bm.Write([]string{"intree"})).To(Succeed())
 
 		cfg := v1beta1.ModuleConfig{
-			ContainerImage:       imageName,
-			InTreeModuleToRemove: inTreeModuleToRemove,
+			ContainerImage: imageName,
 			Modprobe: v1beta1.ModprobeSpec{
 				ModuleName: moduleName,
 				DirName:    dirName,
@@ -100,18 +262,41 @@ var _ = Describe("worker_LoadKmod", func() {
 
 		gomock.InOrder(
 			im.EXPECT().MountImage(ctx, imageName, &cfg),
-			mr.EXPECT().Run(ctx, "-rv", inTreeModuleToRemove),
+			mv.EXPECT().VerifyCompatible(&cfg, ""),
 			mr.EXPECT().Run(ctx, "-vd", dirName, moduleName),
+			mv.EXPECT().VerifyLoaded(&cfg, ""),
+		)
+
+		Expect(w.LoadKmod(ctx, &cfg, "")).To(Succeed())
+		Expect(bm.Path()).NotTo(BeAnExistingFile())
+	})
+
+	It("should install the firmware files if configured", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:   moduleName,
+				DirName:      dirName,
+				FirmwarePath: "/firmwareDir",
+			},
+		}
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg).Return(imageDir, nil),
+			mv.EXPECT().VerifyCompatible(&cfg, imageDir),
+			fm.EXPECT().Install(imageDir+"/firmwareDir", hostDir),
+			mr.EXPECT().Run(ctx, "-vd", imageDir+dirName, moduleName),
+			mv.EXPECT().VerifyLoaded(&cfg, imageDir),
 		)
 
 		Expect(
-			w.LoadKmod(ctx, &cfg, ""),
+			w.LoadKmod(ctx, &cfg, hostDir),
 		).NotTo(
 			HaveOccurred(),
 		)
 	})
 
-	It("should copy all the firmware files/directories if configured", func() {
+	It("should not load the module if the firmware could not be installed", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
 			Modprobe: v1beta1.ModprobeSpec{
@@ -121,29 +306,121 @@ var _ = Describe("worker_LoadKmod", func() {
 			},
 		}
 
-		err := os.MkdirAll(imageDir+"/"+"firmwareDir/binDir", 0750)
-		Expect(err).Should(BeNil())
-		err = os.WriteFile(imageDir+"/"+"firmwareDir/firwmwareFile1", []byte("some data 1"), 0660)
-		Expect(err).Should(BeNil())
-		err = os.WriteFile(imageDir+"/"+"firmwareDir/binDir/firwmwareFile2", []byte("some data 2"), 0660)
-		Expect(err).Should(BeNil())
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg).Return(imageDir, nil),
+			mv.EXPECT().VerifyCompatible(&cfg, imageDir),
+			fm.
+				EXPECT().
+				Install(imageDir+"/firmwareDir", hostDir).
+				Return(NewError(ReasonFirmware, errors.New("random error"))),
+		)
+
+		err := w.LoadKmod(ctx, &cfg, hostDir)
+		Expect(ReasonFromError(err)).To(Equal(ReasonFirmware))
+		Expect(PhaseFromError(err)).To(Equal(PhaseInstallFirmware))
+	})
+
+	It("should generate modules.dep before running modprobe", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  "1.2.3",
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		modDir := filepath.Join(imageDir, dirName, "lib", "modules", "1.2.3")
+		Expect(os.MkdirAll(modDir, 0755)).To(Succeed())
 
 		gomock.InOrder(
 			im.EXPECT().MountImage(ctx, imageName, &cfg).Return(imageDir, nil),
+			mv.EXPECT().VerifyCompatible(&cfg, imageDir),
+			dm.EXPECT().EnsureModulesDep(modDir),
 			mr.EXPECT().Run(ctx, "-vd", imageDir+dirName, moduleName),
+			mv.EXPECT().VerifyLoaded(&cfg, imageDir),
 		)
 
 		Expect(
-			w.LoadKmod(ctx, &cfg, hostDir),
+			w.LoadKmod(ctx, &cfg, ""),
 		).NotTo(
 			HaveOccurred(),
 		)
-		_, err = os.Stat(hostDir + "/binDir")
-		Expect(err).Should(BeNil())
-		_, err = os.Stat(hostDir + "/binDir/firwmwareFile2")
-		Expect(err).Should(BeNil())
-		_, err = os.Stat(hostDir + "/firwmwareFile1")
-		Expect(err).Should(BeNil())
+	})
+
+	It("should not run modprobe if modules.dep could not be generated", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  "1.2.3",
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		modDir := filepath.Join(imageDir, dirName, "lib", "modules", "1.2.3")
+		Expect(os.MkdirAll(modDir, 0755)).To(Succeed())
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg).Return(imageDir, nil),
+			mv.EXPECT().VerifyCompatible(&cfg, imageDir),
+			dm.EXPECT().EnsureModulesDep(modDir).Return(errors.New("random error")),
+		)
+
+		err := w.LoadKmod(ctx, &cfg, "")
+		Expect(err).To(MatchError(ContainSubstring("random error")))
+		Expect(PhaseFromError(err)).To(Equal(PhaseDepmod))
+	})
+
+	It("should run the hooks around modprobe", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		hr := NewMockHookRunner(gomock.NewController(GinkgoT()))
+		w = NewWorker(im, mr, mv, nil, fm, dm, hr, bm, nil, Timeouts{}, GinkgoLogr)
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg).Return(imageDir, nil),
+			mv.EXPECT().VerifyCompatible(&cfg, imageDir),
+			hr.EXPECT().RunHook(ctx, &cfg, imageDir, HookPreLoad),
+			mr.EXPECT().Run(ctx, "-vd", imageDir+dirName, moduleName),
+			hr.EXPECT().RunHook(ctx, &cfg, imageDir, HookPostLoad),
+			mv.EXPECT().VerifyLoaded(&cfg, imageDir),
+		)
+
+		Expect(
+			w.LoadKmod(ctx, &cfg, ""),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should not run modprobe if the pre-load hook failed", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe:       v1beta1.ModprobeSpec{ModuleName: moduleName},
+		}
+
+		hr := NewMockHookRunner(gomock.NewController(GinkgoT()))
+		w = NewWorker(im, mr, mv, nil, fm, dm, hr, bm, nil, Timeouts{}, GinkgoLogr)
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg).Return(imageDir, nil),
+			mv.EXPECT().VerifyCompatible(&cfg, imageDir),
+			hr.
+				EXPECT().
+				RunHook(ctx, &cfg, imageDir, HookPreLoad).
+				Return(NewError(ReasonPreLoadHookFailed, errors.New("random error"))),
+		)
+
+		err := w.LoadKmod(ctx, &cfg, "")
+		Expect(PhaseFromError(err)).To(Equal(PhasePreLoadHook))
+		Expect(ReasonFromError(err)).To(Equal(ReasonPreLoadHookFailed))
 	})
 
 	It("should use rawArgs if they are defined", func() {
@@ -158,7 +435,9 @@ var _ = Describe("worker_LoadKmod", func() {
 
 		gomock.InOrder(
 			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			mv.EXPECT().VerifyCompatible(&cfg, ""),
 			mr.EXPECT().Run(ctx, ToInterfaceSlice(rawArgs)...),
+			mv.EXPECT().VerifyLoaded(&cfg, ""),
 		)
 
 		Expect(
@@ -181,9 +460,11 @@ var _ = Describe("worker_LoadKmod", func() {
 
 		gomock.InOrder(
 			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			mv.EXPECT().VerifyCompatible(&cfg, ""),
 			mr.
 				EXPECT().
 				Run(ctx, "-vd", dirName, "a", "b", "c", moduleName, "key0=value0", "key1=value1"),
+			mv.EXPECT().VerifyLoaded(&cfg, ""),
 		)
 
 		Expect(
@@ -195,7 +476,7 @@ var _ = Describe("worker_LoadKmod", func() {
 })
 
 var _ = Describe("worker_SetFirmwareClassPath", func() {
-	w := NewWorker(nil, nil, GinkgoLogr)
+	w := NewWorker(nil, nil, nil, nil, nil, nil, nil, nil, nil, Timeouts{}, GinkgoLogr)
 
 	AfterEach(func() {
 		firmwareClassPathLocation = FirmwareClassPathLocation
@@ -241,10 +522,51 @@ var _ = Describe("worker_SetFirmwareClassPath", func() {
 	)
 })
 
+var _ = Describe("worker_VerifyKmod", func() {
+	var (
+		mv *MockModuleVerifier
+		w  Worker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		mv = NewMockModuleVerifier(ctrl)
+		w = NewWorker(nil, nil, mv, nil, nil, nil, nil, nil, nil, Timeouts{}, GinkgoLogr)
+	})
+
+	cfg := &v1beta1.ModuleConfig{
+		Modprobe: v1beta1.ModprobeSpec{ModuleName: "some-module"},
+	}
+
+	It("should return an error with the reason and phase if the module is not live", func() {
+		mv.EXPECT().VerifyLive(cfg).Return(NewError(ReasonModuleNotLoaded, errors.New("random error")))
+
+		err := w.VerifyKmod(cfg)
+		Expect(err).To(HaveOccurred())
+		Expect(ReasonFromError(err)).To(Equal(ReasonModuleNotLoaded))
+		Expect(PhaseFromError(err)).To(Equal(PhaseVerifyLoaded))
+	})
+
+	It("should work as expected", func() {
+		mv.EXPECT().VerifyLive(cfg)
+
+		Expect(
+			w.VerifyKmod(cfg),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
+
 var _ = Describe("worker_UnloadKmod", func() {
 	var (
+		bm       BlacklistManager
+		dm       *MockDepmod
+		fm       *MockFirmwareManager
 		im       *MockImageMounter
 		mr       *MockModprobeRunner
+		mv       *MockModuleVerifier
+		uc       *MockUsageChecker
 		w        Worker
 		imageDir string
 		hostDir  string
@@ -254,7 +576,12 @@ var _ = Describe("worker_UnloadKmod", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		im = NewMockImageMounter(ctrl)
 		mr = NewMockModprobeRunner(ctrl)
-		w = NewWorker(im, mr, GinkgoLogr)
+		mv = NewMockModuleVerifier(ctrl)
+		uc = NewMockUsageChecker(ctrl)
+		fm = NewMockFirmwareManager(ctrl)
+		dm = NewMockDepmod(ctrl)
+		bm = NewBlacklistManager(GinkgoT().TempDir(), "some-namespace", "some-name", GinkgoLogr)
+		w = NewWorker(im, mr, mv, uc, fm, dm, NewHookRunner(GinkgoLogr), bm, nil, Timeouts{}, GinkgoLogr)
 		var err error
 		imageDir, err = os.MkdirTemp("", "imageDir")
 		Expect(err).Should(BeNil())
@@ -305,6 +632,7 @@ var _ = Describe("worker_UnloadKmod", func() {
 
 		gomock.InOrder(
 			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			uc.EXPECT().ModuleUsage(moduleName),
 			mr.EXPECT().Run(ctx, "-rvd", dirName, moduleName).Return(errors.New("random error")),
 		)
 
@@ -315,6 +643,28 @@ var _ = Describe("worker_UnloadKmod", func() {
 		)
 	})
 
+	It("should remove the blacklist before unloading the module", func() {
+		Expect(bm.Write([]string{"intree"})).To(Succeed())
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			uc.EXPECT().ModuleUsage(moduleName),
+			mr.EXPECT().Run(ctx, "-rvd", dirName, moduleName).Do(func(_ context.Context, _ ...string) {
+				Expect(bm.Path()).NotTo(BeAnExistingFile())
+			}),
+		)
+
+		Expect(w.UnloadKmod(ctx, &cfg, "")).To(Succeed())
+	})
+
 	It("should use rawArgs if they are defined", func() {
 		rawArgs := []string{"a", "b", "c"}
 
@@ -349,6 +699,7 @@ var _ = Describe("worker_UnloadKmod", func() {
 
 		gomock.InOrder(
 			im.EXPECT().MountImage(ctx, imageName, &cfg),
+			uc.EXPECT().ModuleUsage(moduleName),
 			mr.
 				EXPECT().
 				Run(ctx, "-rvd", dirName, "a", "b", "c", moduleName),
@@ -361,7 +712,124 @@ var _ = Describe("worker_UnloadKmod", func() {
 		)
 	})
 
-	It("should remove all firmware file only", func() {
+	Context("module in use", func() {
+		inUse := &ModuleUsage{Name: moduleName, RefCount: 1, Holders: []string{"other"}}
+
+		newCfg := func(policy v1beta1.ModuleInUsePolicy) *v1beta1.ModuleConfig {
+			return &v1beta1.ModuleConfig{
+				ContainerImage: imageName,
+				Modprobe: v1beta1.ModprobeSpec{
+					ModuleName:  moduleName,
+					DirName:     dirName,
+					InUsePolicy: policy,
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			w = NewWorker(
+				im,
+				mr,
+				mv,
+				uc,
+				fm,
+				dm,
+				NewHookRunner(GinkgoLogr),
+				bm,
+				nil,
+				Timeouts{InUse: 50 * time.Millisecond, InUsePollInterval: time.Millisecond},
+				GinkgoLogr,
+			)
+		})
+
+		It("should not unload the module with the Skip policy", func() {
+			cfg := newCfg(v1beta1.ModuleInUsePolicySkip)
+
+			Expect(bm.Write([]string{"intree"})).To(Succeed())
+
+			gomock.InOrder(
+				im.EXPECT().MountImage(ctx, imageName, cfg),
+				uc.EXPECT().ModuleUsage(moduleName).Return(inUse, nil),
+			)
+
+			Expect(
+				w.UnloadKmod(ctx, cfg, ""),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			// The Module is not configured anymore, so the in-tree modules may be loaded again.
+			Expect(bm.Path()).NotTo(BeAnExistingFile())
+		})
+
+		It("should force the removal with the Force policy", func() {
+			cfg := newCfg(v1beta1.ModuleInUsePolicyForce)
+
+			gomock.InOrder(
+				im.EXPECT().MountImage(ctx, imageName, cfg),
+				uc.EXPECT().ModuleUsage(moduleName).Return(inUse, nil),
+				mr.EXPECT().Run(ctx, "-rvd", dirName, "--force", moduleName),
+			)
+
+			Expect(
+				w.UnloadKmod(ctx, cfg, ""),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should wait for the module to be released with the Wait policy", func() {
+			cfg := newCfg(v1beta1.ModuleInUsePolicyWait)
+
+			gomock.InOrder(
+				im.EXPECT().MountImage(ctx, imageName, cfg),
+				uc.EXPECT().ModuleUsage(moduleName).Return(inUse, nil).Times(2),
+				uc.EXPECT().ModuleUsage(moduleName).Return(&ModuleUsage{Name: moduleName}, nil),
+				mr.EXPECT().Run(ctx, "-rvd", dirName, moduleName),
+			)
+
+			Expect(
+				w.UnloadKmod(ctx, cfg, ""),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should report the holders if the module is not released in time", func() {
+			cfg := newCfg("")
+
+			gomock.InOrder(
+				im.EXPECT().MountImage(ctx, imageName, cfg),
+				uc.EXPECT().ModuleUsage(moduleName).Return(inUse, nil).MinTimes(1),
+			)
+
+			err := w.UnloadKmod(ctx, cfg, "")
+			Expect(err).To(MatchError(ContainSubstring("used by modules other")))
+			Expect(ReasonFromError(err)).To(Equal(ReasonModuleInUse))
+			Expect(PhaseFromError(err)).To(Equal(PhaseCheckModuleUsage))
+		})
+
+		It("should report modules taken after the check as in use", func() {
+			cfg := newCfg("")
+
+			gomock.InOrder(
+				im.EXPECT().MountImage(ctx, imageName, cfg),
+				uc.EXPECT().ModuleUsage(moduleName),
+				mr.
+					EXPECT().
+					Run(ctx, "-rvd", dirName, moduleName).
+					Return(errors.New("modprobe: FATAL: Module test is in use.")),
+			)
+
+			Expect(
+				ReasonFromError(w.UnloadKmod(ctx, cfg, "")),
+			).To(
+				Equal(ReasonModuleInUse),
+			)
+		})
+	})
+
+	It("should remove the firmware files after unloading the module", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
 			Modprobe: v1beta1.ModprobeSpec{
@@ -371,27 +839,13 @@ var _ = Describe("worker_UnloadKmod", func() {
 			},
 		}
 
-		// prepare the image firmware directories + files
-		err := os.MkdirAll(imageDir+"/"+"firmwareDir/binDir", 0750)
-		Expect(err).Should(BeNil())
-		err = os.WriteFile(imageDir+"/"+"firmwareDir/firmwareFile1", []byte("some data 1"), 0660)
-		Expect(err).Should(BeNil())
-		err = os.WriteFile(imageDir+"/"+"firmwareDir/binDir/firmwareFile2", []byte("some data 2"), 0660)
-		Expect(err).Should(BeNil())
-
-		// prepare the mapped host firmware directories + files
-		err = os.MkdirAll(hostDir+"/binDir", 0750)
-		Expect(err).Should(BeNil())
-		err = os.WriteFile(hostDir+"/firmwareFile1", []byte("some data 1"), 0660)
-		Expect(err).Should(BeNil())
-		err = os.WriteFile(hostDir+"/binDir/firmwareFile2", []byte("some data 2"), 0660)
-		Expect(err).Should(BeNil())
-
 		gomock.InOrder(
 			im.EXPECT().MountImage(ctx, imageName, &cfg).Return(imageDir, nil),
+			uc.EXPECT().ModuleUsage(moduleName),
 			mr.
 				EXPECT().
 				Run(ctx, "-rvd", imageDir+dirName, moduleName),
+			fm.EXPECT().Remove(imageDir+"/firmwareDir", hostDir).Return(errors.New("random error")),
 		)
 
 		Expect(
@@ -399,14 +853,33 @@ var _ = Describe("worker_UnloadKmod", func() {
 		).NotTo(
 			HaveOccurred(),
 		)
+	})
+	It("should run the hooks around modprobe", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
 
-		// check only the files deletion
-		_, err = os.Stat(hostDir + "/binDir")
-		Expect(err).Should(BeNil())
-		_, err = os.Stat(hostDir + "/binDir/firwmwareFile2")
-		Expect(err).NotTo(BeNil())
-		_, err = os.Stat(hostDir + "/firwmwareFile1")
-		Expect(err).NotTo(BeNil())
+		hr := NewMockHookRunner(gomock.NewController(GinkgoT()))
+		w = NewWorker(im, mr, mv, uc, fm, dm, hr, bm, nil, Timeouts{}, GinkgoLogr)
+
+		gomock.InOrder(
+			im.EXPECT().MountImage(ctx, imageName, &cfg).Return(imageDir, nil),
+			hr.EXPECT().RunHook(ctx, &cfg, imageDir, HookPreUnload),
+			uc.EXPECT().ModuleUsage(moduleName),
+			mr.EXPECT().Run(ctx, "-rvd", imageDir+dirName, moduleName),
+			hr.
+				EXPECT().
+				RunHook(ctx, &cfg, imageDir, HookPostUnload).
+				Return(NewError(ReasonPostUnloadHookFailed, errors.New("random error"))),
+		)
+
+		err := w.UnloadKmod(ctx, &cfg, "")
+		Expect(PhaseFromError(err)).To(Equal(PhasePostUnloadHook))
+		Expect(ReasonFromError(err)).To(Equal(ReasonPostUnloadHookFailed))
 	})
 })
 
@@ -0,0 +1,89 @@
+package localimage
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// Transport is the scheme of an image reference that designates an image stored on the node's filesystem.
+type Transport string
+
+const (
+	// TransportOCILayout designates an OCI image layout directory.
+	TransportOCILayout Transport = "oci"
+	// TransportOCIArchive designates a tarball containing an OCI image layout.
+	TransportOCIArchive Transport = "oci-archive"
+)
+
+// Reference is a parsed local image reference.
+// Its format is transport:path, optionally followed by :tag or @digest to select an image in the layout's index.
+// The tag is matched against the org.opencontainers.image.ref.name annotation.
+type Reference struct {
+	Transport Transport
+	Path      string
+	Tag       string
+	Digest    string
+}
+
+// IsLocal returns true if image starts with one of the local transports.
+func IsLocal(image string) bool {
+	t, _, ok := strings.Cut(image, ":")
+	if !ok {
+		return false
+	}
+
+	switch Transport(t) {
+	case TransportOCILayout, TransportOCIArchive:
+		return true
+	default:
+		return false
+	}
+}
+
+// Parse parses a local image reference such as oci:/path/to/layout:tag or oci-archive:/path/to/image.tar.
+func Parse(image string) (*Reference, error) {
+	if !IsLocal(image) {
+		return nil, fmt.Errorf("%q is not a local image reference", image)
+	}
+
+	t, rest, _ := strings.Cut(image, ":")
+
+	ref := Reference{Transport: Transport(t)}
+
+	if p, digest, ok := strings.Cut(rest, "@"); ok {
+		h, err := v1.NewHash(digest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid digest in %q: %v", image, err)
+		}
+
+		ref.Path = p
+		ref.Digest = h.String()
+	} else {
+		var hasTag bool
+
+		if ref.Path, ref.Tag, hasTag = strings.Cut(rest, ":"); hasTag && ref.Tag == "" {
+			return nil, fmt.Errorf("empty tag in %q", image)
+		}
+	}
+
+	if ref.Path == "" {
+		return nil, fmt.Errorf("missing path in %q", image)
+	}
+
+	if !filepath.IsAbs(ref.Path) {
+		return nil, fmt.Errorf("path %q in %q is not absolute", ref.Path, image)
+	}
+
+	if filepath.Clean(ref.Path) != ref.Path {
+		return nil, fmt.Errorf("path %q in %q is not canonical", ref.Path, image)
+	}
+
+	if strings.ContainsAny(ref.Tag, ":@/") {
+		return nil, fmt.Errorf("invalid tag %q in %q", ref.Tag, image)
+	}
+
+	return &ref, nil
+}
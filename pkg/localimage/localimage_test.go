@@ -0,0 +1,51 @@
+package localimage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+var _ = DescribeTable(
+	"IsLocal",
+	func(image string, expected bool) {
+		Expect(IsLocal(image)).To(Equal(expected))
+	},
+	Entry(nil, "oci:/some/path", true),
+	Entry(nil, "oci-archive:/some/path.tar", true),
+	Entry(nil, "quay.io/org/image:tag", false),
+	Entry(nil, "localhost:5000/image", false),
+	Entry(nil, "oci", false),
+)
+
+var _ = Describe("Parse", func() {
+	DescribeTable(
+		"should parse valid references",
+		func(image string, expected Reference) {
+			Expect(Parse(image)).To(Equal(&expected))
+		},
+		Entry(nil, "oci:/some/path", Reference{Transport: TransportOCILayout, Path: "/some/path"}),
+		Entry(nil, "oci:/some/path:v1", Reference{Transport: TransportOCILayout, Path: "/some/path", Tag: "v1"}),
+		Entry(
+			nil,
+			"oci-archive:/some/path.tar@"+digest,
+			Reference{Transport: TransportOCIArchive, Path: "/some/path.tar", Digest: digest},
+		),
+	)
+
+	DescribeTable(
+		"should return an error for invalid references",
+		func(image string) {
+			_, err := Parse(image)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("not local", "quay.io/org/image:tag"),
+		Entry("relative path", "oci:some/path"),
+		Entry("missing path", "oci::tag"),
+		Entry("non-canonical path", "oci:/some/../path"),
+		Entry("empty tag", "oci:/some/path:"),
+		Entry("invalid tag", "oci:/some/path:a:b"),
+		Entry("invalid digest", "oci:/some/path@sha256:1234"),
+	)
+})
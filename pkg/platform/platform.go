@@ -0,0 +1,102 @@
+// Package platform selects the image matching a node's platform in multi-architecture image indexes.
+// The worker and the controllers use the same rules, so that the image checked by the controllers is the one unpacked
+// on the node.
+package platform
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultPlatform is the platform assumed for index entries that do not specify one.
+var defaultPlatform = v1.Platform{OS: "linux", Architecture: "amd64"}
+
+// Parse parses a platform in the os/architecture[/variant] format.
+func Parse(s string) (*v1.Platform, error) {
+	p, err := v1.ParsePlatform(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid platform %q: %v", s, err)
+	}
+
+	if p.OS == "" || p.Architecture == "" {
+		return nil, fmt.Errorf("invalid platform %q: expected os/architecture[/variant]", s)
+	}
+
+	return p, nil
+}
+
+// Runtime returns the platform of the running binary.
+// On 32-bit ARM, the variant is derived from the GOARM value the binary was built with.
+func Runtime() *v1.Platform {
+	p := v1.Platform{
+		OS:           runtime.GOOS,
+		Architecture: runtime.GOARCH,
+	}
+
+	if runtime.GOARCH == "arm" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, s := range bi.Settings {
+				if s.Key == "GOARM" {
+					p.Variant = "v" + s.Value
+				}
+			}
+		}
+	}
+
+	return &p
+}
+
+// ForNode returns the platform of node, as reported by the kubelet.
+// It returns nil if the node does not report its operating system or architecture.
+func ForNode(node *corev1.Node) *v1.Platform {
+	info := node.Status.NodeInfo
+
+	if info.OperatingSystem == "" || info.Architecture == "" {
+		return nil
+	}
+
+	return &v1.Platform{
+		OS:           info.OperatingSystem,
+		Architecture: info.Architecture,
+	}
+}
+
+// Matches returns true if an index entry with platform given can run on required.
+// OS and architecture must be identical; the variant and OS version are only compared if required sets them.
+// Entries without a platform are assumed to be linux/amd64.
+func Matches(given *v1.Platform, required v1.Platform) bool {
+	g := defaultPlatform
+
+	if given != nil {
+		g = *given
+	}
+
+	if g.OS != required.OS || g.Architecture != required.Architecture {
+		return false
+	}
+
+	if required.Variant != "" && g.Variant != required.Variant {
+		return false
+	}
+
+	if required.OSVersion != "" && g.OSVersion != required.OSVersion {
+		return false
+	}
+
+	return true
+}
+
+// SelectManifest returns the first entry of index that matches p.
+func SelectManifest(index *v1.IndexManifest, p v1.Platform) (*v1.Descriptor, error) {
+	for i, m := range index.Manifests {
+		if Matches(m.Platform, p) {
+			return &index.Manifests[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no image for platform %s in the index", p.String())
+}
@@ -0,0 +1,100 @@
+package platform
+
+import (
+	"runtime"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Parse", func() {
+	DescribeTable(
+		"should work as expected",
+		func(s string, expected *v1.Platform, expectError bool) {
+			p, err := Parse(s)
+
+			if expectError {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(Equal(expected))
+		},
+		Entry(nil, "linux/amd64", &v1.Platform{OS: "linux", Architecture: "amd64"}, false),
+		Entry(nil, "linux/arm/v7", &v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}, false),
+		Entry("no architecture", "linux", nil, true),
+		Entry("empty", "", nil, true),
+		Entry("too many slashes", "linux/arm/v7/extra", nil, true),
+	)
+})
+
+var _ = Describe("Runtime", func() {
+	It("should return the platform of the binary", func() {
+		p := Runtime()
+
+		Expect(p.OS).To(Equal(runtime.GOOS))
+		Expect(p.Architecture).To(Equal(runtime.GOARCH))
+	})
+})
+
+var _ = Describe("ForNode", func() {
+	It("should return nil if the node does not report its platform", func() {
+		Expect(ForNode(&corev1.Node{})).To(BeNil())
+	})
+
+	It("should return the node's platform", func() {
+		node := corev1.Node{
+			Status: corev1.NodeStatus{
+				NodeInfo: corev1.NodeSystemInfo{OperatingSystem: "linux", Architecture: "arm64"},
+			},
+		}
+
+		Expect(ForNode(&node)).To(Equal(&v1.Platform{OS: "linux", Architecture: "arm64"}))
+	})
+})
+
+var _ = Describe("SelectManifest", func() {
+	index := &v1.IndexManifest{
+		Manifests: []v1.Descriptor{
+			{
+				Digest: v1.Hash{Algorithm: "sha256", Hex: "no-platform"},
+			},
+			{
+				Digest:   v1.Hash{Algorithm: "sha256", Hex: "arm64"},
+				Platform: &v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			},
+			{
+				Digest:   v1.Hash{Algorithm: "sha256", Hex: "armv6"},
+				Platform: &v1.Platform{OS: "linux", Architecture: "arm", Variant: "v6"},
+			},
+			{
+				Digest:   v1.Hash{Algorithm: "sha256", Hex: "armv7"},
+				Platform: &v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+			},
+		},
+	}
+
+	DescribeTable(
+		"should work as expected",
+		func(p v1.Platform, expectedHex string) {
+			d, err := SelectManifest(index, p)
+
+			if expectedHex == "" {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(d.Digest.Hex).To(Equal(expectedHex))
+		},
+		Entry("entry without platform", v1.Platform{OS: "linux", Architecture: "amd64"}, "no-platform"),
+		Entry("any variant", v1.Platform{OS: "linux", Architecture: "arm64"}, "arm64"),
+		Entry("first entry for the architecture", v1.Platform{OS: "linux", Architecture: "arm"}, "armv6"),
+		Entry("explicit variant", v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}, "armv7"),
+		Entry("missing variant", v1.Platform{OS: "linux", Architecture: "arm", Variant: "v5"}, ""),
+		Entry("missing architecture", v1.Platform{OS: "linux", Architecture: "s390x"}, ""),
+	)
+})